@@ -7,6 +7,7 @@
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -15,7 +16,10 @@
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,15 +30,19 @@
 	"github.com/mattn/go-colorable"
 	"github.com/rancher/rancher/pkg/agent/clean"
 	"github.com/rancher/rancher/pkg/agent/cluster"
+	"github.com/rancher/rancher/pkg/agent/connect"
+	"github.com/rancher/rancher/pkg/agent/diagnose"
 	"github.com/rancher/rancher/pkg/agent/node"
 	"github.com/rancher/rancher/pkg/agent/rancher"
 	"github.com/rancher/rancher/pkg/features"
+	"github.com/rancher/rancher/pkg/logging"
 	"github.com/rancher/rancher/pkg/logserver"
 	"github.com/rancher/rancher/pkg/rkenodeconfigclient"
 	"github.com/rancher/rancher/pkg/rkenodeconfigserver"
 	"github.com/rancher/remotedialer"
 	"github.com/rancher/wrangler/pkg/signals"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 var (
@@ -44,6 +52,9 @@
 const (
 	Token                    = "X-API-Tunnel-Token"
 	KubeletCertValidityLimit = time.Hour * 72
+
+	serverCAFile   = "/etc/kubernetes/ssl/certs/serverca"
+	dockerCertsDir = "/etc/docker/certs.d"
 )
 
 func main() {
@@ -62,6 +73,7 @@ func main() {
 		if os.Getenv("CATTLE_DEBUG") == "true" || os.Getenv("RANCHER_DEBUG") == "true" {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
+		logging.SetLevel(logrus.GetLevel())
 
 		initFeatures()
 
@@ -96,11 +108,39 @@ func runArgs(ctx context.Context) error {
 	switch os.Args[1] {
 	case "clean":
 		return clean.Run(ctx, os.Args)
+	case "diagnose":
+		return runDiagnose(ctx)
 	default:
 		return run(ctx)
 	}
 }
 
+// runDiagnose checks the connectivity of the agent to Rancher and prints the report as JSON.
+func runDiagnose(ctx context.Context) error {
+	token, server, err := getTokenAndURL()
+	if err != nil {
+		return err
+	}
+	servers, err := connect.ParseServers(server, os.Getenv("CATTLE_SERVER_FAILOVER_URLS"))
+	if err != nil {
+		return err
+	}
+
+	// only the token is sent, the parameters of the agent would register it
+	report := diagnose.Run(ctx, VERSION, diagnose.Options{
+		Servers:    servers,
+		Headers:    http.Header{Token: {token}},
+		CAChecksum: cluster.CAChecksum(),
+	})
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("connectivity diagnosis failed")
+	}
+	return nil
+}
+
 func initFeatures() {
 	features.InitializeFeatures(nil, os.Getenv("CATTLE_FEATURES"))
 }
@@ -215,156 +255,85 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	serverURL, err := url.Parse(server)
+	servers, err := connect.ParseServers(server, os.Getenv("CATTLE_SERVER_FAILOVER_URLS"))
 	if err != nil {
 		return err
 	}
+	pinner := connect.NewCAPinner(cluster.CAChecksum(), caRotator(servers))
+	selector := connect.NewSelector(servers, pinner)
 
-	// Check if secure connection can be made successfully
-	var httpClient = &http.Client{
-		Timeout: time.Second * 5,
+	// Check if secure connection can be made successfully to at least one server
+	if _, err := selector.Select(ctx); err != nil {
+		if err := checkServerCertificates(servers); err != nil {
+			return err
+		}
 	}
 
-	_, err = httpClient.Get(server)
-	if err != nil {
-		if strings.Contains(err.Error(), "x509:") {
-			certErr := err
-			if strings.Contains(err.Error(), "certificate signed by unknown authority") {
-				certErr = fmt.Errorf("Certificate chain is not complete, please check if all needed intermediate certificates are included in the server certificate (in the correct order) and if the cacerts setting in Rancher either contains the correct CA certificate (in the case of using self signed certificates) or is empty (in the case of using a certificate signed by a recognized CA). Certificate information is displayed above. error: %s", err)
-			}
-			if strings.Contains(err.Error(), "certificate has expired or is not yet valid") {
-				certErr = fmt.Errorf("Server certificate is not valid, please check if the host has the correct time configured and if the server certificate has a notAfter date and time in the future. Certificate information is displayed above. error: %s", err)
-			}
-			if strings.Contains(err.Error(), "because it doesn't contain any IP SANs") || strings.Contains(err.Error(), "certificate is not valid for any names, but wanted to match") || strings.Contains(err.Error(), "cannot validate certificate for") {
-				certErr = fmt.Errorf("Server certificate does not contain correct DNS and/or IP address entries in the Subject Alternative Names (SAN). Certificate information is displayed above. error: %s", err)
-			}
-			insecureClient := &http.Client{
-				Timeout: time.Second * 5,
-				Transport: &http.Transport{
-					TLSClientConfig: &tls.Config{
-						InsecureSkipVerify: true,
-					},
-				},
+	var established int32
+	// onConnect returns the callback of a session to a server. Each session gets the server it connected to, as the
+	// reconnection loop moves on to another one.
+	onConnect := func(server connect.Server) func(context.Context, *remotedialer.Session) error {
+		return func(ctx context.Context, _ *remotedialer.Session) error {
+			atomic.StoreInt32(&established, 1)
+			connected()
+			if pinner != nil {
+				// keep learning the next CA of the server while connected, so that a rotation does not cut the agent off
+				go wait.UntilWithContext(ctx, func(ctx context.Context) {
+					if err := pinner.Announce(ctx, server); err != nil {
+						logrus.Warnf("Failed to check the next CA certificate of %s: %v", server.URL, err)
+					}
+				}, 5*time.Minute)
 			}
-			res, err := insecureClient.Get(server)
+			connectConfig := fmt.Sprintf("https://%s/v3/connect/config", server.URL.Host)
+			interval, err := rkenodeconfigclient.ConfigClient(ctx, connectConfig, headers, writeCertsOnly)
 			if err != nil {
-				logrus.Errorf("Could not connect to %s: %v", server, err)
-				os.Exit(1)
-			}
-			var lastFoundIssuer string
-			if res.TLS != nil && len(res.TLS.PeerCertificates) > 0 {
-				logrus.Infof("Certificate details from %s", serverURL)
-				var previouscert *x509.Certificate
-				for i := range res.TLS.PeerCertificates {
-					cert := res.TLS.PeerCertificates[i]
-					logrus.Infof("Certificate #%d (%s)", i, serverURL)
-					certinfo(cert)
-					if i > 0 {
-						if previouscert.Issuer.String() != cert.Subject.String() {
-							logrus.Errorf("Certficate's Subject (%s) does not match with previous certificate Issuer (%s). Please check if the configured server certificate contains all needed intermediate certificates and make sure they are in the correct order (server certificate first, intermediates after)", cert.Subject.String(), previouscert.Issuer.String())
-						}
-					}
-					previouscert = cert
-					lastFoundIssuer = cert.Issuer.String()
-				}
+				return err
 			}
-			caFileLocation := "/etc/kubernetes/ssl/certs/serverca"
-			if _, err := os.Stat(caFileLocation); err == nil {
-				caFile, err := ioutil.ReadFile(caFileLocation)
-				if err != nil {
-					return err
-				}
-				var blocks [][]byte
-				for {
-					var certDERBlock *pem.Block
-					certDERBlock, caFile = pem.Decode(caFile)
-					if certDERBlock == nil {
-						break
-					}
-
-					if certDERBlock.Type == "CERTIFICATE" {
-						blocks = append(blocks, certDERBlock.Bytes)
-					}
-				}
-				if len(blocks) > 1 {
-					logrus.Warnf("Found %d certificates at %s, should be 1", len(blocks), caFileLocation)
-				}
-				logrus.Infof("Certificate details for %s", caFileLocation)
-
-				blockcount := 0
-				var lastCACert *x509.Certificate
-				for _, block := range blocks {
-					cert, err := x509.ParseCertificate(block)
-					if err != nil {
-						logrus.Println(err)
-						continue
-					}
 
-					logrus.Infof("Certificate #%d (%s)", blockcount, caFileLocation)
-					certinfo(cert)
+			if writeCertsOnly {
+				exitCertWriter(ctx)
+			}
 
-					blockcount = blockcount + 1
-					lastCACert = cert
-				}
-				if lastFoundIssuer != lastCACert.Issuer.String() {
-					logrus.Errorf("Issuer of last certificate found in chain (%s) does not match with CA certificate Issuer (%s). Please check if the configured server certificate contains all needed intermediate certificates and make sure they are in the correct order (server certificate first, intermediates after)", lastFoundIssuer, lastCACert.Issuer.String())
+			if isCluster() {
+				err = rancher.Run(topContext)
+				if err != nil {
+					logrus.Fatal(err)
 				}
+				return nil
 			}
-			return certErr
-		}
-	}
-
-	onConnect := func(ctx context.Context, _ *remotedialer.Session) error {
-		connected()
-		connectConfig := fmt.Sprintf("https://%s/v3/connect/config", serverURL.Host)
-		interval, err := rkenodeconfigclient.ConfigClient(ctx, connectConfig, headers, writeCertsOnly)
-		if err != nil {
-			return err
-		}
 
-		if writeCertsOnly {
-			exitCertWriter(ctx)
-		}
-
-		if isCluster() {
-			err = rancher.Run(topContext)
-			if err != nil {
-				logrus.Fatal(err)
+			if err := cleanup(context.Background()); err != nil {
+				logrus.Warnf("Unable to perform docker cleanup: %v", err)
 			}
-			return nil
-		}
 
-		if err := cleanup(context.Background()); err != nil {
-			logrus.Warnf("Unable to perform docker cleanup: %v", err)
-		}
+			go func() {
+				logrus.Infof("Starting plan monitor, checking every %v seconds", interval)
+				tt := time.Duration(interval) * time.Second
+				for {
+					select {
+					case <-time.After(tt):
+						// each time we request a plan we should
+						// check if our cert about to expire
+						err = KubeletNeedsNewCertificate(headers)
+						if err != nil {
+							logrus.Errorf("failed to check validity of kubelet certs: %v", err)
+						}
+						receivedInterval, err := rkenodeconfigclient.ConfigClient(ctx, connectConfig, headers, writeCertsOnly)
+						if err != nil {
+							logrus.Errorf("failed to check plan: %v", err)
+						} else if receivedInterval != 0 && receivedInterval != interval {
+							tt = time.Duration(receivedInterval) * time.Second
+							logrus.Infof("Plan monitor checking %v seconds", receivedInterval)
+						}
 
-		go func() {
-			logrus.Infof("Starting plan monitor, checking every %v seconds", interval)
-			tt := time.Duration(interval) * time.Second
-			for {
-				select {
-				case <-time.After(tt):
-					// each time we request a plan we should
-					// check if our cert about to expire
-					err = KubeletNeedsNewCertificate(headers)
-					if err != nil {
-						logrus.Errorf("failed to check validity of kubelet certs: %v", err)
-					}
-					receivedInterval, err := rkenodeconfigclient.ConfigClient(ctx, connectConfig, headers, writeCertsOnly)
-					if err != nil {
-						logrus.Errorf("failed to check plan: %v", err)
-					} else if receivedInterval != 0 && receivedInterval != interval {
-						tt = time.Duration(receivedInterval) * time.Second
-						logrus.Infof("Plan monitor checking %v seconds", receivedInterval)
+					case <-ctx.Done():
+						return
 					}
-
-				case <-ctx.Done():
-					return
 				}
-			}
-		}()
+			}()
 
-		return nil
+			return nil
+		}
 	}
 
 	if isCluster() {
@@ -373,8 +342,15 @@ func run(ctx context.Context) error {
 		}()
 	}
 
+	backoff := connect.Backoff()
+	steered := 0
 	for {
-		wsURL := fmt.Sprintf("wss://%s/v3/connect", serverURL.Host)
+		current, err := selector.Select(ctx)
+		if err != nil {
+			logrus.Errorf("No healthy Rancher server, trying %s: %v", current.URL, err)
+		}
+
+		wsURL := fmt.Sprintf("wss://%s/v3/connect", current.URL.Host)
 		if !isConnect() {
 			wsURL += "/register"
 		}
@@ -387,7 +363,10 @@ func run(ctx context.Context) error {
 
 		logrus.Infof("Connecting to %s with token starting with %s", wsURL, token[:len(token)/2])
 		logrus.Tracef("Connecting to %s with token %s", wsURL, token)
-		remotedialer.ClientConnect(ctx, wsURL, headers, nil, func(proto, address string) bool {
+		atomic.StoreInt32(&established, 0)
+		tunnelHeaders := headers.Clone()
+		tunnelHeaders.Set(connect.SteeringHeader, strconv.Itoa(steered))
+		err = connect.ConnectToProxy(ctx, wsURL, tunnelHeaders, func(proto, address string) bool {
 			switch proto {
 			case "tcp":
 				return true
@@ -397,8 +376,170 @@ func run(ctx context.Context) error {
 				return address == "//./pipe/docker_engine"
 			}
 			return false
-		}, onConnect)
-		time.Sleep(5 * time.Second)
+		}, current.Dialer(), onConnect(current))
+		var steerErr *connect.SteeredError
+		if errors.As(err, &steerErr) {
+			// another Rancher server owns the cluster, reconnect through the load balancer hoping to reach it. Rancher
+			// stops steering once tunnel-steering-max-attempts is reached.
+			steered++
+			logrus.Infof("Rancher server %s owns the cluster, reconnecting", steerErr.Owner)
+			time.Sleep(connect.SteeringDelay())
+			continue
+		}
+		steered = 0
+		if err != nil {
+			logrus.WithError(err).Error("Remotedialer proxy error")
+		}
+
+		// back off further on every attempt that fails to establish a session
+		if atomic.LoadInt32(&established) == 1 {
+			backoff = connect.Backoff()
+		}
+		delay := backoff.Step()
+		logrus.Infof("Reconnecting in %v", delay.Round(time.Millisecond))
+		time.Sleep(delay)
+	}
+}
+
+// checkServerCertificates diagnoses the certificates of the servers the agent failed to reach. It returns an error if
+// none of the servers presents a certificate the agent can verify.
+func checkServerCertificates(servers []*url.URL) error {
+	var certErr error
+	for _, server := range servers {
+		err := checkServerCertificate(server)
+		if err == nil {
+			return nil
+		}
+		if certErr == nil {
+			certErr = err
+		}
+	}
+	return certErr
+}
+
+// checkServerCertificate logs the certificate chain of a server whose certificate cannot be verified and returns an
+// error describing the problem.
+func checkServerCertificate(serverURL *url.URL) error {
+	server := serverURL.String()
+	var httpClient = &http.Client{
+		Timeout: time.Second * 5,
+	}
+
+	_, err := httpClient.Get(server)
+	if err == nil || !strings.Contains(err.Error(), "x509:") {
+		return nil
+	}
+
+	certErr := err
+	if strings.Contains(err.Error(), "certificate signed by unknown authority") {
+		certErr = fmt.Errorf("Certificate chain is not complete, please check if all needed intermediate certificates are included in the server certificate (in the correct order) and if the cacerts setting in Rancher either contains the correct CA certificate (in the case of using self signed certificates) or is empty (in the case of using a certificate signed by a recognized CA). Certificate information is displayed above. error: %s", err)
+	}
+	if strings.Contains(err.Error(), "certificate has expired or is not yet valid") {
+		certErr = fmt.Errorf("Server certificate is not valid, please check if the host has the correct time configured and if the server certificate has a notAfter date and time in the future. Certificate information is displayed above. error: %s", err)
+	}
+	if strings.Contains(err.Error(), "because it doesn't contain any IP SANs") || strings.Contains(err.Error(), "certificate is not valid for any names, but wanted to match") || strings.Contains(err.Error(), "cannot validate certificate for") {
+		certErr = fmt.Errorf("Server certificate does not contain correct DNS and/or IP address entries in the Subject Alternative Names (SAN). Certificate information is displayed above. error: %s", err)
+	}
+	insecureClient := &http.Client{
+		Timeout: time.Second * 5,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	res, err := insecureClient.Get(server)
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %v", server, err)
+	}
+	var lastFoundIssuer string
+	if res.TLS != nil && len(res.TLS.PeerCertificates) > 0 {
+		logrus.Infof("Certificate details from %s", serverURL)
+		var previouscert *x509.Certificate
+		for i := range res.TLS.PeerCertificates {
+			cert := res.TLS.PeerCertificates[i]
+			logrus.Infof("Certificate #%d (%s)", i, serverURL)
+			certinfo(cert)
+			if i > 0 {
+				if previouscert.Issuer.String() != cert.Subject.String() {
+					logrus.Errorf("Certficate's Subject (%s) does not match with previous certificate Issuer (%s). Please check if the configured server certificate contains all needed intermediate certificates and make sure they are in the correct order (server certificate first, intermediates after)", cert.Subject.String(), previouscert.Issuer.String())
+				}
+			}
+			previouscert = cert
+			lastFoundIssuer = cert.Issuer.String()
+		}
+	}
+	caFileLocation := serverCAFile
+	if _, err := os.Stat(caFileLocation); err == nil {
+		caFile, err := ioutil.ReadFile(caFileLocation)
+		if err != nil {
+			return err
+		}
+		var blocks [][]byte
+		for {
+			var certDERBlock *pem.Block
+			certDERBlock, caFile = pem.Decode(caFile)
+			if certDERBlock == nil {
+				break
+			}
+
+			if certDERBlock.Type == "CERTIFICATE" {
+				blocks = append(blocks, certDERBlock.Bytes)
+			}
+		}
+		if len(blocks) > 1 {
+			logrus.Warnf("Found %d certificates at %s, should be 1", len(blocks), caFileLocation)
+		}
+		logrus.Infof("Certificate details for %s", caFileLocation)
+
+		blockcount := 0
+		var lastCACert *x509.Certificate
+		for _, block := range blocks {
+			cert, err := x509.ParseCertificate(block)
+			if err != nil {
+				logrus.Println(err)
+				continue
+			}
+
+			logrus.Infof("Certificate #%d (%s)", blockcount, caFileLocation)
+			certinfo(cert)
+
+			blockcount = blockcount + 1
+			lastCACert = cert
+		}
+		if lastFoundIssuer != lastCACert.Issuer.String() {
+			logrus.Errorf("Issuer of last certificate found in chain (%s) does not match with CA certificate Issuer (%s). Please check if the configured server certificate contains all needed intermediate certificates and make sure they are in the correct order (server certificate first, intermediates after)", lastFoundIssuer, lastCACert.Issuer.String())
+		}
+	}
+	return certErr
+}
+
+// caRotator returns the handler pinning the CA certificate Rancher rotated to for the rest of the agent's lifetime:
+// the trust stores run.sh set up for servers are updated, and the clients built against the old trust are rebuilt.
+func caRotator(servers []*url.URL) func(checksum, ca string) {
+	return func(checksum, ca string) {
+		os.Setenv("CATTLE_CA_CHECKSUM", checksum)
+		writeCA(serverCAFile, ca)
+		for _, server := range servers {
+			writeCA(filepath.Join(dockerCertsDir, server.Host, "ca.crt"), ca)
+		}
+
+		rootCAs, err := connect.SystemRootsWith(ca)
+		if err != nil {
+			logrus.Errorf("Failed to trust rotated CA certificate: %v", err)
+			return
+		}
+		rkenodeconfigclient.SetRootCAs(rootCAs)
+	}
+}
+
+// writeCA replaces the CA certificate at path, if the file exists.
+func writeCA(path, ca string) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	if err := ioutil.WriteFile(path, []byte(ca), 0600); err != nil {
+		logrus.Errorf("Failed to write rotated CA certificate to %s: %v", path, err)
 	}
 }
 
@@ -13,8 +13,10 @@
 	_ "github.com/rancher/norman/controller"
 	"github.com/rancher/norman/pkg/kwrapper/k8s"
 	"github.com/rancher/rancher/pkg/data/management"
+	"github.com/rancher/rancher/pkg/logging"
 	"github.com/rancher/rancher/pkg/logserver"
 	"github.com/rancher/rancher/pkg/rancher"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/version"
 	"github.com/rancher/wrangler/pkg/signals"
 	"github.com/sirupsen/logrus"
@@ -160,6 +162,19 @@ func main() {
 			Usage:       "Declare specific feature values on start up. Example: \"kontainer-driver=true\" - kontainer driver feature will be enabled despite false default value",
 			Destination: &config.Features,
 		},
+		cli.StringFlag{
+			Name:        "tracing-endpoint",
+			EnvVar:      "CATTLE_TRACING_ENDPOINT",
+			Usage:       "OTLP/HTTP endpoint of the OpenTelemetry collector to export traces to, e.g. http://otel-collector:4318. Tracing is disabled when empty",
+			Destination: &config.TracingEndpoint,
+		},
+		cli.Float64Flag{
+			Name:        "tracing-sample-ratio",
+			EnvVar:      "CATTLE_TRACING_SAMPLE_RATIO",
+			Value:       1,
+			Usage:       "Ratio of the traces started by Rancher that are sampled, between 0 and 1",
+			Destination: &config.TracingSampleRatio,
+		},
 	}
 
 	app.Action = func(c *cli.Context) error {
@@ -198,6 +213,10 @@ func initLogs(c *cli.Context, cfg rancher.Options) {
 		logrus.SetLevel(logrus.TraceLevel)
 		logrus.Tracef("Loglevel set to [%v]", logrus.TraceLevel)
 	}
+	logging.SetLevel(logrus.GetLevel())
+	if err := logging.SetComponentLevels(os.Getenv(settings.GetEnvKey(settings.LogLevels.Name))); err != nil {
+		logrus.Errorf("Failed to set the component log levels: %v", err)
+	}
 
 	logserver.StartServerWithDefaults()
 }
@@ -0,0 +1,49 @@
+package connect
+
+import (
+	"math"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	defaultBackoff    = 5 * time.Second
+	defaultBackoffMax = 5 * time.Minute
+)
+
+// Backoff returns the reconnect backoff of the agent: the delay starts at CATTLE_RECONNECT_BACKOFF, doubles on every
+// failed attempt up to CATTLE_RECONNECT_BACKOFF_MAX, and is jittered by up to 50% so that agents do not reconnect in
+// lockstep after a Rancher outage.
+func Backoff() wait.Backoff {
+	return NewBackoff(durationEnv("CATTLE_RECONNECT_BACKOFF", defaultBackoff),
+		durationEnv("CATTLE_RECONNECT_BACKOFF_MAX", defaultBackoffMax))
+}
+
+func NewBackoff(base, max time.Duration) wait.Backoff {
+	if max < base {
+		max = base
+	}
+	return wait.Backoff{
+		Duration: base,
+		Factor:   2,
+		Jitter:   0.5,
+		Steps:    math.MaxInt32,
+		Cap:      max,
+	}
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logrus.Warnf("Ignoring invalid %s %q, using %v", key, value, def)
+		return def
+	}
+	return d
+}
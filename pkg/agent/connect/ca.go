@@ -0,0 +1,179 @@
+package connect
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	caCertsPath     = "/v3/settings/cacerts"
+	nextCACertsPath = "/v3/settings/next-cacerts"
+)
+
+// Checksum returns the checksum of a CA certificate as Rancher computes it for CATTLE_CA_CHECKSUM.
+func Checksum(ca string) string {
+	if !strings.HasSuffix(ca, "\n") {
+		ca += "\n"
+	}
+	digest := sha256.Sum256([]byte(ca))
+	return hex.EncodeToString(digest[:])
+}
+
+// CAPinner verifies the CA a Rancher server presents against a pinned checksum. The server can announce the CA it
+// is about to rotate to with the next-cacerts setting: once the agent learnt it over a verified connection, the
+// next CA is accepted in place of the pinned one and becomes the new pin.
+type CAPinner struct {
+	lock     sync.Mutex
+	checksum string
+	next     string
+	onRotate func(checksum, ca string)
+}
+
+// NewCAPinner returns a pinner for checksum, or nil if checksum is empty. onRotate, if not nil, is called with the
+// new checksum and CA when the pin moves to the announced next CA.
+func NewCAPinner(checksum string, onRotate func(checksum, ca string)) *CAPinner {
+	if checksum == "" {
+		return nil
+	}
+	return &CAPinner{
+		checksum: checksum,
+		onRotate: onRotate,
+	}
+}
+
+// Checksum returns the currently pinned checksum.
+func (p *CAPinner) Checksum() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.checksum
+}
+
+// RootCAs fetches the CA of server and returns a pool holding it, if its checksum is the pinned or the announced
+// next one. The CA is fetched without verifying the server certificate, as the checksum is what establishes trust.
+func (p *CAPinner) RootCAs(ctx context.Context, server *url.URL) (*x509.CertPool, error) {
+	insecure := &http.Client{
+		Timeout: healthCheckTimeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	ca, err := getSetting(ctx, insecure, server, caCertsPath)
+	if err != nil {
+		return nil, err
+	}
+	if ca == "" {
+		return nil, fmt.Errorf("CA checksum is pinned but there is no CA certificate configured at %s%s", server, caCertsPath)
+	}
+
+	checksum := Checksum(ca)
+	if err := p.accept(checksum, ca); err != nil {
+		return nil, fmt.Errorf("CA certificate of %s: %w", server, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(ca)) {
+		return nil, fmt.Errorf("value from %s%s does not look like an x509 certificate", server, caCertsPath)
+	}
+	return pool, nil
+}
+
+// Announce fetches the next CA of a server the agent is connected to, so that the agent accepts it once the
+// server rotates its certificate.
+func (p *CAPinner) Announce(ctx context.Context, server Server) error {
+	next, err := getSetting(ctx, server.HTTPClient(healthCheckTimeout), server.URL, nextCACertsPath)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var checksum string
+	if next != "" {
+		checksum = Checksum(next)
+	}
+	if checksum == p.checksum {
+		checksum = ""
+	}
+	if checksum != p.next {
+		if checksum == "" {
+			logrus.Infof("Rancher server %s withdrew its next CA certificate", server.URL)
+		} else {
+			logrus.Infof("Rancher server %s announced next CA certificate with checksum %s", server.URL, checksum)
+		}
+		p.next = checksum
+	}
+	return nil
+}
+
+// SystemRootsWith returns the system roots with ca added. The system roots are loaded once per process, so clients
+// built before the CA was written to the trust store only trust it when rebuilt with this pool.
+func SystemRootsWith(ca string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(ca)) {
+		return nil, fmt.Errorf("CA certificate does not look like an x509 certificate")
+	}
+	return pool, nil
+}
+
+func (p *CAPinner) accept(checksum, ca string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch checksum {
+	case p.checksum:
+		return nil
+	case p.next:
+		logrus.Infof("Rancher CA certificate rotated, pinning checksum %s in place of %s", checksum, p.checksum)
+		p.checksum, p.next = checksum, ""
+		if p.onRotate != nil {
+			p.onRotate(checksum, ca)
+		}
+		return nil
+	}
+	return fmt.Errorf("checksum %s does not match the pinned checksum %s", checksum, p.checksum)
+}
+
+func getSetting(ctx context.Context, client *http.Client, server *url.URL, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.String()+path, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("getting %s%s returned %s", server, path, resp.Status)
+	}
+
+	var setting struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&setting); err != nil {
+		return "", fmt.Errorf("decoding %s%s: %w", server, path, err)
+	}
+	return setting.Value, nil
+}
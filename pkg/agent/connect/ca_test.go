@@ -0,0 +1,110 @@
+package connect
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	assert.Equal(t, Checksum("ca\n"), Checksum("ca"))
+	assert.NotEqual(t, Checksum("ca"), Checksum("other"))
+}
+
+func TestCAPinner(t *testing.T) {
+	assert.Nil(t, NewCAPinner("", nil))
+
+	server := newFakeRancher(t)
+	currentCA := server.caCerts
+
+	var rotated string
+	pinner := NewCAPinner(Checksum("old CA"), func(checksum, ca string) {
+		rotated = checksum
+	})
+
+	// the server presents a CA that is neither pinned nor announced
+	_, err := pinner.RootCAs(context.Background(), server.URL)
+	assert.Error(t, err)
+	assert.Empty(t, rotated)
+
+	// a server trusted under the old pin announces the CA it rotates to
+	server.nextCACerts = currentCA
+	require.NoError(t, pinner.Announce(context.Background(), Server{URL: server.URL, RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}))
+
+	pool, err := pinner.RootCAs(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.NotNil(t, pool)
+	assert.Equal(t, Checksum(currentCA), rotated)
+	assert.Equal(t, Checksum(currentCA), pinner.Checksum())
+
+	// the pin stays on the new CA
+	_, err = pinner.RootCAs(context.Background(), server.URL)
+	assert.NoError(t, err)
+}
+
+// selfSignedCert returns a certificate for 127.0.0.1 that is its own CA.
+func selfSignedCert(t *testing.T) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "rotated-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestCAHandoff(t *testing.T) {
+	old := newFakeRancher(t)
+	rotated := newFakeRancherWithCert(t, selfSignedCert(t))
+	require.NotEqual(t, old.caCerts, rotated.caCerts)
+
+	// the agent rebuilds its clients with the CA Rancher rotated to, as the agent does on rotation
+	var rootCAs *x509.CertPool
+	pinner := NewCAPinner(Checksum(old.caCerts), func(checksum, ca string) {
+		pool, err := SystemRootsWith(ca)
+		require.NoError(t, err)
+		rootCAs = pool
+	})
+
+	// Rancher announces the next CA before rotating to it
+	old.nextCACerts = rotated.caCerts
+	require.NoError(t, pinner.Announce(context.Background(), Server{URL: old.URL, RootCAs: old.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}))
+
+	// after the rotation the server presents the new CA, which the agent takes over
+	_, err := pinner.RootCAs(context.Background(), rotated.URL)
+	require.NoError(t, err)
+	assert.Equal(t, Checksum(rotated.caCerts), pinner.Checksum())
+	require.NotNil(t, rootCAs)
+
+	_, err = (&http.Client{}).Get(rotated.Server.URL + "/ping")
+	assert.Error(t, err, "clients built before the rotation do not trust the new CA")
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs}}}
+	resp, err := client.Get(rotated.Server.URL + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = SystemRootsWith("not a certificate")
+	assert.Error(t, err)
+}
@@ -0,0 +1,12 @@
+package connect
+
+// ProbePath is the path of the tunnel probe of the Rancher server. The probe checks the token of an agent and whether
+// the websocket upgrade headers of its request reached the server, without registering the agent or opening a tunnel
+// session.
+const ProbePath = "/v3/connect/probe"
+
+// ProbeResult is the response of the tunnel probe to a valid token.
+type ProbeResult struct {
+	// WebsocketUpgrade is whether the websocket upgrade headers of the probe reached the server.
+	WebsocketUpgrade bool `json:"websocketUpgrade"`
+}
@@ -0,0 +1,148 @@
+package connect
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rancher/remotedialer"
+	"github.com/sirupsen/logrus"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// Server is a Rancher server the agent can connect to, with the CA pool its certificate is verified against.
+type Server struct {
+	URL *url.URL
+	// RootCAs is nil when the certificate of the server is verified against the system roots.
+	RootCAs *x509.CertPool
+}
+
+// HTTPClient returns a client for requests to the server.
+func (s Server) HTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: s.tlsConfig(),
+		},
+	}
+}
+
+// Dialer returns the websocket dialer for the tunnel to the server.
+func (s Server) Dialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: remotedialer.HandshakeTimeOut,
+		TLSClientConfig:  s.tlsConfig(),
+	}
+}
+
+func (s Server) tlsConfig() *tls.Config {
+	if s.RootCAs == nil {
+		return nil
+	}
+	return &tls.Config{RootCAs: s.RootCAs}
+}
+
+// ParseServers returns the ordered list of server URLs from the primary server URL and a comma separated list of
+// failover URLs, dropping duplicates.
+func ParseServers(primary, failover string) ([]*url.URL, error) {
+	var (
+		result []*url.URL
+		seen   = map[string]bool{}
+	)
+	for _, server := range append([]string{primary}, strings.Split(failover, ",")...) {
+		server = strings.TrimSuffix(strings.TrimSpace(server), "/")
+		if server == "" || seen[server] {
+			continue
+		}
+		seen[server] = true
+
+		u, err := url.Parse(server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL %s: %w", server, err)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("invalid server URL %s: must be an https URL", server)
+		}
+		result = append(result, u)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no server URL configured")
+	}
+	return result, nil
+}
+
+// Selector picks the server the agent connects to. The servers are health checked in order and the first healthy
+// one is used, so that the agent fails back to a preferred server once it recovers.
+type Selector struct {
+	lock    sync.Mutex
+	servers []*url.URL
+	pinner  *CAPinner
+	next    int
+}
+
+// NewSelector returns a selector for servers. If pinner is not nil, the certificates of the servers are verified
+// against the CA it pins rather than the system roots.
+func NewSelector(servers []*url.URL, pinner *CAPinner) *Selector {
+	return &Selector{
+		servers: servers,
+		pinner:  pinner,
+	}
+}
+
+// Select returns the first healthy server. If none is healthy, the servers are returned in turn along with the
+// error of the last health check, so that the agent keeps trying all of them.
+func (s *Selector) Select(ctx context.Context) (Server, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var lastErr error
+	for _, u := range s.servers {
+		server, err := s.check(ctx, u)
+		if err == nil {
+			return server, nil
+		}
+		logrus.Warnf("Rancher server %s is unhealthy: %v", u, err)
+		lastErr = err
+	}
+
+	u := s.servers[s.next%len(s.servers)]
+	s.next++
+	return Server{URL: u}, lastErr
+}
+
+func (s *Selector) check(ctx context.Context, u *url.URL) (Server, error) {
+	server := Server{URL: u}
+	if s.pinner != nil {
+		rootCAs, err := s.pinner.RootCAs(ctx, u)
+		if err != nil {
+			return server, err
+		}
+		server.RootCAs = rootCAs
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String()+"/ping", nil)
+	if err != nil {
+		return server, err
+	}
+	resp, err := server.HTTPClient(healthCheckTimeout).Do(req)
+	if err != nil {
+		return server, err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return server, fmt.Errorf("ping returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return server, nil
+}
@@ -0,0 +1,117 @@
+package connect
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServers(t *testing.T) {
+	servers, err := ParseServers("https://rancher.example.com/", " https://eu.rancher.example.com, https://rancher.example.com,,https://us.rancher.example.com")
+	require.NoError(t, err)
+	var result []string
+	for _, server := range servers {
+		result = append(result, server.String())
+	}
+	assert.Equal(t, []string{
+		"https://rancher.example.com",
+		"https://eu.rancher.example.com",
+		"https://us.rancher.example.com",
+	}, result)
+
+	_, err = ParseServers("http://rancher.example.com", "")
+	assert.Error(t, err)
+
+	_, err = ParseServers("", "")
+	assert.Error(t, err)
+}
+
+type fakeRancher struct {
+	*httptest.Server
+	URL         *url.URL
+	healthy     bool
+	caCerts     string
+	nextCACerts string
+}
+
+// newFakeRancher returns a Rancher server serving its own certificate as cacerts. All httptest servers share the
+// same certificate.
+func newFakeRancher(t *testing.T) *fakeRancher {
+	return newFakeRancherWithCert(t, nil)
+}
+
+// newFakeRancherWithCert returns a Rancher server serving its own certificate as cacerts, using certificate rather
+// than the httptest one if it is not nil.
+func newFakeRancherWithCert(t *testing.T, certificate *tls.Certificate) *fakeRancher {
+	f := &fakeRancher{healthy: true}
+	f.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/ping":
+			if !f.healthy {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			rw.Write([]byte("pong"))
+		case caCertsPath:
+			json.NewEncoder(rw).Encode(map[string]string{"value": f.caCerts})
+		case nextCACertsPath:
+			json.NewEncoder(rw).Encode(map[string]string{"value": f.nextCACerts})
+		default:
+			http.NotFound(rw, req)
+		}
+	}))
+	if certificate != nil {
+		f.Server.TLS = &tls.Config{Certificates: []tls.Certificate{*certificate}}
+	}
+	f.Server.StartTLS()
+	t.Cleanup(f.Server.Close)
+	f.caCerts = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.Server.Certificate().Raw}))
+	f.URL, _ = url.Parse(f.Server.URL)
+	return f
+}
+
+func TestSelector(t *testing.T) {
+	primary, secondary := newFakeRancher(t), newFakeRancher(t)
+	selector := NewSelector([]*url.URL{primary.URL, secondary.URL}, NewCAPinner(Checksum(primary.caCerts), nil))
+	selected := func() (string, bool) {
+		server, err := selector.Select(context.Background())
+		return server.URL.Host, err == nil
+	}
+
+	host, ok := selected()
+	assert.True(t, ok)
+	assert.Equal(t, primary.URL.Host, host)
+
+	primary.healthy = false
+	host, ok = selected()
+	assert.True(t, ok)
+	assert.Equal(t, secondary.URL.Host, host)
+
+	primary.healthy = true
+	host, _ = selected()
+	assert.Equal(t, primary.URL.Host, host, "fails back to the primary server")
+
+	primary.healthy, secondary.healthy = false, false
+	host, ok = selected()
+	assert.False(t, ok)
+	assert.Equal(t, primary.URL.Host, host)
+	host, _ = selected()
+	assert.Equal(t, secondary.URL.Host, host, "unhealthy servers are tried in turn")
+}
+
+func TestSelectorSystemRoots(t *testing.T) {
+	server := newFakeRancher(t)
+	selector := NewSelector([]*url.URL{server.URL}, nil)
+
+	// httptest certificates are not trusted by the system roots
+	_, err := selector.Select(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rancher/remotedialer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SteeringHeader is sent by the agents able to reconnect to the Rancher server owning their cluster. Its value is
+	// the number of times in a row the agent was steered, which Rancher bounds by tunnel-steering-max-attempts.
+	SteeringHeader = "X-Cattle-Tunnel-Steering"
+	// OwnerHeader holds the peer ID of the Rancher server owning the cluster of a steered agent.
+	OwnerHeader = "X-Cattle-Tunnel-Owner"
+
+	steeringDelay = 500 * time.Millisecond
+)
+
+// SteeredError is returned by ConnectToProxy when the Rancher server asked the agent to reconnect, as another Rancher
+// server owns the controllers of its cluster. Rancher stops steering the agent once the value of SteeringHeader reaches
+// tunnel-steering-max-attempts, so the agent does not bound the reconnects itself.
+type SteeredError struct {
+	Owner string
+}
+
+func (e *SteeredError) Error() string {
+	return fmt.Sprintf("steered to Rancher server %s", e.Owner)
+}
+
+// SteeringDelay returns the jittered delay before reconnecting after being steered. The agent reconnects through the
+// load balancer, so the delay is short and does not back off.
+func SteeringDelay() time.Duration {
+	return steeringDelay/2 + time.Duration(rand.Int63n(int64(steeringDelay)))
+}
+
+// ConnectToProxy connects the tunnel to the server and serves it like remotedialer.ConnectToProxy, except that it
+// returns a *SteeredError when the server steers the agent to another Rancher server.
+func ConnectToProxy(rootCtx context.Context, proxyURL string, headers http.Header, auth remotedialer.ConnectAuthorizer, dialer *websocket.Dialer, onConnect func(context.Context, *remotedialer.Session) error) error {
+	logrus.WithField("url", proxyURL).Info("Connecting to proxy")
+
+	if dialer == nil {
+		dialer = &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: remotedialer.HandshakeTimeOut}
+	}
+	ws, resp, err := dialer.DialContext(rootCtx, proxyURL, headers)
+	if err != nil {
+		if resp == nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusMisdirectedRequest {
+			return &SteeredError{Owner: resp.Header.Get(OwnerHeader)}
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%w: response status %s: %s", err, resp.Status, body)
+	}
+	defer ws.Close()
+
+	result := make(chan error, 2)
+
+	ctx, cancel := context.WithCancel(rootCtx)
+	defer cancel()
+
+	session := remotedialer.NewClientSession(auth, ws)
+	defer session.Close()
+
+	if onConnect != nil {
+		go func() {
+			if err := onConnect(ctx, session); err != nil {
+				result <- err
+			}
+		}()
+	}
+
+	go func() {
+		_, err := session.Serve(ctx)
+		result <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		logrus.WithField("url", proxyURL).WithField("err", ctx.Err()).Info("Proxy done")
+		return nil
+	case err := <-result:
+		return err
+	}
+}
@@ -0,0 +1,50 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectToProxySteered(t *testing.T) {
+	var steering string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		steering = req.Header.Get(SteeringHeader)
+		if req.Header.Get("X-API-Tunnel-Token") == "" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			rw.Write([]byte("failed authentication"))
+			return
+		}
+		rw.Header().Set(OwnerHeader, "10.0.0.2")
+		rw.WriteHeader(http.StatusMisdirectedRequest)
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/v3/connect"
+	headers := http.Header{"X-API-Tunnel-Token": {"token"}, SteeringHeader: {"2"}}
+	err := ConnectToProxy(context.Background(), wsURL, headers, nil, &websocket.Dialer{}, nil)
+	var steerErr *SteeredError
+	require.True(t, errors.As(err, &steerErr))
+	assert.Equal(t, "10.0.0.2", steerErr.Owner)
+	assert.Equal(t, "2", steering)
+
+	err = ConnectToProxy(context.Background(), wsURL, http.Header{}, nil, nil, nil)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &steerErr))
+	assert.Contains(t, err.Error(), "failed authentication")
+}
+
+func TestSteeringDelay(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		delay := SteeringDelay()
+		assert.GreaterOrEqual(t, delay, steeringDelay/2)
+		assert.Less(t, delay, steeringDelay*3/2)
+	}
+}
@@ -0,0 +1,407 @@
+/*
+Package diagnose runs the connectivity checklist of the agent against the Rancher servers it is configured for and
+produces a machine-readable report:
+
+DNS resolution of the server host, TCP reachability of its port, the proxy the agent would go through, the TLS
+certificate chain and its SANs validated against the cacerts of the server, the validity of the agent token and the
+websocket handshake of the tunnel. The token and the handshake are checked against the tunnel probe of the server,
+which neither registers the agent nor opens a tunnel session.
+*/
+package diagnose
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/agent/connect"
+)
+
+const (
+	timeout = 5 * time.Second
+	// websocketKey is the Sec-WebSocket-Key of the handshake, any base64 encoded 16 bytes do
+	websocketKey = "ZGlhZ25vc2UtcHJvYmUtMQ=="
+)
+
+// Check statuses. A check is skipped when a check it depends on failed.
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Options configures the checks.
+type Options struct {
+	Servers []*url.URL
+	// Headers are the headers the agent authenticates with. Only its token is needed, the parameters of the agent
+	// register it.
+	Headers http.Header
+	// CAChecksum is the pinned checksum of the Rancher CA, if any.
+	CAChecksum string
+}
+
+// Report is the result of the checks.
+type Report struct {
+	Time    time.Time      `json:"time"`
+	Version string         `json:"version"`
+	Passed  bool           `json:"passed"`
+	Proxy   ProxyEnv       `json:"proxy"`
+	Servers []ServerReport `json:"servers"`
+}
+
+// ProxyEnv are the proxy environment variables of the agent.
+type ProxyEnv struct {
+	HTTPProxy  string `json:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`
+}
+
+type ServerReport struct {
+	URL    string  `json:"url"`
+	Passed bool    `json:"passed"`
+	Checks []Check `json:"checks"`
+}
+
+type Check struct {
+	Name     string                 `json:"name"`
+	Status   string                 `json:"status"`
+	Message  string                 `json:"message,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+	Duration string                 `json:"duration,omitempty"`
+}
+
+// Run runs the checks against every server.
+func Run(ctx context.Context, version string, opts Options) Report {
+	report := Report{
+		Time:    time.Now().UTC(),
+		Version: version,
+		Passed:  true,
+		Proxy: ProxyEnv{
+			HTTPProxy:  getenv("HTTP_PROXY"),
+			HTTPSProxy: getenv("HTTPS_PROXY"),
+			NoProxy:    getenv("NO_PROXY"),
+		},
+	}
+	for _, server := range opts.Servers {
+		serverReport := (&diagnosis{opts: opts, server: server}).run(ctx)
+		report.Passed = report.Passed && serverReport.Passed
+		report.Servers = append(report.Servers, serverReport)
+	}
+	return report
+}
+
+// Write prints the report as indented JSON.
+func (r Report) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func getenv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return os.Getenv(strings.ToLower(key))
+}
+
+// diagnosis holds the state of the checks of one server, as each check builds on the previous ones.
+type diagnosis struct {
+	opts   Options
+	server *url.URL
+	report ServerReport
+
+	proxied bool
+	rootCAs *x509.CertPool
+	tlsOK   bool
+}
+
+func (d *diagnosis) run(ctx context.Context) ServerReport {
+	d.report = ServerReport{
+		URL:    d.server.String(),
+		Passed: true,
+	}
+
+	dnsOK := d.check("dns", func() (string, string, map[string]interface{}) { return d.dns(ctx) })
+	d.check("proxy", d.proxy)
+	tcpOK := false
+	if dnsOK || d.proxied {
+		tcpOK = d.check("tcp", func() (string, string, map[string]interface{}) { return d.tcp(ctx) })
+	} else {
+		d.skip("tcp", "dns")
+	}
+	if tcpOK || d.proxied {
+		d.tlsOK = d.check("tls", func() (string, string, map[string]interface{}) { return d.tls(ctx) })
+	} else {
+		d.skip("tls", "tcp")
+	}
+	tokenOK := false
+	if d.tlsOK {
+		tokenOK = d.check("token", func() (string, string, map[string]interface{}) { return d.token(ctx) })
+	} else {
+		d.skip("token", "tls")
+	}
+	if tokenOK {
+		d.check("websocket", func() (string, string, map[string]interface{}) { return d.websocket(ctx) })
+	} else {
+		d.skip("websocket", "token")
+	}
+	return d.report
+}
+
+// check runs a check and records its result. It returns false if the check failed.
+func (d *diagnosis) check(name string, f func() (string, string, map[string]interface{})) bool {
+	start := time.Now()
+	status, message, details := f()
+	d.report.Checks = append(d.report.Checks, Check{
+		Name:     name,
+		Status:   status,
+		Message:  message,
+		Details:  details,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	})
+	if status == StatusFail {
+		d.report.Passed = false
+		return false
+	}
+	return true
+}
+
+func (d *diagnosis) skip(name, dependency string) {
+	d.report.Passed = false
+	d.report.Checks = append(d.report.Checks, Check{
+		Name:    name,
+		Status:  StatusSkip,
+		Message: fmt.Sprintf("skipped as the %s check failed", dependency),
+	})
+}
+
+func (d *diagnosis) dns(ctx context.Context) (string, string, map[string]interface{}) {
+	addresses, err := net.DefaultResolver.LookupHost(ctx, d.server.Hostname())
+	if err != nil {
+		return StatusFail, err.Error(), nil
+	}
+	return StatusPass, fmt.Sprintf("%s resolves to %s", d.server.Hostname(), strings.Join(addresses, ", ")),
+		map[string]interface{}{"addresses": addresses}
+}
+
+func (d *diagnosis) proxy() (string, string, map[string]interface{}) {
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: d.server})
+	if err != nil {
+		return StatusFail, fmt.Sprintf("invalid proxy configuration: %v", err), nil
+	}
+	if proxyURL == nil {
+		return StatusPass, "connecting directly", nil
+	}
+	d.proxied = true
+	return StatusPass, fmt.Sprintf("connecting through proxy %s", proxyURL.Redacted()),
+		map[string]interface{}{"proxy": proxyURL.Redacted()}
+}
+
+func (d *diagnosis) tcp(ctx context.Context) (string, string, map[string]interface{}) {
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", hostPort(d.server))
+	if err != nil {
+		if d.proxied {
+			// the agent goes through the proxy, the server may well not be reachable directly
+			return StatusWarn, fmt.Sprintf("not reachable directly: %v", err), nil
+		}
+		return StatusFail, err.Error(), nil
+	}
+	defer conn.Close()
+	return StatusPass, fmt.Sprintf("connected to %s", conn.RemoteAddr()),
+		map[string]interface{}{"remoteAddress": conn.RemoteAddr().String()}
+}
+
+func (d *diagnosis) tls(ctx context.Context) (string, string, map[string]interface{}) {
+	insecure := connect.Server{URL: d.server}.HTTPClient(timeout)
+	insecure.Transport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+
+	resp, err := get(ctx, insecure, d.server.String()+"/v3/settings/cacerts", nil)
+	if err != nil {
+		return StatusFail, err.Error(), nil
+	}
+	defer resp.Body.Close()
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return StatusFail, "server did not present a certificate", nil
+	}
+
+	var caCerts string
+	if resp.StatusCode == http.StatusOK {
+		var setting struct {
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&setting); err == nil {
+			caCerts = setting.Value
+		}
+	}
+
+	certs := resp.TLS.PeerCertificates
+	details := map[string]interface{}{
+		"certificates": certificateInfos(certs),
+	}
+	var warnings []string
+	for i := 1; i < len(certs); i++ {
+		if certs[i-1].Issuer.String() != certs[i].Subject.String() {
+			warnings = append(warnings, fmt.Sprintf("certificate #%d (%s) is not the issuer of certificate #%d (%s): check the intermediate certificates are in the correct order",
+				i, certs[i].Subject, i-1, certs[i-1].Issuer))
+		}
+	}
+
+	verifyOpts := x509.VerifyOptions{
+		DNSName:       d.server.Hostname(),
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		verifyOpts.Intermediates.AddCert(cert)
+	}
+	if caCerts != "" {
+		details["caChecksum"] = connect.Checksum(caCerts)
+		if d.opts.CAChecksum != "" && connect.Checksum(caCerts) != d.opts.CAChecksum {
+			return StatusFail, fmt.Sprintf("checksum %s of the cacerts setting does not match the pinned checksum %s",
+				connect.Checksum(caCerts), d.opts.CAChecksum), details
+		}
+		verifyOpts.Roots = x509.NewCertPool()
+		if !verifyOpts.Roots.AppendCertsFromPEM([]byte(caCerts)) {
+			return StatusFail, "the cacerts setting does not hold an x509 certificate", details
+		}
+		d.rootCAs = verifyOpts.Roots
+	} else if d.opts.CAChecksum != "" {
+		return StatusFail, "a CA checksum is pinned but the server has no cacerts setting", details
+	}
+
+	if _, err := certs[0].Verify(verifyOpts); err != nil {
+		return StatusFail, certificateError(err), details
+	}
+	if len(warnings) > 0 {
+		details["warnings"] = warnings
+		return StatusWarn, strings.Join(warnings, "; "), details
+	}
+	if caCerts != "" {
+		return StatusPass, "certificate chain verified against the cacerts setting", details
+	}
+	return StatusPass, "certificate chain verified against the system trust store", details
+}
+
+func (d *diagnosis) token(ctx context.Context) (string, string, map[string]interface{}) {
+	resp, err := get(ctx, d.client(), d.server.String()+connect.ProbePath, d.opts.Headers)
+	if err != nil {
+		return StatusFail, err.Error(), nil
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return StatusPass, "the token is valid", nil
+	case http.StatusUnauthorized:
+		return StatusFail, "the token is not valid for this server", nil
+	case http.StatusNotFound:
+		return StatusWarn, "the server has no tunnel probe to check the token with", nil
+	default:
+		return StatusFail, fmt.Sprintf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(body))), nil
+	}
+}
+
+// websocket checks that the headers of a websocket handshake reach the server. The handshake is sent to the tunnel
+// probe, which reports whether it found the upgrade headers rather than upgrading the connection.
+func (d *diagnosis) websocket(ctx context.Context) (string, string, map[string]interface{}) {
+	client := d.client()
+	// websockets are upgraded from HTTP/1.1 requests
+	client.Transport.(*http.Transport).TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+
+	headers := http.Header{}
+	for k, v := range d.opts.Headers {
+		headers[k] = v
+	}
+	headers.Set("Connection", "Upgrade")
+	headers.Set("Upgrade", "websocket")
+	headers.Set("Sec-WebSocket-Version", "13")
+	headers.Set("Sec-WebSocket-Key", websocketKey)
+
+	resp, err := get(ctx, client, d.server.String()+connect.ProbePath, headers)
+	if err != nil {
+		return StatusFail, err.Error(), nil
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return StatusWarn, "the server has no tunnel probe to check the websocket handshake with", nil
+	default:
+		return StatusFail, fmt.Sprintf("unexpected response %s to the websocket handshake", resp.Status), nil
+	}
+	var result connect.ProbeResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return StatusFail, fmt.Sprintf("invalid response to the websocket handshake: %v", err), nil
+	}
+	if !result.WebsocketUpgrade {
+		return StatusFail, "the websocket upgrade headers do not reach the server: check that the proxies and load balancers in front of it pass the Connection and Upgrade headers", nil
+	}
+	return StatusPass, "the websocket upgrade headers reach the server", nil
+}
+
+func (d *diagnosis) client() *http.Client {
+	return connect.Server{URL: d.server, RootCAs: d.rootCAs}.HTTPClient(timeout)
+}
+
+func get(ctx context.Context, client *http.Client, u string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	return client.Do(req)
+}
+
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+func certificateInfos(certs []*x509.Certificate) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, cert := range certs {
+		var ips []string
+		for _, ip := range cert.IPAddresses {
+			ips = append(ips, ip.String())
+		}
+		result = append(result, map[string]interface{}{
+			"subject":     cert.Subject.String(),
+			"issuer":      cert.Issuer.String(),
+			"isCA":        cert.IsCA,
+			"dnsNames":    cert.DNSNames,
+			"ipAddresses": ips,
+			"notBefore":   cert.NotBefore.UTC(),
+			"notAfter":    cert.NotAfter.UTC(),
+		})
+	}
+	return result
+}
+
+// certificateError explains the common certificate verification failures.
+func certificateError(err error) string {
+	switch err.(type) {
+	case x509.UnknownAuthorityError:
+		return fmt.Sprintf("certificate chain is not complete or not signed by the configured CA: check all intermediate certificates are included in the server certificate and the cacerts setting holds the CA (or is empty for a certificate signed by a recognized CA): %v", err)
+	case x509.CertificateInvalidError:
+		return fmt.Sprintf("server certificate is not valid, check the host time and the notAfter date of the certificate: %v", err)
+	case x509.HostnameError:
+		return fmt.Sprintf("server certificate does not contain the server host in its Subject Alternative Names: %v", err)
+	}
+	return err.Error()
+}
@@ -0,0 +1,147 @@
+package diagnose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/rancher/rancher/pkg/agent/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenHeader = "X-API-Tunnel-Token"
+
+// newServer returns a Rancher server with a tunnel probe, serving its own certificate as cacerts. It counts the
+// requests to the tunnel itself.
+func newServer(t *testing.T, connects *int32) *httptest.Server {
+	var caCerts string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v3/settings/cacerts":
+			json.NewEncoder(rw).Encode(map[string]string{"value": caCerts})
+		case connect.ProbePath:
+			if req.Header.Get(tokenHeader) != "valid" {
+				http.Error(rw, "token is not valid", http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(rw).Encode(connect.ProbeResult{WebsocketUpgrade: websocket.IsWebSocketUpgrade(req)})
+		case "/v3/connect", "/v3/connect/register":
+			atomic.AddInt32(connects, 1)
+			http.Error(rw, "unexpected connection", http.StatusBadRequest)
+		default:
+			http.NotFound(rw, req)
+		}
+	}))
+	t.Cleanup(server.Close)
+	caCerts = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	return server
+}
+
+func statuses(report ServerReport) map[string]string {
+	result := map[string]string{}
+	for _, check := range report.Checks {
+		result[check.Name] = check.Status
+	}
+	return result
+}
+
+func TestRun(t *testing.T) {
+	var connects int32
+	server := newServer(t, &connects)
+	serverURL, _ := url.Parse(server.URL)
+
+	report := Run(context.Background(), "v2.6.0", Options{
+		Servers: []*url.URL{serverURL},
+		Headers: http.Header{tokenHeader: {"valid"}},
+	})
+	require.Len(t, report.Servers, 1)
+	assert.Equal(t, map[string]string{
+		"dns":       StatusPass,
+		"proxy":     StatusPass,
+		"tcp":       StatusPass,
+		"tls":       StatusPass,
+		"token":     StatusPass,
+		"websocket": StatusPass,
+	}, statuses(report.Servers[0]))
+	assert.True(t, report.Passed)
+	assert.Zero(t, atomic.LoadInt32(&connects), "the agent does not connect to the tunnel")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.Write(buf))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "v2.6.0", decoded.Version)
+}
+
+func TestRunInvalidToken(t *testing.T) {
+	server := newServer(t, new(int32))
+	serverURL, _ := url.Parse(server.URL)
+
+	report := Run(context.Background(), "dev", Options{
+		Servers: []*url.URL{serverURL},
+		Headers: http.Header{tokenHeader: {"invalid"}},
+	})
+	result := statuses(report.Servers[0])
+	assert.Equal(t, StatusPass, result["tls"])
+	assert.Equal(t, StatusFail, result["token"])
+	assert.Equal(t, StatusSkip, result["websocket"])
+	assert.False(t, report.Passed)
+}
+
+func TestRunChecksumMismatch(t *testing.T) {
+	server := newServer(t, new(int32))
+	serverURL, _ := url.Parse(server.URL)
+
+	report := Run(context.Background(), "dev", Options{
+		Servers:    []*url.URL{serverURL},
+		Headers:    http.Header{tokenHeader: {"valid"}},
+		CAChecksum: connect.Checksum("another CA"),
+	})
+	result := statuses(report.Servers[0])
+	assert.Equal(t, StatusFail, result["tls"])
+	assert.Equal(t, StatusSkip, result["token"])
+	assert.False(t, report.Passed)
+}
+
+func TestRunUnreachable(t *testing.T) {
+	server := newServer(t, new(int32))
+	serverURL, _ := url.Parse(server.URL)
+	server.Close()
+
+	report := Run(context.Background(), "dev", Options{
+		Servers: []*url.URL{serverURL},
+	})
+	result := statuses(report.Servers[0])
+	assert.Equal(t, StatusPass, result["dns"])
+	assert.Equal(t, StatusFail, result["tcp"])
+	assert.Equal(t, StatusSkip, result["tls"])
+}
+
+func TestRunUpgradeHeadersDropped(t *testing.T) {
+	server := newServer(t, new(int32))
+	// a proxy in front of the server that does not pass the upgrade headers
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		req.Header.Del("Connection")
+		req.Header.Del("Upgrade")
+		handler.ServeHTTP(rw, req)
+	})
+	serverURL, _ := url.Parse(server.URL)
+
+	report := Run(context.Background(), "dev", Options{
+		Servers: []*url.URL{serverURL},
+		Headers: http.Header{tokenHeader: {"valid"}},
+	})
+	result := statuses(report.Servers[0])
+	assert.Equal(t, StatusPass, result["token"])
+	assert.Equal(t, StatusFail, result["websocket"])
+	assert.False(t, report.Passed)
+}
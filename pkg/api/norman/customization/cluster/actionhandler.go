@@ -12,6 +12,7 @@
 	"github.com/rancher/rancher/pkg/catalog/manager"
 	mgmtclient "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/rancher/rancher/pkg/clustermanager"
+	corev1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/user"
 	v1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
@@ -39,6 +40,10 @@ type ActionHandler struct {
 	CisConfigLister               v3.CisConfigLister
 	TokenClient                   v3.TokenInterface
 	Auth                          requests.Authenticator
+	RKESystemImagesLister         v3.RkeK8sSystemImageLister
+	RKESystemImages               v3.RkeK8sSystemImageInterface
+	ConfigMapLister               corev1.ConfigMapLister
+	SecretLister                  corev1.SecretLister
 }
 
 func canUpdateCluster(apiContext *types.APIContext) bool {
@@ -131,6 +136,11 @@ func (a ActionHandler) ClusterActionHandler(actionName string, action *types.Act
 			return httperror.NewAPIError(httperror.PermissionDenied, "can not save the cluster as an RKETemplate")
 		}
 		return a.saveAsTemplate(actionName, action, apiContext)
+	case v32.ClusterActionCheckImages:
+		if !canUpdateCluster(apiContext) {
+			return httperror.NewAPIError(httperror.PermissionDenied, "can not check the images of the cluster")
+		}
+		return a.CheckImages(actionName, action, apiContext)
 	}
 	return httperror.NewAPIError(httperror.NotFound, "not found")
 }
@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/catalog/utils"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	util "github.com/rancher/rancher/pkg/cluster"
+	kd "github.com/rancher/rancher/pkg/controllers/management/kontainerdrivermetadata"
+	"github.com/rancher/rancher/pkg/image"
+	"github.com/rancher/rancher/pkg/image/airgap"
+	"github.com/rancher/rancher/pkg/namespace"
+	rketypes "github.com/rancher/rke/types"
+	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckImages computes the images the cluster needs for its current or a target Kubernetes version and reports
+// which of them are missing from the registry the cluster pulls from, so air-gapped clusters can be checked before
+// an upgrade.
+func (a ActionHandler) CheckImages(actionName string, action *types.Action, apiContext *types.APIContext) error {
+	cluster, err := a.ClusterClient.Get(apiContext.ID, metav1.GetOptions{})
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.NotFound, fmt.Sprintf("failed to get cluster %s", apiContext.ID))
+	}
+
+	input := client.CheckImagesInput{}
+	data, err := ioutil.ReadAll(apiContext.Request.Body)
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.InvalidBodyContent, "failed to read request body")
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &input); err != nil {
+			return httperror.WrapAPIError(err, httperror.InvalidBodyContent, "failed to parse request content")
+		}
+	}
+
+	k8sVersion := input.KubernetesVersion
+	if k8sVersion == "" {
+		if cluster.Spec.RancherKubernetesEngineConfig != nil {
+			k8sVersion = cluster.Spec.RancherKubernetesEngineConfig.Version
+		} else if cluster.Status.Version != nil {
+			k8sVersion = cluster.Status.Version.GitVersion
+		}
+	}
+
+	var rkeSystemImages *rketypes.RKESystemImages
+	if cluster.Spec.RancherKubernetesEngineConfig != nil {
+		systemImages, err := kd.GetRKESystemImages(k8sVersion, a.RKESystemImagesLister, a.RKESystemImages)
+		if err != nil {
+			return httperror.WrapAPIError(err, httperror.InvalidBodyContent, fmt.Sprintf("failed to get system images of Kubernetes version %s", k8sVersion))
+		}
+		rkeSystemImages = &systemImages
+	}
+
+	appValues, err := a.clusterAppValues(cluster.Name)
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.ClusterUnavailable, "failed to list apps installed in the cluster")
+	}
+
+	imageList, err := a.ConfigMapLister.Get(namespace.System, utils.GetCatalogImageCacheName(utils.SystemLibraryName))
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to get image list for system catalog")
+	}
+	windowsChartImages, linuxChartImages := image.ParseCatalogImageListConfigMap(imageList)
+
+	chartImages := map[image.OSType][]string{image.Linux: linuxChartImages, image.Windows: windowsChartImages}
+	sources := map[string]map[string]struct{}{}
+	var images []string
+	for _, osType := range []image.OSType{image.Linux, image.Windows} {
+		if osType == image.Windows && !cluster.Spec.WindowsPreferedCluster {
+			continue
+		}
+		clusterImages, err := image.GetClusterImages(image.ClusterImageConfig{
+			Cluster:           cluster,
+			OsType:            osType,
+			KubernetesVersion: k8sVersion,
+			RKESystemImages:   rkeSystemImages,
+			SystemChartImages: chartImages[osType],
+			AppValues:         appValues,
+		})
+		if err != nil {
+			return httperror.WrapAPIError(err, httperror.ServerError, "failed to compute cluster images")
+		}
+		for _, clusterImage := range clusterImages {
+			if _, ok := sources[clusterImage.Image]; !ok {
+				images = append(images, clusterImage.Image)
+				sources[clusterImage.Image] = map[string]struct{}{}
+			}
+			for _, source := range clusterImage.Sources {
+				sources[clusterImage.Image][source] = struct{}{}
+			}
+		}
+	}
+
+	registryURL, registryConfig, err := util.GeneratePrivateRegistryEncodedDockerConfig(cluster, a.SecretLister)
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to get private registry credentials")
+	}
+	credentials, err := airgap.DockerConfigCredentials(registryConfig)
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to get private registry credentials")
+	}
+
+	output := v3.CheckImagesOutput{
+		Registry:          registryURL,
+		KubernetesVersion: k8sVersion,
+		Ready:             true,
+	}
+	for _, status := range airgap.NewClient(credentials).CheckImages(apiContext.Request.Context(), images) {
+		imageStatus := v3.ImageStatus{
+			Image:   status.Image,
+			Sources: image.SortedSources(sources[status.Image]),
+			Present: status.Present,
+		}
+		if status.Err != nil {
+			imageStatus.Error = status.Err.Error()
+		}
+		if !status.Present {
+			output.Ready = false
+			output.Missing = append(output.Missing, status.Image)
+		}
+		output.Images = append(output.Images, imageStatus)
+	}
+
+	response, err := convert.EncodeToMap(output)
+	if err != nil {
+		return err
+	}
+	response["type"] = client.CheckImagesOutputType
+	apiContext.WriteResponse(http.StatusOK, response)
+	return nil
+}
+
+// clusterAppValues returns the values of every catalog app installed in the cluster, with the chart defaults merged
+// in, keyed by namespace/name of the app.
+func (a ActionHandler) clusterAppValues(clusterName string) (map[string]map[string]interface{}, error) {
+	userContext, err := a.ClusterManager.UserContextNoControllers(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := userContext.Catalog.V1().App().List("", metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]map[string]interface{}, len(apps.Items))
+	for _, app := range apps.Items {
+		appValues := map[string]interface{}{}
+		for k, v := range app.Spec.Values {
+			appValues[k] = v
+		}
+		if app.Spec.Chart != nil {
+			appValues = chartutil.CoalesceTables(appValues, app.Spec.Chart.Values)
+		}
+		values[app.Namespace+"/"+app.Name] = appValues
+	}
+	return values, nil
+}
@@ -54,6 +54,7 @@ func (f *Formatter) Formatter(request *types.APIContext, resource *types.RawReso
 		} else {
 			resource.AddAction(request, v32.ClusterActionEnableMonitoring)
 		}
+		resource.AddAction(request, v32.ClusterActionCheckImages)
 	}
 
 	// If this is an RKE1 cluster only
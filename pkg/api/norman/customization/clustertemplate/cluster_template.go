@@ -14,6 +14,7 @@
 
 	"github.com/rancher/norman/api/access"
 	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/parse"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/norman/types/convert"
 	"github.com/rancher/norman/types/values"
@@ -24,12 +25,15 @@
 	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/retry"
 )
 
 const (
-	enableRevisionAction  = "enable"
-	disableRevisionAction = "disable"
+	enableRevisionAction          = "enable"
+	disableRevisionAction         = "disable"
+	migrateClustersRevisionAction = "migrateClusters"
+	clusterTemplateLabel          = "io.cattle.field/clusterTemplateId"
 )
 
 type Wrapper struct {
@@ -38,10 +42,12 @@ type Wrapper struct {
 	ClusterTemplateRevisionLister v3.ClusterTemplateRevisionLister
 	ClusterTemplateRevisions      v3.ClusterTemplateRevisionInterface
 	ClusterTemplateQuestions      []v32.Question
+	ClusterLister                 v3.ClusterLister
 }
 
 func (w Wrapper) Formatter(apiContext *types.APIContext, resource *types.RawResource) {
 	resource.Links["revisions"] = apiContext.URLBuilder.Link("revisions", resource)
+	resource.Links["compliance"] = apiContext.URLBuilder.Link("compliance", resource)
 }
 
 func (w Wrapper) RevisionFormatter(apiContext *types.APIContext, resource *types.RawResource) {
@@ -49,6 +55,7 @@ func (w Wrapper) RevisionFormatter(apiContext *types.APIContext, resource *types
 	if err := apiContext.AccessControl.CanDo(v3.ClusterTemplateRevisionGroupVersionKind.Group, v3.ClusterTemplateRevisionResource.Name, "update", apiContext, resource.Values, apiContext.Schema); err == nil {
 		if convert.ToBool(resource.Values["enabled"]) {
 			resource.AddAction(apiContext, disableRevisionAction)
+			resource.AddAction(apiContext, migrateClustersRevisionAction)
 		} else {
 			resource.AddAction(apiContext, enableRevisionAction)
 		}
@@ -87,10 +94,70 @@ func (w Wrapper) LinkHandler(apiContext *types.APIContext, next types.RequestHan
 		apiContext.Type = client.ClusterTemplateRevisionType
 		apiContext.WriteResponse(http.StatusOK, templateVersions)
 		return nil
+	case "compliance":
+		var template client.ClusterTemplate
+		if err := access.ByID(apiContext, &managementschema.Version, client.ClusterTemplateType, apiContext.ID, &template); err != nil {
+			return err
+		}
+		var revisions []client.ClusterTemplateRevision
+		revisionConditions := []*types.QueryCondition{
+			types.NewConditionFromString(client.ClusterTemplateRevisionFieldClusterTemplateID, types.ModifierEQ, template.ID),
+		}
+		if err := access.List(apiContext, &managementschema.Version, client.ClusterTemplateRevisionType, &types.QueryOptions{Conditions: revisionConditions}, &revisions); err != nil {
+			return err
+		}
+		var clusters []client.Cluster
+		clusterConditions := []*types.QueryCondition{
+			types.NewConditionFromString(client.ClusterFieldClusterTemplateID, types.ModifierEQ, template.ID),
+		}
+		if err := access.List(apiContext, &managementschema.Version, client.ClusterType, &types.QueryOptions{Conditions: clusterConditions}, &clusters); err != nil {
+			return err
+		}
+		res, err := json.Marshal(complianceReport(template, revisions, clusters))
+		if err != nil {
+			return httperror.WrapAPIError(err, httperror.ServerError, fmt.Sprintf("Error marshalling the Cluster Template compliance output, %v", err))
+		}
+		apiContext.Response.Header().Set("Content-Type", "application/json")
+		apiContext.Response.Write(res)
+		return nil
 	}
 	return nil
 }
 
+// complianceReport lists the clusters of a template by revision, from the newest revision to the oldest.
+func complianceReport(template client.ClusterTemplate, revisions []client.ClusterTemplateRevision, clusters []client.Cluster) v32.ClusterTemplateComplianceOutput {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Created > revisions[j].Created
+	})
+	clusterNames := map[string][]string{}
+	for _, cluster := range clusters {
+		clusterNames[cluster.ClusterTemplateRevisionID] = append(clusterNames[cluster.ClusterTemplateRevisionID], cluster.ID)
+	}
+
+	output := v32.ClusterTemplateComplianceOutput{DefaultRevisionName: template.DefaultRevisionID}
+	newerRevisions := 0
+	for _, revision := range revisions {
+		names := clusterNames[revision.ID]
+		sort.Strings(names)
+		enabled := revision.Enabled == nil || *revision.Enabled
+		output.Revisions = append(output.Revisions, v32.ClusterTemplateRevisionCompliance{
+			RevisionName:    revision.ID,
+			DisplayName:     revision.Name,
+			Enabled:         enabled,
+			Default:         revision.ID == template.DefaultRevisionID,
+			RevisionsBehind: newerRevisions,
+			ClusterNames:    names,
+		})
+		if enabled {
+			newerRevisions++
+		} else {
+			output.DeprecatedClusterNames = append(output.DeprecatedClusterNames, names...)
+		}
+	}
+	sort.Strings(output.DeprecatedClusterNames)
+	return output
+}
+
 func (w Wrapper) ClusterTemplateRevisionsActionHandler(actionName string, action *types.Action, apiContext *types.APIContext) error {
 
 	canUpdateClusterTemplateRevision := func() bool {
@@ -114,6 +181,11 @@ func (w Wrapper) ClusterTemplateRevisionsActionHandler(actionName string, action
 		return w.updateEnabledFlagOnRevision(apiContext, true)
 	case "listquestions":
 		return w.listRevisionQuestions(actionName, action, apiContext)
+	case "migrateClusters":
+		if !canUpdateClusterTemplateRevision() {
+			return httperror.NewAPIError(httperror.PermissionDenied, "can not access clusterTemplateRevision")
+		}
+		return w.migrateClusters(apiContext)
 
 	}
 	return httperror.NewAPIError(httperror.NotFound, "not found")
@@ -160,6 +232,95 @@ func (w Wrapper) updateEnabledFlagOnRevision(apiContext *types.APIContext, enabl
 	return nil
 }
 
+func (w Wrapper) migrateClusters(apiContext *types.APIContext) error {
+	revision, err := w.loadRevision(apiContext)
+	if err != nil {
+		return err
+	}
+	if revision.Spec.Enabled != nil && !*revision.Spec.Enabled {
+		return httperror.NewAPIError(httperror.InvalidState, "clusterTemplateRevision is disabled, clusters can not be migrated to it")
+	}
+
+	actionInput, err := parse.ReadBody(apiContext.Request)
+	if err != nil {
+		return err
+	}
+	var input v32.ClusterTemplateMigrationInput
+	if err := convert.ToObj(actionInput, &input); err != nil {
+		return httperror.WrapAPIError(err, httperror.InvalidBodyContent, "invalid clusterTemplateMigrationInput")
+	}
+	if len(input.ClusterNames) == 0 {
+		return httperror.NewFieldAPIError(httperror.MissingRequired, "clusterNames", "")
+	}
+	if input.BatchSize < 1 {
+		input.BatchSize = 1
+	}
+
+	// clusters can only be in one migration of the template at a time
+	templateName := strings.TrimPrefix(revision.Spec.ClusterTemplateName, namespace.GlobalNamespace+":")
+	revisions, err := w.ClusterTemplateRevisionLister.List(namespace.GlobalNamespace, labels.SelectorFromSet(labels.Set{clusterTemplateLabel: templateName}))
+	if err != nil {
+		return err
+	}
+	inMigration := map[string]string{}
+	for _, r := range revisions {
+		migration := r.Status.Migration
+		if migration == nil || migration.State != v32.ClusterTemplateMigrationStateMigrating {
+			continue
+		}
+		if r.Name == revision.Name {
+			return httperror.NewAPIError(httperror.Conflict, "a migration of clusters to the clusterTemplateRevision is in progress")
+		}
+		for _, name := range migration.ClusterNames {
+			inMigration[name] = r.Name
+		}
+	}
+
+	clusterSchema := apiContext.Schemas.Schema(&managementschema.Version, client.ClusterType)
+	seen := map[string]bool{}
+	var clusterNames []string
+	for _, name := range input.ClusterNames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		cluster, err := w.ClusterLister.Get("", name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return httperror.NewAPIError(httperror.InvalidOption, fmt.Sprintf("cluster %s is not found", name))
+			}
+			return err
+		}
+		if err := apiContext.AccessControl.CanDo(v3.ClusterGroupVersionKind.Group, v3.ClusterResource.Name, "update", apiContext, map[string]interface{}{"id": name}, clusterSchema); err != nil {
+			return httperror.NewAPIError(httperror.PermissionDenied, fmt.Sprintf("can not update cluster %s", name))
+		}
+		if cluster.Spec.ClusterTemplateName != revision.Spec.ClusterTemplateName {
+			return httperror.NewAPIError(httperror.InvalidOption, fmt.Sprintf("cluster %s is not created from the clusterTemplate of the revision", name))
+		}
+		if other, ok := inMigration[name]; ok {
+			return httperror.NewAPIError(httperror.Conflict, fmt.Sprintf("cluster %s is being migrated to clusterTemplateRevision %s", name, other))
+		}
+		clusterNames = append(clusterNames, name)
+	}
+
+	revisionCopy := revision.DeepCopy()
+	revisionCopy.Status.Migration = &v32.ClusterTemplateMigration{
+		ClusterNames: clusterNames,
+		BatchSize:    input.BatchSize,
+		State:        v32.ClusterTemplateMigrationStateMigrating,
+		StartedAt:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, err := w.ClusterTemplateRevisions.Update(revisionCopy); err != nil {
+		if apierrors.IsConflict(err) {
+			return httperror.WrapAPIError(err, httperror.Conflict, "clusterTemplateRevision was updated, retry the migration")
+		}
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to start the migration of clusters to clusterTemplateRevision")
+	}
+
+	apiContext.WriteResponse(http.StatusNoContent, map[string]interface{}{})
+	return nil
+}
+
 func (w Wrapper) loadRevision(apiContext *types.APIContext) (*v3.ClusterTemplateRevision, error) {
 	//load the templaterevision
 	split := strings.SplitN(apiContext.ID, ":", 2)
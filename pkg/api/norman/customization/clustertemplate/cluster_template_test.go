@@ -0,0 +1,36 @@
+package clustertemplate
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/types"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplianceReport(t *testing.T) {
+	disabled := false
+	template := client.ClusterTemplate{DefaultRevisionID: "cattle-global-data:ctr-2"}
+	revisions := []client.ClusterTemplateRevision{
+		{Resource: types.Resource{ID: "cattle-global-data:ctr-1"}, Name: "v1", Created: "2022-01-01T00:00:00Z"},
+		{Resource: types.Resource{ID: "cattle-global-data:ctr-3"}, Name: "v3", Created: "2022-03-01T00:00:00Z"},
+		{Resource: types.Resource{ID: "cattle-global-data:ctr-2"}, Name: "v2", Created: "2022-02-01T00:00:00Z", Enabled: &disabled},
+	}
+	clusters := []client.Cluster{
+		{Resource: types.Resource{ID: "c-3"}, ClusterTemplateRevisionID: "cattle-global-data:ctr-1"},
+		{Resource: types.Resource{ID: "c-1"}, ClusterTemplateRevisionID: "cattle-global-data:ctr-2"},
+		{Resource: types.Resource{ID: "c-2"}, ClusterTemplateRevisionID: "cattle-global-data:ctr-1"},
+	}
+
+	assert.Equal(t, v32.ClusterTemplateComplianceOutput{
+		DefaultRevisionName: "cattle-global-data:ctr-2",
+		Revisions: []v32.ClusterTemplateRevisionCompliance{
+			{RevisionName: "cattle-global-data:ctr-3", DisplayName: "v3", Enabled: true},
+			{RevisionName: "cattle-global-data:ctr-2", DisplayName: "v2", Default: true, RevisionsBehind: 1, ClusterNames: []string{"c-1"}},
+			// disabled revisions are not counted as newer revisions
+			{RevisionName: "cattle-global-data:ctr-1", DisplayName: "v1", Enabled: true, RevisionsBehind: 1, ClusterNames: []string{"c-2", "c-3"}},
+		},
+		DeprecatedClusterNames: []string{"c-1"},
+	}, complianceReport(template, revisions, clusters))
+}
@@ -3,12 +3,15 @@
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/rancher/norman/api/access"
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
 	v3client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/rancher/rancher/pkg/features"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 type Validator struct {
@@ -43,14 +46,38 @@ func (v *Validator) Validator(request *types.APIContext, schema *types.Schema, d
 		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("feature flag cannot be changed from current value: %v", *obj.Status.LockedValue))
 	}
 
-	_, ok = newValue.(bool)
+	value, ok := newValue.(bool)
 	if !ok {
 		return httperror.NewAPIError(httperror.InvalidBodyContent, "feature value must be a bool")
 	}
 
+	if enableAt, ok := data[v3client.FeatureFieldEnableAt].(string); ok && enableAt != "" {
+		if _, err := time.Parse(time.RFC3339, enableAt); err != nil {
+			return httperror.NewAPIError(httperror.InvalidFormat, fmt.Sprintf("enableAt must be a RFC3339 time: %v", err))
+		}
+	}
+
+	if value != features.IsEnabled(obj) {
+		if err := features.ValidateDependencies(id, value, v.enabled); err != nil {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+		}
+	}
+
 	return nil
 }
 
+// enabled returns the effective value of a feature, falling back to the value in memory for features not installed.
+func (v *Validator) enabled(name string) (bool, error) {
+	obj, err := v.FeatureLister.Get("", name)
+	if apierrors.IsNotFound(err) {
+		f := features.GetFeatureByName(name)
+		return f != nil && f.Enabled(), nil
+	} else if err != nil {
+		return false, err
+	}
+	return features.IsEnabled(obj), nil
+}
+
 func Formatter(request *types.APIContext, resource *types.RawResource) {
 	if request.Method == http.MethodGet {
 		resource.Values["value"] = getEffectiveValue(resource)
@@ -0,0 +1,396 @@
+package namespace
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementagent/nslabels"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/networkpolicy"
+	quotacontroller "github.com/rancher/rancher/pkg/controllers/managementuser/resourcequota"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/helm"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/resourcequota"
+	schema "github.com/rancher/rancher/pkg/schemas/cluster.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubelet/util/format"
+)
+
+// move moves a namespace to another project, or out of its project, after checking that it fits in the quota of the
+// project. It reports the quota the namespace gets and how its access and network policies change, and only reports
+// them on a dry run.
+func (w ActionWrapper) move(apiContext *types.APIContext, actionInput map[string]interface{}) error {
+	var input schema.NamespaceMove
+	if err := convert.ToObj(actionInput, &input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+	}
+	clusterID := w.ClusterManager.ClusterName(apiContext)
+	_, projectName := ref.Parse(input.ProjectID)
+	userContext, err := w.ClusterManager.UserContextNoControllers(clusterID)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		return httperror.NewAPIError(httperror.NotFound, err.Error())
+	}
+
+	var project *v3.Project
+	projectID := ""
+	if projectName != "" {
+		project, err = userContext.Management.Management.Projects(clusterID).Get(projectName, metav1.GetOptions{})
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+			return httperror.NewAPIError(httperror.NotFound, err.Error())
+		}
+		projectID = ref.Ref(project)
+		if project.Spec.ResourceQuota != nil {
+			// keep namespaces from being moved to, or created in, the project while its quota is checked
+			mu := resourcequota.GetProjectLock(projectID)
+			mu.Lock()
+			defer mu.Unlock()
+		}
+	}
+
+	nsClient := userContext.Core.Namespaces("")
+	ns, err := nsClient.Get(apiContext.ID, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		return httperror.NewAPIError(httperror.NotFound, err.Error())
+	}
+	if ns.Annotations[helm.AppIDsLabel] != "" {
+		return errors.New("namespace is currently being used")
+	}
+	previousProjectID := ns.Annotations[nslabels.ProjectIDFieldLabel]
+	if previousProjectID == projectID {
+		return httperror.NewAPIError(httperror.InvalidOption, fmt.Sprintf("namespace %s is already in project %s", ns.Name, input.ProjectID))
+	}
+	var previousProject *v3.Project
+	if _, previousProjectName := ref.Parse(previousProjectID); previousProjectName != "" {
+		previousProject, err = userContext.Management.Management.Projects(clusterID).Get(previousProjectName, metav1.GetOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	output := schema.NamespaceMoveOutput{
+		ProjectID:         projectID,
+		PreviousProjectID: previousProjectID,
+	}
+	output.ResourceQuota, err = moveResourceQuota(ns, project, nsClient)
+	if err != nil {
+		return err
+	}
+	output.AccessChanges, err = moveAccessChanges(userContext, previousProject, project)
+	if err != nil {
+		return err
+	}
+	output.NetworkPoliciesAdded, output.NetworkPoliciesRemoved, err = moveNetworkPolicyChanges(userContext, clusterID, previousProject, project)
+	if err != nil {
+		return err
+	}
+
+	if !input.DryRun {
+		// the project and the quota of the namespace are changed with a single update, which fails if the namespace
+		// changed since it was checked
+		if projectID == "" {
+			delete(ns.Annotations, nslabels.ProjectIDFieldLabel)
+			delete(ns.Labels, nslabels.ProjectIDFieldLabel)
+		} else {
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+			ns.Annotations[nslabels.ProjectIDFieldLabel] = projectID
+		}
+		if output.ResourceQuota == nil {
+			delete(ns.Annotations, quotacontroller.ResourceQuotaAnnotation)
+		} else {
+			quota, err := json.Marshal(output.ResourceQuota)
+			if err != nil {
+				return err
+			}
+			ns.Annotations[quotacontroller.ResourceQuotaAnnotation] = string(quota)
+		}
+		if _, err := nsClient.Update(ns); err != nil {
+			if kerrors.IsConflict(err) {
+				return httperror.NewAPIError(httperror.Conflict, fmt.Sprintf("namespace %s changed while being moved, retry the move", ns.Name))
+			}
+			return err
+		}
+		namespaceOwnerMap.Add(ns.Name, projectID, time.Hour)
+		output.Moved = true
+	}
+
+	data, err := convert.EncodeToMap(output)
+	if err != nil {
+		return err
+	}
+	data["type"] = "namespaceMoveOutput"
+	apiContext.WriteResponse(http.StatusOK, data)
+	return nil
+}
+
+// moveResourceQuota returns the quota of a namespace in the project it moves to: its own quota when it sets every
+// resource of the project quota, the default namespace quota of the project otherwise. It fails when the quota does
+// not fit in what is left of the project quota, or is lower than the defaults of the namespace limit range.
+func moveResourceQuota(ns *corev1.Namespace, project *v3.Project, nsClient namespaceLister) (*schema.NamespaceResourceQuota, error) {
+	if project == nil || project.Spec.ResourceQuota == nil {
+		return nil, nil
+	}
+	projectLimit := &project.Spec.ResourceQuota.Limit
+	projectLimitMap, err := resourcequota.LimitToMap(projectLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var nsLimit *v32.ResourceQuotaLimit
+	if value := ns.Annotations[quotacontroller.ResourceQuotaAnnotation]; value != "" {
+		var nsQuota v32.NamespaceResourceQuota
+		if err := json.Unmarshal([]byte(value), &nsQuota); err != nil {
+			return nil, err
+		}
+		nsLimitMap, err := resourcequota.LimitToMap(&nsQuota.Limit)
+		if err != nil {
+			return nil, err
+		}
+		if hasAllLimits(nsLimitMap, projectLimitMap) {
+			nsLimit = &nsQuota.Limit
+		}
+	}
+	if nsLimit == nil {
+		if project.Spec.NamespaceDefaultResourceQuota == nil {
+			return nil, httperror.NewAPIError(httperror.InvalidState,
+				fmt.Sprintf("project %s has a resource quota but no namespace default resource quota", project.Spec.DisplayName))
+		}
+		nsLimit = &project.Spec.NamespaceDefaultResourceQuota.Limit
+	}
+
+	namespaces, err := nsClient.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	projectID := ref.Ref(project)
+	var nsLimits []*v32.ResourceQuotaLimit
+	for _, n := range namespaces.Items {
+		if n.Name == ns.Name || n.Annotations[nslabels.ProjectIDFieldLabel] != projectID || n.Annotations[quotacontroller.ResourceQuotaAnnotation] == "" {
+			continue
+		}
+		var nsQuota v32.NamespaceResourceQuota
+		if err := json.Unmarshal([]byte(n.Annotations[quotacontroller.ResourceQuotaAnnotation]), &nsQuota); err != nil {
+			return nil, err
+		}
+		nsLimits = append(nsLimits, &nsQuota.Limit)
+	}
+	isFit, exceeded, err := resourcequota.IsQuotaFit(nsLimit, nsLimits, projectLimit)
+	if err != nil {
+		return nil, err
+	}
+	if !isFit {
+		return nil, httperror.NewAPIError(httperror.MaxLimitExceeded,
+			fmt.Sprintf("namespace quota exceeds what is left of the quota of project %s on fields: %s", project.Spec.DisplayName,
+				format.ResourceList(exceeded)))
+	}
+
+	limit := project.Spec.ContainerDefaultResourceLimit
+	if value := ns.Annotations[quotacontroller.LimitRangeAnnotation]; value != "" && value != "null" {
+		limit = &v32.ContainerResourceLimit{}
+		if err := json.Unmarshal([]byte(value), limit); err != nil {
+			return nil, err
+		}
+	}
+	if err := resourcequota.ValidateLimitRangeQuota(limit, nsLimit); err != nil {
+		return nil, httperror.NewAPIError(httperror.MaxLimitExceeded, fmt.Sprintf("container default resource limit does not fit in the namespace quota: %v", err))
+	}
+
+	quota := &schema.NamespaceResourceQuota{}
+	if err := convert.ToObj(v32.NamespaceResourceQuota{Limit: *nsLimit}, quota); err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+func hasAllLimits(nsLimits, projectLimits map[string]string) bool {
+	for k := range projectLimits {
+		if _, ok := nsLimits[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type namespaceLister interface {
+	List(opts metav1.ListOptions) (*corev1.NamespaceList, error)
+}
+
+// moveAccessChanges lists the role templates that users and groups gain and lose in a namespace that moves between
+// projects, through the bindings of the projects.
+func moveAccessChanges(userContext *config.UserContext, from, to *v3.Project) ([]schema.NamespaceMoveAccessChange, error) {
+	fromBindings, err := projectBindings(userContext, from)
+	if err != nil {
+		return nil, err
+	}
+	toBindings, err := projectBindings(userContext, to)
+	if err != nil {
+		return nil, err
+	}
+	return accessChanges(fromBindings, toBindings), nil
+}
+
+func projectBindings(userContext *config.UserContext, project *v3.Project) ([]v3.ProjectRoleTemplateBinding, error) {
+	if project == nil {
+		return nil, nil
+	}
+	bindings, err := userContext.Management.Management.ProjectRoleTemplateBindings(project.Name).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return bindings.Items, nil
+}
+
+type bindingSubject struct {
+	userName           string
+	groupPrincipalName string
+}
+
+func accessChanges(from, to []v3.ProjectRoleTemplateBinding) []schema.NamespaceMoveAccessChange {
+	fromRoles := subjectRoles(from)
+	toRoles := subjectRoles(to)
+
+	subjects := map[bindingSubject]bool{}
+	for subject := range fromRoles {
+		subjects[subject] = true
+	}
+	for subject := range toRoles {
+		subjects[subject] = true
+	}
+
+	var changes []schema.NamespaceMoveAccessChange
+	for subject := range subjects {
+		change := schema.NamespaceMoveAccessChange{
+			UserName:           subject.userName,
+			GroupPrincipalName: subject.groupPrincipalName,
+		}
+		for role := range toRoles[subject] {
+			if !fromRoles[subject][role] {
+				change.Granted = append(change.Granted, role)
+			}
+		}
+		for role := range fromRoles[subject] {
+			if !toRoles[subject][role] {
+				change.Revoked = append(change.Revoked, role)
+			}
+		}
+		if len(change.Granted) == 0 && len(change.Revoked) == 0 {
+			continue
+		}
+		sort.Strings(change.Granted)
+		sort.Strings(change.Revoked)
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].UserName != changes[j].UserName {
+			return changes[i].UserName < changes[j].UserName
+		}
+		return changes[i].GroupPrincipalName < changes[j].GroupPrincipalName
+	})
+	return changes
+}
+
+func subjectRoles(bindings []v3.ProjectRoleTemplateBinding) map[bindingSubject]map[string]bool {
+	roles := map[bindingSubject]map[string]bool{}
+	for _, binding := range bindings {
+		if binding.DeletionTimestamp != nil || (binding.UserName == "" && binding.GroupPrincipalName == "") {
+			continue
+		}
+		subject := bindingSubject{userName: binding.UserName, groupPrincipalName: binding.GroupPrincipalName}
+		if roles[subject] == nil {
+			roles[subject] = map[string]bool{}
+		}
+		roles[subject][binding.RoleTemplateName] = true
+	}
+	return roles
+}
+
+// moveNetworkPolicyChanges lists the network policies added to and removed from a namespace that moves between
+// projects, when project network isolation is enabled in its cluster.
+func moveNetworkPolicyChanges(userContext *config.UserContext, clusterID string, from, to *v3.Project) ([]string, []string, error) {
+	cluster, err := userContext.Management.Management.Clusters("").Get(clusterID, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cluster.Status.AppliedEnableNetworkPolicy {
+		return nil, nil, nil
+	}
+	fromPolicies, err := projectNetworkPolicies(userContext, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toPolicies, err := projectNetworkPolicies(userContext, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	added, removed := networkPolicyChanges(fromPolicies, toPolicies)
+	return added, removed, nil
+}
+
+// projectNetworkPolicies returns the names of the network policies that the network policy controller programs in
+// the namespaces of a project: its default policy and the ones rendered from its templates.
+func projectNetworkPolicies(userContext *config.UserContext, project *v3.Project) ([]string, error) {
+	if project == nil {
+		return nil, nil
+	}
+	policies := []string{networkpolicy.DefaultNamespacePolicyName}
+	if project.Labels[networkpolicy.SystemProjectLabel] == "true" {
+		policies = []string{networkpolicy.DefaultSystemProjectNamespacePolicyName}
+	}
+	pnps, err := userContext.Management.Management.ProjectNetworkPolicies(project.Name).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pnp := range pnps.Items {
+		if pnp.Spec.Template != nil && pnp.DeletionTimestamp == nil {
+			policies = append(policies, networkpolicy.TemplatePolicyName(pnp.Name))
+		}
+	}
+	return policies, nil
+}
+
+func networkPolicyChanges(from, to []string) ([]string, []string) {
+	fromSet := map[string]bool{}
+	for _, name := range from {
+		fromSet[name] = true
+	}
+	toSet := map[string]bool{}
+	for _, name := range to {
+		toSet[name] = true
+	}
+
+	var added, removed []string
+	for name := range toSet {
+		if !fromSet[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range fromSet {
+		if !toSet[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
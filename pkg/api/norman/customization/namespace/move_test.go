@@ -0,0 +1,124 @@
+package namespace
+
+import (
+	"testing"
+
+	"github.com/rancher/norman/httperror"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementagent/nslabels"
+	quotacontroller "github.com/rancher/rancher/pkg/controllers/managementuser/resourcequota"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	schema "github.com/rancher/rancher/pkg/schemas/cluster.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeNamespaceLister []corev1.Namespace
+
+func (f fakeNamespaceLister) List(opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	return &corev1.NamespaceList{Items: f}, nil
+}
+
+func newMoveNamespace(name, projectID, quota string) corev1.Namespace {
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{nslabels.ProjectIDFieldLabel: projectID},
+		},
+	}
+	if quota != "" {
+		ns.Annotations[quotacontroller.ResourceQuotaAnnotation] = quota
+	}
+	return ns
+}
+
+func newMoveProject() *v3.Project {
+	return &v3.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "p-2", Namespace: "c-1"},
+		Spec: v32.ProjectSpec{
+			DisplayName: "destination",
+			ResourceQuota: &v32.ProjectResourceQuota{
+				Limit: v32.ResourceQuotaLimit{Pods: "10"},
+			},
+			NamespaceDefaultResourceQuota: &v32.NamespaceResourceQuota{
+				Limit: v32.ResourceQuotaLimit{Pods: "4"},
+			},
+		},
+	}
+}
+
+func TestMoveResourceQuota(t *testing.T) {
+	project := newMoveProject()
+	other := newMoveNamespace("other", "c-1:p-2", `{"limit":{"pods":"4"}}`)
+
+	// a namespace without quota gets the default namespace quota of the project
+	ns := newMoveNamespace("ns", "c-1:p-1", "")
+	quota, err := moveResourceQuota(&ns, project, fakeNamespaceLister{other})
+	require.NoError(t, err)
+	assert.Equal(t, &schema.NamespaceResourceQuota{Limit: schema.ResourceQuotaLimit{Pods: "4"}}, quota)
+
+	// a namespace keeps its own quota when it sets every resource of the project quota
+	ns = newMoveNamespace("ns", "c-1:p-1", `{"limit":{"pods":"6","secrets":"5"}}`)
+	quota, err = moveResourceQuota(&ns, project, fakeNamespaceLister{other})
+	require.NoError(t, err)
+	assert.Equal(t, &schema.NamespaceResourceQuota{Limit: schema.ResourceQuotaLimit{Pods: "6", Secrets: "5"}}, quota)
+
+	// which must fit in what is left of the project quota
+	ns = newMoveNamespace("ns", "c-1:p-1", `{"limit":{"pods":"7"}}`)
+	_, err = moveResourceQuota(&ns, project, fakeNamespaceLister{other})
+	require.Error(t, err)
+	assert.Equal(t, httperror.MaxLimitExceeded, err.(*httperror.APIError).Code)
+
+	// the namespaces of other projects are not counted
+	outside := newMoveNamespace("outside", "c-1:p-3", `{"limit":{"pods":"10"}}`)
+	_, err = moveResourceQuota(&ns, project, fakeNamespaceLister{outside})
+	require.NoError(t, err)
+
+	// the default container limits must fit in the quota
+	ns = newMoveNamespace("ns", "c-1:p-1", "")
+	project.Spec.ResourceQuota.Limit.RequestsCPU = "2"
+	project.Spec.NamespaceDefaultResourceQuota.Limit.RequestsCPU = "1"
+	ns.Annotations[quotacontroller.LimitRangeAnnotation] = `{"requestsCpu":"2"}`
+	_, err = moveResourceQuota(&ns, project, fakeNamespaceLister{})
+	require.Error(t, err)
+	assert.Equal(t, httperror.MaxLimitExceeded, err.(*httperror.APIError).Code)
+
+	quota, err = moveResourceQuota(&ns, &v3.Project{}, fakeNamespaceLister{})
+	require.NoError(t, err)
+	assert.Nil(t, quota)
+}
+
+func TestAccessChanges(t *testing.T) {
+	binding := func(user, group, role string) v3.ProjectRoleTemplateBinding {
+		return v3.ProjectRoleTemplateBinding{UserName: user, GroupPrincipalName: group, RoleTemplateName: role}
+	}
+	from := []v3.ProjectRoleTemplateBinding{
+		binding("u-alice", "", "project-owner"),
+		binding("u-bob", "", "project-member"),
+		binding("", "github_team://1", "read-only"),
+	}
+	to := []v3.ProjectRoleTemplateBinding{
+		binding("u-alice", "", "project-member"),
+		binding("u-bob", "", "project-member"),
+		binding("u-carol", "", "project-owner"),
+	}
+
+	assert.Equal(t, []schema.NamespaceMoveAccessChange{
+		{GroupPrincipalName: "github_team://1", Revoked: []string{"read-only"}},
+		{UserName: "u-alice", Granted: []string{"project-member"}, Revoked: []string{"project-owner"}},
+		{UserName: "u-carol", Granted: []string{"project-owner"}},
+	}, accessChanges(from, to))
+	assert.Empty(t, accessChanges(from, from))
+}
+
+func TestNetworkPolicyChanges(t *testing.T) {
+	added, removed := networkPolicyChanges([]string{"np-default", "pnp-a"}, []string{"np-default", "pnp-b", "pnp-c"})
+	assert.Equal(t, []string{"pnp-b", "pnp-c"}, added)
+	assert.Equal(t, []string{"pnp-a"}, removed)
+
+	added, removed = networkPolicyChanges([]string{"np-default"}, nil)
+	assert.Empty(t, added)
+	assert.Equal(t, []string{"np-default"}, removed)
+}
@@ -11,13 +11,9 @@
 	"github.com/rancher/norman/types/convert"
 	client "github.com/rancher/rancher/pkg/client/generated/cluster/v3"
 	"github.com/rancher/rancher/pkg/clustermanager"
-	"github.com/rancher/rancher/pkg/controllers/managementagent/nslabels"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/helm"
 	"github.com/rancher/rancher/pkg/rbac"
-	"github.com/rancher/rancher/pkg/ref"
 	schema "github.com/rancher/rancher/pkg/schemas/cluster.cattle.io/v3"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/cache"
 )
 
@@ -72,48 +68,9 @@ func (w ActionWrapper) ActionHandler(actionName string, action *types.Action, ap
 
 	switch actionName {
 	case "move":
-		clusterID := w.ClusterManager.ClusterName(apiContext)
-		_, projectID := ref.Parse(convert.ToString(actionInput["projectId"]))
-		userContext, err := w.ClusterManager.UserContextNoControllers(clusterID)
-		if err != nil {
-			if !kerrors.IsNotFound(err) {
-				return err
-			}
-			return httperror.NewAPIError(httperror.NotFound, err.Error())
-		}
-		if projectID != "" {
-			project, err := userContext.Management.Management.Projects(clusterID).Get(projectID, metav1.GetOptions{})
-			if err != nil {
-				return err
-			}
-			if project.Spec.ResourceQuota != nil {
-				return errors.Errorf("can't move namespace. Project %s has resource quota set", project.Spec.DisplayName)
-			}
-		}
-		nsClient := userContext.Core.Namespaces("")
-		ns, err := nsClient.Get(apiContext.ID, metav1.GetOptions{})
-		if err != nil {
-			if !kerrors.IsNotFound(err) {
-				return err
-			}
-			return httperror.NewAPIError(httperror.NotFound, err.Error())
-		}
-		if ns.Annotations[helm.AppIDsLabel] != "" {
-			return errors.New("namespace is currently being used")
-		}
-		if projectID == "" {
-			delete(ns.Annotations, nslabels.ProjectIDFieldLabel)
-			delete(ns.Labels, nslabels.ProjectIDFieldLabel)
-		} else {
-			ns.Annotations[nslabels.ProjectIDFieldLabel] = convert.ToString(actionInput["projectId"])
-		}
-		if _, err := nsClient.Update(ns); err != nil {
-			return err
-		}
-	default:
-		return errors.New("invalid action")
+		return w.move(apiContext, actionInput)
 	}
-	return nil
+	return errors.New("invalid action")
 }
 
 func NewFormatter(next types.Formatter) types.Formatter {
@@ -5,7 +5,6 @@
 	"fmt"
 	"strings"
 
-	"github.com/mitchellh/mapstructure"
 	"github.com/rancher/norman/api/access"
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
@@ -27,6 +26,7 @@
 const roleTemplatesRequired = "authz.management.cattle.io/creator-role-bindings"
 const quotaField = "resourceQuota"
 const namespaceQuotaField = "namespaceDefaultResourceQuota"
+const containerResourceLimitField = "containerDefaultResourceLimit"
 
 type projectStore struct {
 	types.Store
@@ -59,6 +59,10 @@ func (s *projectStore) Create(apiContext *types.APIContext, schema *types.Schema
 		return nil, err
 	}
 
+	if err := validateContainerResourceLimit(data); err != nil {
+		return nil, err
+	}
+
 	values.PutValue(data, annotation, "annotations", roleTemplatesRequired)
 
 	return s.Store.Create(apiContext, schema, data)
@@ -69,6 +73,10 @@ func (s *projectStore) Update(apiContext *types.APIContext, schema *types.Schema
 		return nil, err
 	}
 
+	if err := validateContainerResourceLimit(data); err != nil {
+		return nil, err
+	}
+
 	return s.Store.Update(apiContext, schema, data, id)
 }
 
@@ -142,14 +150,20 @@ func (s *projectStore) validateResourceQuota(apiContext *types.APIContext, data
 	if err != nil {
 		return err
 	}
+	if err := resourcequota.ValidateLimit(projectQuotaLimit); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidFormat, quotaField, err.Error())
+	}
+	if err := resourcequota.ValidateLimit(nsQuotaLimit); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidFormat, namespaceQuotaField, err.Error())
+	}
 
 	// limits in namespace default quota should include all limits defined in the project quota
-	projectQuotaLimitMap, err := convert.EncodeToMap(projectQuotaLimit)
+	projectQuotaLimitMap, err := resourcequota.LimitToMap(projectQuotaLimit)
 	if err != nil {
 		return err
 	}
 
-	nsQuotaLimitMap, err := convert.EncodeToMap(nsQuotaLimit)
+	nsQuotaLimitMap, err := resourcequota.LimitToMap(nsQuotaLimit)
 	if err != nil {
 		return err
 	}
@@ -165,6 +179,40 @@ func (s *projectStore) validateResourceQuota(apiContext *types.APIContext, data
 	return s.isQuotaFit(apiContext, nsQuotaLimit, projectQuotaLimit, id)
 }
 
+// validateContainerResourceLimit checks the limit range of the project, and that it fits in the default quota of its
+// namespaces.
+func validateContainerResourceLimit(data map[string]interface{}) error {
+	limitO := data[containerResourceLimitField]
+	if limitO == nil {
+		return nil
+	}
+	var limit v32.ContainerResourceLimit
+	if err := convert.ToObj(limitO, &limit); err != nil {
+		return err
+	}
+	if err := resourcequota.ValidateLimitRange(&limit); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidFormat, containerResourceLimitField, err.Error())
+	}
+
+	nsQuotaO := data[namespaceQuotaField]
+	if nsQuotaO == nil {
+		return nil
+	}
+	var nsQuota mgmtclient.NamespaceResourceQuota
+	if err := convert.ToObj(nsQuotaO, &nsQuota); err != nil {
+		return err
+	}
+	nsQuotaLimit, err := limitToLimit(nsQuota.Limit)
+	if err != nil {
+		return err
+	}
+	if err := resourcequota.ValidateLimitRangeQuota(&limit, nsQuotaLimit); err != nil {
+		return httperror.NewFieldAPIError(httperror.MaxLimitExceeded, containerResourceLimitField,
+			fmt.Sprintf("does not fit in the %s: %v", namespaceQuotaField, err))
+	}
+	return nil
+}
+
 func (s *projectStore) isQuotaFit(apiContext *types.APIContext, nsQuotaLimit *v32.ResourceQuotaLimit,
 	projectQuotaLimit *v32.ResourceQuotaLimit, id string) error {
 	// check that namespace default quota is within project quota
@@ -188,21 +236,25 @@ func (s *projectStore) isQuotaFit(apiContext *types.APIContext, nsQuotaLimit *v3
 
 	// check if fields were added or removed
 	// and update project's namespaces accordingly
-	defaultQuotaLimitMap, err := convert.EncodeToMap(nsQuotaLimit)
+	defaultQuotaLimitMap, err := resourcequota.LimitToMap(nsQuotaLimit)
 	if err != nil {
 		return err
 	}
 
-	usedQuotaLimitMap := map[string]interface{}{}
+	usedQuotaLimitMap := map[string]string{}
 	if project.ResourceQuota != nil && project.ResourceQuota.UsedLimit != nil {
-		usedQuotaLimitMap, err = convert.EncodeToMap(project.ResourceQuota.UsedLimit)
+		usedLimit, err := limitToLimit(project.ResourceQuota.UsedLimit)
+		if err != nil {
+			return err
+		}
+		usedQuotaLimitMap, err = resourcequota.LimitToMap(usedLimit)
 		if err != nil {
 			return err
 		}
 	}
 
-	limitToAdd := map[string]interface{}{}
-	limitToRemove := map[string]interface{}{}
+	limitToAdd := map[string]string{}
+	limitToRemove := map[string]string{}
 	for key, value := range defaultQuotaLimitMap {
 		if _, ok := usedQuotaLimitMap[key]; !ok {
 			limitToAdd[key] = value
@@ -220,13 +272,7 @@ func (s *projectStore) isQuotaFit(apiContext *types.APIContext, nsQuotaLimit *v3
 		delete(usedQuotaLimitMap, key)
 	}
 
-	var usedLimitToCheck mgmtclient.ResourceQuotaLimit
-	err = convert.ToObj(usedQuotaLimitMap, &usedLimitToCheck)
-	if err != nil {
-		return err
-	}
-
-	usedQuotaLimit, err := limitToLimit(&usedLimitToCheck)
+	usedQuotaLimit, err := resourcequota.MapToLimit(usedQuotaLimitMap)
 	if err != nil {
 		return err
 	}
@@ -244,11 +290,7 @@ func (s *projectStore) isQuotaFit(apiContext *types.APIContext, nsQuotaLimit *v3
 	}
 
 	// check if default quota is enough to set on namespaces
-	toAppend := &mgmtclient.ResourceQuotaLimit{}
-	if err := mapstructure.Decode(limitToAdd, toAppend); err != nil {
-		return err
-	}
-	converted, err := limitToLimit(toAppend)
+	converted, err := resourcequota.MapToLimit(limitToAdd)
 	if err != nil {
 		return err
 	}
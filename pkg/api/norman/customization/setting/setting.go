@@ -13,6 +13,7 @@
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	v3client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/logging"
 	"github.com/rancher/rancher/pkg/settings"
 )
 
@@ -80,6 +81,8 @@ func Validator(request *types.APIContext, schema *types.Schema, data map[string]
 		_, err = providerrefresh.ParseMaxAge(newValueString)
 	case "auth-user-info-resync-cron":
 		_, err = providerrefresh.ParseCron(newValueString)
+	case "log-levels":
+		_, err = logging.ParseLevels(newValueString)
 	case "kubeconfig-token-ttl-minutes":
 		var tokenTTL time.Duration
 		tokenTTL, err = tokens.ParseTokenTTL(newValueString)
@@ -293,6 +293,10 @@ func Clusters(ctx context.Context, schemas *types.Schemas, managementContext *co
 	handler.CisConfigLister = managementContext.Management.CisConfigs("").Controller().Lister()
 	handler.CisBenchmarkVersionClient = managementContext.Management.CisBenchmarkVersions("")
 	handler.CisBenchmarkVersionLister = managementContext.Management.CisBenchmarkVersions("").Controller().Lister()
+	handler.RKESystemImagesLister = managementContext.Management.RkeK8sSystemImages("").Controller().Lister()
+	handler.RKESystemImages = managementContext.Management.RkeK8sSystemImages("")
+	handler.ConfigMapLister = managementContext.Core.ConfigMaps("").Controller().Lister()
+	handler.SecretLister = managementContext.Core.Secrets("").Controller().Lister()
 
 	clusterValidator.CisConfigClient = managementContext.Management.CisConfigs(namespace.GlobalNamespace)
 	clusterValidator.CisConfigLister = managementContext.Management.CisConfigs(namespace.GlobalNamespace).Controller().Lister()
@@ -865,6 +869,7 @@ func ClusterTemplates(schemas *types.Schemas, management *config.ScaledContext)
 		ClusterTemplateLister:         management.Management.ClusterTemplates("").Controller().Lister(),
 		ClusterTemplateRevisionLister: management.Management.ClusterTemplateRevisions("").Controller().Lister(),
 		ClusterTemplateRevisions:      management.Management.ClusterTemplateRevisions(""),
+		ClusterLister:                 management.Management.Clusters("").Controller().Lister(),
 	}
 	wrapper.ClusterTemplateQuestions = wrapper.BuildQuestionsFromSchema(schemas.Schema(&managementschema.Version, client.ClusterSpecBaseType), schemas, "")
 
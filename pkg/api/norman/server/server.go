@@ -15,6 +15,7 @@
 	clusterSchema "github.com/rancher/rancher/pkg/schemas/cluster.cattle.io/v3"
 	managementSchema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
 	projectSchema "github.com/rancher/rancher/pkg/schemas/project.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/tracing"
 	"github.com/rancher/rancher/pkg/types/config"
 )
 
@@ -33,6 +34,8 @@ func New(ctx context.Context, scaledContext *config.ScaledContext, clusterManage
 		return nil, err
 	}
 
+	tracing.NormanSchemas(scaledContext.Schemas)
+
 	server, err := norman.NewServer(scaledContext.Schemas)
 	if err != nil {
 		return nil, err
@@ -393,6 +393,25 @@ func transposeNameFields(data map[string]interface{}, clusterConfigSchema *types
 	return data
 }
 
+// LoadTemplateUpdate returns the data of an update of an existing cluster to a clusterTemplateRevision of its
+// clusterTemplate, loaded from the revision with the answers of the data to its questions. The monitoring and alerting
+// flags of the cluster are kept as is, a revision does not turn them off.
+func LoadTemplateUpdate(clusterTemplateRevision *apimgmtv3.ClusterTemplateRevision, clusterTemplate *apimgmtv3.ClusterTemplate, data map[string]interface{}, clusterConfigSchema *types.Schema, existingCluster map[string]interface{}, secretLister v1.SecretLister) (map[string]interface{}, error) {
+	clusterUpdate, err := loadDataFromTemplate(clusterTemplateRevision, clusterTemplate, data, clusterConfigSchema, existingCluster, secretLister)
+	if err != nil {
+		return nil, err
+	}
+	clusterUpdate = cleanQuestions(clusterUpdate)
+
+	if !clusterTemplateRevision.Spec.ClusterConfig.EnableClusterMonitoring {
+		clusterUpdate[managementv3.ClusterSpecFieldEnableClusterMonitoring] = existingCluster[managementv3.ClusterSpecFieldEnableClusterMonitoring]
+	}
+	if !clusterTemplateRevision.Spec.ClusterConfig.EnableClusterAlerting {
+		clusterUpdate[managementv3.ClusterSpecFieldEnableClusterAlerting] = existingCluster[managementv3.ClusterSpecFieldEnableClusterAlerting]
+	}
+	return clusterUpdate, nil
+}
+
 func loadDataFromTemplate(clusterTemplateRevision *apimgmtv3.ClusterTemplateRevision, clusterTemplate *apimgmtv3.ClusterTemplate, data map[string]interface{}, clusterConfigSchema *types.Schema, existingCluster map[string]interface{}, secretLister v1.SecretLister) (map[string]interface{}, error) {
 	clusterConfig := *clusterTemplateRevision.Spec.ClusterConfig
 	clusterConfigSpec, err := assemblers.AssembleRKEConfigTemplateSpec(clusterTemplateRevision, apimgmtv3.ClusterSpec{ClusterSpecBase: clusterConfig}, secretLister)
@@ -739,57 +758,27 @@ func (r *Store) Update(apiContext *types.APIContext, schema *types.Schema, data
 		}
 
 		clusterConfigSchema := apiContext.Schemas.Schema(&managementschema.Version, managementv3.ClusterSpecBaseType)
-		clusterUpdate, err := loadDataFromTemplate(clusterTemplateRevision, clusterTemplate, data, clusterConfigSchema, existingCluster, r.SecretLister)
+		data, err = LoadTemplateUpdate(clusterTemplateRevision, clusterTemplate, data, clusterConfigSchema, existingCluster, r.SecretLister)
 		if err != nil {
 			return nil, err
 		}
-		clusterUpdate = cleanQuestions(clusterUpdate)
-
-		data = clusterUpdate
-
-		//keep monitoring and alerting flags on the cluster as is, no turning off these flags from templaterevision.
-		if !clusterTemplateRevision.Spec.ClusterConfig.EnableClusterMonitoring {
-			data[managementv3.ClusterSpecFieldEnableClusterMonitoring] = existingCluster[managementv3.ClusterSpecFieldEnableClusterMonitoring]
-		}
-		if !clusterTemplateRevision.Spec.ClusterConfig.EnableClusterAlerting {
-			data[managementv3.ClusterSpecFieldEnableClusterAlerting] = existingCluster[managementv3.ClusterSpecFieldEnableClusterAlerting]
-		}
-
 	} else if existingCluster[managementv3.ClusterSpecFieldClusterTemplateRevisionID] != nil {
 		return nil, httperror.NewFieldAPIError(httperror.MissingRequired, "ClusterTemplateRevision", "this cluster is created from a clusterTemplateRevision, please pass the clusterTemplateRevision")
 	}
 
-	err = setKubernetesVersion(data, false)
-	if err != nil {
-		return nil, err
-	}
-	enableCRIDockerd(data)
-	setInstanceMetadataHostname(data)
-	if err := setNodeUpgradeStrategy(data, existingCluster); err != nil {
-		return nil, err
-	}
 	data, err = r.transposeDynamicFieldToGenericConfig(data)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := validateNetworkFlag(data, false); err != nil {
-		return nil, httperror.NewFieldAPIError(httperror.InvalidOption, "enableNetworkPolicy", err.Error())
-	}
-
-	cleanPrivateRegistry(data)
 	dialer, err := r.DialerFactory.ClusterDialer(id)
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting dialer")
 	}
-	if err := validateUpdatedS3Credentials(existingCluster, data, dialer); err != nil {
-		return nil, err
-	}
-	if err := validateKeyRotation(data); err != nil {
-		return nil, err
+	cluster, err := r.ClusterLister.Get("", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster, try again %v", err)
 	}
-	handleScheduledScan(data)
-	if err := r.validateUnavailableNodes(data, existingCluster, id); err != nil {
+	if err := ValidateUpdate(data, existingCluster, cluster, r.NodeLister, dialer); err != nil {
 		return nil, err
 	}
 
@@ -1509,11 +1498,32 @@ func cleanPrivateRegistry(data map[string]interface{}) {
 	values.PutValue(data, updatedRegistries, "rancherKubernetesEngineConfig", "privateRegistries")
 }
 
-func (r *Store) validateUnavailableNodes(data, existingData map[string]interface{}, id string) error {
-	cluster, err := r.ClusterLister.Get("", id)
-	if err != nil {
-		return fmt.Errorf("error getting cluster, try again %v", err)
+// ValidateUpdate sets the defaults of the data of an update of an existing cluster and validates it, checking the cluster
+// has enough nodes ready when the update upgrades it.
+func ValidateUpdate(data, existingCluster map[string]interface{}, cluster *apimgmtv3.Cluster, nodeLister v3.NodeLister, dialer dialer.Dialer) error {
+	if err := setKubernetesVersion(data, false); err != nil {
+		return err
 	}
+	enableCRIDockerd(data)
+	setInstanceMetadataHostname(data)
+	if err := setNodeUpgradeStrategy(data, existingCluster); err != nil {
+		return err
+	}
+	if err := validateNetworkFlag(data, false); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidOption, "enableNetworkPolicy", err.Error())
+	}
+	cleanPrivateRegistry(data)
+	if err := validateUpdatedS3Credentials(existingCluster, data, dialer); err != nil {
+		return err
+	}
+	if err := validateKeyRotation(data); err != nil {
+		return err
+	}
+	handleScheduledScan(data)
+	return validateUnavailableNodes(data, existingCluster, cluster, nodeLister)
+}
+
+func validateUnavailableNodes(data, existingData map[string]interface{}, cluster *apimgmtv3.Cluster, nodeLister v3.NodeLister) error {
 	// no need to validate if cluster's already provisioning or upgrading
 	if !apimgmtv3.ClusterConditionProvisioned.IsTrue(cluster) ||
 		!apimgmtv3.ClusterConditionUpdated.IsTrue(cluster) ||
@@ -1531,7 +1541,7 @@ func (r *Store) validateUnavailableNodes(data, existingData map[string]interface
 	if reflect.DeepEqual(status, spec) {
 		return nil
 	}
-	nodes, err := r.NodeLister.List(id, labels.Everything())
+	nodes, err := nodeLister.List(cluster.Name, labels.Everything())
 	if err != nil {
 		return fmt.Errorf("error fetching nodes, try again %v", err)
 	}
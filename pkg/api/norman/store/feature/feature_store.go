@@ -3,6 +3,8 @@
 import (
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/values"
+	"github.com/rancher/rancher/pkg/features"
 )
 
 type Store struct {
@@ -18,3 +20,11 @@ func New(store types.Store) types.Store {
 func (s *Store) Delete(apiContext *types.APIContext, schema *types.Schema, id string) (map[string]interface{}, error) {
 	return nil, httperror.NewAPIError(httperror.MethodNotAllowed, "cannot delete features")
 }
+
+// Update records the user changing the feature, for the history of the feature.
+func (s *Store) Update(apiContext *types.APIContext, schema *types.Schema, data map[string]interface{}, id string) (map[string]interface{}, error) {
+	if user := apiContext.Request.Header.Get("Impersonate-User"); user != "" {
+		values.PutValue(data, user, "annotations", features.UpdatedByAnnotation)
+	}
+	return s.Store.Update(apiContext, schema, data, id)
+}
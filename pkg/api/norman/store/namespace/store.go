@@ -76,19 +76,19 @@ func (p *Store) validateResourceQuota(apiContext *types.APIContext, schema *type
 		projectID = ns.ProjectID
 	}
 	if projectID == "" {
-		return nil
+		return validateContainerResourceLimit(data, nil)
 	}
 	var project mgmtclient.Project
 	if err := access.ByID(apiContext, &mgmtschema.Version, mgmtclient.ProjectType, projectID, &project); err != nil {
 		return err
 	}
 	if project.ResourceQuota == nil {
-		return nil
+		return validateContainerResourceLimit(data, nil)
 	}
 	var nsQuota mgmtclient.NamespaceResourceQuota
 	if quota == nil {
 		if project.NamespaceDefaultResourceQuota == nil {
-			return nil
+			return validateContainerResourceLimit(data, nil)
 		}
 		nsQuota = *project.NamespaceDefaultResourceQuota
 	} else {
@@ -105,14 +105,17 @@ func (p *Store) validateResourceQuota(apiContext *types.APIContext, schema *type
 	if err != nil {
 		return err
 	}
+	if err := resourcequota.ValidateLimit(nsQuotaLimit); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidFormat, quotaField, err.Error())
+	}
 
 	// limits in namespace should include all limits defined on a project
-	projectQuotaLimitMap, err := convert.EncodeToMap(projectQuotaLimit)
+	projectQuotaLimitMap, err := resourcequota.LimitToMap(projectQuotaLimit)
 	if err != nil {
 		return err
 	}
 
-	nsQuotaLimitMap, err := convert.EncodeToMap(nsQuotaLimit)
+	nsQuotaLimitMap, err := resourcequota.LimitToMap(nsQuotaLimit)
 	if err != nil {
 		return err
 	}
@@ -160,6 +163,9 @@ func (p *Store) validateResourceQuota(apiContext *types.APIContext, schema *type
 	if len(crlMap) <= 0 {
 		data[containerResourceLimitField] = project.ContainerDefaultResourceLimit
 	}
+	if err := validateContainerResourceLimit(data, nsQuotaLimit); err != nil {
+		return err
+	}
 
 	isFit, exceeded, err := resourcequota.IsQuotaFit(nsQuotaLimit, nsLimits, projectQuotaLimit)
 	if err != nil || isFit {
@@ -169,6 +175,26 @@ func (p *Store) validateResourceQuota(apiContext *types.APIContext, schema *type
 	return httperror.NewFieldAPIError(httperror.MaxLimitExceeded, quotaField, fmt.Sprintf("exceeds projectLimit on fields: %s", format.ResourceList(exceeded)))
 }
 
+// validateContainerResourceLimit checks the limit range of the namespace, and that it fits in its quota.
+func validateContainerResourceLimit(data map[string]interface{}, quota *v32.ResourceQuotaLimit) error {
+	limitO := data[containerResourceLimitField]
+	if limitO == nil {
+		return nil
+	}
+	var limit v32.ContainerResourceLimit
+	if err := convert.ToObj(limitO, &limit); err != nil {
+		return err
+	}
+	if err := resourcequota.ValidateLimitRange(&limit); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidFormat, containerResourceLimitField, err.Error())
+	}
+	if err := resourcequota.ValidateLimitRangeQuota(&limit, quota); err != nil {
+		return httperror.NewFieldAPIError(httperror.MaxLimitExceeded, containerResourceLimitField,
+			fmt.Sprintf("does not fit in the %s: %v", quotaField, err))
+	}
+	return nil
+}
+
 func limitToLimit(from *mgmtclient.ResourceQuotaLimit) (*v32.ResourceQuotaLimit, error) {
 	var to v32.ResourceQuotaLimit
 	err := convert.ToObj(from, &to)
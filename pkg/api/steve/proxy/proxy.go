@@ -9,9 +9,11 @@
 	"time"
 
 	gmux "github.com/gorilla/mux"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
 	v3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	managementv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/tracing"
 	"github.com/rancher/remotedialer"
 	"github.com/rancher/steve/pkg/auth"
 	"github.com/rancher/steve/pkg/proxy"
@@ -28,6 +30,7 @@
 type Handler struct {
 	authorizer    authorizer.Authorizer
 	dialerFactory ClusterDialerFactory
+	guard         *circuit.Guard
 }
 
 type ClusterDialerFactory func(clusterID string) remotedialer.Dialer
@@ -48,7 +51,8 @@ func NewProxyMiddleware(sar v1.AuthorizationV1Interface,
 	dialerFactory ClusterDialerFactory,
 	clusters v3.ClusterCache,
 	localSupport bool,
-	localCluster http.Handler) (func(http.Handler) http.Handler, error) {
+	localCluster http.Handler,
+	guard *circuit.Guard) (func(http.Handler) http.Handler, error) {
 	cfg := authorizerfactory.DelegatingAuthorizerConfig{
 		SubjectAccessReviewClient: sar,
 		AllowCacheTTL:             time.Second * time.Duration(settings.AuthorizationCacheTTLSeconds.GetInt()),
@@ -61,7 +65,7 @@ func NewProxyMiddleware(sar v1.AuthorizationV1Interface,
 		return nil, err
 	}
 
-	proxyHandler := NewProxyHandler(authorizer, dialerFactory, clusters)
+	proxyHandler := NewProxyHandler(authorizer, dialerFactory, clusters, guard)
 
 	mux := gmux.NewRouter()
 	mux.UseEncodedPath()
@@ -112,10 +116,12 @@ func routeToShellProxy(key, value string, localSupport bool, localCluster http.H
 
 func NewProxyHandler(authorizer authorizer.Authorizer,
 	dialerFactory ClusterDialerFactory,
-	clusters v3.ClusterCache) *Handler {
+	clusters v3.ClusterCache,
+	guard *circuit.Guard) *Handler {
 	return &Handler{
 		authorizer:    authorizer,
 		dialerFactory: dialerFactory,
+		guard:         guard,
 	}
 }
 
@@ -169,7 +175,7 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	handler.ServeHTTP(rw, req)
+	h.guard.Handler(clusterID, handler).ServeHTTP(rw, req)
 }
 
 func (h *Handler) userCanAccessCluster(req *http.Request, clusterID string) bool {
@@ -213,8 +219,9 @@ func (h *Handler) next(clusterID, prefix string) (http.Handler, error) {
 		Host:      "http://" + clusterID,
 		UserAgent: rest.DefaultKubernetesUserAgent() + " cluster " + clusterID,
 		Transport: &http.Transport{
-			DialContext: h.dialer,
+			DialContext: tracing.DialContext(clusterID, h.guard.Dialer(clusterID, h.dialer)),
 		},
+		WrapTransport: tracing.Transport,
 	}
 
 	next := proxy.ImpersonatingHandler(prefix, cfg)
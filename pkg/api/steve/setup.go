@@ -10,11 +10,13 @@
 	"github.com/rancher/rancher/pkg/api/steve/navlinks"
 	"github.com/rancher/rancher/pkg/api/steve/settings"
 	"github.com/rancher/rancher/pkg/api/steve/userpreferences"
+	"github.com/rancher/rancher/pkg/tracing"
 	"github.com/rancher/rancher/pkg/wrangler"
 	steve "github.com/rancher/steve/pkg/server"
 )
 
 func Setup(ctx context.Context, server *steve.Server, config *wrangler.Context) error {
+	server.SchemaFactory.AddTemplate(tracing.SteveTemplate())
 	userpreferences.Register(server.BaseSchemas, server.ClientFactory)
 	if err := clusters.Register(ctx, server, config); err != nil {
 		return err
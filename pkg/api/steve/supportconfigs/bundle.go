@@ -0,0 +1,424 @@
+package supportconfigs
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/clusterconnected"
+	"github.com/rancher/rancher/pkg/features"
+	"github.com/rancher/rancher/pkg/managedcharts/cspadapter"
+	"github.com/rancher/rancher/pkg/peermanager"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/tunnelserver"
+	"github.com/rancher/rancher/pkg/version"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	manifestFile = "manifest.json"
+	// leaderLease is the lease the Rancher controllers are elected with.
+	leaderLease          = "cattle-controllers"
+	leaderLeaseNamespace = "kube-system"
+	eventsMaxAge         = time.Hour
+	eventsMaxCount       = 1000
+)
+
+var errNotCollected = errors.New("not collected")
+
+type settingLister interface {
+	List(selector labels.Selector) ([]*v3.Setting, error)
+}
+
+type featureLister interface {
+	List(selector labels.Selector) ([]*v3.Feature, error)
+}
+
+type clusterLister interface {
+	List(selector labels.Selector) ([]*v3.Cluster, error)
+}
+
+// bundleSources are the sources of the support bundle.
+type bundleSources struct {
+	settings    settingLister
+	features    featureLister
+	clusters    clusterLister
+	k8s         kubernetes.Interface
+	sessions    *tunnelserver.Sessions
+	peerManager peermanager.PeerManager
+}
+
+// bundleFile is a file of the support bundle. A file is left out if collect returns errNotCollected.
+type bundleFile struct {
+	name    string
+	collect func(ctx context.Context) ([]byte, error)
+}
+
+// manifest describes the content of a support bundle.
+type manifest struct {
+	GeneratedAt    time.Time         `json:"generatedAt"`
+	RancherVersion string            `json:"rancherVersion"`
+	RedactionRules []string          `json:"redactionRules"`
+	Files          []manifestEntry   `json:"files"`
+	Errors         map[string]string `json:"errors,omitempty"`
+	Skipped        map[string]string `json:"skipped,omitempty"`
+	Durations      map[string]string `json:"durations,omitempty"`
+}
+
+type manifestEntry struct {
+	Name   string `json:"name"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// writeBundle collects the files and writes them, followed by the manifest, to a tar archive. A file that fails to
+// be collected is recorded in the manifest and does not fail the bundle.
+func writeBundle(ctx context.Context, w io.Writer, files []bundleFile) error {
+	m := manifest{
+		GeneratedAt:    time.Now().UTC(),
+		RancherVersion: version.FriendlyVersion(),
+		RedactionRules: redactionRules,
+		Errors:         map[string]string{},
+		Skipped:        map[string]string{},
+		Durations:      map[string]string{},
+	}
+
+	tw := tar.NewWriter(w)
+	for _, file := range files {
+		start := time.Now()
+		body, err := file.collect(ctx)
+		m.Durations[file.name] = time.Since(start).Round(time.Millisecond).String()
+		if errors.Is(err, errNotCollected) {
+			m.Skipped[file.name] = err.Error()
+			continue
+		} else if err != nil {
+			m.Errors[file.name] = err.Error()
+			continue
+		}
+		if err := writeTarFile(tw, file.name, body, m.GeneratedAt); err != nil {
+			return err
+		}
+		digest := sha256.Sum256(body)
+		m.Files = append(m.Files, manifestEntry{
+			Name:   file.name,
+			Size:   len(body),
+			SHA256: hex.EncodeToString(digest[:]),
+		})
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, manifestFile, data, m.GeneratedAt); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, body []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(body)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(body)
+	return err
+}
+
+// jsonFile returns a file holding the redacted JSON of the value f collects.
+func jsonFile(name string, f func(ctx context.Context) (interface{}, error)) bundleFile {
+	return bundleFile{
+		name: name,
+		collect: func(ctx context.Context) ([]byte, error) {
+			value, err := f(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// round trip through JSON so that redaction applies to the same structure as is written
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			var generic interface{}
+			if err := json.Unmarshal(data, &generic); err != nil {
+				return nil, err
+			}
+			return json.MarshalIndent(redact(generic), "", "  ")
+		},
+	}
+}
+
+func (s *bundleSources) files(csp func(ctx context.Context) (interface{}, error)) []bundleFile {
+	return []bundleFile{
+		jsonFile("rancher/config.json", csp),
+		jsonFile("rancher/version.json", s.versions),
+		jsonFile("rancher/settings.json", s.settingValues),
+		jsonFile("rancher/features.json", s.featureValues),
+		jsonFile("rancher/clusters.json", s.clusterStatus),
+		jsonFile("rancher/leader.json", s.leader),
+		jsonFile("rancher/events.json", s.events),
+		{name: "rancher/profiles/goroutine.txt", collect: goroutineProfile},
+		{name: "rancher/profiles/heap.pprof", collect: heapProfile},
+	}
+}
+
+func (s *bundleSources) versions(ctx context.Context) (interface{}, error) {
+	result := map[string]interface{}{
+		"rancher":   version.Version,
+		"gitCommit": version.GitCommit,
+		"server":    settings.ServerVersion.Get(),
+		"go":        runtime.Version(),
+		"platform":  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	k8sVersion, err := s.k8s.Discovery().ServerVersion()
+	if err != nil {
+		result["kubernetesError"] = err.Error()
+	} else {
+		result["kubernetes"] = k8sVersion
+	}
+	return result, nil
+}
+
+type settingValue struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+func (s *bundleSources) settingValues(ctx context.Context) (interface{}, error) {
+	list, err := s.settings.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]settingValue, 0, len(list))
+	for _, setting := range list {
+		value := settingValue{
+			Name:    setting.Name,
+			Value:   setting.Value,
+			Default: setting.Default,
+			Source:  setting.Source,
+		}
+		// settings are redacted by name, as their values are not keyed by it
+		if sensitiveKey.MatchString(setting.Name) {
+			if value.Value != "" {
+				value.Value = redacted
+			}
+			if value.Default != "" {
+				value.Default = redacted
+			}
+		}
+		result = append(result, value)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+type featureValue struct {
+	Enabled bool  `json:"enabled"`
+	Value   *bool `json:"value"`
+	Default bool  `json:"default"`
+	Locked  *bool `json:"locked,omitempty"`
+	Dynamic bool  `json:"dynamic"`
+}
+
+func (s *bundleSources) featureValues(ctx context.Context) (interface{}, error) {
+	list, err := s.features.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]featureValue{}
+	for _, feature := range list {
+		result[feature.Name] = featureValue{
+			Enabled: features.IsEnabled(feature),
+			Value:   feature.Spec.Value,
+			Default: feature.Status.Default,
+			Locked:  feature.Status.LockedValue,
+			Dynamic: feature.Status.Dynamic,
+		}
+	}
+	return result, nil
+}
+
+type clusterStatus struct {
+	Name          string                     `json:"name"`
+	DisplayName   string                     `json:"displayName,omitempty"`
+	Driver        string                     `json:"driver,omitempty"`
+	Provider      string                     `json:"provider,omitempty"`
+	Version       string                     `json:"version,omitempty"`
+	AgentImage    string                     `json:"agentImage,omitempty"`
+	Ready         string                     `json:"ready"`
+	Connected     string                     `json:"connected"`
+	Conditions    []v3.ClusterCondition      `json:"conditions,omitempty"`
+	AgentSessions []tunnelserver.SessionInfo `json:"agentSessions,omitempty"`
+}
+
+// clusterStatus reports the clusters with their conditions and the agent sessions connected to this Rancher server.
+func (s *bundleSources) clusterStatus(ctx context.Context) (interface{}, error) {
+	list, err := s.clusters.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := map[string][]tunnelserver.SessionInfo{}
+	if s.sessions != nil {
+		for _, session := range s.sessions.List() {
+			if !session.Peer {
+				sessions[session.Cluster] = append(sessions[session.Cluster], session)
+			}
+		}
+	}
+
+	result := make([]clusterStatus, 0, len(list))
+	for _, cluster := range list {
+		status := clusterStatus{
+			Name:          cluster.Name,
+			DisplayName:   cluster.Spec.DisplayName,
+			Driver:        cluster.Status.Driver,
+			Provider:      cluster.Status.Provider,
+			AgentImage:    cluster.Status.AgentImage,
+			Ready:         string(v3.ClusterConditionReady.GetStatus(cluster)),
+			Connected:     string(clusterconnected.Connected.GetStatus(cluster)),
+			Conditions:    cluster.Status.Conditions,
+			AgentSessions: sessions[cluster.Name],
+		}
+		if cluster.Status.Version != nil {
+			status.Version = cluster.Status.Version.GitVersion
+		}
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// leader reports the holder of the controllers lease and the peers of this Rancher server.
+func (s *bundleSources) leader(ctx context.Context) (interface{}, error) {
+	result := map[string]interface{}{}
+	if s.peerManager != nil {
+		result["peers"] = s.peerManager.Peers()
+	}
+
+	lease, err := s.k8s.CoordinationV1().Leases(leaderLeaseNamespace).Get(ctx, leaderLease, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		result["leaseError"] = fmt.Sprintf("lease %s/%s not found", leaderLeaseNamespace, leaderLease)
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	result["lease"] = map[string]interface{}{
+		"holderIdentity":       lease.Spec.HolderIdentity,
+		"acquireTime":          lease.Spec.AcquireTime,
+		"renewTime":            lease.Spec.RenewTime,
+		"leaseDurationSeconds": lease.Spec.LeaseDurationSeconds,
+		"leaseTransitions":     lease.Spec.LeaseTransitions,
+	}
+	return result, nil
+}
+
+type event struct {
+	Namespace string                 `json:"namespace"`
+	Object    corev1.ObjectReference `json:"object"`
+	Type      string                 `json:"type"`
+	Reason    string                 `json:"reason"`
+	Message   string                 `json:"message"`
+	Count     int32                  `json:"count,omitempty"`
+	LastSeen  time.Time              `json:"lastSeen"`
+}
+
+// events reports the most recent events of the local cluster.
+func (s *bundleSources) events(ctx context.Context) (interface{}, error) {
+	list, err := s.k8s.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-eventsMaxAge)
+	var result []event
+	for _, e := range list.Items {
+		lastSeen := eventTime(e)
+		if lastSeen.Before(since) {
+			continue
+		}
+		result = append(result, event{
+			Namespace: e.Namespace,
+			Object:    e.InvolvedObject,
+			Type:      e.Type,
+			Reason:    e.Reason,
+			Message:   e.Message,
+			Count:     e.Count,
+			LastSeen:  lastSeen,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	if len(result) > eventsMaxCount {
+		result = result[:eventsMaxCount]
+	}
+	return result, nil
+}
+
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case e.Series != nil:
+		return e.Series.LastObservedTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+func goroutineProfile(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	err := pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	return buf.Bytes(), err
+}
+
+// heapProfile returns the heap profile as sampled by the runtime, in the gzipped protobuf format of pprof.
+func heapProfile(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	err := pprof.Lookup("heap").WriteTo(&buf, 0)
+	return buf.Bytes(), err
+}
+
+// cspConfig returns the configuration of the csp-adapter, or errNotCollected if the adapter is not installed.
+func (h *Handler) cspConfig(ctx context.Context) (interface{}, error) {
+	if h.adapterUtil != nil {
+		if _, err := h.adapterUtil.GetRelease(); errors.Is(err, cspadapter.ErrNotFound) {
+			return nil, fmt.Errorf("%w: csp-adapter is not installed", errNotCollected)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	config, err := h.getCSPConfig()
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("%w: configmap %s/%s not found", errNotCollected, cspadapter.ChartNamespace, cspAdapterConfigmap)
+	}
+	return config, err
+}
@@ -2,8 +2,8 @@
 package supportconfigs
 
 import (
-	"archive/tar"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -37,6 +37,7 @@ type Handler struct {
 	ConfigMaps           v1.ConfigMapInterface
 	SubjectAccessReviews authv1.SubjectAccessReviewInterface
 	adapterUtil          *cspadapter.ChartUtil
+	sources              *bundleSources
 }
 
 // NewHandler creates a handler using the clients defined in scaledContext
@@ -45,6 +46,14 @@ func NewHandler(scaledContext *config.ScaledContext) Handler {
 		ConfigMaps:           scaledContext.Core.ConfigMaps(cspadapter.ChartNamespace),
 		SubjectAccessReviews: scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews(),
 		adapterUtil:          cspadapter.NewChartUtil(scaledContext.Wrangler.RESTClientGetter),
+		sources: &bundleSources{
+			settings:    scaledContext.Wrangler.Mgmt.Setting().Cache(),
+			features:    scaledContext.Wrangler.Mgmt.Feature().Cache(),
+			clusters:    scaledContext.Wrangler.Mgmt.Cluster().Cache(),
+			k8s:         scaledContext.K8sClient,
+			sessions:    scaledContext.Wrangler.TunnelSessions,
+			peerManager: scaledContext.PeerManager,
+		},
 	}
 }
 
@@ -61,25 +70,10 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		util.ReturnHTTPError(writer, request, http.StatusForbidden, http.StatusText(http.StatusForbidden))
 		return
 	}
-	_, err = h.adapterUtil.GetRelease()
-	if err != nil {
-		if errors.Is(err, cspadapter.ErrNotFound) {
-			// if the adapter is not installed, return a 501 so the user knows to install the adapter
-			util.ReturnHTTPError(writer, request, http.StatusNotImplemented, "csp-adapter must be installed in order to to generate supportconfigs")
-			return
-		}
-		logrus.Warnf("[%s] Error when attempting to determine if adapter is installed, %s", logPrefix, err)
-		util.ReturnHTTPError(writer, request, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
-		return
-	}
 	logrus.Infof("[%s] Generating supportconfig", logPrefix)
-	archive, err := h.generateSupportConfig()
+	archive, err := h.generateSupportConfig(request.Context())
 	logrus.Infof("[%s] Done Generating supportconfig", logPrefix)
 	if err != nil {
-		if errors.Is(err, errNotFound) {
-			util.ReturnHTTPError(writer, request, http.StatusServiceUnavailable, "supportconfig not yet generated, try again later")
-			return
-		}
 		logrus.Errorf("[%s] Error when generating supportconfig %v", logPrefix, err)
 		util.ReturnHTTPError(writer, request, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
@@ -125,42 +119,16 @@ func (h *Handler) authorize(r *http.Request) (bool, error) {
 	return true, nil
 }
 
-// generateSupportConfig produces an io.Reader with the supportconfig ready to be returned using a http.ResponseWriter
-// Returns an err if it can't get the support config
-func (h *Handler) generateSupportConfig() (io.Reader, error) {
-	cspConfig, err := h.getCSPConfig()
-	// csp adapter won't always be installed
-	if err != nil {
-		return nil, err
-	}
-	configData, err := json.MarshalIndent(cspConfig, "", "  ")
-	if err != nil {
-		return nil, err
-	}
-
+// generateSupportConfig produces an io.Reader with the supportconfig ready to be returned using a http.ResponseWriter.
+// The supportconfig holds the csp-adapter config, if the adapter is installed, the redacted settings, feature flags,
+// cluster and agent connection status, leader election state, recent events, version information and runtime
+// profiles, along with a manifest listing them. Returns an err if the archive can't be written
+func (h *Handler) generateSupportConfig(ctx context.Context) (io.Reader, error) {
 	var buf bytes.Buffer
-	tw := tar.NewWriter(&buf)
-
-	files := map[string][]byte{
-		"rancher/config.json": configData,
-	}
-
-	for name, body := range files {
-		hdr := &tar.Header{
-			Name: name,
-			Mode: 0600,
-			Size: int64(len(body)),
-		}
-		if err := tw.WriteHeader(hdr); err != nil {
-			return nil, err
-		}
-		if _, err := tw.Write(body); err != nil {
-			return nil, err
-		}
+	if err := writeBundle(ctx, &buf, h.sources.files(h.cspConfig)); err != nil {
+		return nil, err
 	}
-
-	err = tw.Close()
-	return &buf, err
+	return &buf, nil
 }
 
 // getCSPConfig gets the configmap produced by the csp-adapter returns an error if not able to produce the map. Will return
@@ -0,0 +1,64 @@
+package supportconfigs
+
+import (
+	"regexp"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// sensitiveKey matches the keys and setting names whose values are redacted from support bundles.
+	sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private-?key|api-?key|access-?key|kubeconfig)`)
+	// urlUserInfo matches the user info of URLs in any value, e.g. the credentials of a proxy URL.
+	urlUserInfo = regexp.MustCompile(`://[^/@\s"]+@`)
+	// bearerToken matches bearer tokens in any value.
+	bearerToken = regexp.MustCompile(`(?i)bearer\s+[a-z0-9._~+/=:-]+`)
+)
+
+// redactionRules describes the redaction applied to every file of the bundle, for the manifest.
+var redactionRules = []string{
+	"values of keys and setting names matching " + sensitiveKey.String(),
+	"user info of URLs matching " + urlUserInfo.String(),
+	"bearer tokens matching " + bearerToken.String(),
+}
+
+// redact returns a copy of a JSON like value with the values of sensitive keys and the credentials embedded in strings
+// replaced.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if sensitiveKey.MatchString(key) && !empty(item) {
+				result[key] = redacted
+				continue
+			}
+			result[key] = redact(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = redact(item)
+		}
+		return result
+	case string:
+		return redactString(v)
+	}
+	return value
+}
+
+func redactString(s string) string {
+	s = urlUserInfo.ReplaceAllString(s, "://"+redacted+"@")
+	return bearerToken.ReplaceAllString(s, "Bearer "+redacted)
+}
+
+func empty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	}
+	return false
+}
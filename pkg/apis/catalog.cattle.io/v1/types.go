@@ -32,6 +32,16 @@ type RepoSpec struct {
 	// GitBranch The git branch to follow
 	GitBranch string `json:"gitBranch,omitempty"`
 
+	// GitSubPath the directory of the git repo to index, the root of the repo if empty
+	GitSubPath string `json:"gitSubPath,omitempty"`
+
+	// GitTagSemver a semver constraint, if set the highest tag matching it is followed instead of GitBranch
+	GitTagSemver string `json:"gitTagSemver,omitempty"`
+
+	// GitAdditionalBranches git branches to index next to GitBranch. Their chart versions are annotated with
+	// catalog.cattle.io/git-branch, a version that GitBranch also has is taken from GitBranch
+	GitAdditionalBranches []string `json:"gitAdditionalBranches,omitempty"`
+
 	// CABundle is a PEM encoded CA bundle which will be used to validate the repo's certificate.
 	// If unspecified, system trust roots will be used.
 	CABundle []byte `json:"caBundle,omitempty"`
@@ -93,6 +103,18 @@ type RepoStatus struct {
 	// The git commit used to generate the index
 	Commit string `json:"commit,omitempty"`
 
+	// The git tag used for the last successful index when following tags
+	Tag string `json:"tag,omitempty"`
+
+	// The semver constraint used to select the tag of the last successful index
+	TagSemver string `json:"tagSemver,omitempty"`
+
+	// The directory of the git repo used for the last successful index
+	SubPath string `json:"subPath,omitempty"`
+
+	// The git commits of the additional branches used to generate the index, keyed by branch
+	BranchCommits map[string]string `json:"branchCommits,omitempty"`
+
 	Conditions []genericcondition.GenericCondition `json:"conditions,omitempty"`
 }
 
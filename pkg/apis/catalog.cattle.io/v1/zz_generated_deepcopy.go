@@ -406,6 +406,11 @@ func (in *ReleaseStatus) DeepCopy() *ReleaseStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoSpec) DeepCopyInto(out *RepoSpec) {
 	*out = *in
+	if in.GitAdditionalBranches != nil {
+		in, out := &in.GitAdditionalBranches, &out.GitAdditionalBranches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.CABundle != nil {
 		in, out := &in.CABundle, &out.CABundle
 		*out = make([]byte, len(*in))
@@ -442,6 +447,13 @@ func (in *RepoSpec) DeepCopy() *RepoSpec {
 func (in *RepoStatus) DeepCopyInto(out *RepoStatus) {
 	*out = *in
 	in.DownloadTime.DeepCopyInto(&out.DownloadTime)
+	if in.BranchCommits != nil {
+		in, out := &in.BranchCommits, &out.BranchCommits
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]genericcondition.GenericCondition, len(*in))
@@ -40,9 +40,10 @@ func (p *Project) ObjClusterName() string {
 }
 
 type ProjectStatus struct {
-	Conditions                    []ProjectCondition `json:"conditions"`
-	PodSecurityPolicyTemplateName string             `json:"podSecurityPolicyTemplateId"`
-	MonitoringStatus              *MonitoringStatus  `json:"monitoringStatus,omitempty" norman:"nocreate,noupdate"`
+	Conditions                    []ProjectCondition         `json:"conditions"`
+	PodSecurityPolicyTemplateName string                     `json:"podSecurityPolicyTemplateId"`
+	MonitoringStatus              *MonitoringStatus          `json:"monitoringStatus,omitempty" norman:"nocreate,noupdate"`
+	ResourceQuotaUsage            *ProjectResourceQuotaUsage `json:"resourceQuotaUsage,omitempty" norman:"nocreate,noupdate"`
 }
 
 type ProjectCondition struct {
@@ -68,6 +69,7 @@ type ProjectSpec struct {
 	NamespaceDefaultResourceQuota *NamespaceResourceQuota `json:"namespaceDefaultResourceQuota,omitempty"`
 	ContainerDefaultResourceLimit *ContainerResourceLimit `json:"containerDefaultResourceLimit,omitempty"`
 	EnableProjectMonitoring       bool                    `json:"enableProjectMonitoring" norman:"default=false"`
+	ResourceQuotaAlert            *ResourceQuotaAlert     `json:"resourceQuotaAlert,omitempty"`
 }
 
 func (p *ProjectSpec) ObjClusterName() string {
@@ -192,6 +194,107 @@ func (c *ClusterRoleTemplateBinding) ObjClusterName() string {
 	return c.ClusterName
 }
 
+const (
+	// RoleTemplateBindingRequestPending is the phase of a request waiting for an approver.
+	RoleTemplateBindingRequestPending = "Pending"
+	// RoleTemplateBindingRequestApproved is the phase of an approved request whose binding is not created yet.
+	RoleTemplateBindingRequestApproved = "Approved"
+	// RoleTemplateBindingRequestDenied is the phase of a request an approver denied.
+	RoleTemplateBindingRequestDenied = "Denied"
+	// RoleTemplateBindingRequestActive is the phase of a request whose binding exists.
+	RoleTemplateBindingRequestActive = "Active"
+	// RoleTemplateBindingRequestExpired is the phase of a request whose binding expired and was removed.
+	RoleTemplateBindingRequestExpired = "Expired"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RoleTemplateBindingRequest asks for a ClusterRoleTemplateBinding, or a ProjectRoleTemplateBinding when a project is
+// set, that is only created once an approver approves the request. It lives in the namespace of the cluster.
+type RoleTemplateBindingRequest struct {
+	types.Namespaced
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RoleTemplateBindingRequestSpec   `json:"spec"`
+	Status RoleTemplateBindingRequestStatus `json:"status"`
+}
+
+func (r *RoleTemplateBindingRequest) ObjClusterName() string {
+	return r.Spec.ClusterName
+}
+
+type RoleTemplateBindingRequestSpec struct {
+	UserName           string `json:"userName,omitempty"`
+	GroupPrincipalName string `json:"groupPrincipalName,omitempty"`
+	ClusterName        string `json:"clusterName"`
+	// ProjectName is the project the binding is for, in the clusterName:projectName format, empty for a cluster
+	// binding.
+	ProjectName      string `json:"projectName,omitempty"`
+	RoleTemplateName string `json:"roleTemplateName"`
+	// Duration is how long the binding lasts once the request is approved. The binding does not expire if it is zero.
+	Duration metav1.Duration `json:"duration,omitempty"`
+	Reason   string          `json:"reason,omitempty"`
+}
+
+type RoleTemplateBindingRequestStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// DecidedBy is the user who approved or denied the request, at DecidedAt.
+	DecidedBy string `json:"decidedBy,omitempty"`
+	DecidedAt string `json:"decidedAt,omitempty"`
+	Message   string `json:"message,omitempty"`
+	// BindingName is the namespace:name of the binding created for the request.
+	BindingName string `json:"bindingName,omitempty"`
+	// ExpiresAt is the RFC3339 time the binding expires at, set once when the request is approved.
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RBACDriftReport lists the differences between the RoleBindings and ClusterRoleBindings of a cluster and the ones
+// its ClusterRoleTemplateBindings and ProjectRoleTemplateBindings grant. There is one per cluster, in the namespace of
+// the cluster.
+type RBACDriftReport struct {
+	types.Namespaced
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status RBACDriftReportStatus `json:"status"`
+}
+
+func (r *RBACDriftReport) ObjClusterName() string {
+	return r.Status.ClusterName
+}
+
+type RBACDriftReportStatus struct {
+	ClusterName string `json:"clusterName,omitempty"`
+	CheckedAt   string `json:"checkedAt,omitempty"`
+	// Missing are the bindings granted by a ClusterRoleTemplateBinding or ProjectRoleTemplateBinding that do not exist.
+	Missing []RBACDriftBinding `json:"missing,omitempty"`
+	// Modified are the granted bindings whose role, subjects or owner label differ from the granted ones.
+	Modified []RBACDriftBinding `json:"modified,omitempty"`
+	// Unmanaged are the bindings to the ClusterRoles of RoleTemplates that no ClusterRoleTemplateBinding or
+	// ProjectRoleTemplateBinding grants.
+	Unmanaged []RBACDriftBinding `json:"unmanaged,omitempty"`
+	// Repaired is the number of missing, modified and stale bindings the last check repaired.
+	Repaired int    `json:"repaired,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type RBACDriftBinding struct {
+	// Kind is ClusterRoleBinding or RoleBinding.
+	Kind      string           `json:"kind"`
+	Namespace string           `json:"namespace,omitempty"`
+	Name      string           `json:"name"`
+	RoleName  string           `json:"roleName,omitempty"`
+	Subjects  []rbacv1.Subject `json:"subjects,omitempty"`
+	// Owner is the namespace_name of the ClusterRoleTemplateBinding or ProjectRoleTemplateBinding granting the binding.
+	Owner  string `json:"owner,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
 type SetPodSecurityPolicyTemplateInput struct {
 	PodSecurityPolicyTemplateName string `json:"podSecurityPolicyTemplateId" norman:"required,type=reference[podSecurityPolicyTemplate]"`
 }
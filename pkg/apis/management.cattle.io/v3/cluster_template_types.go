@@ -13,6 +13,12 @@
 	ClusterTemplateRevisionConditionRKESecretsMigrated condition.Cond = "RKESecretsMigrated"
 )
 
+const (
+	ClusterTemplateMigrationStateMigrating = "Migrating"
+	ClusterTemplateMigrationStateCompleted = "Completed"
+	ClusterTemplateMigrationStateFailed    = "Failed"
+)
+
 type ClusterTemplateRevisionConditionType string
 
 type ClusterTemplateRevisionCondition struct {
@@ -94,8 +100,55 @@ type ClusterTemplateRevisionStatus struct {
 	KubeletExtraEnvSecret            string                             `json:"kubeletExtraEnvSecret,omitempty" norman:"nocreate,noupdate"`
 	PrivateRegistryECRSecret         string                             `json:"privateRegistryECRSecret,omitempty" norman:"nocreate,noupdate"`
 	Conditions                       []ClusterTemplateRevisionCondition `json:"conditions,omitempty"`
+	// Migration is the last migration of clusters to the revision.
+	Migration *ClusterTemplateMigration `json:"migration,omitempty" norman:"nocreate,noupdate"`
+}
+
+// ClusterTemplateMigration is a staged migration of clusters to a revision of their template. The clusters are
+// migrated a batch at a time, the next batch being started once the clusters of the previous one are provisioned with
+// the revision, and a cluster failing to be migrated stops the migration.
+type ClusterTemplateMigration struct {
+	// ClusterNames are the clusters to migrate, in the order they are migrated.
+	ClusterNames []string `json:"clusterNames,omitempty"`
+	BatchSize    int      `json:"batchSize,omitempty"`
+	State        string   `json:"state,omitempty"`
+	Message      string   `json:"message,omitempty"`
+	// Migrating are the clusters of the current batch.
+	Migrating   []string                          `json:"migrating,omitempty"`
+	Migrated    []string                          `json:"migrated,omitempty"`
+	Failed      []ClusterTemplateMigrationFailure `json:"failed,omitempty"`
+	StartedAt   string                            `json:"startedAt,omitempty"`
+	CompletedAt string                            `json:"completedAt,omitempty"`
+}
+
+type ClusterTemplateMigrationFailure struct {
+	ClusterName string `json:"clusterName,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type ClusterTemplateMigrationInput struct {
+	ClusterNames []string `json:"clusterNames,omitempty" norman:"required"`
+	BatchSize    int      `json:"batchSize,omitempty" norman:"default=1,min=1"`
 }
 
 type ClusterTemplateQuestionsOutput struct {
 	Questions []Question `json:"questions,omitempty"`
 }
+
+// ClusterTemplateComplianceOutput lists the clusters of a template by revision.
+type ClusterTemplateComplianceOutput struct {
+	DefaultRevisionName string                              `json:"defaultRevisionName,omitempty"`
+	Revisions           []ClusterTemplateRevisionCompliance `json:"revisions,omitempty"`
+	// DeprecatedClusterNames are the clusters using disabled revisions, from which no cluster can be created.
+	DeprecatedClusterNames []string `json:"deprecatedClusterNames,omitempty"`
+}
+
+type ClusterTemplateRevisionCompliance struct {
+	RevisionName string `json:"revisionName,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	Enabled      bool   `json:"enabled"`
+	Default      bool   `json:"default"`
+	// RevisionsBehind is the number of enabled revisions of the template created after the revision.
+	RevisionsBehind int      `json:"revisionsBehind"`
+	ClusterNames    []string `json:"clusterNames,omitempty"`
+}
@@ -39,6 +39,7 @@ func init() {
 	ClusterActionRotateEncryptionKey   = "rotateEncryptionKey"
 	ClusterActionRunSecurityScan       = "runSecurityScan"
 	ClusterActionSaveAsTemplate        = "saveAsTemplate"
+	ClusterActionCheckImages           = "checkImages"
 
 	// ClusterConditionReady Cluster ready to serve API (healthy when true, unhealthy when false)
 	ClusterConditionReady          condition.Cond = "Ready"
@@ -364,6 +365,26 @@ type RotateEncryptionKeyOutput struct {
 	Message string `json:"message,omitempty"`
 }
 
+type CheckImagesInput struct {
+	// KubernetesVersion to check the images of, defaults to the version the cluster runs.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+}
+
+type CheckImagesOutput struct {
+	Registry          string        `json:"registry,omitempty"`
+	KubernetesVersion string        `json:"kubernetesVersion,omitempty"`
+	Ready             bool          `json:"ready"`
+	Missing           []string      `json:"missing,omitempty"`
+	Images            []ImageStatus `json:"images,omitempty"`
+}
+
+type ImageStatus struct {
+	Image   string   `json:"image,omitempty"`
+	Sources []string `json:"sources,omitempty"`
+	Present bool     `json:"present"`
+	Error   string   `json:"error,omitempty"`
+}
+
 type LocalClusterAuthEndpoint struct {
 	Enabled bool   `json:"enabled"`
 	FQDN    string `json:"fqdn,omitempty"`
@@ -32,6 +32,8 @@ type Feature struct {
 
 type FeatureSpec struct {
 	Value *bool `json:"value" norman:"required"`
+	// EnableAt is the time at which the feature is enabled, so that a feature can be turned on during a planned window.
+	EnableAt *metav1.Time `json:"enableAt,omitempty"`
 }
 
 type FeatureStatus struct {
@@ -39,4 +41,20 @@ type FeatureStatus struct {
 	Default     bool   `json:"default"`
 	Description string `json:"description"`
 	LockedValue *bool  `json:"lockedValue"`
+	// Dependencies are the features that must be enabled for this feature to be enabled.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// History records the latest changes of the effective value of the feature, oldest first.
+	History []FeatureTransition `json:"history,omitempty"`
+	// EffectiveValue is the effective value the feature was last seen with, the changes from which are recorded in
+	// History.
+	EffectiveValue *bool `json:"effectiveValue,omitempty"`
+	// ScheduleFailure is why the feature could not be enabled at EnableAt. The enablement is retried until it
+	// succeeds or EnableAt is removed.
+	ScheduleFailure string `json:"scheduleFailure,omitempty"`
+}
+
+type FeatureTransition struct {
+	Value bool        `json:"value"`
+	User  string      `json:"user,omitempty"`
+	Time  metav1.Time `json:"time"`
 }
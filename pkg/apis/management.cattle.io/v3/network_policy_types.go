@@ -10,6 +10,44 @@
 type ProjectNetworkPolicySpec struct {
 	ProjectName string `json:"projectName,omitempty" norman:"required,type=reference[project]"`
 	Description string `json:"description"`
+	// Template is rendered into a NetworkPolicy in every namespace of the project. The default policy of a project,
+	// isolating it from the other projects, has no template.
+	Template *ProjectNetworkPolicyTemplate `json:"template,omitempty"`
+}
+
+// ProjectNetworkPolicyTemplate is a NetworkPolicy applied to every namespace of a project, whose peers can be
+// projects.
+type ProjectNetworkPolicyTemplate struct {
+	// PodSelector selects the pods of each namespace the policy applies to, all of them when empty.
+	PodSelector map[string]string `json:"podSelector,omitempty"`
+	// PolicyTypes are the directions of the traffic the policy isolates, Ingress and Egress. They default to the
+	// directions the policy has rules for.
+	PolicyTypes []string `json:"policyTypes,omitempty" norman:"type=array[enum],options=Ingress|Egress"`
+	// Ingress are the rules of the ingress traffic allowed to the selected pods.
+	Ingress []ProjectNetworkPolicyRule `json:"ingress,omitempty"`
+	// Egress are the rules of the egress traffic allowed from the selected pods.
+	Egress []ProjectNetworkPolicyRule `json:"egress,omitempty"`
+	// AllowDNS allows the egress traffic to the DNS port of any destination, on UDP and TCP.
+	AllowDNS bool `json:"allowDns,omitempty"`
+}
+
+// ProjectNetworkPolicyRule allows the traffic from or to its peers on its ports. A rule without peers allows the
+// traffic of all peers, and a rule without ports allows the traffic on all ports.
+type ProjectNetworkPolicyRule struct {
+	// ProjectNames are the projects of the cluster whose namespaces are peers.
+	ProjectNames []string `json:"projectNames,omitempty" norman:"type=array[reference[project]]"`
+	// CIDRs are the IP blocks that are peers.
+	CIDRs []string `json:"cidrs,omitempty"`
+	// ExceptCIDRs are the IP blocks within CIDRs that are not peers.
+	ExceptCIDRs []string                   `json:"exceptCidrs,omitempty"`
+	Ports       []ProjectNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+type ProjectNetworkPolicyPort struct {
+	Protocol string `json:"protocol,omitempty" norman:"options=TCP|UDP|SCTP,default=TCP"`
+	Port     int32  `json:"port,omitempty"`
+	// EndPort makes the rule allow the range of ports from Port to EndPort.
+	EndPort int32 `json:"endPort,omitempty"`
 }
 
 func (p *ProjectNetworkPolicySpec) ObjClusterName() string {
@@ -23,6 +23,39 @@ type ResourceQuotaLimit struct {
 	RequestsStorage        string `json:"requestsStorage,omitempty"`
 	LimitsCPU              string `json:"limitsCpu,omitempty"`
 	LimitsMemory           string `json:"limitsMemory,omitempty"`
+
+	// Extended holds quotas on resources without a field of their own, keyed by their ResourceQuota name, such as
+	// requests.nvidia.com/gpu, count/deployments.apps or gold.storageclass.storage.k8s.io/requests.storage.
+	Extended map[string]string `json:"extended,omitempty"`
+}
+
+// ResourceQuotaAlert configures the events raised when the usage of a project quota crosses a threshold.
+type ResourceQuotaAlert struct {
+	// Thresholds are the percentages of the project quota whose crossing by the usage of a resource raises an event.
+	// They default to the resource-quota-alert-thresholds setting.
+	Thresholds []int `json:"thresholds,omitempty" norman:"min=1,max=100"`
+	// Recipients are sent the events through their notifier.
+	Recipients []Recipient `json:"recipients,omitempty"`
+}
+
+// ProjectResourceQuotaUsage is a snapshot of the quota of a project, of how much of it is allocated to its namespaces
+// and of how much of it their pods actually use. It is updated at most once a minute, when the usage of a resource
+// changes by 5% of its limit, and right away when a threshold is crossed.
+type ProjectResourceQuotaUsage struct {
+	Limit      ResourceQuotaLimit            `json:"limit,omitempty"`
+	Allocated  ResourceQuotaLimit            `json:"allocated,omitempty"`
+	Used       ResourceQuotaLimit            `json:"used,omitempty"`
+	Namespaces []NamespaceResourceQuotaUsage `json:"namespaces,omitempty"`
+	// Thresholds are, by resource, the highest alert threshold crossed by its usage.
+	Thresholds map[string]int `json:"thresholds,omitempty"`
+	UpdatedAt  string         `json:"updatedAt,omitempty"`
+}
+
+// NamespaceResourceQuotaUsage is a snapshot of the quota of a namespace and of how much of it its pods actually use.
+type NamespaceResourceQuotaUsage struct {
+	Namespace string             `json:"namespace,omitempty"`
+	Limit     ResourceQuotaLimit `json:"limit,omitempty"`
+	Used      ResourceQuotaLimit `json:"used,omitempty"`
 }
 
 type ContainerResourceLimit struct {
@@ -30,4 +63,28 @@ type ContainerResourceLimit struct {
 	RequestsMemory string `json:"requestsMemory,omitempty"`
 	LimitsCPU      string `json:"limitsCpu,omitempty"`
 	LimitsMemory   string `json:"limitsMemory,omitempty"`
+
+	// Container bounds the resources of each container.
+	Container *ResourceLimitBounds `json:"container,omitempty"`
+	// Pod bounds the resources of all the containers of a pod.
+	Pod *ResourceLimitBounds `json:"pod,omitempty"`
+	// PersistentVolumeClaim bounds the storage requested by each persistent volume claim.
+	PersistentVolumeClaim *StorageLimitBounds `json:"persistentVolumeClaim,omitempty"`
+}
+
+// ResourceLimitBounds are the min and max of the requests and limits of the cpu and memory of containers or pods, and
+// the max ratio of their limit to their request.
+type ResourceLimitBounds struct {
+	MinCPU                     string `json:"minCpu,omitempty"`
+	MinMemory                  string `json:"minMemory,omitempty"`
+	MaxCPU                     string `json:"maxCpu,omitempty"`
+	MaxMemory                  string `json:"maxMemory,omitempty"`
+	MaxLimitRequestRatioCPU    string `json:"maxLimitRequestRatioCpu,omitempty"`
+	MaxLimitRequestRatioMemory string `json:"maxLimitRequestRatioMemory,omitempty"`
+}
+
+// StorageLimitBounds are the min and max of the storage requested by persistent volume claims.
+type StorageLimitBounds struct {
+	MinStorage string `json:"minStorage,omitempty"`
+	MaxStorage string `json:"maxStorage,omitempty"`
 }
@@ -1147,6 +1147,50 @@ func (in *ChangePasswordInput) DeepCopy() *ChangePasswordInput {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckImagesInput) DeepCopyInto(out *CheckImagesInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckImagesInput.
+func (in *CheckImagesInput) DeepCopy() *CheckImagesInput {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckImagesInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckImagesOutput) DeepCopyInto(out *CheckImagesOutput) {
+	*out = *in
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImageStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckImagesOutput.
+func (in *CheckImagesOutput) DeepCopy() *CheckImagesOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckImagesOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CisBenchmarkVersion) DeepCopyInto(out *CisBenchmarkVersion) {
 	*out = *in
@@ -2630,6 +2674,34 @@ func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateComplianceOutput) DeepCopyInto(out *ClusterTemplateComplianceOutput) {
+	*out = *in
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]ClusterTemplateRevisionCompliance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeprecatedClusterNames != nil {
+		in, out := &in.DeprecatedClusterNames, &out.DeprecatedClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateComplianceOutput.
+func (in *ClusterTemplateComplianceOutput) DeepCopy() *ClusterTemplateComplianceOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateComplianceOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
 	*out = *in
@@ -2663,6 +2735,79 @@ func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateMigration) DeepCopyInto(out *ClusterTemplateMigration) {
+	*out = *in
+	if in.ClusterNames != nil {
+		in, out := &in.ClusterNames, &out.ClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Migrating != nil {
+		in, out := &in.Migrating, &out.Migrating
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Migrated != nil {
+		in, out := &in.Migrated, &out.Migrated
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]ClusterTemplateMigrationFailure, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateMigration.
+func (in *ClusterTemplateMigration) DeepCopy() *ClusterTemplateMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateMigrationFailure) DeepCopyInto(out *ClusterTemplateMigrationFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateMigrationFailure.
+func (in *ClusterTemplateMigrationFailure) DeepCopy() *ClusterTemplateMigrationFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateMigrationFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateMigrationInput) DeepCopyInto(out *ClusterTemplateMigrationInput) {
+	*out = *in
+	if in.ClusterNames != nil {
+		in, out := &in.ClusterNames, &out.ClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateMigrationInput.
+func (in *ClusterTemplateMigrationInput) DeepCopy() *ClusterTemplateMigrationInput {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateMigrationInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterTemplateQuestionsOutput) DeepCopyInto(out *ClusterTemplateQuestionsOutput) {
 	*out = *in
@@ -2715,6 +2860,27 @@ func (in *ClusterTemplateRevision) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevisionCompliance) DeepCopyInto(out *ClusterTemplateRevisionCompliance) {
+	*out = *in
+	if in.ClusterNames != nil {
+		in, out := &in.ClusterNames, &out.ClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionCompliance.
+func (in *ClusterTemplateRevisionCompliance) DeepCopy() *ClusterTemplateRevisionCompliance {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevisionCompliance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterTemplateRevisionCondition) DeepCopyInto(out *ClusterTemplateRevisionCondition) {
 	*out = *in
@@ -2805,6 +2971,11 @@ func (in *ClusterTemplateRevisionStatus) DeepCopyInto(out *ClusterTemplateRevisi
 		*out = make([]ClusterTemplateRevisionCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(ClusterTemplateMigration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3098,6 +3269,21 @@ func (in *Condition) DeepCopy() *Condition {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerResourceLimit) DeepCopyInto(out *ContainerResourceLimit) {
 	*out = *in
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(ResourceLimitBounds)
+		**out = **in
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(ResourceLimitBounds)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(StorageLimitBounds)
+		**out = **in
+	}
 	return
 }
 
@@ -3567,6 +3753,10 @@ func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EnableAt != nil {
+		in, out := &in.EnableAt, &out.EnableAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -3588,6 +3778,23 @@ func (in *FeatureStatus) DeepCopyInto(out *FeatureStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]FeatureTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EffectiveValue != nil {
+		in, out := &in.EffectiveValue, &out.EffectiveValue
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -3601,6 +3808,23 @@ func (in *FeatureStatus) DeepCopy() *FeatureStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureTransition) DeepCopyInto(out *FeatureTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureTransition.
+func (in *FeatureTransition) DeepCopy() *FeatureTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Field) DeepCopyInto(out *Field) {
 	*out = *in
@@ -4760,6 +4984,27 @@ func (in *HTTPClientConfig) DeepCopy() *HTTPClientConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImportClusterYamlInput) DeepCopyInto(out *ImportClusterYamlInput) {
 	*out = *in
@@ -6065,7 +6310,7 @@ func (in *MultiClusterAppStatus) DeepCopy() *MultiClusterAppStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespaceResourceQuota) DeepCopyInto(out *NamespaceResourceQuota) {
 	*out = *in
-	out.Limit = in.Limit
+	in.Limit.DeepCopyInto(&out.Limit)
 	return
 }
 
@@ -6079,6 +6324,24 @@ func (in *NamespaceResourceQuota) DeepCopy() *NamespaceResourceQuota {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceResourceQuotaUsage) DeepCopyInto(out *NamespaceResourceQuotaUsage) {
+	*out = *in
+	in.Limit.DeepCopyInto(&out.Limit)
+	in.Used.DeepCopyInto(&out.Used)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceResourceQuotaUsage.
+func (in *NamespaceResourceQuotaUsage) DeepCopy() *NamespaceResourceQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceResourceQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Node) DeepCopyInto(out *Node) {
 	*out = *in
@@ -8157,7 +8420,7 @@ func (in *ProjectNetworkPolicy) DeepCopyInto(out *ProjectNetworkPolicy) {
 	out.Namespaced = in.Namespaced
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(ProjectNetworkPolicyStatus)
@@ -8217,9 +8480,66 @@ func (in *ProjectNetworkPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectNetworkPolicyPort) DeepCopyInto(out *ProjectNetworkPolicyPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyPort.
+func (in *ProjectNetworkPolicyPort) DeepCopy() *ProjectNetworkPolicyPort {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectNetworkPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectNetworkPolicyRule) DeepCopyInto(out *ProjectNetworkPolicyRule) {
+	*out = *in
+	if in.ProjectNames != nil {
+		in, out := &in.ProjectNames, &out.ProjectNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExceptCIDRs != nil {
+		in, out := &in.ExceptCIDRs, &out.ExceptCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ProjectNetworkPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyRule.
+func (in *ProjectNetworkPolicyRule) DeepCopy() *ProjectNetworkPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectNetworkPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectNetworkPolicySpec) DeepCopyInto(out *ProjectNetworkPolicySpec) {
 	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(ProjectNetworkPolicyTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8249,11 +8569,53 @@ func (in *ProjectNetworkPolicyStatus) DeepCopy() *ProjectNetworkPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectNetworkPolicyTemplate) DeepCopyInto(out *ProjectNetworkPolicyTemplate) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PolicyTypes != nil {
+		in, out := &in.PolicyTypes, &out.PolicyTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]ProjectNetworkPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]ProjectNetworkPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectNetworkPolicyTemplate.
+func (in *ProjectNetworkPolicyTemplate) DeepCopy() *ProjectNetworkPolicyTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectNetworkPolicyTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectResourceQuota) DeepCopyInto(out *ProjectResourceQuota) {
 	*out = *in
-	out.Limit = in.Limit
-	out.UsedLimit = in.UsedLimit
+	in.Limit.DeepCopyInto(&out.Limit)
+	in.UsedLimit.DeepCopyInto(&out.UsedLimit)
 	return
 }
 
@@ -8267,6 +8629,39 @@ func (in *ProjectResourceQuota) DeepCopy() *ProjectResourceQuota {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectResourceQuotaUsage) DeepCopyInto(out *ProjectResourceQuotaUsage) {
+	*out = *in
+	in.Limit.DeepCopyInto(&out.Limit)
+	in.Allocated.DeepCopyInto(&out.Allocated)
+	in.Used.DeepCopyInto(&out.Used)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespaceResourceQuotaUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectResourceQuotaUsage.
+func (in *ProjectResourceQuotaUsage) DeepCopy() *ProjectResourceQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectResourceQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectRoleTemplateBinding) DeepCopyInto(out *ProjectRoleTemplateBinding) {
 	*out = *in
@@ -8333,17 +8728,22 @@ func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	if in.ResourceQuota != nil {
 		in, out := &in.ResourceQuota, &out.ResourceQuota
 		*out = new(ProjectResourceQuota)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.NamespaceDefaultResourceQuota != nil {
 		in, out := &in.NamespaceDefaultResourceQuota, &out.NamespaceDefaultResourceQuota
 		*out = new(NamespaceResourceQuota)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ContainerDefaultResourceLimit != nil {
 		in, out := &in.ContainerDefaultResourceLimit, &out.ContainerDefaultResourceLimit
 		*out = new(ContainerResourceLimit)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceQuotaAlert != nil {
+		in, out := &in.ResourceQuotaAlert, &out.ResourceQuotaAlert
+		*out = new(ResourceQuotaAlert)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -8371,6 +8771,11 @@ func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 		*out = new(MonitoringStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResourceQuotaUsage != nil {
+		in, out := &in.ResourceQuotaUsage, &out.ResourceQuotaUsage
+		*out = new(ProjectResourceQuotaUsage)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8648,6 +9053,125 @@ func (in *Question) DeepCopy() *Question {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACDriftBinding) DeepCopyInto(out *RBACDriftBinding) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]rbacv1.Subject, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftBinding.
+func (in *RBACDriftBinding) DeepCopy() *RBACDriftBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACDriftBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACDriftReport) DeepCopyInto(out *RBACDriftReport) {
+	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReport.
+func (in *RBACDriftReport) DeepCopy() *RBACDriftReport {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACDriftReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RBACDriftReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACDriftReportList) DeepCopyInto(out *RBACDriftReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RBACDriftReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReportList.
+func (in *RBACDriftReportList) DeepCopy() *RBACDriftReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACDriftReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RBACDriftReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACDriftReportStatus) DeepCopyInto(out *RBACDriftReportStatus) {
+	*out = *in
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		*out = make([]RBACDriftBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Modified != nil {
+		in, out := &in.Modified, &out.Modified
+		*out = make([]RBACDriftBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Unmanaged != nil {
+		in, out := &in.Unmanaged, &out.Unmanaged
+		*out = make([]RBACDriftBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACDriftReportStatus.
+func (in *RBACDriftReportStatus) DeepCopy() *RBACDriftReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACDriftReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RancherUserNotification) DeepCopyInto(out *RancherUserNotification) {
 	*out = *in
@@ -8723,9 +9247,58 @@ func (in *Recipient) DeepCopy() *Recipient {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceLimitBounds) DeepCopyInto(out *ResourceLimitBounds) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceLimitBounds.
+func (in *ResourceLimitBounds) DeepCopy() *ResourceLimitBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceLimitBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaAlert) DeepCopyInto(out *ResourceQuotaAlert) {
+	*out = *in
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]Recipient, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaAlert.
+func (in *ResourceQuotaAlert) DeepCopy() *ResourceQuotaAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceQuotaLimit) DeepCopyInto(out *ResourceQuotaLimit) {
 	*out = *in
+	if in.Extended != nil {
+		in, out := &in.Extended, &out.Extended
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -8992,6 +9565,101 @@ func (in *RoleTemplate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplateBindingRequest) DeepCopyInto(out *RoleTemplateBindingRequest) {
+	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateBindingRequest.
+func (in *RoleTemplateBindingRequest) DeepCopy() *RoleTemplateBindingRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplateBindingRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RoleTemplateBindingRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplateBindingRequestList) DeepCopyInto(out *RoleTemplateBindingRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RoleTemplateBindingRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateBindingRequestList.
+func (in *RoleTemplateBindingRequestList) DeepCopy() *RoleTemplateBindingRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplateBindingRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RoleTemplateBindingRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplateBindingRequestSpec) DeepCopyInto(out *RoleTemplateBindingRequestSpec) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateBindingRequestSpec.
+func (in *RoleTemplateBindingRequestSpec) DeepCopy() *RoleTemplateBindingRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplateBindingRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplateBindingRequestStatus) DeepCopyInto(out *RoleTemplateBindingRequestStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplateBindingRequestStatus.
+func (in *RoleTemplateBindingRequestStatus) DeepCopy() *RoleTemplateBindingRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplateBindingRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleTemplateList) DeepCopyInto(out *RoleTemplateList) {
 	*out = *in
@@ -9665,6 +10333,22 @@ func (in *SplunkConfig) DeepCopy() *SplunkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageLimitBounds) DeepCopyInto(out *StorageLimitBounds) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageLimitBounds.
+func (in *StorageLimitBounds) DeepCopy() *StorageLimitBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageLimitBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubQuestion) DeepCopyInto(out *SubQuestion) {
 	*out = *in
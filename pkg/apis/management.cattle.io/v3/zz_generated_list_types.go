@@ -1080,6 +1080,23 @@ func NewProjectRoleTemplateBinding(namespace, name string, obj ProjectRoleTempla
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RBACDriftReportList is a list of RBACDriftReport resources
+type RBACDriftReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RBACDriftReport `json:"items"`
+}
+
+func NewRBACDriftReport(namespace, name string, obj RBACDriftReport) *RBACDriftReport {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("RBACDriftReport").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // RancherUserNotificationList is a list of RancherUserNotification resources
 type RancherUserNotificationList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -1165,6 +1182,23 @@ func NewRoleTemplate(namespace, name string, obj RoleTemplate) *RoleTemplate {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RoleTemplateBindingRequestList is a list of RoleTemplateBindingRequest resources
+type RoleTemplateBindingRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RoleTemplateBindingRequest `json:"items"`
+}
+
+func NewRoleTemplateBindingRequest(namespace, name string, obj RoleTemplateBindingRequest) *RoleTemplateBindingRequest {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("RoleTemplateBindingRequest").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // SamlProviderList is a list of SamlProvider resources
 type SamlProviderList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -92,11 +92,13 @@
 	ProjectMonitorGraphResourceName                     = "projectmonitorgraphs"
 	ProjectNetworkPolicyResourceName                    = "projectnetworkpolicies"
 	ProjectRoleTemplateBindingResourceName              = "projectroletemplatebindings"
+	RBACDriftReportResourceName                         = "rbacdriftreports"
 	RancherUserNotificationResourceName                 = "rancherusernotifications"
 	RkeAddonResourceName                                = "rkeaddons"
 	RkeK8sServiceOptionResourceName                     = "rkek8sserviceoptions"
 	RkeK8sSystemImageResourceName                       = "rkek8ssystemimages"
 	RoleTemplateResourceName                            = "roletemplates"
+	RoleTemplateBindingRequestResourceName              = "roletemplatebindingrequests"
 	SamlProviderResourceName                            = "samlproviders"
 	SamlTokenResourceName                               = "samltokens"
 	SettingResourceName                                 = "settings"
@@ -257,6 +259,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ProjectNetworkPolicyList{},
 		&ProjectRoleTemplateBinding{},
 		&ProjectRoleTemplateBindingList{},
+		&RBACDriftReport{},
+		&RBACDriftReportList{},
 		&RancherUserNotification{},
 		&RancherUserNotificationList{},
 		&RkeAddon{},
@@ -267,6 +271,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&RkeK8sSystemImageList{},
 		&RoleTemplate{},
 		&RoleTemplateList{},
+		&RoleTemplateBindingRequest{},
+		&RoleTemplateBindingRequestList{},
 		&SamlProvider{},
 		&SamlProviderList{},
 		&SamlToken{},
@@ -7,6 +7,7 @@
 	"github.com/sirupsen/logrus"
 	authV1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
 	v1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 )
 
@@ -93,3 +94,29 @@ func (sar subjectAccessReview) checkUserCanImpersonateGroup(sarClient v1.Subject
 
 	return true, nil
 }
+
+// UserCanAccess checks if the user of the request can perform verb on resource in the management.cattle.io group.
+// A request without a user is denied.
+func UserCanAccess(req *http.Request, sarClient v1.SubjectAccessReviewInterface, verb, resource string) (bool, error) {
+	userInfo, ok := request.UserFrom(req.Context())
+	if !ok {
+		return false, nil
+	}
+
+	review := authV1.SubjectAccessReview{
+		Spec: authV1.SubjectAccessReviewSpec{
+			User:   userInfo.GetName(),
+			Groups: userInfo.GetGroups(),
+			ResourceAttributes: &authV1.ResourceAttributes{
+				Verb:     verb,
+				Resource: resource,
+				Group:    "management.cattle.io",
+			},
+		},
+	}
+	result, err := sarClient.Create(req.Context(), &review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"github.com/rancher/wrangler/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tagsRefspec fetches every tag of the remote, moving the local tags that were changed on the remote.
+const tagsRefspec = "+refs/tags/*:refs/tags/*"
+
+// checkout wraps the wrangler git client of a checkout with the remote operations it does not provide directly.
+type checkout struct {
+	*git.Git
+}
+
+func newCheckout(secret *corev1.Secret, dir, gitURL string, insecureSkipTLS bool, caBundle []byte) (*checkout, error) {
+	g, err := gitForRepo(secret, dir, gitURL, insecureSkipTLS, caBundle)
+	if err != nil {
+		return nil, err
+	}
+	return &checkout{Git: g}, nil
+}
+
+// FetchTags fetches every tag of the remote into the checkout. The refspec is no revision of the checkout, so
+// Ensure fetches it from the remote with the credentials, CA bundle and known hosts of the client. Ensure then
+// resets the checkout to one of the fetched tags, callers check out the revision they want afterwards.
+func (c *checkout) FetchTags() error {
+	return c.Ensure(tagsRefspec)
+}
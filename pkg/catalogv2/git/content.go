@@ -18,7 +18,7 @@ func Icon(namespace, name, gitURL string, chartVersion *repo.ChartVersion) (io.R
 		return nil, "", fmt.Errorf("failed to find chartName %s version %s: %w", chartVersion.Name, chartVersion.Version, validation.NotFound)
 	}
 
-	dir := gitDir(namespace, name, gitURL)
+	dir := branchDir(namespace, name, gitURL, chartVersion.Annotations[BranchAnnotation])
 	icon := chartVersion.Icon
 	if strings.HasPrefix(icon, "file://") && len(chartVersion.URLs[0]) > 0 {
 		icon = filepath.Join(chartVersion.URLs[0], strings.TrimPrefix(icon, "file://"))
@@ -33,7 +33,7 @@ func Icon(namespace, name, gitURL string, chartVersion *repo.ChartVersion) (io.R
 }
 
 func Chart(namespace, name, gitURL string, chartVersion *repo.ChartVersion) (io.ReadCloser, error) {
-	dir := gitDir(namespace, name, gitURL)
+	dir := branchDir(namespace, name, gitURL, chartVersion.Annotations[BranchAnnotation])
 
 	if len(chartVersion.URLs) == 0 {
 		return nil, fmt.Errorf("failed to find chartName %s version %s: %w", chartVersion.Name, chartVersion.Version, validation.NotFound)
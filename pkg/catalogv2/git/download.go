@@ -29,8 +29,16 @@ func gitDir(namespace, name, gitURL string) string {
 	return filepath.Join(stateDir, namespace, name, hash(gitURL))
 }
 
+// branchDir returns the directory a branch indexed in addition to the main branch of the repo is checked out in.
+func branchDir(namespace, name, gitURL, branch string) string {
+	if branch == "" {
+		return gitDir(namespace, name, gitURL)
+	}
+	return gitDir(namespace, name, gitURL+"#"+branch)
+}
+
 func Head(secret *corev1.Secret, namespace, name, gitURL, branch string, insecureSkipTLS bool, caBundle []byte) (string, error) {
-	git, err := gitForRepo(secret, namespace, name, gitURL, insecureSkipTLS, caBundle)
+	git, err := gitForRepo(secret, gitDir(namespace, name, gitURL), gitURL, insecureSkipTLS, caBundle)
 	if err != nil {
 		return "", err
 	}
@@ -39,7 +47,7 @@ func Head(secret *corev1.Secret, namespace, name, gitURL, branch string, insecur
 }
 
 func Update(secret *corev1.Secret, namespace, name, gitURL, branch string, insecureSkipTLS bool, caBundle []byte) (string, error) {
-	git, err := gitForRepo(secret, namespace, name, gitURL, insecureSkipTLS, caBundle)
+	git, err := gitForRepo(secret, gitDir(namespace, name, gitURL), gitURL, insecureSkipTLS, caBundle)
 	if err != nil {
 		return "", err
 	}
@@ -59,7 +67,31 @@ func Ensure(secret *corev1.Secret, namespace, name, gitURL, commit string, insec
 	if commit == "" {
 		return nil
 	}
-	git, err := gitForRepo(secret, namespace, name, gitURL, insecureSkipTLS, caBundle)
+	git, err := gitForRepo(secret, gitDir(namespace, name, gitURL), gitURL, insecureSkipTLS, caBundle)
+	if err != nil {
+		return err
+	}
+
+	return git.Ensure(commit)
+}
+
+// UpdateBranch updates the checkout of a branch that is indexed in addition to the main branch of the repo. Every
+// such branch is checked out in its own directory.
+func UpdateBranch(secret *corev1.Secret, namespace, name, gitURL, branch string, insecureSkipTLS bool, caBundle []byte) (string, error) {
+	git, err := gitForRepo(secret, branchDir(namespace, name, gitURL, branch), gitURL, insecureSkipTLS, caBundle)
+	if err != nil {
+		return "", err
+	}
+
+	return git.Update(branch)
+}
+
+// EnsureBranch makes sure the checkout of an additional branch is at the given commit.
+func EnsureBranch(secret *corev1.Secret, namespace, name, gitURL, branch, commit string, insecureSkipTLS bool, caBundle []byte) error {
+	if commit == "" {
+		return nil
+	}
+	git, err := gitForRepo(secret, branchDir(namespace, name, gitURL, branch), gitURL, insecureSkipTLS, caBundle)
 	if err != nil {
 		return err
 	}
@@ -71,7 +103,7 @@ func isBundled(git *git.Git) bool {
 	return strings.HasPrefix(git.Directory, staticDir)
 }
 
-func gitForRepo(secret *corev1.Secret, namespace, name, gitURL string, insecureSkipTLS bool, caBundle []byte) (*git.Git, error) {
+func gitForRepo(secret *corev1.Secret, dir, gitURL string, insecureSkipTLS bool, caBundle []byte) (*git.Git, error) {
 	isGitSSH, err := isGitSSH(gitURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify the type of URL %s: %w", gitURL, err)
@@ -85,7 +117,6 @@ func gitForRepo(secret *corev1.Secret, namespace, name, gitURL string, insecureS
 			return nil, fmt.Errorf("invalid git URL scheme %s, only http(s) and git supported", u.Scheme)
 		}
 	}
-	dir := gitDir(namespace, name, gitURL)
 	headers := map[string]string{}
 	if settings.InstallUUID.Get() != "" {
 		headers["X-Install-Uuid"] = settings.InstallUUID.Get()
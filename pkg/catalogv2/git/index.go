@@ -4,29 +4,61 @@
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/rancher/rancher/pkg/catalogv2/chart"
 	"helm.sh/helm/v3/pkg/provenance"
 	"helm.sh/helm/v3/pkg/repo"
 )
 
-func BuildOrGetIndex(namespace, name, gitURL string) (*repo.IndexFile, error) {
-	dir := gitDir(namespace, name, gitURL)
-	return buildOrGetIndex(dir)
+const (
+	// CommitAnnotation records the git commit a chart version was indexed from.
+	CommitAnnotation = "catalog.cattle.io/git-commit"
+	// BranchAnnotation records the additional branch a chart version was indexed from.
+	BranchAnnotation = "catalog.cattle.io/git-branch"
+)
+
+// IndexOptions selects what part of a checkout is indexed and how its chart versions are annotated.
+type IndexOptions struct {
+	// SubPath is the directory of the repo that is searched for charts, the repo root if empty.
+	SubPath string
+	// Commit is the commit the checkout is at.
+	Commit string
+	// Branch is set when indexing a branch in addition to the main branch of the repo. Its chart versions are
+	// annotated with it so their content is read from the checkout of that branch.
+	Branch string
 }
 
-func buildOrGetIndex(dir string) (*repo.IndexFile, error) {
+func BuildOrGetIndex(namespace, name, gitURL string, opts IndexOptions) (*repo.IndexFile, error) {
+	dir := branchDir(namespace, name, gitURL, opts.Branch)
+	index, err := buildOrGetIndex(dir, opts.SubPath)
+	if err != nil {
+		return nil, err
+	}
+	annotateIndex(index, opts)
+	return index, nil
+}
+
+func buildOrGetIndex(dir, subPath string) (*repo.IndexFile, error) {
 	if err := ensureNoSymlinks(dir); err != nil {
 		return nil, err
 	}
 
+	root := filepath.Join(dir, subPath)
+	if rel, err := filepath.Rel(dir, root); err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return nil, fmt.Errorf("path %s is outside of the repo", subPath)
+	}
+	if s, err := os.Stat(root); err != nil || !s.IsDir() {
+		return nil, fmt.Errorf("path %s is not a directory of the repo", subPath)
+	}
+
 	var (
 		existingIndex *repo.IndexFile
 		indexPath     = ""
 		builtIndex    = repo.NewIndexFile()
 	)
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if info.Name() == "index.yaml" {
 			if indexPath == "" || len(path) < len(indexPath) {
 				if index, err := repo.LoadIndexFile(path); err == nil {
@@ -74,6 +106,23 @@ func buildOrGetIndex(dir string) (*repo.IndexFile, error) {
 	return builtIndex, nil
 }
 
+// annotateIndex records the commit, and the branch if it is an additional one, on every chart version of index.
+func annotateIndex(index *repo.IndexFile, opts IndexOptions) {
+	for _, versions := range index.Entries {
+		for _, version := range versions {
+			if version.Annotations == nil {
+				version.Annotations = map[string]string{}
+			}
+			if opts.Commit != "" {
+				version.Annotations[CommitAnnotation] = opts.Commit
+			}
+			if opts.Branch != "" {
+				version.Annotations[BranchAnnotation] = opts.Branch
+			}
+		}
+	}
+}
+
 func ensureNoSymlinks(dir string) error {
 	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info == nil {
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeChart(t *testing.T, dir, name, version string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	chartYAML := "apiVersion: v2\nname: " + name + "\nversion: " + version + "\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chartYAML), 0644))
+}
+
+func TestBuildOrGetIndexSubPath(t *testing.T) {
+	dir := t.TempDir()
+	writeChart(t, filepath.Join(dir, "charts", "app"), "app", "1.0.0")
+	writeChart(t, filepath.Join(dir, "other", "tool"), "tool", "2.0.0")
+
+	index, err := buildOrGetIndex(dir, "charts")
+	require.NoError(t, err)
+	assert.Contains(t, index.Entries, "app")
+	assert.NotContains(t, index.Entries, "tool")
+	assert.Equal(t, []string{filepath.Join("charts", "app")}, index.Entries["app"][0].URLs)
+
+	index, err = buildOrGetIndex(dir, "")
+	require.NoError(t, err)
+	assert.Contains(t, index.Entries, "app")
+	assert.Contains(t, index.Entries, "tool")
+
+	_, err = buildOrGetIndex(dir, "../outside")
+	assert.Error(t, err)
+	_, err = buildOrGetIndex(dir, "missing")
+	assert.Error(t, err)
+}
+
+func TestAnnotateIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeChart(t, filepath.Join(dir, "app"), "app", "1.0.0")
+	writeChart(t, filepath.Join(dir, "tool"), "tool", "2.0.0+build.1")
+
+	index, err := buildOrGetIndex(dir, "")
+	require.NoError(t, err)
+	annotateIndex(index, IndexOptions{Commit: "abc"})
+	assert.Equal(t, "1.0.0", index.Entries["app"][0].Version)
+	assert.Equal(t, "abc", index.Entries["app"][0].Annotations[CommitAnnotation])
+	assert.NotContains(t, index.Entries["app"][0].Annotations, BranchAnnotation)
+
+	branchIndex, err := buildOrGetIndex(dir, "")
+	require.NoError(t, err)
+	annotateIndex(branchIndex, IndexOptions{Commit: "def", Branch: "release/v2.7"})
+	assert.Equal(t, "1.0.0", branchIndex.Entries["app"][0].Version)
+	assert.Equal(t, "2.0.0+build.1", branchIndex.Entries["tool"][0].Version)
+	assert.Equal(t, "def", branchIndex.Entries["app"][0].Annotations[CommitAnnotation])
+	assert.Equal(t, "release/v2.7", branchIndex.Entries["app"][0].Annotations[BranchAnnotation])
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// UpdateTag fetches the tags of the repo and checks out the highest semver tag matching constraint. It returns the
+// tag and its commit.
+func UpdateTag(secret *corev1.Secret, namespace, name, gitURL, constraint string, insecureSkipTLS bool, caBundle []byte) (string, string, error) {
+	dir := gitDir(namespace, name, gitURL)
+	git, err := newCheckout(secret, dir, gitURL, insecureSkipTLS, caBundle)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := git.Ensure("HEAD"); err != nil {
+		return "", "", err
+	}
+	if err := git.FetchTags(); err != nil {
+		return "", "", err
+	}
+
+	output, err := localGit(dir, "for-each-ref", "--format=%(refname:strip=2)", "refs/tags")
+	if err != nil {
+		return "", "", err
+	}
+
+	tag, err := latestTag(strings.Fields(output), constraint)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := git.Ensure("refs/tags/" + tag); err != nil {
+		return "", "", err
+	}
+
+	commit, err := localGit(dir, "rev-parse", "HEAD")
+	return tag, commit, err
+}
+
+// latestTag returns the highest of the tags that are semantic versions matching constraint.
+func latestTag(tags []string, constraint string) (string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid tag constraint %s: %w", constraint, err)
+	}
+
+	var (
+		latest    string
+		latestVer *semver.Version
+	)
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if latestVer == nil || v.GreaterThan(latestVer) {
+			latest, latestVer = tag, v
+		}
+	}
+	if latestVer == nil {
+		return "", fmt.Errorf("no tag matches %s", constraint)
+	}
+	return latest, nil
+}
+
+// localGit runs git against the checkout in dir. It does not reach the remote, so no credentials are needed.
+func localGit(dir string, args ...string) (string, error) {
+	output := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = output
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s error: %w, detail: %v", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(output.String()), nil
+}
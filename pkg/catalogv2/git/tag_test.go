@@ -0,0 +1,55 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rancher/wrangler/pkg/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.2.0", "v1.10.1", "v2.0.0", "v2.1.0-rc1", "latest"}
+
+	tag, err := latestTag(tags, "^1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.10.1", tag)
+
+	tag, err = latestTag(tags, ">=2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", tag)
+
+	_, err = latestTag(tags, ">=3.0.0")
+	assert.Error(t, err)
+
+	_, err = latestTag(tags, "not a constraint")
+	assert.Error(t, err)
+}
+
+func TestFetchTags(t *testing.T) {
+	remote := t.TempDir()
+	run := func(dir string, args ...string) string {
+		output, err := localGit(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err)
+		return output
+	}
+	run(remote, "init", "-q")
+	run(remote, "commit", "-q", "--allow-empty", "-m", "first")
+	run(remote, "tag", "v1.0.0")
+
+	// the remote is a local path, which newCheckout does not accept
+	g, err := git.NewGit(filepath.Join(t.TempDir(), "checkout"), remote, nil)
+	require.NoError(t, err)
+	r := &checkout{Git: g}
+	require.NoError(t, r.Ensure("HEAD"))
+
+	// tags created on the remote after the clone are only brought in by fetching them
+	run(remote, "commit", "-q", "--allow-empty", "-m", "second")
+	run(remote, "tag", "v1.1.0")
+	require.NoError(t, r.FetchTags())
+
+	tags := run(r.Directory, "for-each-ref", "--format=%(refname:strip=2)", "refs/tags")
+	assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, strings.Fields(tags))
+}
@@ -0,0 +1,128 @@
+package helm
+
+import (
+	"strings"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"gopkg.in/yaml.v2"
+)
+
+type questionsFile struct {
+	Questions []v3.Question `yaml:"questions,omitempty"`
+}
+
+// boundsFile reads the bounds of the questions of a questions.yaml. The bounds of v3.Question are plain ints, so a
+// bound of 0 could not be told apart from a question without that bound.
+type boundsFile struct {
+	Questions []questionBounds `yaml:"questions,omitempty"`
+}
+
+type questionBounds struct {
+	Min          *int             `yaml:"min,omitempty"`
+	Max          *int             `yaml:"max,omitempty"`
+	MinLength    *int             `yaml:"min_length,omitempty"`
+	MaxLength    *int             `yaml:"max_length,omitempty"`
+	Subquestions []questionBounds `yaml:"subquestions,omitempty"`
+}
+
+// SchemaFromQuestions converts a Rancher questions.yaml to a JSON schema of the values it sets. Questions only
+// shown under a condition (show_if, subquestions) are never required, as whether they apply depends on other values.
+// Values set from the form may arrive as strings, so numbers and booleans also accept their string form.
+func SchemaFromQuestions(data []byte) (map[string]interface{}, error) {
+	var file questionsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Questions) == 0 {
+		return nil, nil
+	}
+	var bounds boundsFile
+	if err := yaml.Unmarshal(data, &bounds); err != nil {
+		return nil, err
+	}
+
+	schema := objectSchema()
+	for i, q := range file.Questions {
+		qBounds := bounds.Questions[i]
+		addProperty(schema, q.Variable, questionSchema(q.Type, q.Description, q.Options, qBounds), q.Required && q.ShowIf == "")
+		for j, sq := range q.Subquestions {
+			addProperty(schema, sq.Variable, questionSchema(sq.Type, sq.Description, sq.Options, qBounds.Subquestions[j]), false)
+		}
+	}
+	return schema, nil
+}
+
+func objectSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+// addProperty adds the schema of a dotted variable, e.g. ingress.tls.source, creating the intermediate objects.
+func addProperty(schema map[string]interface{}, variable string, property map[string]interface{}, required bool) {
+	if variable == "" {
+		return
+	}
+	parts := strings.Split(variable, ".")
+	for _, part := range parts[:len(parts)-1] {
+		properties := schema["properties"].(map[string]interface{})
+		next, ok := properties[part].(map[string]interface{})
+		if !ok || next["type"] != "object" {
+			next = objectSchema()
+			properties[part] = next
+		}
+		schema = next
+	}
+
+	name := parts[len(parts)-1]
+	schema["properties"].(map[string]interface{})[name] = property
+	if required {
+		requiredNames, _ := schema["required"].([]interface{})
+		schema["required"] = append(requiredNames, name)
+	}
+}
+
+func questionSchema(questionType, description string, options []string, bounds questionBounds) map[string]interface{} {
+	property := map[string]interface{}{}
+	if description != "" {
+		property["description"] = description
+	}
+
+	switch questionType {
+	case "int":
+		property["type"] = []interface{}{"integer", "string"}
+		property["pattern"] = "^-?[0-9]+$"
+		if bounds.Min != nil {
+			property["minimum"] = *bounds.Min
+		}
+		if bounds.Max != nil {
+			property["maximum"] = *bounds.Max
+		}
+	case "float":
+		property["type"] = []interface{}{"number", "string"}
+		property["pattern"] = `^-?[0-9]+(\.[0-9]+)?$`
+	case "boolean":
+		property["type"] = []interface{}{"boolean", "string"}
+		property["enum"] = []interface{}{true, false, "true", "false"}
+	case "enum":
+		property["type"] = "string"
+		if len(options) > 0 {
+			enum := make([]interface{}, 0, len(options))
+			for _, option := range options {
+				enum = append(enum, option)
+			}
+			property["enum"] = enum
+		}
+	case "string", "multiline", "password", "hostname":
+		property["type"] = "string"
+		if bounds.MinLength != nil {
+			property["minLength"] = *bounds.MinLength
+		}
+		if bounds.MaxLength != nil {
+			property["maxLength"] = *bounds.MaxLength
+		}
+	}
+	// other types (storageclass, secret, map[string], ...) reference objects or have no fixed shape and are not checked
+	return property
+}
@@ -395,6 +395,9 @@ func (s *Operations) getUpgradeCommand(repoNamespace, repoName string, body io.R
 		if err != nil {
 			return status, nil, err
 		}
+		if err := validateValues(cmd.Chart, chartUpgrade.Values); err != nil {
+			return status, nil, err
+		}
 		cmd.ReleaseName = chartUpgrade.ReleaseName
 		cmd.Operation = "upgrade"
 		cmd.ArgObjects = []interface{}{
@@ -697,6 +700,9 @@ func (s *Operations) getInstallCommand(repoNamespace, repoName string, body io.R
 		if err != nil {
 			return status, nil, err
 		}
+		if err := validateValues(cmd.Chart, chartInstall.Values); err != nil {
+			return status, nil, err
+		}
 		cmd.Operation = "install"
 		cmd.ArgObjects = []interface{}{
 			chartInstall,
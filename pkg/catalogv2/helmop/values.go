@@ -0,0 +1,95 @@
+package helmop
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/rancher/pkg/catalogv2/helm"
+	"github.com/rancher/wrangler/pkg/schemas/validation"
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// valuesSchema returns the JSON schema of the chart values: the chart's values.schema.json if it has one, else a
+// schema generated from its questions.yaml. It returns nil if the chart has neither.
+func valuesSchema(files map[string][]byte, schema []byte) ([]byte, error) {
+	if len(schema) > 0 {
+		return schema, nil
+	}
+	for _, name := range []string{"questions.yaml", "questions.yml"} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+		questionsSchema, err := helm.SchemaFromQuestions(data)
+		if err != nil || questionsSchema == nil {
+			return nil, err
+		}
+		return json.Marshal(questionsSchema)
+	}
+	return nil, nil
+}
+
+// validateValues checks the values submitted for a chart, merged with the chart defaults as helm would, against the
+// values schema of the chart. The returned API error names the first invalid field and lists all of them.
+func validateValues(chartData []byte, values map[string]interface{}) error {
+	chrt, err := loader.LoadArchive(bytes.NewReader(chartData))
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{}
+	for _, file := range chrt.Files {
+		files[file.Name] = file.Data
+	}
+	schema, err := valuesSchema(files, chrt.Schema)
+	if err != nil {
+		return apierror.WrapAPIError(err, validation.ServerError, fmt.Sprintf("failed to read the values schema of chart %s", chrt.Name()))
+	}
+	if schema == nil {
+		return nil
+	}
+
+	merged, err := chartutil.CoalesceValues(chrt, values)
+	if err != nil {
+		return apierror.WrapAPIError(err, validation.InvalidBodyContent, "failed to merge values with the chart defaults")
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewGoLoader(map[string]interface{}(merged)))
+	if err != nil {
+		return apierror.WrapAPIError(err, validation.ServerError, fmt.Sprintf("failed to validate values against the schema of chart %s", chrt.Name()))
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	var messages []string
+	for _, resultErr := range result.Errors() {
+		messages = append(messages, fmt.Sprintf("%s: %s", valuesField(resultErr), resultErr.Description()))
+	}
+	sort.Strings(messages)
+	return apierror.NewFieldAPIError(validation.InvalidBodyContent, strings.SplitN(messages[0], ":", 2)[0],
+		fmt.Sprintf("values of chart %s are invalid: %s", chrt.Name(), strings.Join(messages, "; ")))
+}
+
+// valuesField returns the path of the invalid value, prefixed with "values". Errors about a missing property are
+// reported against the property rather than the object holding it.
+func valuesField(resultErr gojsonschema.ResultError) string {
+	field := resultErr.Field()
+	if property, ok := resultErr.Details()["property"].(string); ok && resultErr.Type() == "required" {
+		if field == gojsonschema.STRING_CONTEXT_ROOT {
+			field = property
+		} else {
+			field += "." + property
+		}
+	}
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		return "values"
+	}
+	return "values." + field
+}
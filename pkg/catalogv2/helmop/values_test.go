@@ -0,0 +1,97 @@
+package helmop
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/yaml"
+)
+
+const testQuestions = `questions:
+- variable: replicas
+  type: int
+  required: true
+  min: 1
+  max: 5
+- variable: ingress.enabled
+  type: boolean
+  show_subquestion_if: true
+  subquestions:
+  - variable: ingress.host
+    type: hostname
+    required: true
+- variable: mode
+  type: enum
+  options: [fast, safe]
+- variable: offset
+  type: int
+  min: 0
+`
+
+func chartArchive(t *testing.T, values map[string]interface{}, schema string, files map[string]string) []byte {
+	t.Helper()
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "test", Version: "1.0.0"},
+	}
+	valuesYAML, err := yaml.Marshal(values)
+	require.NoError(t, err)
+	c.Raw = append(c.Raw, &chart.File{Name: chartutil.ValuesfileName, Data: valuesYAML})
+	if schema != "" {
+		c.Schema = []byte(schema)
+	}
+	for name, data := range files {
+		c.Files = append(c.Files, &chart.File{Name: name, Data: []byte(data)})
+	}
+	path, err := chartutil.Save(c, t.TempDir())
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func TestValidateValuesQuestions(t *testing.T) {
+	archive := chartArchive(t, map[string]interface{}{"replicas": 1, "mode": "safe"}, "", map[string]string{"questions.yaml": testQuestions})
+
+	assert.NoError(t, validateValues(archive, nil))
+	assert.NoError(t, validateValues(archive, map[string]interface{}{"replicas": "3", "ingress": map[string]interface{}{"enabled": "true"}}))
+
+	err := validateValues(archive, map[string]interface{}{"replicas": 7, "mode": "slow"})
+	require.Error(t, err)
+	apiErr, ok := err.(*apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, "values.mode", apiErr.FieldName)
+	assert.Contains(t, apiErr.Message, "values.mode")
+	assert.Contains(t, apiErr.Message, "values.replicas")
+
+	err = validateValues(archive, map[string]interface{}{"replicas": "many"})
+	require.Error(t, err)
+	assert.Equal(t, "values.replicas", err.(*apierror.APIError).FieldName)
+
+	// a bound of 0 is still a bound
+	err = validateValues(archive, map[string]interface{}{"offset": -1})
+	require.Error(t, err)
+	assert.Equal(t, "values.offset", err.(*apierror.APIError).FieldName)
+	assert.NoError(t, validateValues(archive, map[string]interface{}{"offset": 0}))
+}
+
+func TestValidateValuesSchema(t *testing.T) {
+	schema := `{"type": "object", "required": ["image"], "properties": {"image": {"type": "object", "required": ["tag"], "properties": {"tag": {"type": "string"}}}}}`
+	// the schema takes precedence over questions
+	archive := chartArchive(t, map[string]interface{}{}, schema, map[string]string{"questions.yaml": testQuestions})
+
+	err := validateValues(archive, map[string]interface{}{"image": map[string]interface{}{}})
+	require.Error(t, err)
+	assert.Equal(t, "values.image.tag", err.(*apierror.APIError).FieldName)
+
+	assert.NoError(t, validateValues(archive, map[string]interface{}{"image": map[string]interface{}{"tag": "v1"}}))
+}
+
+func TestValidateValuesWithoutSchema(t *testing.T) {
+	archive := chartArchive(t, map[string]interface{}{"a": 1}, "", nil)
+	assert.NoError(t, validateValues(archive, map[string]interface{}{"anything": true}))
+}
@@ -1,16 +1,22 @@
 package client
 
 const (
-	ContainerResourceLimitType                = "containerResourceLimit"
-	ContainerResourceLimitFieldLimitsCPU      = "limitsCpu"
-	ContainerResourceLimitFieldLimitsMemory   = "limitsMemory"
-	ContainerResourceLimitFieldRequestsCPU    = "requestsCpu"
-	ContainerResourceLimitFieldRequestsMemory = "requestsMemory"
+	ContainerResourceLimitType                       = "containerResourceLimit"
+	ContainerResourceLimitFieldContainer             = "container"
+	ContainerResourceLimitFieldLimitsCPU             = "limitsCpu"
+	ContainerResourceLimitFieldLimitsMemory          = "limitsMemory"
+	ContainerResourceLimitFieldPersistentVolumeClaim = "persistentVolumeClaim"
+	ContainerResourceLimitFieldPod                   = "pod"
+	ContainerResourceLimitFieldRequestsCPU           = "requestsCpu"
+	ContainerResourceLimitFieldRequestsMemory        = "requestsMemory"
 )
 
 type ContainerResourceLimit struct {
-	LimitsCPU      string `json:"limitsCpu,omitempty" yaml:"limitsCpu,omitempty"`
-	LimitsMemory   string `json:"limitsMemory,omitempty" yaml:"limitsMemory,omitempty"`
-	RequestsCPU    string `json:"requestsCpu,omitempty" yaml:"requestsCpu,omitempty"`
-	RequestsMemory string `json:"requestsMemory,omitempty" yaml:"requestsMemory,omitempty"`
+	Container             *ResourceLimitBounds `json:"container,omitempty" yaml:"container,omitempty"`
+	LimitsCPU             string               `json:"limitsCpu,omitempty" yaml:"limitsCpu,omitempty"`
+	LimitsMemory          string               `json:"limitsMemory,omitempty" yaml:"limitsMemory,omitempty"`
+	PersistentVolumeClaim *StorageLimitBounds  `json:"persistentVolumeClaim,omitempty" yaml:"persistentVolumeClaim,omitempty"`
+	Pod                   *ResourceLimitBounds `json:"pod,omitempty" yaml:"pod,omitempty"`
+	RequestsCPU           string               `json:"requestsCpu,omitempty" yaml:"requestsCpu,omitempty"`
+	RequestsMemory        string               `json:"requestsMemory,omitempty" yaml:"requestsMemory,omitempty"`
 }
@@ -61,7 +61,7 @@ type NamespaceOperations interface {
 	ByID(id string) (*Namespace, error)
 	Delete(container *Namespace) error
 
-	ActionMove(resource *Namespace, input *NamespaceMove) error
+	ActionMove(resource *Namespace, input *NamespaceMove) (*NamespaceMoveOutput, error)
 }
 
 func newNamespaceClient(apiClient *Client) *NamespaceClient {
@@ -133,7 +133,8 @@ func (c *NamespaceClient) Delete(container *Namespace) error {
 	return c.apiClient.Ops.DoResourceDelete(NamespaceType, &container.Resource)
 }
 
-func (c *NamespaceClient) ActionMove(resource *Namespace, input *NamespaceMove) error {
-	err := c.apiClient.Ops.DoAction(NamespaceType, "move", &resource.Resource, input, nil)
-	return err
+func (c *NamespaceClient) ActionMove(resource *Namespace, input *NamespaceMove) (*NamespaceMoveOutput, error) {
+	resp := &NamespaceMoveOutput{}
+	err := c.apiClient.Ops.DoAction(NamespaceType, "move", &resource.Resource, input, resp)
+	return resp, err
 }
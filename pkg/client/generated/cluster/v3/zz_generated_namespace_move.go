@@ -2,9 +2,11 @@
 
 const (
 	NamespaceMoveType           = "namespaceMove"
+	NamespaceMoveFieldDryRun    = "dryRun"
 	NamespaceMoveFieldProjectID = "projectId"
 )
 
 type NamespaceMove struct {
+	DryRun    bool   `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
 	ProjectID string `json:"projectId,omitempty" yaml:"projectId,omitempty"`
 }
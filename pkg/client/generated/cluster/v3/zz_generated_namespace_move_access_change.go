@@ -0,0 +1,16 @@
+package client
+
+const (
+	NamespaceMoveAccessChangeType                    = "namespaceMoveAccessChange"
+	NamespaceMoveAccessChangeFieldGranted            = "granted"
+	NamespaceMoveAccessChangeFieldGroupPrincipalName = "groupPrincipalName"
+	NamespaceMoveAccessChangeFieldRevoked            = "revoked"
+	NamespaceMoveAccessChangeFieldUserName           = "userName"
+)
+
+type NamespaceMoveAccessChange struct {
+	Granted            []string `json:"granted,omitempty" yaml:"granted,omitempty"`
+	GroupPrincipalName string   `json:"groupPrincipalName,omitempty" yaml:"groupPrincipalName,omitempty"`
+	Revoked            []string `json:"revoked,omitempty" yaml:"revoked,omitempty"`
+	UserName           string   `json:"userName,omitempty" yaml:"userName,omitempty"`
+}
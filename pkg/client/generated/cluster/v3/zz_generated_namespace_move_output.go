@@ -0,0 +1,22 @@
+package client
+
+const (
+	NamespaceMoveOutputType                        = "namespaceMoveOutput"
+	NamespaceMoveOutputFieldAccessChanges          = "accessChanges"
+	NamespaceMoveOutputFieldMoved                  = "moved"
+	NamespaceMoveOutputFieldNetworkPoliciesAdded   = "networkPoliciesAdded"
+	NamespaceMoveOutputFieldNetworkPoliciesRemoved = "networkPoliciesRemoved"
+	NamespaceMoveOutputFieldPreviousProjectID      = "previousProjectId"
+	NamespaceMoveOutputFieldProjectID              = "projectId"
+	NamespaceMoveOutputFieldResourceQuota          = "resourceQuota"
+)
+
+type NamespaceMoveOutput struct {
+	AccessChanges          []NamespaceMoveAccessChange `json:"accessChanges,omitempty" yaml:"accessChanges,omitempty"`
+	Moved                  bool                        `json:"moved,omitempty" yaml:"moved,omitempty"`
+	NetworkPoliciesAdded   []string                    `json:"networkPoliciesAdded,omitempty" yaml:"networkPoliciesAdded,omitempty"`
+	NetworkPoliciesRemoved []string                    `json:"networkPoliciesRemoved,omitempty" yaml:"networkPoliciesRemoved,omitempty"`
+	PreviousProjectID      string                      `json:"previousProjectId,omitempty" yaml:"previousProjectId,omitempty"`
+	ProjectID              string                      `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	ResourceQuota          *NamespaceResourceQuota     `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+}
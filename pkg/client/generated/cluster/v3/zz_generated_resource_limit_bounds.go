@@ -0,0 +1,20 @@
+package client
+
+const (
+	ResourceLimitBoundsType                            = "resourceLimitBounds"
+	ResourceLimitBoundsFieldMaxCPU                     = "maxCpu"
+	ResourceLimitBoundsFieldMaxLimitRequestRatioCPU    = "maxLimitRequestRatioCpu"
+	ResourceLimitBoundsFieldMaxLimitRequestRatioMemory = "maxLimitRequestRatioMemory"
+	ResourceLimitBoundsFieldMaxMemory                  = "maxMemory"
+	ResourceLimitBoundsFieldMinCPU                     = "minCpu"
+	ResourceLimitBoundsFieldMinMemory                  = "minMemory"
+)
+
+type ResourceLimitBounds struct {
+	MaxCPU                     string `json:"maxCpu,omitempty" yaml:"maxCpu,omitempty"`
+	MaxLimitRequestRatioCPU    string `json:"maxLimitRequestRatioCpu,omitempty" yaml:"maxLimitRequestRatioCpu,omitempty"`
+	MaxLimitRequestRatioMemory string `json:"maxLimitRequestRatioMemory,omitempty" yaml:"maxLimitRequestRatioMemory,omitempty"`
+	MaxMemory                  string `json:"maxMemory,omitempty" yaml:"maxMemory,omitempty"`
+	MinCPU                     string `json:"minCpu,omitempty" yaml:"minCpu,omitempty"`
+	MinMemory                  string `json:"minMemory,omitempty" yaml:"minMemory,omitempty"`
+}
@@ -0,0 +1,12 @@
+package client
+
+const (
+	StorageLimitBoundsType            = "storageLimitBounds"
+	StorageLimitBoundsFieldMaxStorage = "maxStorage"
+	StorageLimitBoundsFieldMinStorage = "minStorage"
+)
+
+type StorageLimitBounds struct {
+	MaxStorage string `json:"maxStorage,omitempty" yaml:"maxStorage,omitempty"`
+	MinStorage string `json:"minStorage,omitempty" yaml:"minStorage,omitempty"`
+}
@@ -0,0 +1,10 @@
+package client
+
+const (
+	CheckImagesInputType                   = "checkImagesInput"
+	CheckImagesInputFieldKubernetesVersion = "kubernetesVersion"
+)
+
+type CheckImagesInput struct {
+	KubernetesVersion string `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+}
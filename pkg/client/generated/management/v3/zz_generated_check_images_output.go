@@ -0,0 +1,18 @@
+package client
+
+const (
+	CheckImagesOutputType                   = "checkImagesOutput"
+	CheckImagesOutputFieldImages            = "images"
+	CheckImagesOutputFieldKubernetesVersion = "kubernetesVersion"
+	CheckImagesOutputFieldMissing           = "missing"
+	CheckImagesOutputFieldReady             = "ready"
+	CheckImagesOutputFieldRegistry          = "registry"
+)
+
+type CheckImagesOutput struct {
+	Images            []ImageStatus `json:"images,omitempty" yaml:"images,omitempty"`
+	KubernetesVersion string        `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	Missing           []string      `json:"missing,omitempty" yaml:"missing,omitempty"`
+	Ready             bool          `json:"ready,omitempty" yaml:"ready,omitempty"`
+	Registry          string        `json:"registry,omitempty" yaml:"registry,omitempty"`
+}
@@ -193,6 +193,8 @@ type ClusterOperations interface {
 
 	ActionBackupEtcd(resource *Cluster) error
 
+	ActionCheckImages(resource *Cluster, input *CheckImagesInput) (*CheckImagesOutput, error)
+
 	ActionDisableMonitoring(resource *Cluster) error
 
 	ActionEditMonitoring(resource *Cluster, input *MonitoringInput) error
@@ -292,6 +294,12 @@ func (c *ClusterClient) ActionBackupEtcd(resource *Cluster) error {
 	return err
 }
 
+func (c *ClusterClient) ActionCheckImages(resource *Cluster, input *CheckImagesInput) (*CheckImagesOutput, error) {
+	resp := &CheckImagesOutput{}
+	err := c.apiClient.Ops.DoAction(ClusterType, "checkImages", &resource.Resource, input, resp)
+	return resp, err
+}
+
 func (c *ClusterClient) ActionDisableMonitoring(resource *Cluster) error {
 	err := c.apiClient.Ops.DoAction(ClusterType, "disableMonitoring", &resource.Resource, nil, nil)
 	return err
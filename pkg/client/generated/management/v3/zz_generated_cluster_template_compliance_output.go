@@ -0,0 +1,14 @@
+package client
+
+const (
+	ClusterTemplateComplianceOutputType                        = "clusterTemplateComplianceOutput"
+	ClusterTemplateComplianceOutputFieldDefaultRevisionName    = "defaultRevisionName"
+	ClusterTemplateComplianceOutputFieldDeprecatedClusterNames = "deprecatedClusterNames"
+	ClusterTemplateComplianceOutputFieldRevisions              = "revisions"
+)
+
+type ClusterTemplateComplianceOutput struct {
+	DefaultRevisionName    string                              `json:"defaultRevisionName,omitempty" yaml:"defaultRevisionName,omitempty"`
+	DeprecatedClusterNames []string                            `json:"deprecatedClusterNames,omitempty" yaml:"deprecatedClusterNames,omitempty"`
+	Revisions              []ClusterTemplateRevisionCompliance `json:"revisions,omitempty" yaml:"revisions,omitempty"`
+}
@@ -0,0 +1,26 @@
+package client
+
+const (
+	ClusterTemplateMigrationType              = "clusterTemplateMigration"
+	ClusterTemplateMigrationFieldBatchSize    = "batchSize"
+	ClusterTemplateMigrationFieldClusterNames = "clusterNames"
+	ClusterTemplateMigrationFieldCompletedAt  = "completedAt"
+	ClusterTemplateMigrationFieldFailed       = "failed"
+	ClusterTemplateMigrationFieldMessage      = "message"
+	ClusterTemplateMigrationFieldMigrated     = "migrated"
+	ClusterTemplateMigrationFieldMigrating    = "migrating"
+	ClusterTemplateMigrationFieldStartedAt    = "startedAt"
+	ClusterTemplateMigrationFieldState        = "state"
+)
+
+type ClusterTemplateMigration struct {
+	BatchSize    int64                             `json:"batchSize,omitempty" yaml:"batchSize,omitempty"`
+	ClusterNames []string                          `json:"clusterNames,omitempty" yaml:"clusterNames,omitempty"`
+	CompletedAt  string                            `json:"completedAt,omitempty" yaml:"completedAt,omitempty"`
+	Failed       []ClusterTemplateMigrationFailure `json:"failed,omitempty" yaml:"failed,omitempty"`
+	Message      string                            `json:"message,omitempty" yaml:"message,omitempty"`
+	Migrated     []string                          `json:"migrated,omitempty" yaml:"migrated,omitempty"`
+	Migrating    []string                          `json:"migrating,omitempty" yaml:"migrating,omitempty"`
+	StartedAt    string                            `json:"startedAt,omitempty" yaml:"startedAt,omitempty"`
+	State        string                            `json:"state,omitempty" yaml:"state,omitempty"`
+}
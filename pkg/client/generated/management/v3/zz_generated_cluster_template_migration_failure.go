@@ -0,0 +1,12 @@
+package client
+
+const (
+	ClusterTemplateMigrationFailureType             = "clusterTemplateMigrationFailure"
+	ClusterTemplateMigrationFailureFieldClusterName = "clusterName"
+	ClusterTemplateMigrationFailureFieldMessage     = "message"
+)
+
+type ClusterTemplateMigrationFailure struct {
+	ClusterName string `json:"clusterName,omitempty" yaml:"clusterName,omitempty"`
+	Message     string `json:"message,omitempty" yaml:"message,omitempty"`
+}
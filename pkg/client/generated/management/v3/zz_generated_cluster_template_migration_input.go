@@ -0,0 +1,12 @@
+package client
+
+const (
+	ClusterTemplateMigrationInputType              = "clusterTemplateMigrationInput"
+	ClusterTemplateMigrationInputFieldBatchSize    = "batchSize"
+	ClusterTemplateMigrationInputFieldClusterNames = "clusterNames"
+)
+
+type ClusterTemplateMigrationInput struct {
+	BatchSize    int64    `json:"batchSize,omitempty" yaml:"batchSize,omitempty"`
+	ClusterNames []string `json:"clusterNames,omitempty" yaml:"clusterNames,omitempty"`
+}
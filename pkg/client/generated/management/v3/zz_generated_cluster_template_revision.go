@@ -21,6 +21,7 @@
 	ClusterTemplateRevisionFieldEnabled                          = "enabled"
 	ClusterTemplateRevisionFieldKubeletExtraEnvSecret            = "kubeletExtraEnvSecret"
 	ClusterTemplateRevisionFieldLabels                           = "labels"
+	ClusterTemplateRevisionFieldMigration                        = "migration"
 	ClusterTemplateRevisionFieldName                             = "name"
 	ClusterTemplateRevisionFieldOpenStackSecret                  = "openStackSecret"
 	ClusterTemplateRevisionFieldOwnerReferences                  = "ownerReferences"
@@ -56,6 +57,7 @@ type ClusterTemplateRevision struct {
 	Enabled                          *bool                              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 	KubeletExtraEnvSecret            string                             `json:"kubeletExtraEnvSecret,omitempty" yaml:"kubeletExtraEnvSecret,omitempty"`
 	Labels                           map[string]string                  `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Migration                        *ClusterTemplateMigration          `json:"migration,omitempty" yaml:"migration,omitempty"`
 	Name                             string                             `json:"name,omitempty" yaml:"name,omitempty"`
 	OpenStackSecret                  string                             `json:"openStackSecret,omitempty" yaml:"openStackSecret,omitempty"`
 	OwnerReferences                  []OwnerReference                   `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
@@ -97,6 +99,8 @@ type ClusterTemplateRevisionOperations interface {
 
 	ActionEnable(resource *ClusterTemplateRevision) error
 
+	ActionMigrateClusters(resource *ClusterTemplateRevision, input *ClusterTemplateMigrationInput) error
+
 	CollectionActionListquestions(resource *ClusterTemplateRevisionCollection) (*ClusterTemplateQuestionsOutput, error)
 }
 
@@ -179,6 +183,11 @@ func (c *ClusterTemplateRevisionClient) ActionEnable(resource *ClusterTemplateRe
 	return err
 }
 
+func (c *ClusterTemplateRevisionClient) ActionMigrateClusters(resource *ClusterTemplateRevision, input *ClusterTemplateMigrationInput) error {
+	err := c.apiClient.Ops.DoAction(ClusterTemplateRevisionType, "migrateClusters", &resource.Resource, input, nil)
+	return err
+}
+
 func (c *ClusterTemplateRevisionClient) CollectionActionListquestions(resource *ClusterTemplateRevisionCollection) (*ClusterTemplateQuestionsOutput, error) {
 	resp := &ClusterTemplateQuestionsOutput{}
 	err := c.apiClient.Ops.DoCollectionAction(ClusterTemplateRevisionType, "listquestions", &resource.Collection, nil, resp)
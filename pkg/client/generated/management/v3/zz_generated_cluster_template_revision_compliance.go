@@ -0,0 +1,20 @@
+package client
+
+const (
+	ClusterTemplateRevisionComplianceType                 = "clusterTemplateRevisionCompliance"
+	ClusterTemplateRevisionComplianceFieldClusterNames    = "clusterNames"
+	ClusterTemplateRevisionComplianceFieldDefault         = "default"
+	ClusterTemplateRevisionComplianceFieldDisplayName     = "displayName"
+	ClusterTemplateRevisionComplianceFieldEnabled         = "enabled"
+	ClusterTemplateRevisionComplianceFieldRevisionName    = "revisionName"
+	ClusterTemplateRevisionComplianceFieldRevisionsBehind = "revisionsBehind"
+)
+
+type ClusterTemplateRevisionCompliance struct {
+	ClusterNames    []string `json:"clusterNames,omitempty" yaml:"clusterNames,omitempty"`
+	Default         bool     `json:"default,omitempty" yaml:"default,omitempty"`
+	DisplayName     string   `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Enabled         bool     `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	RevisionName    string   `json:"revisionName,omitempty" yaml:"revisionName,omitempty"`
+	RevisionsBehind int64    `json:"revisionsBehind,omitempty" yaml:"revisionsBehind,omitempty"`
+}
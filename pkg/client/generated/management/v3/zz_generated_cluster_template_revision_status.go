@@ -10,6 +10,7 @@
 	ClusterTemplateRevisionStatusFieldBastionHostSSHKeySecret          = "bastionHostSSHKeySecret"
 	ClusterTemplateRevisionStatusFieldConditions                       = "conditions"
 	ClusterTemplateRevisionStatusFieldKubeletExtraEnvSecret            = "kubeletExtraEnvSecret"
+	ClusterTemplateRevisionStatusFieldMigration                        = "migration"
 	ClusterTemplateRevisionStatusFieldOpenStackSecret                  = "openStackSecret"
 	ClusterTemplateRevisionStatusFieldPrivateRegistryECRSecret         = "privateRegistryECRSecret"
 	ClusterTemplateRevisionStatusFieldPrivateRegistrySecret            = "privateRegistrySecret"
@@ -29,6 +30,7 @@ type ClusterTemplateRevisionStatus struct {
 	BastionHostSSHKeySecret          string                             `json:"bastionHostSSHKeySecret,omitempty" yaml:"bastionHostSSHKeySecret,omitempty"`
 	Conditions                       []ClusterTemplateRevisionCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	KubeletExtraEnvSecret            string                             `json:"kubeletExtraEnvSecret,omitempty" yaml:"kubeletExtraEnvSecret,omitempty"`
+	Migration                        *ClusterTemplateMigration          `json:"migration,omitempty" yaml:"migration,omitempty"`
 	OpenStackSecret                  string                             `json:"openStackSecret,omitempty" yaml:"openStackSecret,omitempty"`
 	PrivateRegistryECRSecret         string                             `json:"privateRegistryECRSecret,omitempty" yaml:"privateRegistryECRSecret,omitempty"`
 	PrivateRegistrySecret            string                             `json:"privateRegistrySecret,omitempty" yaml:"privateRegistrySecret,omitempty"`
@@ -9,6 +9,7 @@
 	FeatureFieldAnnotations          = "annotations"
 	FeatureFieldCreated              = "created"
 	FeatureFieldCreatorID            = "creatorId"
+	FeatureFieldEnableAt             = "enableAt"
 	FeatureFieldLabels               = "labels"
 	FeatureFieldName                 = "name"
 	FeatureFieldOwnerReferences      = "ownerReferences"
@@ -26,6 +27,7 @@ type Feature struct {
 	Annotations          map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	Created              string            `json:"created,omitempty" yaml:"created,omitempty"`
 	CreatorID            string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	EnableAt             string            `json:"enableAt,omitempty" yaml:"enableAt,omitempty"`
 	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	Name                 string            `json:"name,omitempty" yaml:"name,omitempty"`
 	OwnerReferences      []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
@@ -1,16 +1,24 @@
 package client
 
 const (
-	FeatureStatusType             = "featureStatus"
-	FeatureStatusFieldDefault     = "default"
-	FeatureStatusFieldDescription = "description"
-	FeatureStatusFieldDynamic     = "dynamic"
-	FeatureStatusFieldLockedValue = "lockedValue"
+	FeatureStatusType                 = "featureStatus"
+	FeatureStatusFieldDefault         = "default"
+	FeatureStatusFieldDependencies    = "dependencies"
+	FeatureStatusFieldDescription     = "description"
+	FeatureStatusFieldDynamic         = "dynamic"
+	FeatureStatusFieldEffectiveValue  = "effectiveValue"
+	FeatureStatusFieldHistory         = "history"
+	FeatureStatusFieldLockedValue     = "lockedValue"
+	FeatureStatusFieldScheduleFailure = "scheduleFailure"
 )
 
 type FeatureStatus struct {
-	Default     bool   `json:"default,omitempty" yaml:"default,omitempty"`
-	Description string `json:"description,omitempty" yaml:"description,omitempty"`
-	Dynamic     bool   `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
-	LockedValue *bool  `json:"lockedValue,omitempty" yaml:"lockedValue,omitempty"`
+	Default         bool                `json:"default,omitempty" yaml:"default,omitempty"`
+	Dependencies    []string            `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Description     string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Dynamic         bool                `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
+	EffectiveValue  *bool               `json:"effectiveValue,omitempty" yaml:"effectiveValue,omitempty"`
+	History         []FeatureTransition `json:"history,omitempty" yaml:"history,omitempty"`
+	LockedValue     *bool               `json:"lockedValue,omitempty" yaml:"lockedValue,omitempty"`
+	ScheduleFailure string              `json:"scheduleFailure,omitempty" yaml:"scheduleFailure,omitempty"`
 }
@@ -0,0 +1,14 @@
+package client
+
+const (
+	FeatureTransitionType       = "featureTransition"
+	FeatureTransitionFieldTime  = "time"
+	FeatureTransitionFieldUser  = "user"
+	FeatureTransitionFieldValue = "value"
+)
+
+type FeatureTransition struct {
+	Time  string `json:"time,omitempty" yaml:"time,omitempty"`
+	User  string `json:"user,omitempty" yaml:"user,omitempty"`
+	Value bool   `json:"value,omitempty" yaml:"value,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	ImageStatusType         = "imageStatus"
+	ImageStatusFieldError   = "error"
+	ImageStatusFieldImage   = "image"
+	ImageStatusFieldPresent = "present"
+	ImageStatusFieldSources = "sources"
+)
+
+type ImageStatus struct {
+	Error   string   `json:"error,omitempty" yaml:"error,omitempty"`
+	Image   string   `json:"image,omitempty" yaml:"image,omitempty"`
+	Present bool     `json:"present,omitempty" yaml:"present,omitempty"`
+	Sources []string `json:"sources,omitempty" yaml:"sources,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	NamespaceResourceQuotaUsageType           = "namespaceResourceQuotaUsage"
+	NamespaceResourceQuotaUsageFieldLimit     = "limit"
+	NamespaceResourceQuotaUsageFieldNamespace = "namespace"
+	NamespaceResourceQuotaUsageFieldUsed      = "used"
+)
+
+type NamespaceResourceQuotaUsage struct {
+	Limit     *ResourceQuotaLimit `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Namespace string              `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Used      *ResourceQuotaLimit `json:"used,omitempty" yaml:"used,omitempty"`
+}
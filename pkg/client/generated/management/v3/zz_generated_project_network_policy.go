@@ -18,6 +18,7 @@
 	ProjectNetworkPolicyFieldRemoved              = "removed"
 	ProjectNetworkPolicyFieldState                = "state"
 	ProjectNetworkPolicyFieldStatus               = "status"
+	ProjectNetworkPolicyFieldTemplate             = "template"
 	ProjectNetworkPolicyFieldTransitioning        = "transitioning"
 	ProjectNetworkPolicyFieldTransitioningMessage = "transitioningMessage"
 	ProjectNetworkPolicyFieldUUID                 = "uuid"
@@ -25,21 +26,22 @@
 
 type ProjectNetworkPolicy struct {
 	types.Resource
-	Annotations          map[string]string           `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Created              string                      `json:"created,omitempty" yaml:"created,omitempty"`
-	CreatorID            string                      `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
-	Description          string                      `json:"description,omitempty" yaml:"description,omitempty"`
-	Labels               map[string]string           `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Name                 string                      `json:"name,omitempty" yaml:"name,omitempty"`
-	NamespaceId          string                      `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
-	OwnerReferences      []OwnerReference            `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
-	ProjectID            string                      `json:"projectId,omitempty" yaml:"projectId,omitempty"`
-	Removed              string                      `json:"removed,omitempty" yaml:"removed,omitempty"`
-	State                string                      `json:"state,omitempty" yaml:"state,omitempty"`
-	Status               *ProjectNetworkPolicyStatus `json:"status,omitempty" yaml:"status,omitempty"`
-	Transitioning        string                      `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
-	TransitioningMessage string                      `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
-	UUID                 string                      `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Annotations          map[string]string             `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Created              string                        `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID            string                        `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	Description          string                        `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels               map[string]string             `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Name                 string                        `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespaceId          string                        `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
+	OwnerReferences      []OwnerReference              `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	ProjectID            string                        `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	Removed              string                        `json:"removed,omitempty" yaml:"removed,omitempty"`
+	State                string                        `json:"state,omitempty" yaml:"state,omitempty"`
+	Status               *ProjectNetworkPolicyStatus   `json:"status,omitempty" yaml:"status,omitempty"`
+	Template             *ProjectNetworkPolicyTemplate `json:"template,omitempty" yaml:"template,omitempty"`
+	Transitioning        string                        `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
+	TransitioningMessage string                        `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
+	UUID                 string                        `json:"uuid,omitempty" yaml:"uuid,omitempty"`
 }
 
 type ProjectNetworkPolicyCollection struct {
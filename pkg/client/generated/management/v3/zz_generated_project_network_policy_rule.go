@@ -0,0 +1,16 @@
+package client
+
+const (
+	ProjectNetworkPolicyRuleType             = "projectNetworkPolicyRule"
+	ProjectNetworkPolicyRuleFieldCIDRs       = "cidrs"
+	ProjectNetworkPolicyRuleFieldExceptCIDRs = "exceptCidrs"
+	ProjectNetworkPolicyRuleFieldPorts       = "ports"
+	ProjectNetworkPolicyRuleFieldProjectIDs  = "projectIds"
+)
+
+type ProjectNetworkPolicyRule struct {
+	CIDRs       []string                   `json:"cidrs,omitempty" yaml:"cidrs,omitempty"`
+	ExceptCIDRs []string                   `json:"exceptCidrs,omitempty" yaml:"exceptCidrs,omitempty"`
+	Ports       []ProjectNetworkPolicyPort `json:"ports,omitempty" yaml:"ports,omitempty"`
+	ProjectIDs  []string                   `json:"projectIds,omitempty" yaml:"projectIds,omitempty"`
+}
@@ -4,9 +4,11 @@
 	ProjectNetworkPolicySpecType             = "projectNetworkPolicySpec"
 	ProjectNetworkPolicySpecFieldDescription = "description"
 	ProjectNetworkPolicySpecFieldProjectID   = "projectId"
+	ProjectNetworkPolicySpecFieldTemplate    = "template"
 )
 
 type ProjectNetworkPolicySpec struct {
-	Description string `json:"description,omitempty" yaml:"description,omitempty"`
-	ProjectID   string `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	Description string                        `json:"description,omitempty" yaml:"description,omitempty"`
+	ProjectID   string                        `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	Template    *ProjectNetworkPolicyTemplate `json:"template,omitempty" yaml:"template,omitempty"`
 }
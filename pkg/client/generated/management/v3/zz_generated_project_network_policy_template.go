@@ -0,0 +1,18 @@
+package client
+
+const (
+	ProjectNetworkPolicyTemplateType             = "projectNetworkPolicyTemplate"
+	ProjectNetworkPolicyTemplateFieldAllowDNS    = "allowDns"
+	ProjectNetworkPolicyTemplateFieldEgress      = "egress"
+	ProjectNetworkPolicyTemplateFieldIngress     = "ingress"
+	ProjectNetworkPolicyTemplateFieldPodSelector = "podSelector"
+	ProjectNetworkPolicyTemplateFieldPolicyTypes = "policyTypes"
+)
+
+type ProjectNetworkPolicyTemplate struct {
+	AllowDNS    bool                       `json:"allowDns,omitempty" yaml:"allowDns,omitempty"`
+	Egress      []ProjectNetworkPolicyRule `json:"egress,omitempty" yaml:"egress,omitempty"`
+	Ingress     []ProjectNetworkPolicyRule `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	PodSelector map[string]string          `json:"podSelector,omitempty" yaml:"podSelector,omitempty"`
+	PolicyTypes []string                   `json:"policyTypes,omitempty" yaml:"policyTypes,omitempty"`
+}
@@ -0,0 +1,20 @@
+package client
+
+const (
+	ProjectResourceQuotaUsageType            = "projectResourceQuotaUsage"
+	ProjectResourceQuotaUsageFieldAllocated  = "allocated"
+	ProjectResourceQuotaUsageFieldLimit      = "limit"
+	ProjectResourceQuotaUsageFieldNamespaces = "namespaces"
+	ProjectResourceQuotaUsageFieldThresholds = "thresholds"
+	ProjectResourceQuotaUsageFieldUpdatedAt  = "updatedAt"
+	ProjectResourceQuotaUsageFieldUsed       = "used"
+)
+
+type ProjectResourceQuotaUsage struct {
+	Allocated  *ResourceQuotaLimit           `json:"allocated,omitempty" yaml:"allocated,omitempty"`
+	Limit      *ResourceQuotaLimit           `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Namespaces []NamespaceResourceQuotaUsage `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Thresholds map[string]int64              `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	UpdatedAt  string                        `json:"updatedAt,omitempty" yaml:"updatedAt,omitempty"`
+	Used       *ResourceQuotaLimit           `json:"used,omitempty" yaml:"used,omitempty"`
+}
@@ -9,6 +9,7 @@
 	ProjectSpecFieldEnableProjectMonitoring       = "enableProjectMonitoring"
 	ProjectSpecFieldNamespaceDefaultResourceQuota = "namespaceDefaultResourceQuota"
 	ProjectSpecFieldResourceQuota                 = "resourceQuota"
+	ProjectSpecFieldResourceQuotaAlert            = "resourceQuotaAlert"
 )
 
 type ProjectSpec struct {
@@ -19,4 +20,5 @@ type ProjectSpec struct {
 	EnableProjectMonitoring       bool                    `json:"enableProjectMonitoring,omitempty" yaml:"enableProjectMonitoring,omitempty"`
 	NamespaceDefaultResourceQuota *NamespaceResourceQuota `json:"namespaceDefaultResourceQuota,omitempty" yaml:"namespaceDefaultResourceQuota,omitempty"`
 	ResourceQuota                 *ProjectResourceQuota   `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+	ResourceQuotaAlert            *ResourceQuotaAlert     `json:"resourceQuotaAlert,omitempty" yaml:"resourceQuotaAlert,omitempty"`
 }
@@ -5,10 +5,12 @@
 	ProjectStatusFieldConditions                    = "conditions"
 	ProjectStatusFieldMonitoringStatus              = "monitoringStatus"
 	ProjectStatusFieldPodSecurityPolicyTemplateName = "podSecurityPolicyTemplateId"
+	ProjectStatusFieldResourceQuotaUsage            = "resourceQuotaUsage"
 )
 
 type ProjectStatus struct {
-	Conditions                    []ProjectCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
-	MonitoringStatus              *MonitoringStatus  `json:"monitoringStatus,omitempty" yaml:"monitoringStatus,omitempty"`
-	PodSecurityPolicyTemplateName string             `json:"podSecurityPolicyTemplateId,omitempty" yaml:"podSecurityPolicyTemplateId,omitempty"`
+	Conditions                    []ProjectCondition         `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	MonitoringStatus              *MonitoringStatus          `json:"monitoringStatus,omitempty" yaml:"monitoringStatus,omitempty"`
+	PodSecurityPolicyTemplateName string                     `json:"podSecurityPolicyTemplateId,omitempty" yaml:"podSecurityPolicyTemplateId,omitempty"`
+	ResourceQuotaUsage            *ProjectResourceQuotaUsage `json:"resourceQuotaUsage,omitempty" yaml:"resourceQuotaUsage,omitempty"`
 }
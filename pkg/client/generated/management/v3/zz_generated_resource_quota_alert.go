@@ -0,0 +1,12 @@
+package client
+
+const (
+	ResourceQuotaAlertType            = "resourceQuotaAlert"
+	ResourceQuotaAlertFieldRecipients = "recipients"
+	ResourceQuotaAlertFieldThresholds = "thresholds"
+)
+
+type ResourceQuotaAlert struct {
+	Recipients []Recipient `json:"recipients,omitempty" yaml:"recipients,omitempty"`
+	Thresholds []int64     `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+}
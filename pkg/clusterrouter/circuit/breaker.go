@@ -0,0 +1,163 @@
+// Package circuit protects Rancher from the downstream clusters it proxies requests to: a per-cluster circuit breaker
+// fails the requests fast while a cluster cannot be dialed, and a per-user, per-cluster rate limiter bounds the
+// requests each user can proxy.
+package circuit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when dialing a cluster whose breaker is open.
+var ErrOpen = errors.New("circuit breaker open")
+
+type state int
+
+const (
+	closed state = iota
+	open
+	halfOpen
+)
+
+type breaker struct {
+	state    state
+	failures int
+	openedAt time.Time
+	lastErr  error
+}
+
+// Breaker tracks the consecutive failures to dial each cluster. Once threshold failures happened in a row the breaker
+// of the cluster opens and its dials fail fast. After the cooldown a single dial is let through to probe the cluster,
+// which closes the breaker if it succeeds and opens it again otherwise.
+type Breaker struct {
+	lock      sync.Mutex
+	clusters  map[string]*breaker
+	threshold func() int
+	cooldown  func() time.Duration
+	now       func() time.Time
+}
+
+// NewBreaker returns a breaker reading its threshold and cooldown on each use, so that they can be changed at runtime.
+// A threshold of 0 or less disables the breaker.
+func NewBreaker(threshold func() int, cooldown func() time.Duration) *Breaker {
+	return &Breaker{
+		clusters:  map[string]*breaker{},
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Rejects returns whether the requests to the cluster should fail fast, and when they should be retried. The requests
+// are rejected while the breaker is open and while a probe is in flight.
+func (b *Breaker) Rejects(cluster string) (time.Duration, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	c, ok := b.clusters[cluster]
+	if !ok || b.threshold() <= 0 {
+		return 0, nil
+	}
+	switch c.state {
+	case open:
+		if wait := c.openedAt.Add(b.cooldown()).Sub(b.now()); wait > 0 {
+			return wait, b.openError(cluster, c)
+		}
+	case halfOpen:
+		return b.cooldown(), b.openError(cluster, c)
+	}
+	return 0, nil
+}
+
+// allow returns whether a dial to the cluster may proceed, moving an open breaker whose cooldown passed to half-open.
+func (b *Breaker) allow(cluster string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	c, ok := b.clusters[cluster]
+	if !ok || b.threshold() <= 0 {
+		return nil
+	}
+	switch c.state {
+	case open:
+		if b.now().Before(c.openedAt.Add(b.cooldown())) {
+			incRejections(cluster)
+			return b.openError(cluster, c)
+		}
+		b.setState(cluster, c, halfOpen)
+	case halfOpen:
+		incRejections(cluster)
+		return b.openError(cluster, c)
+	}
+	return nil
+}
+
+// record records the result of a dial to the cluster.
+func (b *Breaker) record(cluster string, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	c, ok := b.clusters[cluster]
+	if err == nil {
+		if ok {
+			b.setState(cluster, c, closed)
+			delete(b.clusters, cluster)
+		}
+		return
+	}
+
+	if !ok {
+		c = &breaker{}
+		b.clusters[cluster] = c
+	}
+	c.failures++
+	c.lastErr = err
+	threshold := b.threshold()
+	if c.state == halfOpen || (threshold > 0 && c.failures >= threshold) {
+		c.openedAt = b.now()
+		b.setState(cluster, c, open)
+	}
+}
+
+func (b *Breaker) setState(cluster string, c *breaker, s state) {
+	c.state = s
+	setBreakerState(cluster, s)
+}
+
+func (b *Breaker) openError(cluster string, c *breaker) error {
+	return fmt.Errorf("%w for cluster %s after %d consecutive dial failures, last error: %v", ErrOpen, cluster, c.failures, c.lastErr)
+}
+
+// abort reopens a half-open breaker whose probe was canceled by its caller, so that the next dial probes again.
+func (b *Breaker) abort(cluster string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if c, ok := b.clusters[cluster]; ok && c.state == halfOpen {
+		b.setState(cluster, c, open)
+	}
+}
+
+// Dialer wraps the dialer of a cluster so that its failures are tracked and it fails fast while the breaker is open.
+// Dials canceled by their callers are not counted as failures.
+func (b *Breaker) Dialer(cluster string, dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	if b == nil {
+		return dial
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if err := b.allow(cluster); err != nil {
+			return nil, err
+		}
+		conn, err := dial(ctx, network, address)
+		if err != nil && ctx.Err() != nil {
+			b.abort(cluster)
+			return conn, err
+		}
+		b.record(cluster, err)
+		return conn, err
+	}
+}
@@ -0,0 +1,180 @@
+package circuit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(clock *fakeClock) *Breaker {
+	b := NewBreaker(func() int { return 2 }, func() time.Duration { return 30 * time.Second })
+	b.now = clock.Now
+	return b
+}
+
+func TestBreaker(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := newTestBreaker(clock)
+
+	var dialErr error
+	dials := 0
+	dial := b.Dialer("c-1", func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials++
+		return nil, dialErr
+	})
+
+	dialErr = errors.New("failed to find Session for client c-1")
+	for i := 0; i < 2; i++ {
+		_, err := dial(context.Background(), "tcp", "c-1:443")
+		assert.Equal(t, dialErr, err)
+	}
+	assert.Equal(t, 2, dials)
+
+	_, err := dial(context.Background(), "tcp", "c-1:443")
+	assert.ErrorIs(t, err, ErrOpen, "the breaker must open after threshold failures")
+	assert.Equal(t, 2, dials)
+	wait, err := b.Rejects("c-1")
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.Equal(t, 30*time.Second, wait)
+
+	wait, err = b.Rejects("c-2")
+	assert.NoError(t, err, "other clusters must not be affected")
+	assert.Zero(t, wait)
+
+	clock.now = clock.now.Add(31 * time.Second)
+	_, err = b.Rejects("c-1")
+	assert.NoError(t, err, "a probe must be let through after the cooldown")
+	_, err = dial(context.Background(), "tcp", "c-1:443")
+	assert.Equal(t, dialErr, err)
+	assert.Equal(t, 3, dials)
+	_, err = dial(context.Background(), "tcp", "c-1:443")
+	assert.ErrorIs(t, err, ErrOpen, "a failed probe must open the breaker again")
+
+	clock.now = clock.now.Add(31 * time.Second)
+	dialErr = nil
+	_, err = dial(context.Background(), "tcp", "c-1:443")
+	assert.NoError(t, err)
+	_, err = b.Rejects("c-1")
+	assert.NoError(t, err, "a successful probe must close the breaker")
+	assert.Empty(t, b.clusters)
+}
+
+func TestBreakerCanceledDial(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := newTestBreaker(clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dial := b.Dialer("c-1", func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, ctx.Err()
+	})
+	for i := 0; i < 3; i++ {
+		_, err := dial(ctx, "tcp", "c-1:443")
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	_, err := b.Rejects("c-1")
+	assert.NoError(t, err, "canceled dials must not open the breaker")
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := NewBreaker(func() int { return 0 }, func() time.Duration { return time.Minute })
+	dial := b.Dialer("c-1", func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("unreachable")
+	})
+	for i := 0; i < 5; i++ {
+		_, err := dial(context.Background(), "tcp", "c-1:443")
+		assert.NotErrorIs(t, err, ErrOpen)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	l := NewRateLimiter(func() float64 { return 1 }, func() int { return 2 })
+	l.now = clock.Now
+
+	for i := 0; i < 2; i++ {
+		ok, _ := l.Allow("u-1", "c-1")
+		assert.True(t, ok)
+	}
+	ok, wait := l.Allow("u-1", "c-1")
+	assert.False(t, ok, "the burst must be exhausted")
+	assert.Equal(t, time.Second, wait)
+
+	ok, _ = l.Allow("u-2", "c-1")
+	assert.True(t, ok, "users must be limited separately")
+	ok, _ = l.Allow("u-1", "c-2")
+	assert.True(t, ok, "clusters must be limited separately")
+
+	clock.now = clock.now.Add(time.Second)
+	ok, _ = l.Allow("u-1", "c-1")
+	assert.True(t, ok, "the bucket must refill")
+
+	clock.now = clock.now.Add(limiterIdleTTL + time.Minute)
+	l.Allow("u-3", "c-1")
+	assert.Len(t, l.limiters, 1, "idle limiters must be swept")
+}
+
+func TestGuardHandler(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	g := &Guard{
+		Breaker: newTestBreaker(clock),
+		Limiter: NewRateLimiter(func() float64 { return 1 }, func() int { return 1 }),
+	}
+	g.Limiter.now = clock.Now
+	handler := g.Handler("c-1", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(userName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/k8s/clusters/c-1/api", nil)
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	assert.Equal(t, http.StatusOK, serve("u-1").Code)
+	rw := serve("u-1")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "1", rw.Header().Get("Retry-After"))
+	var status metav1.Status
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &status))
+	assert.Equal(t, metav1.StatusReasonTooManyRequests, status.Reason)
+
+	g.Breaker.record("c-1", errors.New("unreachable"))
+	g.Breaker.record("c-1", errors.New("unreachable"))
+	rw = serve("u-2")
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, "30", rw.Header().Get("Retry-After"))
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &status))
+	assert.Equal(t, metav1.StatusReasonServiceUnavailable, status.Reason)
+	assert.Contains(t, status.Message, "unreachable")
+}
+
+func TestNilGuard(t *testing.T) {
+	var g *Guard
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	assert.NotNil(t, g.Handler("c-1", next))
+	assert.NotNil(t, g.Dialer("c-1", func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, nil
+	}))
+}
@@ -0,0 +1,90 @@
+package circuit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// Guard combines the circuit breaker and the rate limiter of the requests proxied to the downstream clusters. A nil
+// Guard lets every request through.
+type Guard struct {
+	Breaker *Breaker
+	Limiter *RateLimiter
+}
+
+// NewGuard returns a guard configured by the cluster-proxy-* settings.
+func NewGuard() *Guard {
+	return &Guard{
+		Breaker: NewBreaker(
+			settings.ClusterProxyBreakerThreshold.GetInt,
+			func() time.Duration {
+				return time.Duration(settings.ClusterProxyBreakerCooldownSeconds.GetInt()) * time.Second
+			},
+		),
+		Limiter: NewRateLimiter(
+			func() float64 {
+				qps, _ := strconv.ParseFloat(settings.ClusterProxyUserQPS.Get(), 64)
+				return qps
+			},
+			settings.ClusterProxyUserBurst.GetInt,
+		),
+	}
+}
+
+// Dialer wraps the dialer of a cluster with its circuit breaker.
+func (g *Guard) Dialer(cluster string, dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	if g == nil {
+		return dial
+	}
+	return g.Breaker.Dialer(cluster, dial)
+}
+
+// Handler fails the requests to a cluster with a 503 while its breaker is open and with a 429 once their user exceeded
+// the rate limit, before they reach next. The errors are Kubernetes statuses, as the clients are mostly kubectl.
+func (g *Guard) Handler(cluster string, next http.Handler) http.Handler {
+	if g == nil {
+		return next
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if wait, err := g.Breaker.Rejects(cluster); err != nil {
+			incRejections(cluster)
+			writeStatus(rw, wait, apierrors.NewServiceUnavailable(fmt.Sprintf("cluster %s is unavailable: %v", cluster, err)).ErrStatus)
+			return
+		}
+
+		var userName string
+		if user, ok := request.UserFrom(req.Context()); ok {
+			userName = user.GetName()
+		}
+		if ok, wait := g.Limiter.Allow(userName, cluster); !ok {
+			writeStatus(rw, wait, apierrors.NewTooManyRequests(fmt.Sprintf("too many requests to cluster %s", cluster), retrySeconds(wait)).ErrStatus)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func retrySeconds(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
+
+func writeStatus(rw http.ResponseWriter, wait time.Duration, status metav1.Status) {
+	status.APIVersion = "v1"
+	status.Kind = "Status"
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Retry-After", strconv.Itoa(retrySeconds(wait)))
+	rw.WriteHeader(int(status.Code))
+	_ = json.NewEncoder(rw).Encode(status)
+}
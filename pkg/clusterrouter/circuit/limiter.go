@@ -0,0 +1,94 @@
+package circuit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiterIdleTTL is how long the limiter of a user and cluster is kept after its last request.
+	limiterIdleTTL = 10 * time.Minute
+	sweepInterval  = time.Minute
+)
+
+type limiterKey struct {
+	user    string
+	cluster string
+}
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter limits the rate of the requests each user proxies to each cluster with a token bucket.
+type RateLimiter struct {
+	lock      sync.Mutex
+	limiters  map[limiterKey]*limiterEntry
+	qps       func() float64
+	burst     func() int
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewRateLimiter returns a rate limiter reading its rate and burst on each use, so that they can be changed at
+// runtime. A rate of 0 or less disables the limiter.
+func NewRateLimiter(qps func() float64, burst func() int) *RateLimiter {
+	return &RateLimiter{
+		limiters: map[limiterKey]*limiterEntry{},
+		qps:      qps,
+		burst:    burst,
+		now:      time.Now,
+	}
+}
+
+// Allow returns whether the user may proxy a request to the cluster now and, if not, when to retry.
+func (l *RateLimiter) Allow(user, cluster string) (bool, time.Duration) {
+	qps := l.qps()
+	if qps <= 0 {
+		return true, 0
+	}
+	burst := l.burst()
+	if burst < 1 {
+		burst = int(math.Ceil(qps))
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	key := limiterKey{user: user, cluster: cluster}
+	entry, ok := l.limiters[key]
+	if !ok {
+		entry = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+		l.limiters[key] = entry
+	} else if entry.limiter.Limit() != rate.Limit(qps) || entry.limiter.Burst() != burst {
+		entry.limiter.SetLimitAt(now, rate.Limit(qps))
+		entry.limiter.SetBurstAt(now, burst)
+	}
+	entry.lastSeen = now
+
+	if entry.limiter.AllowN(now, 1) {
+		return true, 0
+	}
+	incRateLimited(cluster)
+	return false, time.Duration(float64(time.Second) / qps)
+}
+
+// sweep drops the limiters of the users who stopped sending requests.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, entry := range l.limiters {
+		if now.Sub(entry.lastSeen) > limiterIdleTTL {
+			delete(l.limiters, key)
+		}
+	}
+}
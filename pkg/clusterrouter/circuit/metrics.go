@@ -0,0 +1,68 @@
+package circuit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	prometheusMetrics = false
+
+	breakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "cluster_proxy",
+			Name:      "circuit_state",
+			Help:      "State of the circuit breaker of the requests proxied to a cluster: 0 closed, 1 open, 2 half-open",
+		},
+		[]string{"cluster"},
+	)
+
+	rejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_proxy",
+			Name:      "circuit_rejections_total",
+			Help:      "Total count of requests and dials to a cluster rejected by its open circuit breaker",
+		},
+		[]string{"cluster"},
+	)
+
+	rateLimited = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_proxy",
+			Name:      "rate_limited_total",
+			Help:      "Total count of requests to a cluster rejected as their user exceeded the proxy rate limit",
+		},
+		[]string{"cluster"},
+	)
+
+	// Collectors are the metrics labeled by cluster, so that the metrics of deleted clusters can be garbage collected.
+	Collectors = []interface{}{
+		breakerState, rejections, rateLimited,
+	}
+)
+
+// RegisterMetrics registers the cluster proxy metrics for Prometheus.
+func RegisterMetrics() {
+	prometheusMetrics = true
+
+	prometheus.MustRegister(breakerState)
+	prometheus.MustRegister(rejections)
+	prometheus.MustRegister(rateLimited)
+}
+
+func setBreakerState(cluster string, s state) {
+	if prometheusMetrics {
+		breakerState.WithLabelValues(cluster).Set(float64(s))
+	}
+}
+
+func incRejections(cluster string) {
+	if prometheusMetrics {
+		rejections.WithLabelValues(cluster).Inc()
+	}
+}
+
+func incRateLimited(cluster string) {
+	if prometheusMetrics {
+		rateLimited.WithLabelValues(cluster).Inc()
+	}
+}
@@ -5,6 +5,7 @@
 	"sync"
 
 	"github.com/moby/locker"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
 	"github.com/rancher/rancher/pkg/clusterrouter/proxy"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
@@ -20,9 +21,10 @@ type factory struct {
 	servers              sync.Map
 	localConfig          *rest.Config
 	clusterContextGetter proxy.ClusterContextGetter
+	guard                *circuit.Guard
 }
 
-func newFactory(localConfig *rest.Config, dialer dialer.Factory, lookup ClusterLookup, clusterLister v3.ClusterLister, clusterContextGetter proxy.ClusterContextGetter) *factory {
+func newFactory(localConfig *rest.Config, dialer dialer.Factory, lookup ClusterLookup, clusterLister v3.ClusterLister, clusterContextGetter proxy.ClusterContextGetter, guard *circuit.Guard) *factory {
 	return &factory{
 		dialerFactory:        dialer,
 		serverLock:           locker.New(),
@@ -30,6 +32,7 @@ func newFactory(localConfig *rest.Config, dialer dialer.Factory, lookup ClusterL
 		clusterLister:        clusterLister,
 		localConfig:          localConfig,
 		clusterContextGetter: clusterContextGetter,
+		guard:                guard,
 	}
 }
 
@@ -75,5 +78,5 @@ func (s *factory) get(req *http.Request) (*v3.Cluster, http.Handler, error) {
 }
 
 func (s *factory) newServer(c *v3.Cluster) (server, error) {
-	return proxy.New(s.localConfig, c, s.clusterLister, s.dialerFactory, s.clusterContextGetter)
+	return proxy.New(s.localConfig, c, s.clusterLister, s.dialerFactory, s.clusterContextGetter, s.guard)
 }
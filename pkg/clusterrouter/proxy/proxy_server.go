@@ -13,9 +13,11 @@
 
 	"github.com/rancher/norman/httperror"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
 	dialer2 "github.com/rancher/rancher/pkg/dialer"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/impersonation"
+	"github.com/rancher/rancher/pkg/tracing"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
 	"github.com/rancher/wrangler/pkg/schemas/validation"
@@ -45,6 +47,7 @@ type RemoteService struct {
 	localAuth            string
 	httpTransport        *http.Transport
 	clusterContextGetter ClusterContextGetter
+	guard                *circuit.Guard
 }
 
 var (
@@ -69,11 +72,11 @@ func prefix(cluster *v3.Cluster) string {
 	return "/k8s/clusters/" + cluster.Name
 }
 
-func New(localConfig *rest.Config, cluster *v3.Cluster, clusterLister v3.ClusterLister, factory dialer.Factory, clusterContextGetter ClusterContextGetter) (*RemoteService, error) {
+func New(localConfig *rest.Config, cluster *v3.Cluster, clusterLister v3.ClusterLister, factory dialer.Factory, clusterContextGetter ClusterContextGetter, guard *circuit.Guard) (*RemoteService, error) {
 	if cluster.Spec.Internal {
 		return NewLocal(localConfig, cluster)
 	}
-	return NewRemote(cluster, clusterLister, factory, clusterContextGetter)
+	return NewRemote(cluster, clusterLister, factory, clusterContextGetter, guard)
 }
 
 func NewLocal(localConfig *rest.Config, cluster *v3.Cluster) (*RemoteService, error) {
@@ -108,7 +111,7 @@ func NewLocal(localConfig *rest.Config, cluster *v3.Cluster) (*RemoteService, er
 	return rs, nil
 }
 
-func NewRemote(cluster *v3.Cluster, clusterLister v3.ClusterLister, factory dialer.Factory, clusterContextGetter ClusterContextGetter) (*RemoteService, error) {
+func NewRemote(cluster *v3.Cluster, clusterLister v3.ClusterLister, factory dialer.Factory, clusterContextGetter ClusterContextGetter, guard *circuit.Guard) (*RemoteService, error) {
 	if !v32.ClusterConditionProvisioned.IsTrue(cluster) {
 		return nil, httperror.NewAPIError(httperror.ClusterUnavailable, "cluster not provisioned")
 	}
@@ -132,6 +135,7 @@ func NewRemote(cluster *v3.Cluster, clusterLister v3.ClusterLister, factory dial
 		clusterLister:        clusterLister,
 		factory:              factory,
 		clusterContextGetter: clusterContextGetter,
+		guard:                guard,
 	}, nil
 }
 
@@ -170,7 +174,7 @@ func (r *RemoteService) getTransport() (http.RoundTripper, error) {
 		if err != nil {
 			return nil, err
 		}
-		transport.DialContext = d
+		transport.DialContext = tracing.DialContext(newCluster.Name, r.guard.Dialer(newCluster.Name, d))
 		if dialer2.IsPublicCloudDriver(newCluster) {
 			transport.Proxy = http.ProxyFromEnvironment
 		}
@@ -224,6 +228,7 @@ func (r *RemoteService) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		er.Error(rw, req, err)
 		return
 	}
+	transport = tracing.Transport(transport)
 
 	if r.cluster.Spec.Internal && r.localAuth == "" {
 		req.Header.Del("Authorization")
@@ -5,6 +5,7 @@
 	"net/http"
 
 	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
 	"github.com/rancher/rancher/pkg/clusterrouter/proxy"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
@@ -13,12 +14,14 @@
 
 type Router struct {
 	serverFactory *factory
+	guard         *circuit.Guard
 }
 
-func New(localConfig *rest.Config, lookup ClusterLookup, dialer dialer.Factory, clusterLister v3.ClusterLister, clusterContextGetter proxy.ClusterContextGetter) http.Handler {
-	serverFactory := newFactory(localConfig, dialer, lookup, clusterLister, clusterContextGetter)
+func New(localConfig *rest.Config, lookup ClusterLookup, dialer dialer.Factory, clusterLister v3.ClusterLister, clusterContextGetter proxy.ClusterContextGetter, guard *circuit.Guard) http.Handler {
+	serverFactory := newFactory(localConfig, dialer, lookup, clusterLister, clusterContextGetter, guard)
 	return &Router{
 		serverFactory: serverFactory,
+		guard:         guard,
 	}
 }
 
@@ -39,7 +42,7 @@ func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	handler.ServeHTTP(rw, req)
+	r.guard.Handler(c.Name, handler).ServeHTTP(rw, req)
 }
 
 func response(rw http.ResponseWriter, code httperror.ErrorCode, message string) {
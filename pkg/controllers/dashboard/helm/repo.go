@@ -6,6 +6,7 @@
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
@@ -186,14 +187,23 @@ func (r *repoHandler) ensure(repoSpec *catalog.RepoSpec, status catalog.RepoStat
 		return status, err
 	}
 
-	return status, git.Ensure(secret, metadata.Namespace, metadata.Name, status.URL, status.Commit, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
+	if err := git.Ensure(secret, metadata.Namespace, metadata.Name, status.URL, status.Commit, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle); err != nil {
+		return status, err
+	}
+	for branch, commit := range status.BranchCommits {
+		if err := git.EnsureBranch(secret, metadata.Namespace, metadata.Name, status.URL, branch, commit, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle); err != nil {
+			return status, err
+		}
+	}
+	return status, nil
 }
 
 func (r *repoHandler) download(repoSpec *catalog.RepoSpec, status catalog.RepoStatus, metadata *metav1.ObjectMeta, owner metav1.OwnerReference) (catalog.RepoStatus, error) {
 	var (
-		index  *repo.IndexFile
-		commit string
-		err    error
+		index         *repo.IndexFile
+		commit        string
+		branchCommits map[string]string
+		err           error
 	)
 
 	status.ObservedGeneration = metadata.Generation
@@ -204,26 +214,12 @@ func (r *repoHandler) download(repoSpec *catalog.RepoSpec, status catalog.RepoSt
 	}
 
 	downloadTime := metav1.Now()
-	if repoSpec.GitRepo != "" && status.IndexConfigMapName == "" {
-		commit, err = git.Head(secret, metadata.Namespace, metadata.Name, repoSpec.GitRepo, repoSpec.GitBranch, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
-		if err != nil {
-			return status, err
-		}
-		status.URL = repoSpec.GitRepo
-		status.Branch = repoSpec.GitBranch
-		index, err = git.BuildOrGetIndex(metadata.Namespace, metadata.Name, repoSpec.GitRepo)
-	} else if repoSpec.GitRepo != "" {
-		commit, err = git.Update(secret, metadata.Namespace, metadata.Name, repoSpec.GitRepo, repoSpec.GitBranch, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
-		if err != nil {
-			return status, err
-		}
-		status.URL = repoSpec.GitRepo
-		status.Branch = repoSpec.GitBranch
-		if status.Commit == commit {
+	if repoSpec.GitRepo != "" {
+		index, commit, branchCommits, err = gitIndex(secret, repoSpec, &status, metadata)
+		if err == nil && index == nil {
 			status.DownloadTime = downloadTime
 			return status, nil
 		}
-		index, err = git.BuildOrGetIndex(metadata.Namespace, metadata.Name, repoSpec.GitRepo)
 	} else if repoSpec.URL != "" {
 		status.URL = repoSpec.URL
 		status.Branch = ""
@@ -252,9 +248,75 @@ func (r *repoHandler) download(repoSpec *catalog.RepoSpec, status catalog.RepoSt
 	status.IndexConfigMapResourceVersion = cm.ResourceVersion
 	status.DownloadTime = downloadTime
 	status.Commit = commit
+	status.BranchCommits = branchCommits
 	return status, nil
 }
 
+// gitIndex checks out the refs the repo follows and indexes them. The returned index is nil if the repo was indexed
+// before and none of the checked out commits changed.
+func gitIndex(secret *corev1.Secret, repoSpec *catalog.RepoSpec, status *catalog.RepoStatus, metadata *metav1.ObjectMeta) (*repo.IndexFile, string, map[string]string, error) {
+	var (
+		// without an index the existing checkout, if any, is indexed as is
+		initial = status.IndexConfigMapName == ""
+		commit  string
+		tag     string
+		err     error
+	)
+
+	switch {
+	case repoSpec.GitTagSemver != "":
+		tag, commit, err = git.UpdateTag(secret, metadata.Namespace, metadata.Name, repoSpec.GitRepo, repoSpec.GitTagSemver, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
+	case initial:
+		commit, err = git.Head(secret, metadata.Namespace, metadata.Name, repoSpec.GitRepo, repoSpec.GitBranch, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
+	default:
+		commit, err = git.Update(secret, metadata.Namespace, metadata.Name, repoSpec.GitRepo, repoSpec.GitBranch, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var branchCommits map[string]string
+	for _, branch := range repoSpec.GitAdditionalBranches {
+		branchCommit, err := git.UpdateBranch(secret, metadata.Namespace, metadata.Name, repoSpec.GitRepo, branch, repoSpec.InsecureSkipTLSverify, repoSpec.CABundle)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if branchCommits == nil {
+			branchCommits = map[string]string{}
+		}
+		branchCommits[branch] = branchCommit
+	}
+
+	status.URL = repoSpec.GitRepo
+	status.Branch = repoSpec.GitBranch
+	status.Tag = tag
+	status.TagSemver = repoSpec.GitTagSemver
+	if !initial && status.Commit == commit && status.SubPath == repoSpec.GitSubPath && reflect.DeepEqual(status.BranchCommits, branchCommits) {
+		return nil, commit, branchCommits, nil
+	}
+	status.SubPath = repoSpec.GitSubPath
+
+	index, err := git.BuildOrGetIndex(metadata.Namespace, metadata.Name, repoSpec.GitRepo, git.IndexOptions{
+		SubPath: repoSpec.GitSubPath,
+		Commit:  commit,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	for _, branch := range repoSpec.GitAdditionalBranches {
+		branchIndex, err := git.BuildOrGetIndex(metadata.Namespace, metadata.Name, repoSpec.GitRepo, git.IndexOptions{
+			SubPath: repoSpec.GitSubPath,
+			Commit:  branchCommits[branch],
+			Branch:  branch,
+		})
+		if err != nil {
+			return nil, "", nil, err
+		}
+		mergeIndex(index, branchIndex)
+	}
+	return index, commit, branchCommits, nil
+}
+
 func (r *repoHandler) ensureIndexConfigMap(repo *catalog.ClusterRepo, status *catalog.RepoStatus) error {
 	// Charts from the clusterRepo will be unavailable if the IndexConfigMap recorded in the status does not exist.
 	// By resetting the value of IndexConfigMapName, IndexConfigMapNamespace, IndexConfigMapResourceVersion to "",
@@ -278,6 +340,12 @@ func shouldRefresh(spec *catalog.RepoSpec, status *catalog.RepoStatus) bool {
 	if spec.GitRepo != "" && status.Branch != spec.GitBranch {
 		return true
 	}
+	if spec.GitRepo != "" && (status.SubPath != spec.GitSubPath || status.TagSemver != spec.GitTagSemver) {
+		return true
+	}
+	if spec.GitRepo != "" && !sameBranches(spec.GitAdditionalBranches, status.BranchCommits) {
+		return true
+	}
 	if spec.URL != "" && spec.URL != status.URL {
 		return true
 	}
@@ -293,3 +361,32 @@ func shouldRefresh(spec *catalog.RepoSpec, status *catalog.RepoStatus) bool {
 	refreshTime := time.Now().Add(-interval)
 	return refreshTime.After(status.DownloadTime.Time)
 }
+
+// mergeIndex adds the chart versions of other to index. Unlike IndexFile.Merge it compares versions literally, so
+// versions that only differ in their build metadata are kept apart.
+func mergeIndex(index, other *repo.IndexFile) {
+	for name, versions := range other.Entries {
+		existing := map[string]bool{}
+		for _, version := range index.Entries[name] {
+			existing[version.Version] = true
+		}
+		for _, version := range versions {
+			if !existing[version.Version] {
+				index.Entries[name] = append(index.Entries[name], version)
+			}
+		}
+	}
+}
+
+// sameBranches reports whether commits holds exactly the given branches.
+func sameBranches(branches []string, commits map[string]string) bool {
+	if len(branches) != len(commits) {
+		return false
+	}
+	for _, branch := range branches {
+		if _, ok := commits[branch]; !ok {
+			return false
+		}
+	}
+	return true
+}
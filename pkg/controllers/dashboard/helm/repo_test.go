@@ -6,6 +6,8 @@
 
 	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
 	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -185,6 +187,75 @@ func TestShouldRefresh(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"git repo - sub path changed",
+			&catalog.RepoSpec{
+				GitBranch:  "master",
+				GitRepo:    "git.example.com",
+				GitSubPath: "charts",
+			},
+			&catalog.RepoStatus{
+				Branch:             "master",
+				URL:                "git.example.com",
+				IndexConfigMapName: "configmap",
+				DownloadTime: metav1.Time{
+					Time: time.Now(),
+				},
+			},
+			true,
+		},
+		{
+			"git repo - tag constraint changed",
+			&catalog.RepoSpec{
+				GitRepo:      "git.example.com",
+				GitTagSemver: "^2",
+			},
+			&catalog.RepoStatus{
+				URL:                "git.example.com",
+				TagSemver:          "^1",
+				IndexConfigMapName: "configmap",
+				DownloadTime: metav1.Time{
+					Time: time.Now(),
+				},
+			},
+			true,
+		},
+		{
+			"git repo - additional branches equal status",
+			&catalog.RepoSpec{
+				GitBranch:             "master",
+				GitRepo:               "git.example.com",
+				GitAdditionalBranches: []string{"dev"},
+			},
+			&catalog.RepoStatus{
+				Branch:             "master",
+				URL:                "git.example.com",
+				BranchCommits:      map[string]string{"dev": "abc"},
+				IndexConfigMapName: "configmap",
+				DownloadTime: metav1.Time{
+					Time: time.Now(),
+				},
+			},
+			false,
+		},
+		{
+			"git repo - additional branch added",
+			&catalog.RepoSpec{
+				GitBranch:             "master",
+				GitRepo:               "git.example.com",
+				GitAdditionalBranches: []string{"dev", "next"},
+			},
+			&catalog.RepoStatus{
+				Branch:             "master",
+				URL:                "git.example.com",
+				BranchCommits:      map[string]string{"dev": "abc"},
+				IndexConfigMapName: "configmap",
+				DownloadTime: metav1.Time{
+					Time: time.Now(),
+				},
+			},
+			true,
+		},
 		{
 			"http repo - spec equals status, but unnecessary git branch in spec",
 			&catalog.RepoSpec{
@@ -209,3 +280,19 @@ func TestShouldRefresh(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeIndex(t *testing.T) {
+	index := repo.NewIndexFile()
+	index.Entries["app"] = repo.ChartVersions{{Metadata: &chart.Metadata{Name: "app", Version: "1.0.0"}}}
+	other := repo.NewIndexFile()
+	other.Entries["app"] = repo.ChartVersions{
+		{Metadata: &chart.Metadata{Name: "app", Version: "1.0.0"}},
+		{Metadata: &chart.Metadata{Name: "app", Version: "1.1.0"}},
+	}
+	other.Entries["tool"] = repo.ChartVersions{{Metadata: &chart.Metadata{Name: "tool", Version: "0.1.0"}}}
+
+	mergeIndex(index, other)
+	assert.Len(t, index.Entries["app"], 2)
+	assert.Equal(t, "1.1.0", index.Entries["app"][1].Version)
+	assert.Len(t, index.Entries["tool"], 1)
+}
@@ -0,0 +1,45 @@
+// Package loglevels applies the log-levels setting to the Rancher server, so that the levels of its components follow
+// the setting on every replica.
+package loglevels
+
+import (
+	"context"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	managementcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/logging"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+)
+
+type handler struct {
+	// applied is the last value applied, only the handler of the setting changes it
+	applied string
+}
+
+func Register(ctx context.Context, settingController managementcontrollers.SettingController) {
+	h := &handler{}
+	settingController.OnChange(ctx, "log-levels", h.onChange)
+}
+
+func (h *handler) onChange(key string, setting *v3.Setting) (*v3.Setting, error) {
+	if setting == nil || setting.Name != settings.LogLevels.Name {
+		return setting, nil
+	}
+
+	value := setting.Value
+	if value == "" {
+		value = setting.Default
+	}
+	if value == h.applied {
+		return setting, nil
+	}
+	if err := logging.SetComponentLevels(value); err != nil {
+		// an invalid value is not retried, it has to be fixed in the setting
+		logrus.Errorf("Failed to apply setting %s: %v", setting.Name, err)
+		return setting, nil
+	}
+	h.applied = value
+	logrus.Infof("Component log levels set to [%s]", value)
+	return setting, nil
+}
@@ -3,6 +3,7 @@
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -66,6 +67,9 @@ func (c *crtbLifecycle) Create(obj *v3.ClusterRoleTemplateBinding) (runtime.Obje
 	if err != nil {
 		return nil, err
 	}
+	if expired, err := c.reconcileExpiry(obj); expired || err != nil {
+		return obj, err
+	}
 	err = c.reconcileBindings(obj)
 
 	return obj, err
@@ -79,20 +83,39 @@ func (c *crtbLifecycle) Updated(obj *v3.ClusterRoleTemplateBinding) (runtime.Obj
 	if err := c.reconcileLabels(obj); err != nil {
 		return nil, err
 	}
+	if expired, err := c.reconcileExpiry(obj); expired || err != nil {
+		return obj, err
+	}
 	err = c.reconcileBindings(obj)
 	return obj, err
 }
 
 func (c *crtbLifecycle) Remove(obj *v3.ClusterRoleTemplateBinding) (runtime.Object, error) {
-	if err := c.mgr.reconcileClusterMembershipBindingForDelete("", pkgrbac.GetRTBLabel(obj.ObjectMeta)); err != nil {
-		return nil, err
+	return nil, c.removeBindings(obj)
+}
+
+// reconcileExpiry removes the bindings of the CRTB once it expired, and enqueues it again at its expiry otherwise.
+func (c *crtbLifecycle) reconcileExpiry(binding *v3.ClusterRoleTemplateBinding) (bool, error) {
+	expired, remaining := pkgrbac.BindingExpired(binding, time.Now())
+	if !expired {
+		if remaining > 0 {
+			c.mgr.crtbs.Controller().EnqueueAfter(binding.Namespace, binding.Name, remaining)
+		}
+		return false, nil
 	}
-	if err := c.removeMGMTClusterScopedPrivilegesInProjectNamespace(obj); err != nil {
-		return nil, err
+	logrus.Debugf("[%v] ClusterRoleTemplateBinding %v/%v expired, removing its bindings", ctrbMGMTController, binding.Namespace, binding.Name)
+	return true, c.removeBindings(binding)
+}
+
+func (c *crtbLifecycle) removeBindings(binding *v3.ClusterRoleTemplateBinding) error {
+	if err := c.mgr.reconcileClusterMembershipBindingForDelete("", pkgrbac.GetRTBLabel(binding.ObjectMeta)); err != nil {
+		return err
+	}
+	if err := c.removeMGMTClusterScopedPrivilegesInProjectNamespace(binding); err != nil {
+		return err
 	}
 
-	err := c.mgr.removeAuthV2Permissions(authprovisioningv2.CRTBRoleBindingID, obj)
-	return nil, err
+	return c.mgr.removeAuthV2Permissions(authprovisioningv2.CRTBRoleBindingID, binding)
 }
 
 func (c *crtbLifecycle) reconcileSubject(binding *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
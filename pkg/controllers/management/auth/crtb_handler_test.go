@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/rancher/pkg/controllers/management/authprovisioningv2"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	fakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	rbacv1 "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1"
+	rbacfakes "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1/fakes"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/pkg/apply"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8srbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeRBAC struct {
+	rbacv1.Interface
+	roleBindings        *rbacfakes.RoleBindingInterfaceMock
+	clusterRoleBindings *rbacfakes.ClusterRoleBindingInterfaceMock
+}
+
+func (f *fakeRBAC) RoleBindings(string) rbacv1.RoleBindingInterface {
+	return f.roleBindings
+}
+
+func (f *fakeRBAC) ClusterRoleBindings(string) rbacv1.ClusterRoleBindingInterface {
+	return f.clusterRoleBindings
+}
+
+// newExpiryTestManager returns a manager listing the role bindings, and recording the names of the ones deleted.
+func newExpiryTestManager(roleBindings []*k8srbacv1.RoleBinding, deleted *[]string) *manager {
+	deleteFunc := func(namespace, name string, _ *metav1.DeleteOptions) error {
+		*deleted = append(*deleted, namespace+":"+name)
+		return nil
+	}
+	rbClient := &rbacfakes.RoleBindingInterfaceMock{
+		DeleteNamespacedFunc: deleteFunc,
+		ObjectClientFunc:     func() *objectclient.ObjectClient { return nil },
+	}
+	rbac := &fakeRBAC{
+		roleBindings: rbClient,
+		clusterRoleBindings: &rbacfakes.ClusterRoleBindingInterfaceMock{
+			ObjectClientFunc: func() *objectclient.ObjectClient { return nil },
+		},
+	}
+	// the mock is shared by all namespaces, so Delete only knows the name
+	rbClient.DeleteFunc = func(name string, opts *metav1.DeleteOptions) error {
+		return deleteFunc("", name, opts)
+	}
+
+	indexers := cache.Indexers{membershipBindingOwnerIndex: indexByMembershipBindingOwner}
+	return &manager{
+		mgmt: &config.ManagementContext{RBAC: rbac},
+		projectLister: &fakes.ProjectListerMock{
+			ListFunc: func(namespace string, _ labels.Selector) ([]*v3.Project, error) {
+				return []*v3.Project{{ObjectMeta: metav1.ObjectMeta{Name: "p-1", Namespace: namespace}}}, nil
+			},
+		},
+		rbLister: &rbacfakes.RoleBindingListerMock{
+			ListFunc: func(namespace string, selector labels.Selector) ([]*k8srbacv1.RoleBinding, error) {
+				var result []*k8srbacv1.RoleBinding
+				for _, rb := range roleBindings {
+					if (namespace == "" || rb.Namespace == namespace) && selector.Matches(labels.Set(rb.Labels)) {
+						result = append(result, rb)
+					}
+				}
+				return result, nil
+			},
+		},
+		rbClient:   rbClient,
+		rbIndexer:  cache.NewIndexer(cache.MetaNamespaceKeyFunc, indexers),
+		crbIndexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, indexers),
+		controller: ctrbMGMTController,
+	}
+}
+
+// authV2RoleBinding returns a role binding created by authprovisioningv2 for the binding.
+func authV2RoleBinding(t *testing.T, setID string, owner runtime.Object, namespace, name string) *k8srbacv1.RoleBinding {
+	ownerLabels, _, err := apply.GetLabelsAndAnnotations(setID, owner)
+	require.NoError(t, err)
+	return &k8srbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: ownerLabels}}
+}
+
+func TestCRTBExpiry(t *testing.T) {
+	crtb := &v3.ClusterRoleTemplateBinding{
+		TypeMeta: metav1.TypeMeta{APIVersion: "management.cattle.io/v3", Kind: "ClusterRoleTemplateBinding"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "crtb-1",
+			Namespace:   "c-1",
+			Labels:      map[string]string{RtbCrbRbLabelsUpdated: "true"},
+			Annotations: map[string]string{pkgrbac.ExpiresAtAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)},
+		},
+		UserName:          "u-1",
+		UserPrincipalName: "local://u-1",
+		ClusterName:       "c-1",
+		RoleTemplateName:  "cluster-member",
+	}
+	roleBindings := []*k8srbacv1.RoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rb-project", Namespace: "p-1", Labels: map[string]string{pkgrbac.GetRTBLabel(crtb.ObjectMeta): CrtbInProjectBindingOwner}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rb-other", Namespace: "p-1", Labels: map[string]string{"c-1_crtb-2": CrtbInProjectBindingOwner}}},
+		authV2RoleBinding(t, authprovisioningv2.CRTBRoleBindingID, crtb, "fleet-default", "rb-authv2"),
+	}
+	var deleted []string
+	var enqueued time.Duration
+	lifecycle := &crtbLifecycle{mgr: newExpiryTestManager(roleBindings, &deleted)}
+	lifecycle.mgr.crtbs = &fakes.ClusterRoleTemplateBindingInterfaceMock{
+		ControllerFunc: func() v3.ClusterRoleTemplateBindingController {
+			return &fakes.ClusterRoleTemplateBindingControllerMock{
+				EnqueueAfterFunc: func(_, _ string, after time.Duration) { enqueued = after },
+			}
+		},
+	}
+
+	_, err := lifecycle.Updated(crtb)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{":rb-project", "fleet-default:rb-authv2"}, deleted)
+	assert.Zero(t, enqueued)
+
+	crtb.Annotations[pkgrbac.ExpiresAtAnnotation] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	deleted = nil
+	expired, err := lifecycle.reconcileExpiry(crtb)
+	require.NoError(t, err)
+	assert.False(t, expired)
+	assert.Empty(t, deleted)
+	assert.InDelta(t, time.Hour, enqueued, float64(time.Minute))
+}
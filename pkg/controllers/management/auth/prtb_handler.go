@@ -3,6 +3,7 @@
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -60,6 +61,9 @@ func (p *prtbLifecycle) Create(obj *v3.ProjectRoleTemplateBinding) (runtime.Obje
 	if err != nil {
 		return nil, err
 	}
+	if expired, err := p.reconcileExpiry(obj); expired || err != nil {
+		return obj, err
+	}
 	err = p.reconcileBindings(obj)
 	return obj, err
 }
@@ -75,32 +79,50 @@ func (p *prtbLifecycle) Updated(obj *v3.ProjectRoleTemplateBinding) (runtime.Obj
 	if err := p.reconcileLabels(obj); err != nil {
 		return nil, err
 	}
+	if expired, err := p.reconcileExpiry(obj); expired || err != nil {
+		return obj, err
+	}
 	err = p.reconcileBindings(obj)
 	return obj, err
 }
 
 func (p *prtbLifecycle) Remove(obj *v3.ProjectRoleTemplateBinding) (runtime.Object, error) {
-	parts := strings.SplitN(obj.ProjectName, ":", 2)
+	return nil, p.removeBindings(obj)
+}
+
+// reconcileExpiry removes the bindings of the PRTB once it expired, and enqueues it again at its expiry otherwise.
+func (p *prtbLifecycle) reconcileExpiry(binding *v3.ProjectRoleTemplateBinding) (bool, error) {
+	expired, remaining := pkgrbac.BindingExpired(binding, time.Now())
+	if !expired {
+		if remaining > 0 {
+			p.mgr.prtbs.Controller().EnqueueAfter(binding.Namespace, binding.Name, remaining)
+		}
+		return false, nil
+	}
+	logrus.Debugf("[%v] ProjectRoleTemplateBinding %v/%v expired, removing its bindings", ptrbMGMTController, binding.Namespace, binding.Name)
+	return true, p.removeBindings(binding)
+}
+
+func (p *prtbLifecycle) removeBindings(binding *v3.ProjectRoleTemplateBinding) error {
+	parts := strings.SplitN(binding.ProjectName, ":", 2)
 	if len(parts) < 2 {
-		return nil, errors.Errorf("cannot determine project and cluster from %v", obj.ProjectName)
+		return errors.Errorf("cannot determine project and cluster from %v", binding.ProjectName)
 	}
 	clusterName := parts[0]
-	rtbNsAndName := pkgrbac.GetRTBLabel(obj.ObjectMeta)
+	rtbNsAndName := pkgrbac.GetRTBLabel(binding.ObjectMeta)
 	if err := p.mgr.reconcileProjectMembershipBindingForDelete(clusterName, "", rtbNsAndName); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := p.mgr.reconcileClusterMembershipBindingForDelete("", rtbNsAndName); err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := p.removeMGMTProjectScopedPrivilegesInClusterNamespace(obj, clusterName); err != nil {
-		return nil, err
+	if err := p.removeMGMTProjectScopedPrivilegesInClusterNamespace(binding, clusterName); err != nil {
+		return err
 	}
 
-	err := p.mgr.removeAuthV2Permissions(authprovisioningv2.PRTBRoleBindingID, obj)
-
-	return nil, err
+	return p.mgr.removeAuthV2Permissions(authprovisioningv2.PRTBRoleBindingID, binding)
 }
 
 func (p *prtbLifecycle) reconcileSubject(binding *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/controllers/management/authprovisioningv2"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	fakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8srbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPRTBExpiry(t *testing.T) {
+	prtb := &v3.ProjectRoleTemplateBinding{
+		TypeMeta: metav1.TypeMeta{APIVersion: "management.cattle.io/v3", Kind: "ProjectRoleTemplateBinding"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "prtb-1",
+			Namespace:   "p-1",
+			Labels:      map[string]string{RtbCrbRbLabelsUpdated: "true"},
+			Annotations: map[string]string{pkgrbac.ExpiresAtAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)},
+		},
+		UserName:          "u-1",
+		UserPrincipalName: "local://u-1",
+		ProjectName:       "c-1:p-1",
+		RoleTemplateName:  "project-member",
+	}
+	subjects := []k8srbacv1.Subject{{Kind: k8srbacv1.UserKind, Name: "u-1"}}
+	roleBindings := []*k8srbacv1.RoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rb-cluster", Namespace: "c-1", Labels: map[string]string{pkgrbac.GetRTBLabel(prtb.ObjectMeta): PrtbInClusterBindingOwner}}, Subjects: subjects},
+		authV2RoleBinding(t, authprovisioningv2.PRTBRoleBindingID, prtb, "fleet-default", "rb-authv2"),
+	}
+	var deleted []string
+	var enqueued time.Duration
+	lifecycle := &prtbLifecycle{mgr: newExpiryTestManager(roleBindings, &deleted)}
+	lifecycle.mgr.prtbs = &fakes.ProjectRoleTemplateBindingInterfaceMock{
+		ControllerFunc: func() v3.ProjectRoleTemplateBindingController {
+			return &fakes.ProjectRoleTemplateBindingControllerMock{
+				EnqueueAfterFunc: func(_, _ string, after time.Duration) { enqueued = after },
+			}
+		},
+	}
+
+	_, err := lifecycle.Updated(prtb)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{":rb-cluster", "fleet-default:rb-authv2"}, deleted)
+	assert.Zero(t, enqueued)
+
+	prtb.Annotations[pkgrbac.ExpiresAtAnnotation] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	deleted = nil
+	expired, err := lifecycle.reconcileExpiry(prtb)
+	require.NoError(t, err)
+	assert.False(t, expired)
+	assert.Empty(t, deleted)
+	assert.InDelta(t, time.Hour, enqueued, float64(time.Minute))
+}
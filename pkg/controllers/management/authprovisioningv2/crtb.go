@@ -39,6 +39,13 @@ func (h *handler) OnCRTB(key string, crtb *v3.ClusterRoleTemplateBinding) (*v3.C
 
 	cluster := clusters[0]
 
+	if expired, remaining := rbac.BindingExpired(crtb, time.Now()); expired {
+		logrus.Debugf("[auth-prov-v2-crtb] CRTB %v/%v expired, removing its bindings", crtb.Namespace, crtb.Name)
+		return crtb, h.removeRoleBindings(cluster.Namespace, CRTBRoleBindingID, crtb)
+	} else if remaining > 0 {
+		h.clusterRoleTemplateBindingController.EnqueueAfter(crtb.Namespace, crtb.Name, remaining)
+	}
+
 	rt, err := h.roleTemplatesCache.Get(crtb.RoleTemplateName)
 	if err != nil {
 		return crtb, err
@@ -0,0 +1,62 @@
+package authprovisioningv2
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	provisioningv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/wrangler/pkg/apply/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type indexedClusterCache struct {
+	mockCluster
+	clusters []*provisioningv1.Cluster
+}
+
+func (c indexedClusterCache) GetByIndex(indexName, key string) ([]*provisioningv1.Cluster, error) {
+	return c.clusters, nil
+}
+
+type enqueueCRTBController struct {
+	mgmtcontrollers.ClusterRoleTemplateBindingController
+	enqueued time.Duration
+}
+
+func (c *enqueueCRTBController) EnqueueAfter(namespace, name string, duration time.Duration) {
+	c.enqueued = duration
+}
+
+func Test_OnCRTBExpired(t *testing.T) {
+	applier := &fake.FakeApply{}
+	controller := &enqueueCRTBController{}
+	h := &handler{
+		clusters: indexedClusterCache{clusters: []*provisioningv1.Cluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "fleet-default"}},
+		}},
+		clusterRoleTemplateBindingController: controller,
+		roleBindingApply:                     applier,
+	}
+	crtb := &v3.ClusterRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "crtb-1",
+			Namespace:   "c-1",
+			Annotations: map[string]string{rbac.ExpiresAtAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)},
+		},
+		UserName:         "u-1",
+		ClusterName:      "c-1",
+		RoleTemplateName: "cluster-member",
+	}
+
+	// a resync of the expired CRTB removes its role bindings rather than recreating them
+	_, err := h.OnCRTB("", crtb)
+	require.NoError(t, err)
+	require.Len(t, applier.Objects, 1)
+	assert.Zero(t, applier.Objects[0].Len())
+	assert.Zero(t, controller.enqueued)
+}
@@ -14,6 +14,7 @@
 	"github.com/sirupsen/logrus"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const PRTBRoleBindingID = "auth-prov-v2-prtb-rolebinding"
@@ -47,6 +48,13 @@ func (h *handler) OnPRTB(key string, prtb *v3.ProjectRoleTemplateBinding) (*v3.P
 
 	cluster := clusters[0]
 
+	if expired, remaining := rbac.BindingExpired(prtb, time.Now()); expired {
+		logrus.Debugf("[auth-prov-v2-prtb] PRTB %v/%v expired, removing its bindings", prtb.Namespace, prtb.Name)
+		return prtb, h.removeRoleBindings(cluster.Namespace, PRTBRoleBindingID, prtb)
+	} else if remaining > 0 {
+		h.projectRoleTemplateBindingController.EnqueueAfter(prtb.Namespace, prtb.Name, remaining)
+	}
+
 	err = h.ensureClusterViewBinding(cluster, prtb)
 
 	return prtb, err
@@ -90,6 +98,15 @@ func (h *handler) ensureClusterViewBinding(cluster *v1.Cluster, prtb *v3.Project
 		ApplyObjects(roleBinding)
 }
 
+// removeRoleBindings removes the RoleBindings applied for the owner under the set ID.
+func (h *handler) removeRoleBindings(namespace, setID string, owner runtime.Object) error {
+	return h.roleBindingApply.
+		WithListerNamespace(namespace).
+		WithSetID(setID).
+		WithOwner(owner).
+		ApplyObjects()
+}
+
 func clusterViewName(cluster *v1.Cluster) string {
 	return name.SafeConcatName("r-cluster", cluster.Name, "view")
 }
@@ -0,0 +1,207 @@
+// Package bindingrequest creates the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings of the approved
+// RoleTemplateBindingRequests, and removes them once they expire.
+package bindingrequest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	managementv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/rancher/wrangler/pkg/name"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	handlerName       = "binding-request"
+	removeHandlerName = "binding-request-remove"
+	// RequestLabel is set on the bindings created for a request, to the name of the request.
+	RequestLabel = "authz.management.cattle.io/binding-request"
+)
+
+type handler struct {
+	requests      managementv3.RoleTemplateBindingRequestClient
+	enqueueAfter  func(string, string, time.Duration)
+	roleTemplates managementv3.RoleTemplateCache
+	crtbs         managementv3.ClusterRoleTemplateBindingClient
+	prtbs         managementv3.ProjectRoleTemplateBindingClient
+	now           func() time.Time
+}
+
+func Register(ctx context.Context, wContext *wrangler.Context) {
+	h := handler{
+		requests:      wContext.Mgmt.RoleTemplateBindingRequest(),
+		enqueueAfter:  wContext.Mgmt.RoleTemplateBindingRequest().EnqueueAfter,
+		roleTemplates: wContext.Mgmt.RoleTemplate().Cache(),
+		crtbs:         wContext.Mgmt.ClusterRoleTemplateBinding(),
+		prtbs:         wContext.Mgmt.ProjectRoleTemplateBinding(),
+		now:           time.Now,
+	}
+	wContext.Mgmt.RoleTemplateBindingRequest().OnChange(ctx, handlerName, h.sync)
+	wContext.Mgmt.RoleTemplateBindingRequest().OnRemove(ctx, removeHandlerName, h.remove)
+}
+
+func (h *handler) sync(_ string, obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	if obj == nil || obj.DeletionTimestamp != nil {
+		return obj, nil
+	}
+
+	switch obj.Status.Phase {
+	case "":
+		return h.admit(obj)
+	case v3.RoleTemplateBindingRequestApproved:
+		return h.createBinding(obj)
+	case v3.RoleTemplateBindingRequestActive:
+		return h.expire(obj)
+	}
+	return obj, nil
+}
+
+// remove deletes the binding of an active request, revoking the access it granted.
+func (h *handler) remove(_ string, obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	if obj.Status.Phase != v3.RoleTemplateBindingRequestActive {
+		return obj, nil
+	}
+	return obj, h.deleteBinding(obj)
+}
+
+// admit moves a new request to the pending phase, or denies it if it cannot be granted.
+func (h *handler) admit(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	obj = obj.DeepCopy()
+	obj.Status.Phase = v3.RoleTemplateBindingRequestPending
+	if err := h.validate(obj); err != nil {
+		obj.Status.Phase = v3.RoleTemplateBindingRequestDenied
+		obj.Status.Message = err.Error()
+	}
+	return h.requests.UpdateStatus(obj)
+}
+
+func (h *handler) validate(obj *v3.RoleTemplateBindingRequest) error {
+	spec := obj.Spec
+	if (spec.UserName == "") == (spec.GroupPrincipalName == "") {
+		return fmt.Errorf("exactly one of userName and groupPrincipalName must be set")
+	}
+	if spec.ClusterName != obj.Namespace {
+		return fmt.Errorf("the request must be in the namespace of cluster %s", spec.ClusterName)
+	}
+	roleContext := "cluster"
+	if spec.ProjectName != "" {
+		roleContext = "project"
+		if !strings.HasPrefix(spec.ProjectName, spec.ClusterName+":") {
+			return fmt.Errorf("project %s is not in cluster %s", spec.ProjectName, spec.ClusterName)
+		}
+	}
+	if spec.Duration.Duration < 0 {
+		return fmt.Errorf("duration must not be negative")
+	}
+
+	rt, err := h.roleTemplates.Get(spec.RoleTemplateName)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("role template %s does not exist", spec.RoleTemplateName)
+	} else if err != nil {
+		return err
+	}
+	if rt.Context != roleContext {
+		return fmt.Errorf("role template %s is not a %s role", rt.Name, roleContext)
+	}
+	if rt.Locked {
+		return fmt.Errorf("role template %s is locked", rt.Name)
+	}
+	return nil
+}
+
+// createBinding creates the binding of an approved request, expiring at the expiry set on the request when it was
+// approved, so that retries create the binding with the same expiry.
+func (h *handler) createBinding(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	if obj.Spec.Duration.Duration > 0 && obj.Status.ExpiresAt == "" {
+		// approved without an expiry, such as by a Rancher server predating it: set it before creating the binding
+		obj = obj.DeepCopy()
+		obj.Status.ExpiresAt = h.now().Add(obj.Spec.Duration.Duration).UTC().Format(time.RFC3339)
+		return h.requests.UpdateStatus(obj)
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:   name.SafeConcatName("rtbr", obj.Name),
+		Labels: map[string]string{RequestLabel: obj.Name},
+	}
+	if obj.Status.ExpiresAt != "" {
+		meta.Annotations = map[string]string{rbac.ExpiresAtAnnotation: obj.Status.ExpiresAt}
+	}
+
+	var err error
+	if obj.Spec.ProjectName != "" {
+		meta.Namespace = strings.TrimPrefix(obj.Spec.ProjectName, obj.Spec.ClusterName+":")
+		_, err = h.prtbs.Create(&v3.ProjectRoleTemplateBinding{
+			ObjectMeta:         meta,
+			UserName:           obj.Spec.UserName,
+			GroupPrincipalName: obj.Spec.GroupPrincipalName,
+			ProjectName:        obj.Spec.ProjectName,
+			RoleTemplateName:   obj.Spec.RoleTemplateName,
+		})
+	} else {
+		meta.Namespace = obj.Spec.ClusterName
+		_, err = h.crtbs.Create(&v3.ClusterRoleTemplateBinding{
+			ObjectMeta:         meta,
+			UserName:           obj.Spec.UserName,
+			GroupPrincipalName: obj.Spec.GroupPrincipalName,
+			ClusterName:        obj.Spec.ClusterName,
+			RoleTemplateName:   obj.Spec.RoleTemplateName,
+		})
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return obj, err
+	}
+
+	logrus.Infof("Created binding %s/%s for approved request %s/%s", meta.Namespace, meta.Name, obj.Namespace, obj.Name)
+	obj = obj.DeepCopy()
+	obj.Status.Phase = v3.RoleTemplateBindingRequestActive
+	obj.Status.BindingName = meta.Namespace + ":" + meta.Name
+	return h.requests.UpdateStatus(obj)
+}
+
+// expire deletes the binding of an active request once it expired, and enqueues the request again at its expiry
+// otherwise.
+func (h *handler) expire(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	if obj.Status.ExpiresAt == "" {
+		return obj, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, obj.Status.ExpiresAt)
+	if err != nil {
+		logrus.Warnf("Request %s/%s has an invalid expiry %q, treating it as expired", obj.Namespace, obj.Name, obj.Status.ExpiresAt)
+	} else if remaining := expiresAt.Sub(h.now()); remaining > 0 {
+		h.enqueueAfter(obj.Namespace, obj.Name, remaining)
+		return obj, nil
+	}
+
+	if err := h.deleteBinding(obj); err != nil {
+		return obj, err
+	}
+	obj = obj.DeepCopy()
+	obj.Status.Phase = v3.RoleTemplateBindingRequestExpired
+	obj.Status.Message = "binding expired and was removed"
+	return h.requests.UpdateStatus(obj)
+}
+
+func (h *handler) deleteBinding(obj *v3.RoleTemplateBindingRequest) error {
+	namespace, bindingName, ok := strings.Cut(obj.Status.BindingName, ":")
+	if !ok {
+		return nil
+	}
+
+	var err error
+	if obj.Spec.ProjectName != "" {
+		err = h.prtbs.Delete(namespace, bindingName, &metav1.DeleteOptions{})
+	} else {
+		err = h.crtbs.Delete(namespace, bindingName, &metav1.DeleteOptions{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
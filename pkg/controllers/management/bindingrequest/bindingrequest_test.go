@@ -0,0 +1,214 @@
+package bindingrequest
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	managementv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeRequestClient struct {
+	managementv3.RoleTemplateBindingRequestClient
+}
+
+func (f *fakeRequestClient) UpdateStatus(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	return obj, nil
+}
+
+type fakeRoleTemplateCache struct {
+	managementv3.RoleTemplateCache
+	templates map[string]*v3.RoleTemplate
+}
+
+func (f *fakeRoleTemplateCache) Get(name string) (*v3.RoleTemplate, error) {
+	if rt, ok := f.templates[name]; ok {
+		return rt, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+}
+
+type fakeCRTBClient struct {
+	managementv3.ClusterRoleTemplateBindingClient
+	created []*v3.ClusterRoleTemplateBinding
+	deleted []string
+}
+
+func (f *fakeCRTBClient) Create(obj *v3.ClusterRoleTemplateBinding) (*v3.ClusterRoleTemplateBinding, error) {
+	f.created = append(f.created, obj)
+	return obj, nil
+}
+
+func (f *fakeCRTBClient) Delete(namespace, name string, _ *metav1.DeleteOptions) error {
+	f.deleted = append(f.deleted, namespace+":"+name)
+	return nil
+}
+
+type fakePRTBClient struct {
+	managementv3.ProjectRoleTemplateBindingClient
+	created []*v3.ProjectRoleTemplateBinding
+}
+
+func (f *fakePRTBClient) Create(obj *v3.ProjectRoleTemplateBinding) (*v3.ProjectRoleTemplateBinding, error) {
+	f.created = append(f.created, obj)
+	return obj, nil
+}
+
+func newTestHandler(now time.Time) (*handler, *fakeCRTBClient, *fakePRTBClient) {
+	crtbs, prtbs := &fakeCRTBClient{}, &fakePRTBClient{}
+	return &handler{
+		requests:     &fakeRequestClient{},
+		enqueueAfter: func(string, string, time.Duration) {},
+		roleTemplates: &fakeRoleTemplateCache{templates: map[string]*v3.RoleTemplate{
+			"cluster-member": {ObjectMeta: metav1.ObjectMeta{Name: "cluster-member"}, Context: "cluster"},
+			"project-member": {ObjectMeta: metav1.ObjectMeta{Name: "project-member"}, Context: "project"},
+			"locked":         {ObjectMeta: metav1.ObjectMeta{Name: "locked"}, Context: "cluster", Locked: true},
+		}},
+		crtbs: crtbs,
+		prtbs: prtbs,
+		now:   func() time.Time { return now },
+	}, crtbs, prtbs
+}
+
+func newRequest(spec v3.RoleTemplateBindingRequestSpec) *v3.RoleTemplateBindingRequest {
+	return &v3.RoleTemplateBindingRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall", Namespace: "c-1"},
+		Spec:       spec,
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	h, _, _ := newTestHandler(time.Now())
+
+	tests := []struct {
+		name    string
+		spec    v3.RoleTemplateBindingRequestSpec
+		message string
+	}{
+		{
+			name: "cluster role",
+			spec: v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", RoleTemplateName: "cluster-member"},
+		},
+		{
+			name: "project role",
+			spec: v3.RoleTemplateBindingRequestSpec{GroupPrincipalName: "github_team://1", ClusterName: "c-1", ProjectName: "c-1:p-1", RoleTemplateName: "project-member"},
+		},
+		{
+			name:    "no subject",
+			spec:    v3.RoleTemplateBindingRequestSpec{ClusterName: "c-1", RoleTemplateName: "cluster-member"},
+			message: "exactly one of userName and groupPrincipalName must be set",
+		},
+		{
+			name:    "other cluster",
+			spec:    v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-2", RoleTemplateName: "cluster-member"},
+			message: "the request must be in the namespace of cluster c-2",
+		},
+		{
+			name:    "project of other cluster",
+			spec:    v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", ProjectName: "c-2:p-1", RoleTemplateName: "project-member"},
+			message: "project c-2:p-1 is not in cluster c-1",
+		},
+		{
+			name:    "wrong context",
+			spec:    v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", RoleTemplateName: "project-member"},
+			message: "role template project-member is not a cluster role",
+		},
+		{
+			name:    "locked",
+			spec:    v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", RoleTemplateName: "locked"},
+			message: "role template locked is locked",
+		},
+		{
+			name:    "missing role",
+			spec:    v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", RoleTemplateName: "missing"},
+			message: "role template missing does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := h.sync("", newRequest(tt.spec))
+			require.NoError(t, err)
+			assert.Equal(t, tt.message, obj.Status.Message)
+			if tt.message == "" {
+				assert.Equal(t, v3.RoleTemplateBindingRequestPending, obj.Status.Phase)
+			} else {
+				assert.Equal(t, v3.RoleTemplateBindingRequestDenied, obj.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestCreateBinding(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	h, crtbs, prtbs := newTestHandler(now)
+
+	obj := newRequest(v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", RoleTemplateName: "cluster-member", Duration: metav1.Duration{Duration: 4 * time.Hour}})
+	obj.Status.Phase = v3.RoleTemplateBindingRequestApproved
+	obj, err := h.sync("", obj)
+	require.NoError(t, err)
+	assert.Equal(t, v3.RoleTemplateBindingRequestApproved, obj.Status.Phase)
+	assert.Equal(t, "2023-03-01T16:00:00Z", obj.Status.ExpiresAt, "the expiry must be set before creating the binding")
+	assert.Empty(t, crtbs.created)
+
+	// the expiry set on the request is reused by the retries, however late
+	h.now = func() time.Time { return now.Add(time.Minute) }
+	obj, err = h.sync("", obj)
+	require.NoError(t, err)
+	assert.Equal(t, v3.RoleTemplateBindingRequestActive, obj.Status.Phase)
+	assert.Equal(t, "c-1:rtbr-oncall", obj.Status.BindingName)
+	assert.Equal(t, "2023-03-01T16:00:00Z", obj.Status.ExpiresAt)
+	require.Len(t, crtbs.created, 1)
+	crtb := crtbs.created[0]
+	assert.Equal(t, "c-1", crtb.Namespace)
+	assert.Equal(t, "u-1", crtb.UserName)
+	assert.Equal(t, "cluster-member", crtb.RoleTemplateName)
+	assert.Equal(t, "2023-03-01T16:00:00Z", crtb.Annotations[rbac.ExpiresAtAnnotation])
+	assert.Equal(t, "oncall", crtb.Labels[RequestLabel])
+
+	obj = newRequest(v3.RoleTemplateBindingRequestSpec{GroupPrincipalName: "github_team://1", ClusterName: "c-1", ProjectName: "c-1:p-1", RoleTemplateName: "project-member"})
+	obj.Status.Phase = v3.RoleTemplateBindingRequestApproved
+	obj, err = h.sync("", obj)
+	require.NoError(t, err)
+	assert.Equal(t, "p-1:rtbr-oncall", obj.Status.BindingName)
+	assert.Empty(t, obj.Status.ExpiresAt)
+	require.Len(t, prtbs.created, 1)
+	assert.Equal(t, "c-1:p-1", prtbs.created[0].ProjectName)
+	assert.NotContains(t, prtbs.created[0].Annotations, rbac.ExpiresAtAnnotation)
+}
+
+func TestExpire(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	h, crtbs, _ := newTestHandler(now)
+	var enqueued time.Duration
+	h.enqueueAfter = func(_, _ string, after time.Duration) {
+		enqueued = after
+	}
+
+	obj := newRequest(v3.RoleTemplateBindingRequestSpec{UserName: "u-1", ClusterName: "c-1", RoleTemplateName: "cluster-member"})
+	obj.Status = v3.RoleTemplateBindingRequestStatus{
+		Phase:       v3.RoleTemplateBindingRequestActive,
+		BindingName: "c-1:rtbr-oncall",
+		ExpiresAt:   "2023-03-01T12:30:00Z",
+	}
+	got, err := h.sync("", obj)
+	require.NoError(t, err)
+	assert.Equal(t, v3.RoleTemplateBindingRequestActive, got.Status.Phase)
+	assert.Equal(t, 30*time.Minute, enqueued)
+	assert.Empty(t, crtbs.deleted)
+
+	h.now = func() time.Time { return now.Add(time.Hour) }
+	got, err = h.sync("", obj)
+	require.NoError(t, err)
+	assert.Equal(t, v3.RoleTemplateBindingRequestExpired, got.Status.Phase)
+	assert.Equal(t, []string{"c-1:rtbr-oncall"}, crtbs.deleted)
+
+	_, err = h.remove("", obj)
+	require.NoError(t, err)
+	assert.Len(t, crtbs.deleted, 2, "removing an active request must delete its binding")
+}
@@ -0,0 +1,321 @@
+package clustertemplate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types/convert"
+	clusterstore "github.com/rancher/rancher/pkg/api/norman/store/cluster"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	managementv3 "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/secretmigrator"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/rancher/pkg/types/config/dialer"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	MigrationController = "mgmt-cluster-template-migration-controller"
+	// migrationRequeue is how often a migration checks on the clusters it is migrating.
+	migrationRequeue = 15 * time.Second
+)
+
+// migrationController migrates the clusters of a ClusterTemplateRevision migration to the revision, a batch at a
+// time.
+type migrationController struct {
+	clusters                          v3.ClusterInterface
+	clusterLister                     v3.ClusterLister
+	clusterTemplateRevisions          v3.ClusterTemplateRevisionInterface
+	clusterTemplateRevisionController v3.ClusterTemplateRevisionController
+	clusterTemplateLister             v3.ClusterTemplateLister
+	nodeLister                        v3.NodeLister
+	dialerFactory                     dialer.Factory
+}
+
+func registerMigrationController(ctx context.Context, management *config.ManagementContext) {
+	m := &migrationController{
+		clusters:                          management.Management.Clusters(""),
+		clusterLister:                     management.Management.Clusters("").Controller().Lister(),
+		clusterTemplateRevisions:          management.Management.ClusterTemplateRevisions(""),
+		clusterTemplateRevisionController: management.Management.ClusterTemplateRevisions("").Controller(),
+		clusterTemplateLister:             management.Management.ClusterTemplates("").Controller().Lister(),
+		nodeLister:                        management.Management.Nodes("").Controller().Lister(),
+		dialerFactory:                     management.Dialer,
+	}
+	management.Management.ClusterTemplateRevisions("").AddHandler(ctx, MigrationController, m.sync)
+}
+
+func (m *migrationController) sync(key string, obj *v3.ClusterTemplateRevision) (runtime.Object, error) {
+	if obj == nil || obj.DeletionTimestamp != nil || obj.Status.Migration == nil ||
+		obj.Status.Migration.State != v32.ClusterTemplateMigrationStateMigrating {
+		return obj, nil
+	}
+
+	// the clusters get the secrets of the revision through their own, so these must be migrated out of its config first
+	if !v32.ClusterTemplateRevisionConditionSecretsMigrated.IsTrue(obj) ||
+		!v32.ClusterTemplateRevisionConditionACISecretsMigrated.IsTrue(obj) ||
+		!v32.ClusterTemplateRevisionConditionRKESecretsMigrated.IsTrue(obj) {
+		m.clusterTemplateRevisionController.EnqueueAfter(obj.Namespace, obj.Name, migrationRequeue)
+		return obj, nil
+	}
+
+	migration, err := m.migrate(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	if !reflect.DeepEqual(migration, obj.Status.Migration) {
+		revision := obj.DeepCopy()
+		revision.Status.Migration = migration
+		if obj, err = m.clusterTemplateRevisions.Update(revision); err != nil {
+			return obj, err
+		}
+	}
+	if migration.State == v32.ClusterTemplateMigrationStateMigrating {
+		m.clusterTemplateRevisionController.EnqueueAfter(obj.Namespace, obj.Name, migrationRequeue)
+	}
+	return obj, nil
+}
+
+// migrate checks on the clusters of the current batch of the migration of a revision and starts the next batch once
+// they are all migrated. It returns the updated migration.
+func (m *migrationController) migrate(revision *v3.ClusterTemplateRevision) (*v32.ClusterTemplateMigration, error) {
+	migration := revision.Status.Migration.DeepCopy()
+	revisionName := revision.Namespace + ":" + revision.Name
+
+	var migrating []string
+	for _, name := range migration.Migrating {
+		cluster, err := m.clusterLister.Get("", name)
+		if apierrors.IsNotFound(err) {
+			migration.Failed = append(migration.Failed, v32.ClusterTemplateMigrationFailure{ClusterName: name, Message: "cluster not found"})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		switch {
+		case clusterMigrated(cluster, revisionName):
+			migration.Migrated = append(migration.Migrated, name)
+		case clusterMigrationFailed(cluster, revisionName):
+			migration.Failed = append(migration.Failed, v32.ClusterTemplateMigrationFailure{
+				ClusterName: name,
+				Message:     fmt.Sprintf("failed to provision the cluster: %s", v32.ClusterConditionUpdated.GetMessage(cluster)),
+			})
+		default:
+			migrating = append(migrating, name)
+		}
+	}
+	migration.Migrating = migrating
+	if len(migration.Migrating) > 0 {
+		return migration, nil
+	}
+
+	// a failure stops the migration once the clusters of its batch are done
+	if len(migration.Failed) == 0 {
+		for _, name := range nextBatch(migration) {
+			if err := m.startClusterMigration(name, revision); err != nil {
+				if _, ok := err.(*migrationError); !ok {
+					return nil, err
+				}
+				migration.Failed = append(migration.Failed, v32.ClusterTemplateMigrationFailure{ClusterName: name, Message: err.Error()})
+				continue
+			}
+			migration.Migrating = append(migration.Migrating, name)
+		}
+		if len(migration.Migrating) > 0 {
+			return migration, nil
+		}
+	}
+
+	if len(migration.Failed) > 0 {
+		migration.State = v32.ClusterTemplateMigrationStateFailed
+		migration.Message = fmt.Sprintf("failed to migrate %d of %d clusters", len(migration.Failed), len(migration.ClusterNames))
+	} else {
+		migration.State = v32.ClusterTemplateMigrationStateCompleted
+		migration.Message = ""
+	}
+	migration.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	return migration, nil
+}
+
+// nextBatch returns the next clusters of a migration to migrate.
+func nextBatch(migration *v32.ClusterTemplateMigration) []string {
+	done := map[string]bool{}
+	for _, name := range migration.Migrated {
+		done[name] = true
+	}
+	for _, failure := range migration.Failed {
+		done[failure.ClusterName] = true
+	}
+
+	batchSize := migration.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	var batch []string
+	for _, name := range migration.ClusterNames {
+		if done[name] {
+			continue
+		}
+		batch = append(batch, name)
+		if len(batch) == batchSize {
+			break
+		}
+	}
+	return batch
+}
+
+// clusterMigrated returns whether a cluster is provisioned with a revision and ready.
+func clusterMigrated(cluster *v3.Cluster, revisionName string) bool {
+	return cluster.Spec.ClusterTemplateRevisionName == revisionName &&
+		cluster.Status.AppliedSpec.ClusterTemplateRevisionName == revisionName &&
+		v32.ClusterConditionUpdated.IsTrue(cluster) &&
+		v32.ClusterConditionReady.IsTrue(cluster)
+}
+
+// clusterMigrationFailed returns whether a cluster failed to be provisioned with a revision, or was moved to another
+// revision during the migration.
+func clusterMigrationFailed(cluster *v3.Cluster, revisionName string) bool {
+	if cluster.Spec.ClusterTemplateRevisionName != revisionName {
+		return true
+	}
+	return cluster.Status.FailedSpec != nil && cluster.Status.FailedSpec.ClusterTemplateRevisionName == revisionName
+}
+
+// migrationError is an error that fails the migration of a cluster, rather than one to retry.
+type migrationError struct {
+	message string
+}
+
+func (e *migrationError) Error() string {
+	return e.message
+}
+
+func newMigrationError(format string, args ...interface{}) error {
+	return &migrationError{message: fmt.Sprintf(format, args...)}
+}
+
+// newUpdateError returns the migration error of an update of a cluster to a revision failing to be loaded or
+// validated.
+func newUpdateError(err error) error {
+	if apiErr, ok := err.(*httperror.APIError); ok {
+		return newMigrationError("%s, edit the cluster to migrate it", apiErr.Message)
+	}
+	return newMigrationError("%v, edit the cluster to migrate it", err)
+}
+
+func (m *migrationController) startClusterMigration(name string, revision *v3.ClusterTemplateRevision) error {
+	cluster, err := m.clusterLister.Get("", name)
+	if apierrors.IsNotFound(err) {
+		return newMigrationError("cluster not found")
+	} else if err != nil {
+		return err
+	}
+	if cluster.DeletionTimestamp != nil {
+		return newMigrationError("cluster is being deleted")
+	}
+
+	spec, err := m.renderClusterSpec(cluster, revision)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(*spec, cluster.Spec) {
+		return nil
+	}
+	cluster = cluster.DeepCopy()
+	cluster.Spec = *spec
+	_, err = m.clusters.Update(cluster)
+	return err
+}
+
+// renderClusterSpec returns the spec of a cluster updated to a revision of its template, with the answers of the
+// cluster to the questions of the revision, loaded and validated the same way an update of the cluster to the revision
+// is.
+func (m *migrationController) renderClusterSpec(cluster *v3.Cluster, revision *v3.ClusterTemplateRevision) (*v32.ClusterSpec, error) {
+	if revision.Spec.ClusterConfig == nil {
+		return nil, newMigrationError("clusterTemplateRevision has no cluster config")
+	}
+	template, err := m.clusterTemplateLister.Get(ref.Parse(revision.Spec.ClusterTemplateName))
+	if apierrors.IsNotFound(err) {
+		return nil, newMigrationError("clusterTemplate %s not found", revision.Spec.ClusterTemplateName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the cluster keeps its own secrets, it can only be migrated to a revision setting secrets it already has. The
+	// secrets of the revision are not loaded, and its questions answered by them are left to the secrets of the cluster.
+	revision = revision.DeepCopy()
+	revisionSecrets := reflect.ValueOf(&revision.Status).Elem()
+	clusterSecrets := reflect.TypeOf(v32.ClusterSecrets{})
+	for i := 0; i < clusterSecrets.NumField(); i++ {
+		field := clusterSecrets.Field(i).Name
+		secret := revisionSecrets.FieldByName(field)
+		if !secret.IsValid() || secret.String() == "" {
+			continue
+		}
+		if cluster.GetSecret(field) == "" {
+			return nil, newMigrationError("clusterTemplateRevision sets a %s the cluster does not have, edit the cluster to migrate it", field)
+		}
+		secret.SetString("")
+	}
+	questions := revision.Spec.Questions
+	revision.Spec.Questions = nil
+	for _, question := range questions {
+		if !secretmigrator.MatchesQuestionPath(question.Variable) {
+			revision.Spec.Questions = append(revision.Spec.Questions, question)
+		}
+	}
+
+	existingCluster, err := convert.EncodeToMap(cluster.Spec)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := convert.EncodeToMap(cluster.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	revisionName := revision.Namespace + ":" + revision.Name
+	data := map[string]interface{}{
+		managementv3.ClusterSpecFieldClusterTemplateRevisionID: revisionName,
+		managementv3.ClusterSpecFieldClusterTemplateAnswers:    existingCluster[managementv3.ClusterSpecFieldClusterTemplateAnswers],
+		managementv3.MetadataUpdateFieldAnnotations:            metadata[managementv3.MetadataUpdateFieldAnnotations],
+	}
+	data, err = clusterstore.LoadTemplateUpdate(revision, template, data, nil, existingCluster, nil)
+	if err != nil {
+		return nil, newUpdateError(err)
+	}
+	dialer, err := m.dialerFactory.ClusterDialer(cluster.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting dialer")
+	}
+	if err := clusterstore.ValidateUpdate(data, existingCluster, cluster, m.nodeLister, dialer); err != nil {
+		return nil, newUpdateError(err)
+	}
+
+	var update v32.ClusterSpec
+	if err := convert.ToObj(data, &update); err != nil {
+		return nil, newMigrationError("invalid clusterTemplate, cannot convert to cluster spec: %v", err)
+	}
+	spec := cluster.Spec.DeepCopy()
+	spec.ClusterSpecBase = update.ClusterSpecBase
+	spec.ClusterSecrets = cluster.Spec.ClusterSecrets
+	spec.ClusterTemplateRevisionName = revisionName
+	spec.ClusterTemplateAnswers = update.ClusterTemplateAnswers
+	spec.ClusterTemplateQuestions = nil
+	for _, question := range questions {
+		if secretmigrator.MatchesQuestionPath(question.Variable) {
+			question.Default = ""
+		} else {
+			question = update.ClusterTemplateQuestions[0]
+			update.ClusterTemplateQuestions = update.ClusterTemplateQuestions[1:]
+		}
+		spec.ClusterTemplateQuestions = append(spec.ClusterTemplateQuestions, question)
+	}
+	return spec, nil
+}
@@ -0,0 +1,270 @@
+package clustertemplate
+
+import (
+	"testing"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config/dialer"
+	rketypes "github.com/rancher/rke/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testRevisionName = "cattle-global-data:ctr-2"
+
+func newMigrationRevision() *v3.ClusterTemplateRevision {
+	revision := &v3.ClusterTemplateRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "ctr-2", Namespace: "cattle-global-data"},
+		Spec: v32.ClusterTemplateRevisionSpec{
+			ClusterTemplateName: "cattle-global-data:ct-1",
+			ClusterConfig: &v32.ClusterSpecBase{
+				RancherKubernetesEngineConfig: &rketypes.RancherKubernetesEngineConfig{Version: "v1.24.10-rancher4-1"},
+			},
+			Questions: []v32.Question{
+				{Variable: "rancherKubernetesEngineConfig.ignoreDockerVersion", Type: "boolean", Default: "false"},
+				{Variable: "rancherKubernetesEngineConfig.services.etcd.backupConfig.s3BackupConfig.secretKey", Type: "password", Default: "secret"},
+			},
+		},
+	}
+	v32.ClusterTemplateRevisionConditionSecretsMigrated.True(revision)
+	v32.ClusterTemplateRevisionConditionACISecretsMigrated.True(revision)
+	v32.ClusterTemplateRevisionConditionRKESecretsMigrated.True(revision)
+	return revision
+}
+
+func newMigrationCluster(name string) *v3.Cluster {
+	return &v3.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v32.ClusterSpec{
+			ClusterSpecBase: v32.ClusterSpecBase{
+				RancherKubernetesEngineConfig: &rketypes.RancherKubernetesEngineConfig{Version: "v1.23.16-rancher2-1"},
+			},
+			ClusterTemplateName:         "cattle-global-data:ct-1",
+			ClusterTemplateRevisionName: "cattle-global-data:ctr-1",
+		},
+	}
+}
+
+type fakeDialerFactory struct {
+	dialer.Factory
+}
+
+func (fakeDialerFactory) ClusterDialer(string) (dialer.Dialer, error) {
+	return nil, nil
+}
+
+// newRenderController returns a migration controller rendering the specs of clusters with the nodes.
+func newRenderController(nodes []*v3.Node) *migrationController {
+	return &migrationController{
+		clusterTemplateLister: &fakes.ClusterTemplateListerMock{
+			GetFunc: func(namespace, name string) (*v3.ClusterTemplate, error) {
+				return &v3.ClusterTemplate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}, nil
+			},
+		},
+		nodeLister: &fakes.NodeListerMock{
+			ListFunc: func(namespace string, selector labels.Selector) ([]*v3.Node, error) {
+				return nodes, nil
+			},
+		},
+		dialerFactory: fakeDialerFactory{},
+	}
+}
+
+func TestRenderClusterSpec(t *testing.T) {
+	m := newRenderController(nil)
+	revision := newMigrationRevision()
+	cluster := newMigrationCluster("c-1")
+	cluster.Spec.EnableClusterMonitoring = true
+	cluster.Spec.ClusterTemplateAnswers.Values = map[string]string{"rancherKubernetesEngineConfig.ignoreDockerVersion": "true"}
+
+	spec, err := m.renderClusterSpec(cluster, revision)
+	require.NoError(t, err)
+	assert.Equal(t, testRevisionName, spec.ClusterTemplateRevisionName)
+	assert.Equal(t, "v1.24.10-rancher4-1", spec.RancherKubernetesEngineConfig.Version)
+	assert.True(t, *spec.RancherKubernetesEngineConfig.EnableCRIDockerd)
+	assert.True(t, *spec.RancherKubernetesEngineConfig.IgnoreDockerVersion)
+	// monitoring is kept on when the revision does not turn it on
+	assert.True(t, spec.EnableClusterMonitoring)
+	assert.Equal(t, map[string]string{"rancherKubernetesEngineConfig.ignoreDockerVersion": "true"}, spec.ClusterTemplateAnswers.Values)
+	// the secret answers come from the secrets of the cluster
+	require.Len(t, spec.ClusterTemplateQuestions, 2)
+	assert.Equal(t, "rancherKubernetesEngineConfig.ignoreDockerVersion", spec.ClusterTemplateQuestions[0].Variable)
+	assert.Empty(t, spec.ClusterTemplateQuestions[1].Default)
+	assert.Empty(t, spec.RancherKubernetesEngineConfig.Services.Etcd.BackupConfig)
+
+	cluster.Annotations = map[string]string{"io.cattle.cluster.cridockerd.enable": "false"}
+	spec, err = m.renderClusterSpec(cluster, revision)
+	require.NoError(t, err)
+	assert.False(t, *spec.RancherKubernetesEngineConfig.EnableCRIDockerd)
+
+	revision.Status.S3CredentialSecret = "cattle-global-data:s3"
+	_, err = m.renderClusterSpec(cluster, revision)
+	assert.EqualError(t, err, "clusterTemplateRevision sets a S3CredentialSecret the cluster does not have, edit the cluster to migrate it")
+	cluster.Spec.ClusterSecrets.S3CredentialSecret = "cattle-global-data:c-1-s3"
+	spec, err = m.renderClusterSpec(cluster, revision)
+	require.NoError(t, err)
+	assert.Equal(t, "cattle-global-data:c-1-s3", spec.ClusterSecrets.S3CredentialSecret)
+	assert.Equal(t, "cattle-global-data:s3", revision.Status.S3CredentialSecret)
+
+	revision.Spec.Questions = append(revision.Spec.Questions, v32.Question{Variable: "description", Type: "string", Required: true})
+	_, err = m.renderClusterSpec(cluster, revision)
+	assert.EqualError(t, err, "Missing answer for a required clusterTemplate question: description, edit the cluster to migrate it")
+
+	revision.Spec.Questions = []v32.Question{{Variable: "rancherKubernetesEngineConfig.privateRegistries[0].url", Type: "string"}}
+	cluster.Spec.ClusterTemplateAnswers.Values = map[string]string{"rancherKubernetesEngineConfig.privateRegistries[0].url": "registry.example.com"}
+	spec, err = m.renderClusterSpec(cluster, revision)
+	require.NoError(t, err)
+	require.Len(t, spec.RancherKubernetesEngineConfig.PrivateRegistries, 1)
+	assert.Equal(t, "registry.example.com", spec.RancherKubernetesEngineConfig.PrivateRegistries[0].URL)
+	assert.True(t, spec.RancherKubernetesEngineConfig.PrivateRegistries[0].IsDefault)
+}
+
+func TestRenderClusterSpecKubernetesVersion(t *testing.T) {
+	require.NoError(t, settings.KubernetesVersionsCurrent.Set("v1.23.16-rancher2-1,v1.24.10-rancher4-1"))
+	m := newRenderController(nil)
+	revision := newMigrationRevision()
+	cluster := newMigrationCluster("c-1")
+
+	revision.Spec.ClusterConfig.RancherKubernetesEngineConfig.Version = "1.24.x"
+	spec, err := m.renderClusterSpec(cluster, revision)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.24.10-rancher4-1", spec.RancherKubernetesEngineConfig.Version)
+
+	revision.Spec.ClusterConfig.RancherKubernetesEngineConfig.Version = "1.25.x"
+	_, err = m.renderClusterSpec(cluster, revision)
+	assert.EqualError(t, err, "Requested kubernetesVersion 1.25.x is not supported currently, edit the cluster to migrate it")
+
+	require.NoError(t, settings.KubernetesVersionsDeprecated.Set(`{"v1.24.10-rancher4-1":true}`))
+	defer settings.KubernetesVersionsDeprecated.Set(settings.KubernetesVersionsDeprecated.Default)
+	revision.Spec.ClusterConfig.RancherKubernetesEngineConfig.Version = "v1.24.10-rancher4-1"
+	_, err = m.renderClusterSpec(cluster, revision)
+	assert.EqualError(t, err, "Requested kubernetesVersion v1.24.10-rancher4-1 is deprecated, edit the cluster to migrate it")
+}
+
+func TestRenderClusterSpecUnavailableNodes(t *testing.T) {
+	node := &v3.Node{Status: v32.NodeStatus{NodeConfig: &rketypes.RKEConfigNode{Role: []string{"controlplane"}}}}
+	m := newRenderController([]*v3.Node{node})
+	revision := newMigrationRevision()
+	cluster := newMigrationCluster("c-1")
+	v32.ClusterConditionProvisioned.True(cluster)
+	v32.ClusterConditionUpdated.True(cluster)
+
+	_, err := m.renderClusterSpec(cluster, revision)
+	assert.EqualError(t, err, "not enough control plane nodes ready to upgrade, maxUnavailable: 1, notReady: 1, ready: 0, edit the cluster to migrate it")
+}
+
+func TestMigrationController(t *testing.T) {
+	clusters := map[string]*v3.Cluster{}
+	for _, name := range []string{"c-1", "c-2", "c-3"} {
+		clusters[name] = newMigrationCluster(name)
+	}
+	var updatedClusters []string
+	var enqueued int
+	m := newRenderController(nil)
+	*m = migrationController{
+		clusterTemplateLister: m.clusterTemplateLister,
+		nodeLister:            m.nodeLister,
+		dialerFactory:         m.dialerFactory,
+		clusters: &fakes.ClusterInterfaceMock{
+			UpdateFunc: func(cluster *v3.Cluster) (*v3.Cluster, error) {
+				updatedClusters = append(updatedClusters, cluster.Name)
+				clusters[cluster.Name] = cluster
+				return cluster, nil
+			},
+		},
+		clusterLister: &fakes.ClusterListerMock{
+			GetFunc: func(namespace, name string) (*v3.Cluster, error) {
+				if cluster, ok := clusters[name]; ok {
+					return cluster, nil
+				}
+				return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+			},
+		},
+		clusterTemplateRevisions: &fakes.ClusterTemplateRevisionInterfaceMock{
+			UpdateFunc: func(revision *v3.ClusterTemplateRevision) (*v3.ClusterTemplateRevision, error) {
+				return revision, nil
+			},
+		},
+		clusterTemplateRevisionController: &fakes.ClusterTemplateRevisionControllerMock{
+			EnqueueAfterFunc: func(namespace, name string, after time.Duration) {
+				enqueued++
+			},
+		},
+	}
+
+	revision := newMigrationRevision()
+	revision.Status.Migration = &v32.ClusterTemplateMigration{
+		ClusterNames: []string{"c-1", "c-2", "c-3"},
+		BatchSize:    2,
+		State:        v32.ClusterTemplateMigrationStateMigrating,
+	}
+
+	// the first batch is started
+	obj, err := m.sync("", revision)
+	require.NoError(t, err)
+	revision = obj.(*v3.ClusterTemplateRevision)
+	assert.Equal(t, []string{"c-1", "c-2"}, updatedClusters)
+	assert.Equal(t, []string{"c-1", "c-2"}, revision.Status.Migration.Migrating)
+	assert.Equal(t, testRevisionName, clusters["c-1"].Spec.ClusterTemplateRevisionName)
+	assert.Equal(t, 1, enqueued)
+
+	// the batch is in progress until its clusters are provisioned with the revision
+	obj, err = m.sync("", revision)
+	require.NoError(t, err)
+	revision = obj.(*v3.ClusterTemplateRevision)
+	assert.Equal(t, []string{"c-1", "c-2"}, revision.Status.Migration.Migrating)
+	assert.Len(t, updatedClusters, 2)
+
+	for _, name := range []string{"c-1", "c-2"} {
+		clusters[name].Status.AppliedSpec = clusters[name].Spec
+		v32.ClusterConditionUpdated.True(clusters[name])
+		v32.ClusterConditionReady.True(clusters[name])
+	}
+	obj, err = m.sync("", revision)
+	require.NoError(t, err)
+	revision = obj.(*v3.ClusterTemplateRevision)
+	assert.Equal(t, []string{"c-1", "c-2"}, revision.Status.Migration.Migrated)
+	assert.Equal(t, []string{"c-3"}, revision.Status.Migration.Migrating)
+
+	// a cluster failing to be provisioned fails the migration
+	clusters["c-3"].Status.FailedSpec = clusters["c-3"].Spec.DeepCopy()
+	v32.ClusterConditionUpdated.False(clusters["c-3"])
+	v32.ClusterConditionUpdated.Message(clusters["c-3"], "etcd is unhealthy")
+	obj, err = m.sync("", revision)
+	require.NoError(t, err)
+	revision = obj.(*v3.ClusterTemplateRevision)
+	migration := revision.Status.Migration
+	assert.Equal(t, v32.ClusterTemplateMigrationStateFailed, migration.State)
+	assert.Empty(t, migration.Migrating)
+	assert.Equal(t, []v32.ClusterTemplateMigrationFailure{
+		{ClusterName: "c-3", Message: "failed to provision the cluster: etcd is unhealthy"},
+	}, migration.Failed)
+	assert.NotEmpty(t, migration.CompletedAt)
+
+	// a finished migration is left as is
+	enqueued = 0
+	_, err = m.sync("", revision)
+	require.NoError(t, err)
+	assert.Equal(t, 0, enqueued)
+}
+
+func TestNextBatch(t *testing.T) {
+	migration := &v32.ClusterTemplateMigration{
+		ClusterNames: []string{"c-1", "c-2", "c-3", "c-4"},
+		Migrated:     []string{"c-1"},
+		Failed:       []v32.ClusterTemplateMigrationFailure{{ClusterName: "c-3"}},
+		BatchSize:    2,
+	}
+	assert.Equal(t, []string{"c-2", "c-4"}, nextBatch(migration))
+
+	migration.Migrated = append(migration.Migrated, "c-2", "c-4")
+	assert.Empty(t, nextBatch(migration))
+}
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
@@ -9,11 +10,24 @@
 	"github.com/rancher/rancher/pkg/features"
 	managementv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/wrangler"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+const (
+	featureHandlerName = "feature-handler"
+	// historyLimit is the number of changes of its value kept in the status of a feature.
+	historyLimit = 10
+	// scheduleRetryInterval is how often a scheduled enablement that failed is retried.
+	scheduleRetryInterval = 5 * time.Minute
+)
+
 type handler struct {
 	featuresClient       managementv3.FeatureClient
+	featuresCache        managementv3.FeatureCache
+	featureEnqueue       func(string, time.Duration)
 	tokensLister         managementv3.TokenCache
 	tokenEnqueue         func(string, time.Duration)
 	nodeDriverController managementv3.NodeDriverController
@@ -22,11 +36,13 @@ type handler struct {
 func Register(ctx context.Context, wContext *wrangler.Context) {
 	h := handler{
 		featuresClient:       wContext.Mgmt.Feature(),
+		featuresCache:        wContext.Mgmt.Feature().Cache(),
+		featureEnqueue:       wContext.Mgmt.Feature().EnqueueAfter,
 		tokensLister:         wContext.Mgmt.Token().Cache(),
 		tokenEnqueue:         wContext.Mgmt.Token().EnqueueAfter,
 		nodeDriverController: wContext.Mgmt.NodeDriver(),
 	}
-	wContext.Mgmt.Feature().OnChange(ctx, "feature-handler", h.sync)
+	wContext.Mgmt.Feature().OnChange(ctx, featureHandlerName, h.sync)
 }
 
 func (h *handler) sync(_ string, obj *v3.Feature) (*v3.Feature, error) {
@@ -39,6 +55,16 @@ func (h *handler) sync(_ string, obj *v3.Feature) (*v3.Feature, error) {
 		return obj, err
 	}
 
+	obj, err = h.enableScheduled(obj)
+	if err != nil {
+		return obj, err
+	}
+
+	obj, err = h.recordTransition(obj)
+	if err != nil {
+		return obj, err
+	}
+
 	if obj.Name == features.TokenHashing.Name() {
 		return obj, h.refreshTokens()
 	}
@@ -76,6 +102,92 @@ func (h *handler) refreshTokens() error {
 	return nil
 }
 
+// enableScheduled enables a feature once the time of its scheduled enablement has come, provided its dependencies
+// are enabled by then. Otherwise the failure is recorded on the status of the feature and the enablement retried.
+func (h *handler) enableScheduled(obj *v3.Feature) (*v3.Feature, error) {
+	if obj.Spec.EnableAt == nil {
+		if obj.Status.ScheduleFailure == "" {
+			return obj, nil
+		}
+		featureCopy := obj.DeepCopy()
+		featureCopy.Status.ScheduleFailure = ""
+		return h.featuresClient.Update(featureCopy)
+	}
+	if wait := time.Until(obj.Spec.EnableAt.Time); wait > 0 {
+		h.featureEnqueue(obj.Name, wait)
+		return obj, nil
+	}
+
+	if err := features.ValidateDependencies(obj.Name, true, h.enabled); err != nil {
+		h.featureEnqueue(obj.Name, scheduleRetryInterval)
+		failure := fmt.Sprintf("failed to enable feature as scheduled: %v", err)
+		if obj.Status.ScheduleFailure == failure {
+			return obj, nil
+		}
+		logrus.Warnf("[%s] feature %s: %s, retrying in %v", featureHandlerName, obj.Name, failure, scheduleRetryInterval)
+		featureCopy := obj.DeepCopy()
+		featureCopy.Status.ScheduleFailure = failure
+		return h.featuresClient.Update(featureCopy)
+	}
+
+	featureCopy := obj.DeepCopy()
+	enabled := true
+	featureCopy.Spec.Value = &enabled
+	featureCopy.Spec.EnableAt = nil
+	featureCopy.Status.ScheduleFailure = ""
+	return h.featuresClient.Update(featureCopy)
+}
+
+// recordTransition appends a change of the effective value of a feature to its history, along with the user who made
+// it as recorded by the updated-by annotation. The annotation is cleared once handled so that later changes are not
+// attributed to the same user, unless an enablement is scheduled, which is then attributed to whoever scheduled it.
+// A feature seen for the first time has no change to record, its current value is only kept to compare to.
+func (h *handler) recordTransition(obj *v3.Feature) (*v3.Feature, error) {
+	value := features.IsEnabled(obj)
+	previous, known := value, false
+	if obj.Status.EffectiveValue != nil {
+		previous, known = *obj.Status.EffectiveValue, true
+	} else if n := len(obj.Status.History); n > 0 {
+		previous, known = obj.Status.History[n-1].Value, true
+	}
+	user, annotated := obj.Annotations[features.UpdatedByAnnotation]
+	changed := value != previous
+
+	if known && !changed && (!annotated || obj.Spec.EnableAt != nil) {
+		return obj, nil
+	}
+
+	featureCopy := obj.DeepCopy()
+	featureCopy.Status.EffectiveValue = &value
+	if changed {
+		logrus.Infof("[%s] feature %s changed to %v by %q", featureHandlerName, obj.Name, value, user)
+		featureCopy.Status.History = append(featureCopy.Status.History, v3.FeatureTransition{
+			Value: value,
+			User:  user,
+			Time:  metav1.Now(),
+		})
+		if n := len(featureCopy.Status.History); n > historyLimit {
+			featureCopy.Status.History = featureCopy.Status.History[n-historyLimit:]
+		}
+	}
+	if featureCopy.Spec.EnableAt == nil {
+		delete(featureCopy.Annotations, features.UpdatedByAnnotation)
+	}
+	return h.featuresClient.Update(featureCopy)
+}
+
+// enabled returns the effective value of a feature, falling back to the value in memory for features not installed.
+func (h *handler) enabled(name string) (bool, error) {
+	obj, err := h.featuresCache.Get(name)
+	if apierrors.IsNotFound(err) {
+		f := features.GetFeatureByName(name)
+		return f != nil && f.Enabled(), nil
+	} else if err != nil {
+		return false, err
+	}
+	return features.IsEnabled(obj), nil
+}
+
 // setLockedValue evaluates whether a value should be written to the lockedValue
 // field on status and records the value if so.
 func (h *handler) setLockedValue(obj *v3.Feature) (*v3.Feature, error) {
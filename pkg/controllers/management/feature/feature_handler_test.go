@@ -0,0 +1,129 @@
+package feature
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/features"
+	managementv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeFeatures map[string]*v3.Feature
+
+type fakeFeatureClient struct {
+	managementv3.FeatureClient
+	objs fakeFeatures
+}
+
+func (f *fakeFeatureClient) Update(obj *v3.Feature) (*v3.Feature, error) {
+	f.objs[obj.Name] = obj
+	return obj, nil
+}
+
+type fakeFeatureCache struct {
+	managementv3.FeatureCache
+	objs fakeFeatures
+}
+
+func (f *fakeFeatureCache) Get(name string) (*v3.Feature, error) {
+	if obj, ok := f.objs[name]; ok {
+		return obj, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+}
+
+func newFeature(name string, value *bool, dflt bool) *v3.Feature {
+	return &v3.Feature{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v3.FeatureSpec{Value: value},
+		Status:     v3.FeatureStatus{Default: dflt},
+	}
+}
+
+func TestRecordTransition(t *testing.T) {
+	h := handler{featuresClient: &fakeFeatureClient{objs: fakeFeatures{}}}
+	enabled, disabled := true, false
+
+	// a feature changed from its default before its changes were recorded has no change to record
+	obj := newFeature("fleet", &enabled, false)
+	got, err := h.recordTransition(obj)
+	require.NoError(t, err)
+	assert.Empty(t, got.Status.History)
+	require.NotNil(t, got.Status.EffectiveValue)
+	assert.True(t, *got.Status.EffectiveValue)
+
+	again, err := h.recordTransition(got)
+	require.NoError(t, err)
+	assert.Same(t, got, again, "an unchanged feature must not be updated")
+
+	got.Spec.Value = &disabled
+	got.Annotations = map[string]string{features.UpdatedByAnnotation: "u-admin"}
+	got, err = h.recordTransition(got)
+	require.NoError(t, err)
+	require.Len(t, got.Status.History, 1)
+	assert.Equal(t, "u-admin", got.Status.History[0].User)
+	assert.False(t, got.Status.History[0].Value)
+	assert.False(t, *got.Status.EffectiveValue)
+	assert.NotContains(t, got.Annotations, features.UpdatedByAnnotation)
+
+	for i := 0; i < historyLimit+5; i++ {
+		value := i%2 == 0
+		got.Spec.Value = &value
+		got, err = h.recordTransition(got)
+		require.NoError(t, err)
+	}
+	assert.Len(t, got.Status.History, historyLimit)
+}
+
+func TestEnableScheduled(t *testing.T) {
+	disabled := false
+	objs := fakeFeatures{
+		features.ProvisioningV2.Name(): newFeature(features.ProvisioningV2.Name(), &disabled, true),
+	}
+	var enqueued time.Duration
+	h := handler{
+		featuresClient: &fakeFeatureClient{objs: objs},
+		featuresCache:  &fakeFeatureCache{objs: objs},
+		featureEnqueue: func(_ string, after time.Duration) { enqueued = after },
+	}
+
+	obj := newFeature(features.RKE2.Name(), &disabled, true)
+	obj.Spec.EnableAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+	got, err := h.enableScheduled(obj)
+	require.NoError(t, err)
+	assert.False(t, features.IsEnabled(got))
+	assert.InDelta(t, time.Hour, enqueued, float64(time.Minute))
+
+	// the dependencies are not enabled: the failure is recorded and the enablement retried
+	obj.Spec.EnableAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	got, err = h.enableScheduled(obj)
+	require.NoError(t, err)
+	assert.False(t, features.IsEnabled(got))
+	assert.NotNil(t, got.Spec.EnableAt)
+	assert.Contains(t, got.Status.ScheduleFailure, "failed to enable feature as scheduled")
+	assert.Equal(t, scheduleRetryInterval, enqueued)
+
+	again, err := h.enableScheduled(got)
+	require.NoError(t, err)
+	assert.Same(t, got, again, "the same failure must not be recorded again")
+
+	objs[features.ProvisioningV2.Name()].Spec.Value = nil
+	got, err = h.enableScheduled(got)
+	require.NoError(t, err)
+	assert.True(t, features.IsEnabled(got))
+	assert.Nil(t, got.Spec.EnableAt)
+	assert.Empty(t, got.Status.ScheduleFailure)
+
+	// removing the schedule clears its failure
+	obj = newFeature(features.RKE2.Name(), &disabled, true)
+	obj.Status.ScheduleFailure = "failed"
+	got, err = h.enableScheduled(obj)
+	require.NoError(t, err)
+	assert.Empty(t, got.Status.ScheduleFailure)
+}
@@ -2,8 +2,6 @@
 
 import (
 	"context"
-	"hash/crc32"
-	"math"
 	"sort"
 	"sync"
 	"time"
@@ -149,15 +147,9 @@ func (u *userControllersController) amOwner(peers tpeermanager.Peers, cluster *v
 		return false
 	}
 
-	ck := crc32.ChecksumIEEE([]byte(cluster.UID))
-	if ck == math.MaxUint32 {
-		ck--
-	}
-
-	scaled := int(ck) * len(peers.IDs) / math.MaxUint32
-	logrus.Debugf("%s(%v): (%v * %v) / %v = %v[%v] = %v, self = %v\n", cluster.Name, cluster.UID, ck,
-		uint32(len(peers.IDs)), math.MaxUint32, peers.IDs, scaled, peers.IDs[scaled], peers.SelfID)
-	return peers.IDs[scaled] == peers.SelfID
+	owner := tpeermanager.Owner(peers.IDs, string(cluster.UID))
+	logrus.Debugf("%s(%v): owner of %v = %v, self = %v\n", cluster.Name, cluster.UID, peers.IDs, owner, peers.SelfID)
+	return owner == peers.SelfID
 }
 
 func (u *userControllersController) cleanFinalizers(key string, cluster *v3.Cluster) error {
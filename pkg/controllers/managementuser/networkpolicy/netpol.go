@@ -23,13 +23,13 @@
 )
 
 const (
-	systemProjectLabel = "authz.management.cattle.io/system-project"
+	SystemProjectLabel = "authz.management.cattle.io/system-project"
 	creatorLabel       = "cattle.io/creator"
 )
 
 const (
-	defaultNamespacePolicyName              = "np-default"
-	defaultSystemProjectNamespacePolicyName = "np-default-allow-all"
+	DefaultNamespacePolicyName              = "np-default"
+	DefaultSystemProjectNamespacePolicyName = "np-default-allow-all"
 	hostNetworkPolicyName                   = "hn-nodes"
 	creatorNorman                           = "norman"
 )
@@ -45,6 +45,7 @@ type netpolMgr struct {
 	npClient         rnetworkingv1.Interface
 	projLister       v3.ProjectLister
 	clusterNamespace string
+	pnpLister        v3.ProjectNetworkPolicyLister
 }
 
 func (npmgr *netpolMgr) program(np *knetworkingv1.NetworkPolicy) error {
@@ -116,7 +117,10 @@ func (npmgr *netpolMgr) programNetworkPolicy(projectID string, clusterNamespace
 		// we also guard against overriding existing network policies, the default network policy for a namespace in the system project
 		// will only be added if there are no other network policies in the namespace (network policies are additive)
 		if systemNamespaces[aNS.Name] {
-			npmgr.delete(aNS.Name, defaultNamespacePolicyName)
+			npmgr.delete(aNS.Name, DefaultNamespacePolicyName)
+			if err := npmgr.deleteTemplates(aNS.Name, nil); err != nil {
+				return err
+			}
 
 			// this requirement includes objects with no creatorLabel or a value != creatorNorman
 			labelReq, err := labels.NewRequirement(creatorLabel, selection.NotEquals, []string{creatorNorman})
@@ -131,16 +135,16 @@ func (npmgr *netpolMgr) programNetworkPolicy(projectID string, clusterNamespace
 
 			// there are existing network policies in this system project based namespace, skip programming default
 			if len(nps) > 0 {
-				logrus.Debugf("netPolMgr: namespace=%s in project=%s has existing network policies, skipping programming %s", aNS.Name, id, defaultSystemProjectNamespacePolicyName)
+				logrus.Debugf("netPolMgr: namespace=%s in project=%s has existing network policies, skipping programming %s", aNS.Name, id, DefaultSystemProjectNamespacePolicyName)
 				continue
 			}
 
 			// program default network policy for system project based namespace
-			logrus.Debugf("netPolMgr: programming %s for namespace=%s in project=%s", defaultSystemProjectNamespacePolicyName, aNS.Name, id)
+			logrus.Debugf("netPolMgr: programming %s for namespace=%s in project=%s", DefaultSystemProjectNamespacePolicyName, aNS.Name, id)
 			if err := npmgr.program(generateAllowAllNetworkPolicy(aNS, systemProjectID)); err != nil {
 				return fmt.Errorf(
 					"netPolMgr: programNetworkPolicy: error programming network policy %s for system project based namespace=%s err=%v",
-					defaultSystemProjectNamespacePolicyName, aNS.Name, err,
+					DefaultSystemProjectNamespacePolicyName, aNS.Name, err,
 				)
 			}
 			continue
@@ -148,10 +152,13 @@ func (npmgr *netpolMgr) programNetworkPolicy(projectID string, clusterNamespace
 
 		// namespace is not in system project, so ensure it doesn't have the default policy for system project based namespaces
 		if id != systemProjectID {
-			npmgr.delete(aNS.Name, defaultSystemProjectNamespacePolicyName)
+			npmgr.delete(aNS.Name, DefaultSystemProjectNamespacePolicyName)
 		}
 		if id == "" {
-			npmgr.delete(aNS.Name, defaultNamespacePolicyName)
+			npmgr.delete(aNS.Name, DefaultNamespacePolicyName)
+			if err := npmgr.deleteTemplates(aNS.Name, nil); err != nil {
+				return err
+			}
 			continue
 		}
 		if aNS.DeletionTimestamp != nil {
@@ -163,6 +170,9 @@ func (npmgr *netpolMgr) programNetworkPolicy(projectID string, clusterNamespace
 		if err := npmgr.program(np); err != nil {
 			return fmt.Errorf("netpolMgr: programNetworkPolicy: error programming default network policy for ns=%v err=%v", aNS.Name, err)
 		}
+		if err := npmgr.programTemplates(aNS, projectID); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -322,7 +332,7 @@ func (npmgr *netpolMgr) getRKE2ClusterCNI(mgmtCluster *v3.Cluster) (string, erro
 
 func (npmgr *netpolMgr) getSystemNSInfo(clusterNamespace string) (map[string]bool, string, error) {
 	systemNamespaces := map[string]bool{}
-	set := labels.Set(map[string]string{systemProjectLabel: "true"})
+	set := labels.Set(map[string]string{SystemProjectLabel: "true"})
 	projects, err := npmgr.projLister.List(clusterNamespace, set.AsSelector())
 	systemProjectID := ""
 	if err != nil {
@@ -359,7 +369,7 @@ func (npmgr *netpolMgr) getSystemNSInfo(clusterNamespace string) (map[string]boo
 func generateDefaultNamespaceNetworkPolicy(aNS *corev1.Namespace, projectID string, systemProjectID string) *knetworkingv1.NetworkPolicy {
 	return &knetworkingv1.NetworkPolicy{
 		ObjectMeta: v1.ObjectMeta{
-			Name:      defaultNamespacePolicyName,
+			Name:      DefaultNamespacePolicyName,
 			Namespace: aNS.Name,
 			Labels: map[string]string{
 				nslabels.ProjectIDFieldLabel: projectID,
@@ -395,7 +405,7 @@ func generateDefaultNamespaceNetworkPolicy(aNS *corev1.Namespace, projectID stri
 func generateAllowAllNetworkPolicy(ns *corev1.Namespace, systemProjectID string) *knetworkingv1.NetworkPolicy {
 	return &knetworkingv1.NetworkPolicy{
 		ObjectMeta: v1.ObjectMeta{
-			Name:      defaultSystemProjectNamespacePolicyName,
+			Name:      DefaultSystemProjectNamespacePolicyName,
 			Namespace: ns.Name,
 			Labels: map[string]string{
 				nslabels.ProjectIDFieldLabel: systemProjectID,
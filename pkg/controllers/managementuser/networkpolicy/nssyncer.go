@@ -65,9 +65,12 @@ func (nss *nsSyncer) syncOnMove(nsName string, projectID string, movedToNone boo
 		return fmt.Errorf("nsSyncer: error getting systemNamespaces %v", err)
 	}
 	if movedToNone {
-		nss.npmgr.delete(nsName, defaultNamespacePolicyName)
+		nss.npmgr.delete(nsName, DefaultNamespacePolicyName)
 		nss.npmgr.delete(nsName, hostNetworkPolicyName)
-		nss.npmgr.delete(nsName, defaultSystemProjectNamespacePolicyName)
+		nss.npmgr.delete(nsName, DefaultSystemProjectNamespacePolicyName)
+		if err := nss.npmgr.deleteTemplates(nsName, nil); err != nil {
+			return fmt.Errorf("nsSyncer: error deleting network policies of project templates %v", err)
+		}
 	}
 	if err = nss.syncNodePortServices(systemNamespaces, nsName, movedToNone); err != nil {
 		return fmt.Errorf("nsSyncer: error syncing services %v", err)
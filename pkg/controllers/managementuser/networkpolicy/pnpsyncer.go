@@ -3,7 +3,9 @@
 import (
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 )
 
 type projectNetworkPolicySyncer struct {
@@ -13,8 +15,19 @@ type projectNetworkPolicySyncer struct {
 // Sync invokes the Policy Handler to take care of installing the native network policies
 func (pnps *projectNetworkPolicySyncer) Sync(key string, pnp *v3.ProjectNetworkPolicy) (runtime.Object, error) {
 	if pnp == nil || pnp.DeletionTimestamp != nil {
-		return nil, nil
+		// reprogram the project so that the network policies rendered from the template of the pnp are deleted
+		projectID, _, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return nil, pnps.npmgr.programNetworkPolicy(projectID, pnps.npmgr.clusterNamespace)
 	}
 	logrus.Debugf("projectNetworkPolicySyncer: Sync: pnp=%+v", pnp)
+	if pnp.Spec.Template != nil {
+		// validate the template, so that an invalid one is reported on the pnp
+		if _, err := generateTemplateNetworkPolicy(&corev1.Namespace{}, pnp.Namespace, pnp); err != nil {
+			return nil, err
+		}
+	}
 	return nil, pnps.npmgr.programNetworkPolicy(pnp.Namespace, pnps.npmgr.clusterNamespace)
 }
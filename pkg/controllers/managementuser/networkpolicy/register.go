@@ -50,7 +50,7 @@ func registerDeferred(ctx context.Context, cluster *config.UserContext) {
 	npClient := cluster.Networking
 
 	npmgr := &netpolMgr{clusterLister, clusters, nsLister, nodeLister, pods, projects,
-		npLister, npClient, projectLister, cluster.ClusterName}
+		npLister, npClient, projectLister, cluster.ClusterName, pnpLister}
 	ps := &projectSyncer{pnpLister, pnps, projects, clusterLister, cluster.ClusterName}
 	nss := &nsSyncer{npmgr, clusterLister, serviceLister, podLister,
 		services, pods, cluster.ClusterName}
@@ -0,0 +1,200 @@
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementagent/nslabels"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	knetworkingv1 "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// projectNetworkPolicyLabel is set on the network policies rendered from the template of a ProjectNetworkPolicy, to
+// the name of the ProjectNetworkPolicy.
+const projectNetworkPolicyLabel = "networkpolicy.management.cattle.io/project-network-policy"
+
+const dnsPort = 53
+
+// templatePolicyPrefix is prepended to the name of a ProjectNetworkPolicy to name the network policies rendered from
+// its template, so they cannot replace np-default, np-default-allow-all or hn-nodes.
+const templatePolicyPrefix = "pnp-tmpl-"
+
+// TemplatePolicyName returns the name of the network policies rendered from the template of a ProjectNetworkPolicy.
+func TemplatePolicyName(pnpName string) string {
+	return templatePolicyPrefix + pnpName
+}
+
+// programTemplates renders the templates of the ProjectNetworkPolicies of a project into network policies in one of
+// its namespaces, and deletes the ones rendered from the templates of other projects or of deleted policies.
+func (npmgr *netpolMgr) programTemplates(ns *corev1.Namespace, projectID string) error {
+	pnps, err := npmgr.pnpLister.List(projectID, labels.Everything())
+	if err != nil {
+		return fmt.Errorf("netpolMgr: programTemplates: couldn't list project network policies of project %v err=%v", projectID, err)
+	}
+
+	programmed := map[string]bool{}
+	for _, pnp := range pnps {
+		if pnp.Spec.Template == nil || pnp.DeletionTimestamp != nil {
+			continue
+		}
+		np, err := generateTemplateNetworkPolicy(ns, projectID, pnp)
+		if err != nil {
+			// an invalid template is reported by the projectNetworkPolicySyncer, it must not keep the others from
+			// being programmed
+			logrus.Errorf("netpolMgr: programTemplates: skipping project network policy %v/%v err=%v", pnp.Namespace, pnp.Name, err)
+			continue
+		}
+		if err := npmgr.program(np); err != nil {
+			return fmt.Errorf("netpolMgr: programTemplates: error programming network policy %v for ns=%v err=%v", np.Name, ns.Name, err)
+		}
+		programmed[np.Name] = true
+	}
+	return npmgr.deleteTemplates(ns.Name, programmed)
+}
+
+// deleteTemplates deletes the network policies rendered from templates in a namespace, except the ones to keep.
+func (npmgr *netpolMgr) deleteTemplates(namespace string, keep map[string]bool) error {
+	labelReq, err := labels.NewRequirement(projectNetworkPolicyLabel, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	nps, err := npmgr.npLister.List(namespace, labels.NewSelector().Add(*labelReq))
+	if err != nil {
+		return fmt.Errorf("netpolMgr: deleteTemplates: couldn't list network policies of ns=%v err=%v", namespace, err)
+	}
+	for _, np := range nps {
+		if keep[np.Name] {
+			continue
+		}
+		if err := npmgr.delete(namespace, np.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func generateTemplateNetworkPolicy(ns *corev1.Namespace, projectID string, pnp *v3.ProjectNetworkPolicy) (*knetworkingv1.NetworkPolicy, error) {
+	template := pnp.Spec.Template
+	np := &knetworkingv1.NetworkPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      TemplatePolicyName(pnp.Name),
+			Namespace: ns.Name,
+			Labels: map[string]string{
+				nslabels.ProjectIDFieldLabel: projectID,
+				creatorLabel:                 creatorNorman,
+				projectNetworkPolicyLabel:    pnp.Name,
+			},
+		},
+		Spec: knetworkingv1.NetworkPolicySpec{
+			PodSelector: v1.LabelSelector{MatchLabels: template.PodSelector},
+		},
+	}
+
+	for _, rule := range template.Ingress {
+		peers, ports, err := generateTemplateRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		np.Spec.Ingress = append(np.Spec.Ingress, knetworkingv1.NetworkPolicyIngressRule{From: peers, Ports: ports})
+	}
+	for _, rule := range template.Egress {
+		peers, ports, err := generateTemplateRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		np.Spec.Egress = append(np.Spec.Egress, knetworkingv1.NetworkPolicyEgressRule{To: peers, Ports: ports})
+	}
+	if template.AllowDNS {
+		udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+		port := intstr.FromInt(dnsPort)
+		np.Spec.Egress = append(np.Spec.Egress, knetworkingv1.NetworkPolicyEgressRule{
+			Ports: []knetworkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &port},
+				{Protocol: &tcp, Port: &port},
+			},
+		})
+	}
+
+	for _, policyType := range template.PolicyTypes {
+		switch knetworkingv1.PolicyType(policyType) {
+		case knetworkingv1.PolicyTypeIngress, knetworkingv1.PolicyTypeEgress:
+			np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, knetworkingv1.PolicyType(policyType))
+		default:
+			return nil, fmt.Errorf("invalid policy type %v", policyType)
+		}
+	}
+	if len(np.Spec.PolicyTypes) == 0 {
+		if len(np.Spec.Ingress) > 0 {
+			np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, knetworkingv1.PolicyTypeIngress)
+		}
+		if len(np.Spec.Egress) > 0 {
+			np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, knetworkingv1.PolicyTypeEgress)
+		}
+	}
+	if len(np.Spec.PolicyTypes) == 0 {
+		return nil, fmt.Errorf("template has neither rules nor policy types")
+	}
+	return np, nil
+}
+
+func generateTemplateRule(rule v32.ProjectNetworkPolicyRule) ([]knetworkingv1.NetworkPolicyPeer, []knetworkingv1.NetworkPolicyPort, error) {
+	var peers []knetworkingv1.NetworkPolicyPeer
+	for _, projectName := range rule.ProjectNames {
+		_, projectID := ref.Parse(projectName)
+		peers = append(peers, knetworkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &v1.LabelSelector{
+				MatchLabels: map[string]string{nslabels.ProjectIDFieldLabel: projectID},
+			},
+		})
+	}
+
+	for _, cidr := range rule.CIDRs {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CIDR %v: %v", cidr, err)
+		}
+		ipBlock := &knetworkingv1.IPBlock{CIDR: cidr}
+		for _, except := range rule.ExceptCIDRs {
+			exceptIP, exceptBlock, err := net.ParseCIDR(except)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid CIDR %v: %v", except, err)
+			}
+			blockSize, _ := block.Mask.Size()
+			exceptSize, _ := exceptBlock.Mask.Size()
+			if block.Contains(exceptIP) && exceptSize > blockSize {
+				ipBlock.Except = append(ipBlock.Except, except)
+			}
+		}
+		peers = append(peers, knetworkingv1.NetworkPolicyPeer{IPBlock: ipBlock})
+	}
+
+	var ports []knetworkingv1.NetworkPolicyPort
+	for _, p := range rule.Ports {
+		protocol := corev1.Protocol(p.Protocol)
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		port := knetworkingv1.NetworkPolicyPort{Protocol: &protocol}
+		if p.Port != 0 {
+			portNumber := intstr.FromInt(int(p.Port))
+			port.Port = &portNumber
+		}
+		if p.EndPort != 0 {
+			if p.Port == 0 || p.EndPort < p.Port {
+				return nil, nil, fmt.Errorf("end port %v requires a port lower than it", p.EndPort)
+			}
+			endPort := p.EndPort
+			port.EndPort = &endPort
+		}
+		ports = append(ports, port)
+	}
+	return peers, ports, nil
+}
@@ -0,0 +1,121 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementagent/nslabels"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	knetworkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newTemplatePNP(template *v32.ProjectNetworkPolicyTemplate) *v3.ProjectNetworkPolicy {
+	return &v3.ProjectNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "pnp-1", Namespace: "p-1"},
+		Spec:       v32.ProjectNetworkPolicySpec{ProjectName: "c-1:p-1", Template: template},
+	}
+}
+
+func TestGenerateTemplateNetworkPolicyIngressFromProject(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}}
+	pnp := newTemplatePNP(&v32.ProjectNetworkPolicyTemplate{
+		PodSelector: map[string]string{"app": "web"},
+		Ingress: []v32.ProjectNetworkPolicyRule{{
+			ProjectNames: []string{"c-1:p-2"},
+			Ports:        []v32.ProjectNetworkPolicyPort{{Port: 8080}},
+		}},
+	})
+
+	np, err := generateTemplateNetworkPolicy(ns, "p-1", pnp)
+	require.NoError(t, err)
+	assert.Equal(t, "pnp-tmpl-pnp-1", np.Name)
+	assert.Equal(t, "ns-1", np.Namespace)
+	assert.Equal(t, "pnp-1", np.Labels[projectNetworkPolicyLabel])
+	assert.Equal(t, "p-1", np.Labels[nslabels.ProjectIDFieldLabel])
+	assert.Equal(t, map[string]string{"app": "web"}, np.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []knetworkingv1.PolicyType{knetworkingv1.PolicyTypeIngress}, np.Spec.PolicyTypes)
+
+	require.Len(t, np.Spec.Ingress, 1)
+	require.Len(t, np.Spec.Ingress[0].From, 1)
+	assert.Equal(t, map[string]string{nslabels.ProjectIDFieldLabel: "p-2"}, np.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels)
+	require.Len(t, np.Spec.Ingress[0].Ports, 1)
+	assert.Equal(t, corev1.ProtocolTCP, *np.Spec.Ingress[0].Ports[0].Protocol)
+	assert.Equal(t, intstr.FromInt(8080), *np.Spec.Ingress[0].Ports[0].Port)
+}
+
+func TestGenerateTemplateNetworkPolicyEgressExceptDNS(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}}
+	pnp := newTemplatePNP(&v32.ProjectNetworkPolicyTemplate{
+		PolicyTypes: []string{"Egress"},
+		AllowDNS:    true,
+		Egress: []v32.ProjectNetworkPolicyRule{{
+			CIDRs:       []string{"10.0.0.0/8"},
+			ExceptCIDRs: []string{"10.1.0.0/16", "192.168.0.0/16"},
+			Ports:       []v32.ProjectNetworkPolicyPort{{Protocol: "UDP", Port: 5000, EndPort: 5010}},
+		}},
+	})
+
+	np, err := generateTemplateNetworkPolicy(ns, "p-1", pnp)
+	require.NoError(t, err)
+	assert.Equal(t, []knetworkingv1.PolicyType{knetworkingv1.PolicyTypeEgress}, np.Spec.PolicyTypes)
+	assert.Empty(t, np.Spec.Ingress)
+
+	require.Len(t, np.Spec.Egress, 2)
+	require.Len(t, np.Spec.Egress[0].To, 1)
+	assert.Equal(t, &knetworkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}, np.Spec.Egress[0].To[0].IPBlock)
+	require.Len(t, np.Spec.Egress[0].Ports, 1)
+	assert.Equal(t, corev1.ProtocolUDP, *np.Spec.Egress[0].Ports[0].Protocol)
+	assert.Equal(t, int32(5010), *np.Spec.Egress[0].Ports[0].EndPort)
+
+	dns := np.Spec.Egress[1]
+	assert.Empty(t, dns.To)
+	require.Len(t, dns.Ports, 2)
+	assert.Equal(t, corev1.ProtocolUDP, *dns.Ports[0].Protocol)
+	assert.Equal(t, corev1.ProtocolTCP, *dns.Ports[1].Protocol)
+	assert.Equal(t, intstr.FromInt(dnsPort), *dns.Ports[0].Port)
+}
+
+func TestGenerateTemplateNetworkPolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *v32.ProjectNetworkPolicyTemplate
+	}{
+		{
+			name:     "no rules nor policy types",
+			template: &v32.ProjectNetworkPolicyTemplate{},
+		},
+		{
+			name:     "invalid policy type",
+			template: &v32.ProjectNetworkPolicyTemplate{PolicyTypes: []string{"Sideways"}},
+		},
+		{
+			name: "invalid CIDR",
+			template: &v32.ProjectNetworkPolicyTemplate{
+				Egress: []v32.ProjectNetworkPolicyRule{{CIDRs: []string{"10.0.0.0/33"}}},
+			},
+		},
+		{
+			name: "end port lower than port",
+			template: &v32.ProjectNetworkPolicyTemplate{
+				Ingress: []v32.ProjectNetworkPolicyRule{{Ports: []v32.ProjectNetworkPolicyPort{{Port: 80, EndPort: 70}}}},
+			},
+		},
+		{
+			name: "end port without port",
+			template: &v32.ProjectNetworkPolicyTemplate{
+				Ingress: []v32.ProjectNetworkPolicyRule{{Ports: []v32.ProjectNetworkPolicyPort{{EndPort: 70}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateTemplateNetworkPolicy(&corev1.Namespace{}, "p-1", newTemplatePNP(tt.template))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package rbac
 
 import (
+	"time"
+
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -50,6 +52,13 @@ func (c *crtbLifecycle) syncCRTB(binding *v3.ClusterRoleTemplateBinding) error {
 		return nil
 	}
 
+	if expired, remaining := pkgrbac.BindingExpired(binding, time.Now()); expired {
+		logrus.Debugf("ClusterRoleTemplateBinding %s/%s expired, removing its bindings", binding.Namespace, binding.Name)
+		return c.ensureCRTBDelete(binding)
+	} else if remaining > 0 {
+		c.m.crtbs.Controller().EnqueueAfter(binding.Namespace, binding.Name, remaining)
+	}
+
 	rt, err := c.m.rtLister.Get("", binding.RoleTemplateName)
 	if err != nil {
 		return errors.Wrapf(err, "couldn't get role template %v", binding.RoleTemplateName)
@@ -0,0 +1,435 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	apisv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// DriftReportName is the name of the RBACDriftReport of a cluster, in the namespace of the cluster.
+	DriftReportName = "rbac-drift"
+
+	clusterRoleBindingKind = "ClusterRoleBinding"
+	roleBindingKind        = "RoleBinding"
+
+	// driftCheckDisabledInterval is how often the interval setting is read again while the drift check is disabled.
+	driftCheckDisabledInterval = time.Minute
+)
+
+// grantedBinding is a RoleBinding or ClusterRoleBinding that ensureBindings creates for the ClusterRoleTemplateBindings
+// and ProjectRoleTemplateBindings in owners.
+type grantedBinding struct {
+	kind      string
+	namespace string
+	name      string
+	roleName  string
+	subject   rbacv1.Subject
+	owners    map[string]bool
+}
+
+// observedBinding is a RoleBinding or ClusterRoleBinding of the cluster.
+type observedBinding struct {
+	kind      string
+	namespace string
+	name      string
+	roleRef   rbacv1.RoleRef
+	subjects  []rbacv1.Subject
+	labels    map[string]string
+}
+
+func bindingKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// driftChecker periodically compares the RoleBindings and ClusterRoleBindings of the cluster with the ones its
+// ClusterRoleTemplateBindings and ProjectRoleTemplateBindings grant, and writes the differences to the RBACDriftReport
+// of the cluster. The handlers of the bindings only notice changes of the management objects, so the bindings edited or
+// deleted directly in the cluster are only found here.
+type driftChecker struct {
+	m       *manager
+	reports mgmtv3.RBACDriftReportClient
+	now     func() time.Time
+}
+
+func newDriftChecker(m *manager, reports mgmtv3.RBACDriftReportClient) *driftChecker {
+	return &driftChecker{
+		m:       m,
+		reports: reports,
+		now:     time.Now,
+	}
+}
+
+func (d *driftChecker) run(ctx context.Context) {
+	wait := driftCheckDisabledInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		interval := time.Duration(settings.RBACDriftCheckIntervalMinutes.GetInt()) * time.Minute
+		if interval <= 0 {
+			wait = driftCheckDisabledInterval
+			continue
+		}
+		wait = interval
+
+		status := d.check(settings.RBACDriftAutoRepair.Get() == "true")
+		if err := d.writeReport(status); err != nil {
+			logrus.Errorf("Failed to write RBAC drift report of cluster %s: %v", d.m.clusterName, err)
+		}
+	}
+}
+
+// check returns the drift of the bindings of the cluster, after repairing it when repair is set. Only the stale
+// bindings labeled by ensureBindings are deleted; the unmanaged bindings created by others are left alone.
+func (d *driftChecker) check(repair bool) apisv3.RBACDriftReportStatus {
+	status := apisv3.RBACDriftReportStatus{
+		ClusterName: d.m.clusterName,
+		CheckedAt:   d.now().UTC().Format(time.RFC3339),
+	}
+	granted, err := d.granted()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	observed, err := d.observed()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	managedRoles, err := d.managedRoles()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	var errs []string
+	repairFailed := func(action string, binding apisv3.RBACDriftBinding, err error) {
+		errs = append(errs, fmt.Sprintf("failed to %s %s %s: %v", action, strings.ToLower(binding.Kind), path.Join(binding.Namespace, binding.Name), err))
+	}
+
+	for key, obj := range observed {
+		want, ok := granted[key]
+		if ok {
+			delete(granted, key)
+			reason := modification(want, obj)
+			if reason == "" {
+				continue
+			}
+			binding := driftBinding(obj.kind, obj.namespace, obj.name, obj.roleRef.Name, obj.subjects, firstOwner(want.owners), reason)
+			status.Modified = append(status.Modified, binding)
+			if repair {
+				// The role of a binding cannot change, so it is recreated.
+				if err := d.deleteBinding(obj.kind, obj.namespace, obj.name); err != nil {
+					repairFailed("delete", binding, err)
+				} else if err := d.createBinding(want); err != nil {
+					repairFailed("create", binding, err)
+				} else {
+					status.Repaired++
+				}
+			}
+			continue
+		}
+
+		if owner, ok := obj.labels[rtbOwnerLabel]; ok {
+			binding := driftBinding(obj.kind, obj.namespace, obj.name, obj.roleRef.Name, obj.subjects, owner, fmt.Sprintf("not granted by %s", owner))
+			status.Unmanaged = append(status.Unmanaged, binding)
+			if repair {
+				if err := d.deleteBinding(obj.kind, obj.namespace, obj.name); err != nil {
+					repairFailed("delete", binding, err)
+				} else {
+					status.Repaired++
+				}
+			}
+			continue
+		}
+		if _, ok := obj.labels[rtbOwnerLabelLegacy]; ok {
+			// legacy bindings are removed by the legacy cleaner
+			continue
+		}
+		if obj.roleRef.Kind == "ClusterRole" && managedRoles[obj.roleRef.Name] {
+			status.Unmanaged = append(status.Unmanaged, driftBinding(obj.kind, obj.namespace, obj.name, obj.roleRef.Name, obj.subjects, "", "not created by Rancher"))
+		}
+	}
+
+	for _, want := range granted {
+		binding := driftBinding(want.kind, want.namespace, want.name, want.roleName, []rbacv1.Subject{want.subject}, firstOwner(want.owners), "does not exist")
+		status.Missing = append(status.Missing, binding)
+		if repair {
+			if err := d.createBinding(want); err != nil {
+				repairFailed("create", binding, err)
+			} else {
+				status.Repaired++
+			}
+		}
+	}
+
+	sortDriftBindings(status.Missing)
+	sortDriftBindings(status.Modified)
+	sortDriftBindings(status.Unmanaged)
+	sort.Strings(errs)
+	status.Error = strings.Join(errs, "; ")
+	if len(status.Missing)+len(status.Modified)+len(status.Unmanaged) > 0 {
+		logrus.Infof("Found RBAC drift in cluster %s: %d missing, %d modified and %d unmanaged bindings, repaired %d",
+			d.m.clusterName, len(status.Missing), len(status.Modified), len(status.Unmanaged), status.Repaired)
+	}
+	return status
+}
+
+// granted returns the bindings the ClusterRoleTemplateBindings and ProjectRoleTemplateBindings of the cluster grant, the
+// same way syncCRTB and syncPRTB create them.
+func (d *driftChecker) granted() (map[string]*grantedBinding, error) {
+	granted := map[string]*grantedBinding{}
+	add := func(kind, namespace string, roles map[string]*v3.RoleTemplate, owner string, subject rbacv1.Subject) {
+		for roleName := range roles {
+			_, objectMeta, _, _ := bindingParts(namespace, roleName, owner, subject)
+			key := bindingKey(kind, namespace, objectMeta.Name)
+			if granted[key] == nil {
+				granted[key] = &grantedBinding{
+					kind:      kind,
+					namespace: namespace,
+					name:      objectMeta.Name,
+					roleName:  roleName,
+					subject:   subject,
+					owners:    map[string]bool{},
+				}
+			}
+			granted[key].owners[owner] = true
+		}
+	}
+
+	now := d.now()
+	for _, obj := range d.m.crtbIndexer.List() {
+		crtb, ok := obj.(*v3.ClusterRoleTemplateBinding)
+		if !ok || crtb.ClusterName != d.m.clusterName || crtb.DeletionTimestamp != nil || crtb.RoleTemplateName == "" {
+			continue
+		}
+		if expired, _ := pkgrbac.BindingExpired(crtb, now); expired {
+			continue
+		}
+		subject, roles, err := d.rolesOf(crtb, crtb.RoleTemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if roles != nil {
+			add(clusterRoleBindingKind, "", roles, pkgrbac.GetRTBLabel(crtb.ObjectMeta), subject)
+		}
+	}
+
+	for _, obj := range d.m.prtbIndexer.List() {
+		prtb, ok := obj.(*v3.ProjectRoleTemplateBinding)
+		if !ok || prtb.ObjClusterName() != d.m.clusterName || prtb.DeletionTimestamp != nil || prtb.RoleTemplateName == "" {
+			continue
+		}
+		if expired, _ := pkgrbac.BindingExpired(prtb, now); expired {
+			continue
+		}
+		subject, roles, err := d.rolesOf(prtb, prtb.RoleTemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if roles == nil {
+			continue
+		}
+		namespaces, err := d.m.nsIndexer.ByIndex(nsByProjectIndex, prtb.ProjectName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't list namespaces with project ID %v: %w", prtb.ProjectName, err)
+		}
+		for _, n := range namespaces {
+			ns := n.(*v1.Namespace)
+			if !ns.DeletionTimestamp.IsZero() {
+				continue
+			}
+			add(roleBindingKind, ns.Name, roles, pkgrbac.GetRTBLabel(prtb.ObjectMeta), subject)
+		}
+	}
+	return granted, nil
+}
+
+// rolesOf returns the subject of a binding and the RoleTemplates it grants, or no RoleTemplates if the binding grants
+// nothing because it has no subject or its RoleTemplate does not exist.
+func (d *driftChecker) rolesOf(binding metav1.Object, roleTemplateName string) (rbacv1.Subject, map[string]*v3.RoleTemplate, error) {
+	subject, err := pkgrbac.BuildSubjectFromRTB(binding)
+	if err != nil {
+		return subject, nil, nil
+	}
+	rt, err := d.m.rtLister.Get("", roleTemplateName)
+	if apierrors.IsNotFound(err) {
+		return subject, nil, nil
+	} else if err != nil {
+		return subject, nil, err
+	}
+	roles := map[string]*v3.RoleTemplate{}
+	if err := d.m.gatherRoles(rt, roles, 0); err != nil {
+		logrus.Debugf("Skipping binding %s/%s in RBAC drift check: %v", binding.GetNamespace(), binding.GetName(), err)
+		return subject, nil, nil
+	}
+	return subject, roles, nil
+}
+
+func (d *driftChecker) observed() (map[string]*observedBinding, error) {
+	observed := map[string]*observedBinding{}
+	crbs, err := d.m.crbLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, crb := range crbs {
+		observed[bindingKey(clusterRoleBindingKind, "", crb.Name)] = &observedBinding{
+			kind:     clusterRoleBindingKind,
+			name:     crb.Name,
+			roleRef:  crb.RoleRef,
+			subjects: crb.Subjects,
+			labels:   crb.Labels,
+		}
+	}
+	rbs, err := d.m.rbLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range rbs {
+		observed[bindingKey(roleBindingKind, rb.Namespace, rb.Name)] = &observedBinding{
+			kind:      roleBindingKind,
+			namespace: rb.Namespace,
+			name:      rb.Name,
+			roleRef:   rb.RoleRef,
+			subjects:  rb.Subjects,
+			labels:    rb.Labels,
+		}
+	}
+	return observed, nil
+}
+
+// managedRoles returns the names of the ClusterRoles Rancher creates for RoleTemplates.
+func (d *driftChecker) managedRoles() (map[string]bool, error) {
+	rts, err := d.m.rtLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	roles := map[string]bool{}
+	for _, rt := range rts {
+		if !rt.External {
+			roles[rt.Name] = true
+		}
+	}
+	return roles, nil
+}
+
+// modification returns why a binding differs from the granted one, or nothing if it does not.
+func modification(want *grantedBinding, obj *observedBinding) string {
+	var reasons []string
+	if obj.roleRef.Kind != "ClusterRole" || obj.roleRef.Name != want.roleName {
+		reasons = append(reasons, fmt.Sprintf("role is %s %s instead of ClusterRole %s", obj.roleRef.Kind, obj.roleRef.Name, want.roleName))
+	}
+	if !reflect.DeepEqual(obj.subjects, []rbacv1.Subject{want.subject}) {
+		reasons = append(reasons, "subjects differ")
+	}
+	if !want.owners[obj.labels[rtbOwnerLabel]] {
+		reasons = append(reasons, fmt.Sprintf("%s label is %q", rtbOwnerLabel, obj.labels[rtbOwnerLabel]))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func (d *driftChecker) createBinding(want *grantedBinding) error {
+	_, objectMeta, subjects, roleRef := bindingParts(want.namespace, want.roleName, firstOwner(want.owners), want.subject)
+	var err error
+	if want.kind == clusterRoleBindingKind {
+		_, err = d.m.clusterRoleBindings.Create(&rbacv1.ClusterRoleBinding{
+			ObjectMeta: objectMeta,
+			Subjects:   subjects,
+			RoleRef:    roleRef,
+		})
+	} else {
+		objectMeta.Namespace = want.namespace
+		_, err = d.m.roleBindings.Create(&rbacv1.RoleBinding{
+			ObjectMeta: objectMeta,
+			Subjects:   subjects,
+			RoleRef:    roleRef,
+		})
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	logrus.Infof("Repaired %s %s in cluster %s", strings.ToLower(want.kind), path.Join(want.namespace, want.name), d.m.clusterName)
+	return nil
+}
+
+func (d *driftChecker) deleteBinding(kind, namespace, name string) error {
+	var err error
+	if kind == clusterRoleBindingKind {
+		err = d.m.clusterRoleBindings.Delete(name, &metav1.DeleteOptions{})
+	} else {
+		err = d.m.roleBindings.DeleteNamespaced(namespace, name, &metav1.DeleteOptions{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *driftChecker) writeReport(status apisv3.RBACDriftReportStatus) error {
+	report, err := d.reports.Get(d.m.clusterName, DriftReportName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = d.reports.Create(&apisv3.RBACDriftReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DriftReportName,
+				Namespace: d.m.clusterName,
+			},
+			Status: status,
+		})
+		return err
+	} else if err != nil {
+		return err
+	}
+	report = report.DeepCopy()
+	report.Status = status
+	_, err = d.reports.Update(report)
+	return err
+}
+
+func driftBinding(kind, namespace, name, roleName string, subjects []rbacv1.Subject, owner, reason string) apisv3.RBACDriftBinding {
+	return apisv3.RBACDriftBinding{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		RoleName:  roleName,
+		Subjects:  subjects,
+		Owner:     owner,
+		Reason:    reason,
+	}
+}
+
+func firstOwner(owners map[string]bool) string {
+	var first string
+	for owner := range owners {
+		if first == "" || owner < first {
+			first = owner
+		}
+	}
+	return first
+}
+
+func sortDriftBindings(bindings []apisv3.RBACDriftBinding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindingKey(bindings[i].Kind, bindings[i].Namespace, bindings[i].Name) <
+			bindingKey(bindings[j].Kind, bindings[j].Namespace, bindings[j].Name)
+	})
+}
@@ -0,0 +1,234 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	apisv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	fakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	rbacfakes "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1/fakes"
+	nsutils "github.com/rancher/rancher/pkg/namespace"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeDriftReportClient struct {
+	mgmtv3.RBACDriftReportClient
+	report *apisv3.RBACDriftReport
+}
+
+func (f *fakeDriftReportClient) Get(_, name string, _ metav1.GetOptions) (*apisv3.RBACDriftReport, error) {
+	if f.report == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+	}
+	return f.report, nil
+}
+
+func (f *fakeDriftReportClient) Create(obj *apisv3.RBACDriftReport) (*apisv3.RBACDriftReport, error) {
+	f.report = obj
+	return obj, nil
+}
+
+func (f *fakeDriftReportClient) Update(obj *apisv3.RBACDriftReport) (*apisv3.RBACDriftReport, error) {
+	f.report = obj
+	return obj, nil
+}
+
+type driftFixture struct {
+	checker     *driftChecker
+	createdCRBs []string
+	createdRBs  []string
+	deletedCRBs []string
+	deletedRBs  []string
+}
+
+func newDriftFixture(t *testing.T, crbs []*rbacv1.ClusterRoleBinding, rbs []*rbacv1.RoleBinding) *driftFixture {
+	templates := map[string]*v3.RoleTemplate{
+		"member": {ObjectMeta: metav1.ObjectMeta{Name: "member"}, Context: "cluster", RoleTemplateNames: []string{"view"}},
+		"view":   {ObjectMeta: metav1.ObjectMeta{Name: "view"}, Context: "project"},
+	}
+	crtbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, crtbIndexer.Add(&v3.ClusterRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "crtb-1", Namespace: "c-1"}, ClusterName: "c-1", UserName: "u-1", RoleTemplateName: "member",
+	}))
+	require.NoError(t, crtbIndexer.Add(&v3.ClusterRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "crtb-2", Namespace: "c-2"}, ClusterName: "c-2", UserName: "u-1", RoleTemplateName: "member",
+	}))
+	prtbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, prtbIndexer.Add(&v3.ProjectRoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "prtb-1", Namespace: "p-1"}, ProjectName: "c-1:p-1", UserName: "u-2", RoleTemplateName: "view",
+	}))
+	require.NoError(t, prtbIndexer.Add(&v3.ProjectRoleTemplateBinding{
+		ObjectMeta:       metav1.ObjectMeta{Name: "prtb-expired", Namespace: "p-1", Annotations: map[string]string{pkgrbac.ExpiresAtAnnotation: "2023-01-01T00:00:00Z"}},
+		ProjectName:      "c-1:p-1",
+		UserName:         "u-4",
+		RoleTemplateName: "view",
+	}))
+	nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{nsByProjectIndex: nsutils.NsByProjectID})
+	require.NoError(t, nsIndexer.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1", Annotations: map[string]string{projectIDAnnotation: "c-1:p-1"}}}))
+
+	f := &driftFixture{}
+	m := &manager{
+		clusterName: "c-1",
+		crtbIndexer: crtbIndexer,
+		prtbIndexer: prtbIndexer,
+		nsIndexer:   nsIndexer,
+		rtLister: &fakes.RoleTemplateListerMock{
+			GetFunc: func(_, name string) (*v3.RoleTemplate, error) {
+				if rt, ok := templates[name]; ok {
+					return rt, nil
+				}
+				return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+			},
+			ListFunc: func(string, labels.Selector) ([]*v3.RoleTemplate, error) {
+				return []*v3.RoleTemplate{templates["member"], templates["view"]}, nil
+			},
+		},
+		crbLister: &rbacfakes.ClusterRoleBindingListerMock{
+			ListFunc: func(string, labels.Selector) ([]*rbacv1.ClusterRoleBinding, error) {
+				return crbs, nil
+			},
+		},
+		rbLister: &rbacfakes.RoleBindingListerMock{
+			ListFunc: func(string, labels.Selector) ([]*rbacv1.RoleBinding, error) {
+				return rbs, nil
+			},
+		},
+		clusterRoleBindings: &rbacfakes.ClusterRoleBindingInterfaceMock{
+			CreateFunc: func(obj *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error) {
+				f.createdCRBs = append(f.createdCRBs, obj.Name)
+				return obj, nil
+			},
+			DeleteFunc: func(name string, _ *metav1.DeleteOptions) error {
+				f.deletedCRBs = append(f.deletedCRBs, name)
+				return nil
+			},
+		},
+		roleBindings: &rbacfakes.RoleBindingInterfaceMock{
+			CreateFunc: func(obj *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
+				f.createdRBs = append(f.createdRBs, obj.Namespace+"/"+obj.Name)
+				return obj, nil
+			},
+			DeleteNamespacedFunc: func(namespace, name string, _ *metav1.DeleteOptions) error {
+				f.deletedRBs = append(f.deletedRBs, namespace+"/"+name)
+				return nil
+			},
+		},
+	}
+	f.checker = newDriftChecker(m, &fakeDriftReportClient{})
+	f.checker.now = func() time.Time { return time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return f
+}
+
+func TestDriftCheck(t *testing.T) {
+	u1 := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-1", APIGroup: rbacv1.GroupName}
+	u2 := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-2", APIGroup: rbacv1.GroupName}
+	u3 := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-3", APIGroup: rbacv1.GroupName}
+	crb := func(roleName string, subject rbacv1.Subject, owner string) *rbacv1.ClusterRoleBinding {
+		_, objectMeta, subjects, roleRef := bindingParts("", roleName, owner, subject)
+		return &rbacv1.ClusterRoleBinding{ObjectMeta: objectMeta, Subjects: subjects, RoleRef: roleRef}
+	}
+	_, viewRBMeta, _, viewRoleRef := bindingParts("ns-1", "view", "p-1_prtb-1", u2)
+	viewRBMeta.Namespace = "ns-1"
+
+	member := crb("member", u1, "c-1_crtb-1")
+	view := crb("view", u1, "c-1_crtb-1")
+	stale := crb("member", u3, "c-1_gone")
+	manual := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "manual"},
+		Subjects:   []rbacv1.Subject{u3},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+	}
+	other := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Subjects:   []rbacv1.Subject{u3},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+	}
+	edited := &rbacv1.RoleBinding{ObjectMeta: viewRBMeta, Subjects: []rbacv1.Subject{u3}, RoleRef: viewRoleRef}
+
+	f := newDriftFixture(t, []*rbacv1.ClusterRoleBinding{member, stale, manual, other}, []*rbacv1.RoleBinding{edited})
+	status := f.checker.check(false)
+	assert.Equal(t, "c-1", status.ClusterName)
+	assert.Equal(t, "2023-03-01T12:00:00Z", status.CheckedAt)
+	assert.Empty(t, status.Error)
+
+	require.Len(t, status.Missing, 1)
+	assert.Equal(t, apisv3.RBACDriftBinding{
+		Kind:     "ClusterRoleBinding",
+		Name:     view.Name,
+		RoleName: "view",
+		Subjects: []rbacv1.Subject{u1},
+		Owner:    "c-1_crtb-1",
+		Reason:   "does not exist",
+	}, status.Missing[0])
+
+	require.Len(t, status.Modified, 1)
+	assert.Equal(t, "RoleBinding", status.Modified[0].Kind)
+	assert.Equal(t, "ns-1", status.Modified[0].Namespace)
+	assert.Equal(t, "p-1_prtb-1", status.Modified[0].Owner)
+	assert.Equal(t, "subjects differ", status.Modified[0].Reason)
+
+	require.Len(t, status.Unmanaged, 2)
+	assert.Equal(t, stale.Name, status.Unmanaged[0].Name)
+	assert.Equal(t, "not granted by c-1_gone", status.Unmanaged[0].Reason)
+	assert.Equal(t, "manual", status.Unmanaged[1].Name)
+	assert.Equal(t, "not created by Rancher", status.Unmanaged[1].Reason)
+
+	assert.Zero(t, status.Repaired)
+	assert.Empty(t, f.createdCRBs)
+	assert.Empty(t, f.deletedCRBs)
+}
+
+func TestDriftRepair(t *testing.T) {
+	u1 := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-1", APIGroup: rbacv1.GroupName}
+	u2 := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-2", APIGroup: rbacv1.GroupName}
+	_, memberMeta, _, _ := bindingParts("", "member", "c-1_crtb-1", u1)
+	_, viewMeta, _, _ := bindingParts("", "view", "c-1_crtb-1", u1)
+	_, rbMeta, rbSubjects, rbRoleRef := bindingParts("ns-1", "view", "p-1_prtb-1", u2)
+	rbMeta.Namespace = "ns-1"
+	// the owner label was removed, so the handlers of the bindings no longer see it
+	rbMeta.Labels = nil
+
+	f := newDriftFixture(t, []*rbacv1.ClusterRoleBinding{
+		{
+			ObjectMeta: memberMeta,
+			Subjects:   []rbacv1.Subject{u1},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "manual"},
+			Subjects:   []rbacv1.Subject{u2},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "member"},
+		},
+	}, []*rbacv1.RoleBinding{{ObjectMeta: rbMeta, Subjects: rbSubjects, RoleRef: rbRoleRef}})
+
+	status := f.checker.check(true)
+	assert.Empty(t, status.Error)
+	require.Len(t, status.Modified, 2)
+	assert.Equal(t, "role is ClusterRole cluster-admin instead of ClusterRole member", status.Modified[0].Reason)
+	assert.Equal(t, `authz.cluster.cattle.io/rtb-owner-updated label is ""`, status.Modified[1].Reason)
+	assert.Len(t, status.Missing, 1)
+	assert.Len(t, status.Unmanaged, 1)
+	assert.Equal(t, 3, status.Repaired)
+
+	assert.Equal(t, []string{memberMeta.Name}, f.deletedCRBs, "unmanaged bindings must not be deleted")
+	assert.ElementsMatch(t, []string{memberMeta.Name, viewMeta.Name}, f.createdCRBs)
+	assert.Equal(t, []string{"ns-1/" + rbMeta.Name}, f.deletedRBs)
+	assert.Equal(t, []string{"ns-1/" + rbMeta.Name}, f.createdRBs)
+
+	require.NoError(t, f.checker.writeReport(status))
+	report := f.checker.reports.(*fakeDriftReportClient).report
+	assert.Equal(t, "c-1", report.Namespace)
+	assert.Equal(t, DriftReportName, report.Name)
+	assert.Equal(t, status, report.Status)
+}
@@ -123,6 +123,8 @@ func Register(ctx context.Context, workload *config.UserContext) {
 
 	workload.Core.Namespaces("").AddLifecycle(ctx, "namespace-auth", newNamespaceLifecycle(r, sync))
 	management.Management.RoleTemplates("").AddHandler(ctx, "cluster-roletemplate-sync", newRTLifecycle(r))
+
+	go newDriftChecker(r, workload.Management.Wrangler.Mgmt.RBACDriftReport()).run(ctx)
 }
 
 type manager struct {
@@ -14,6 +14,7 @@
 	namespaceutil "github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/project"
 	projectpkg "github.com/rancher/rancher/pkg/project"
+	pkgrbac "github.com/rancher/rancher/pkg/rbac"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -227,6 +228,10 @@ func (n *nsLifecycle) ensurePRTBAddToNamespace(ns *v1.Namespace) (bool, error) {
 			continue
 		}
 
+		if expired, _ := pkgrbac.BindingExpired(prtb, time.Now()); expired {
+			continue
+		}
+
 		if prtb.RoleTemplateName == "" {
 			logrus.Warnf("ProjectRoleTemplateBinding %v has no role template set. Skipping.", prtb.Name)
 			continue
@@ -3,6 +3,7 @@
 import (
 	"reflect"
 	"sort"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -90,6 +91,13 @@ func (p *prtbLifecycle) syncPRTB(binding *v3.ProjectRoleTemplateBinding) error {
 		return nil
 	}
 
+	if expired, remaining := pkgrbac.BindingExpired(binding, time.Now()); expired {
+		logrus.Debugf("ProjectRoleTemplateBinding %s/%s expired, removing its bindings", binding.Namespace, binding.Name)
+		return p.ensurePRTBDelete(binding)
+	} else if remaining > 0 {
+		p.m.prtbs.Controller().EnqueueAfter(binding.Namespace, binding.Name, remaining)
+	}
+
 	rt, err := p.m.rtLister.Get("", binding.RoleTemplateName)
 	if err != nil {
 		return errors.Wrapf(err, "couldn't get role template %v", binding.RoleTemplateName)
@@ -38,7 +38,7 @@ func (c *quotaResetController) resetNamespaceQuota(key string, project *v3.Proje
 			continue
 		}
 		toUpdate := ns.DeepCopy()
-		delete(toUpdate.Annotations, resourceQuotaAnnotation)
+		delete(toUpdate.Annotations, ResourceQuotaAnnotation)
 		if _, err := c.namespaces.Update(toUpdate); err != nil {
 			return nil, err
 		}
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"time"
 
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config"
@@ -72,6 +73,24 @@ func registerDeferred(ctx context.Context, cluster *config.UserContext) {
 		namespaces: cluster.Core.Namespaces(""),
 	}
 	cluster.Management.Management.Projects(cluster.ClusterName).AddHandler(ctx, "namespaceResourceQuotaResetController", reset.resetNamespaceQuota)
+
+	usage := &usageController{
+		ctx:            ctx,
+		clusterName:    cluster.ClusterName,
+		projects:       cluster.Management.Management.Projects(cluster.ClusterName),
+		enqueueAfter:   cluster.Management.Management.Projects(cluster.ClusterName).Controller().EnqueueAfter,
+		projectLister:  cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
+		nsIndexer:      nsInformer.GetIndexer(),
+		nsLister:       cluster.Core.Namespaces("").Controller().Lister(),
+		quotaLister:    cluster.Core.ResourceQuotas("").Controller().Lister(),
+		events:         cluster.Management.Core.Events(""),
+		notifierLister: cluster.Management.Management.Notifiers(cluster.ClusterName).Controller().Lister(),
+		secretLister:   cluster.Management.Core.Secrets("").Controller().Lister(),
+		dialer:         cluster.Management.Dialer,
+		now:            time.Now,
+	}
+	cluster.Core.ResourceQuotas("").AddHandler(ctx, "resourceQuotaUsageController", usage.syncResourceQuota)
+	cluster.Management.Management.Projects(cluster.ClusterName).AddHandler(ctx, "resourceQuotaProjectUsageController", usage.syncProject)
 }
 
 func nsByProjectID(obj interface{}) ([]string, error) {
@@ -7,26 +7,19 @@
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/ref"
+	validate "github.com/rancher/rancher/pkg/resourcequota"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func convertResourceListToLimit(rList corev1.ResourceList) (*v32.ResourceQuotaLimit, error) {
-	converted, err := convert.EncodeToMap(rList)
-	if err != nil {
-		return nil, err
-	}
-
 	convertedMap := map[string]string{}
-	for key, value := range converted {
-		convertedMap[key] = convert.ToString(value)
+	for key, value := range rList {
+		convertedMap[string(key)] = value.String()
 	}
 
-	toReturn := &v32.ResourceQuotaLimit{}
-	err = convert.ToObj(convertedMap, toReturn)
-
-	return toReturn, err
+	return validate.MapToLimit(convertedMap)
 }
 
 func convertResourceLimitResourceQuotaSpec(limit *v32.ResourceQuotaLimit) (*corev1.ResourceQuotaSpec, error) {
@@ -41,12 +34,7 @@ func convertResourceLimitResourceQuotaSpec(limit *v32.ResourceQuotaLimit) (*core
 }
 
 func convertProjectResourceLimitToResourceList(limit *v32.ResourceQuotaLimit) (corev1.ResourceList, error) {
-	in, err := json.Marshal(limit)
-	if err != nil {
-		return nil, err
-	}
-	limitsMap := map[string]string{}
-	err = json.Unmarshal(in, &limitsMap)
+	limitsMap, err := validate.LimitToMap(limit)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +42,7 @@ func convertProjectResourceLimitToResourceList(limit *v32.ResourceQuotaLimit) (c
 	limits := corev1.ResourceList{}
 	for key, value := range limitsMap {
 		var resourceName corev1.ResourceName
-		if val, ok := resourceQuotaConversion[key]; ok {
+		if val, ok := validate.ResourceQuotaConversion[key]; ok {
 			resourceName = corev1.ResourceName(val)
 		} else {
 			resourceName = corev1.ResourceName(key)
@@ -70,85 +58,18 @@ func convertProjectResourceLimitToResourceList(limit *v32.ResourceQuotaLimit) (c
 	return limits, nil
 }
 
-func convertContainerResourceLimitToResourceList(limit *v32.ContainerResourceLimit) (corev1.ResourceList, corev1.ResourceList, error) {
-	in, err := json.Marshal(limit)
-	if err != nil {
-		return nil, nil, err
-	}
-	limitsMap := map[string]string{}
-	err = json.Unmarshal(in, &limitsMap)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if len(limitsMap) == 0 {
-		return nil, nil, nil
-	}
-
-	limits := corev1.ResourceList{}
-	requests := corev1.ResourceList{}
-	for key, value := range limitsMap {
-		var resourceName corev1.ResourceName
-		request := false
-		if val, ok := limitRangerRequestConversion[key]; ok {
-			resourceName = corev1.ResourceName(val)
-			request = true
-		} else if val, ok := limitRangerLimitConversion[key]; ok {
-			resourceName = corev1.ResourceName(val)
-		}
-		if resourceName == "" {
-			continue
-		}
-
-		resourceQuantity, err := resource.ParseQuantity(value)
-		if err != nil {
-			return nil, nil, err
-		}
-		if request {
-			requests[resourceName] = resourceQuantity
-		} else {
-			limits[resourceName] = resourceQuantity
-		}
-
-	}
-	return requests, limits, nil
-}
-
-var limitRangerRequestConversion = map[string]string{
-	"requestsCpu":    "cpu",
-	"requestsMemory": "memory",
-}
-
-var limitRangerLimitConversion = map[string]string{
-	"limitsCpu":    "cpu",
-	"limitsMemory": "memory",
-}
-
-var resourceQuotaConversion = map[string]string{
-	"replicationControllers": "replicationcontrollers",
-	"configMaps":             "configmaps",
-	"persistentVolumeClaims": "persistentvolumeclaims",
-	"servicesNodePorts":      "services.nodeports",
-	"servicesLoadBalancers":  "services.loadbalancers",
-	"requestsCpu":            "requests.cpu",
-	"requestsMemory":         "requests.memory",
-	"requestsStorage":        "requests.storage",
-	"limitsCpu":              "limits.cpu",
-	"limitsMemory":           "limits.memory",
-}
-
 func getNamespaceResourceQuota(ns *corev1.Namespace) string {
 	if ns.Annotations == nil {
 		return ""
 	}
-	return ns.Annotations[resourceQuotaAnnotation]
+	return ns.Annotations[ResourceQuotaAnnotation]
 }
 
 func getNamespaceContainerDefaultResourceLimit(ns *corev1.Namespace) string {
 	if ns.Annotations == nil {
 		return ""
 	}
-	return ns.Annotations[limitRangeAnnotation]
+	return ns.Annotations[LimitRangeAnnotation]
 }
 
 func getProjectResourceQuotaLimit(ns *corev1.Namespace, projectLister v3.ProjectLister) (*v32.ResourceQuotaLimit, string, error) {
@@ -251,22 +172,5 @@ func getProjectID(ns *corev1.Namespace) string {
 }
 
 func convertPodResourceLimitToLimitRangeSpec(podResourceLimit *v32.ContainerResourceLimit) (*corev1.LimitRangeSpec, error) {
-	request, limit, err := convertContainerResourceLimitToResourceList(podResourceLimit)
-	if err != nil {
-		return nil, err
-	}
-	if request == nil && limit == nil {
-		return nil, nil
-	}
-
-	item := corev1.LimitRangeItem{
-		Type:           corev1.LimitTypeContainer,
-		Default:        limit,
-		DefaultRequest: request,
-	}
-	limits := []corev1.LimitRangeItem{item}
-	limitRangeSpec := &corev1.LimitRangeSpec{
-		Limits: limits,
-	}
-	return limitRangeSpec, err
+	return validate.ConvertLimitToLimitRangeSpec(podResourceLimit)
 }
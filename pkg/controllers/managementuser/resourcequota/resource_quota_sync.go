@@ -19,14 +19,15 @@
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientcache "k8s.io/client-go/tools/cache"
+	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
 	"k8s.io/kubernetes/pkg/kubelet/util/format"
 )
 
 const (
 	projectIDAnnotation             = "field.cattle.io/projectId"
 	resourceQuotaLabel              = "resourcequota.management.cattle.io/default-resource-quota"
-	resourceQuotaAnnotation         = "field.cattle.io/resourceQuota"
-	limitRangeAnnotation            = "field.cattle.io/containerDefaultResourceLimit"
+	ResourceQuotaAnnotation         = "field.cattle.io/resourceQuota"
+	LimitRangeAnnotation            = "field.cattle.io/containerDefaultResourceLimit"
 	ResourceQuotaValidatedCondition = "ResourceQuotaValidated"
 	ResourceQuotaInitCondition      = "ResourceQuotaInit"
 )
@@ -101,12 +102,18 @@ func limitsChanged(existing []corev1.LimitRangeItem, toUpdate []corev1.LimitRang
 	if len(existing) == 0 || len(toUpdate) == 0 {
 		return true
 	}
-	if !apiequality.Semantic.DeepEqual(existing[0].DefaultRequest, toUpdate[0].DefaultRequest) {
-		return true
-	}
-
-	if !apiequality.Semantic.DeepEqual(existing[0].Default, toUpdate[0].Default) {
-		return true
+	for i := range toUpdate {
+		// the api server defaults the container items, so compare the existing items with defaulted ones
+		item := *toUpdate[i].DeepCopy()
+		corev1defaults.SetDefaults_LimitRangeItem(&item)
+		if existing[i].Type != item.Type ||
+			!apiequality.Semantic.DeepEqual(existing[i].DefaultRequest, item.DefaultRequest) ||
+			!apiequality.Semantic.DeepEqual(existing[i].Default, item.Default) ||
+			!apiequality.Semantic.DeepEqual(existing[i].Min, item.Min) ||
+			!apiequality.Semantic.DeepEqual(existing[i].Max, item.Max) ||
+			!apiequality.Semantic.DeepEqual(existing[i].MaxLimitRequestRatio, item.MaxLimitRequestRatio) {
+			return true
+		}
 	}
 	return false
 }
@@ -330,7 +337,7 @@ func (c *SyncController) validateAndSetNamespaceQuota(ns *corev1.Namespace, quot
 		if err != nil {
 			return false, ns, nil, err
 		}
-		updatedNs.Annotations[resourceQuotaAnnotation] = string(b)
+		updatedNs.Annotations[ResourceQuotaAnnotation] = string(b)
 		updatedNs, err = c.Namespaces.Update(updatedNs)
 		if err != nil {
 			return false, updatedNs, nil, err
@@ -429,11 +436,11 @@ func completeQuota(requestedQuota *v32.ResourceQuotaLimit, defaultQuota *v32.Res
 	if requestedQuota == nil || defaultQuota == nil {
 		return nil, nil
 	}
-	requestedQuotaMap, err := convert.EncodeToMap(requestedQuota)
+	requestedQuotaMap, err := validate.LimitToMap(requestedQuota)
 	if err != nil {
 		return nil, err
 	}
-	newLimitMap, err := convert.EncodeToMap(defaultQuota)
+	newLimitMap, err := validate.LimitToMap(defaultQuota)
 	if err != nil {
 		return nil, err
 	}
@@ -441,9 +448,7 @@ func completeQuota(requestedQuota *v32.ResourceQuotaLimit, defaultQuota *v32.Res
 		newLimitMap[key] = value
 	}
 
-	toReturn := &v32.ResourceQuotaLimit{}
-	err = convert.ToObj(newLimitMap, toReturn)
-	return toReturn, err
+	return validate.MapToLimit(newLimitMap)
 }
 
 func completeLimit(existingLimit *v32.ContainerResourceLimit, defaultLimit *v32.ContainerResourceLimit) (*v32.ContainerResourceLimit, error) {
@@ -476,7 +481,7 @@ func completeLimit(existingLimit *v32.ContainerResourceLimit, defaultLimit *v32.
 // zeroOutResourceQuotaLimit takes a resource quota limit and a list of resources exceeding the quota,
 // and returns a new quota limit with exceeded resources zeroed out.
 func zeroOutResourceQuotaLimit(limit *v32.ResourceQuotaLimit, exceeded corev1.ResourceList) (*v32.ResourceQuotaLimit, error) {
-	limitMap, err := convert.EncodeToMap(limit)
+	limitMap, err := validate.LimitToMap(limit)
 	if err != nil {
 		return nil, err
 	}
@@ -486,7 +491,5 @@ func zeroOutResourceQuotaLimit(limit *v32.ResourceQuotaLimit, exceeded corev1.Re
 		limitMap[resource] = "0"
 	}
 
-	toReturn := &v32.ResourceQuotaLimit{}
-	err = convert.ToObj(limitMap, toReturn)
-	return toReturn, err
+	return validate.MapToLimit(limitMap)
 }
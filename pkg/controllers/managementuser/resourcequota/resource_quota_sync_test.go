@@ -46,6 +46,52 @@ func TestLimitsChanged(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "limitsChange ignores the defaults set by the api server",
+			existing: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypeContainer,
+					Max: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+					Default: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+					DefaultRequest: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+				},
+			},
+			toUpdate: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypeContainer,
+					Max: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "limitsChange compares the bounds",
+			existing: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypePersistentVolumeClaim,
+					Max: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+			toUpdate: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypePersistentVolumeClaim,
+					Max: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("20Gi"),
+					},
+				},
+			},
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -107,3 +153,43 @@ func TestSemanticDeepEqual(t *testing.T) {
 	}
 
 }
+
+func TestExtendedResourceQuota(t *testing.T) {
+	defaultQuota := &v32.ResourceQuotaLimit{
+		Pods:     "10",
+		Extended: map[string]string{"requests.nvidia.com/gpu": "1", "count/deployments.apps": "5"},
+	}
+	requestedQuota := &v32.ResourceQuotaLimit{
+		Extended: map[string]string{"requests.nvidia.com/gpu": "2"},
+	}
+
+	completed, err := completeQuota(requestedQuota, defaultQuota)
+	assert.NoError(t, err)
+	assert.Equal(t, &v32.ResourceQuotaLimit{
+		Pods:     "10",
+		Extended: map[string]string{"requests.nvidia.com/gpu": "2", "count/deployments.apps": "5"},
+	}, completed)
+
+	spec, err := convertResourceLimitResourceQuotaSpec(completed)
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ResourceList{
+		corev1.ResourcePods:       resource.MustParse("10"),
+		"requests.nvidia.com/gpu": resource.MustParse("2"),
+		"count/deployments.apps":  resource.MustParse("5"),
+	}, spec.Hard)
+
+	zeroed, err := zeroOutResourceQuotaLimit(completed, corev1.ResourceList{"requests.nvidia.com/gpu": resource.MustParse("2")})
+	assert.NoError(t, err)
+	assert.Equal(t, "0", zeroed.Extended["requests.nvidia.com/gpu"])
+	assert.Equal(t, "5", zeroed.Extended["count/deployments.apps"])
+
+	used, err := convertResourceListToLimit(corev1.ResourceList{
+		"pods":                    resource.MustParse("3"),
+		"requests.nvidia.com/gpu": resource.MustParse("1"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &v32.ResourceQuotaLimit{
+		Pods:     "3",
+		Extended: map[string]string{"requests.nvidia.com/gpu": "1"},
+	}, used)
+}
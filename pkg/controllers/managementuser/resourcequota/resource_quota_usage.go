@@ -0,0 +1,476 @@
+package resourcequota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics/projectquota"
+	"github.com/rancher/rancher/pkg/notifiers"
+	"github.com/rancher/rancher/pkg/ref"
+	validate "github.com/rancher/rancher/pkg/resourcequota"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config/dialer"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	clientcache "k8s.io/client-go/tools/cache"
+)
+
+const (
+	quotaThresholdReason = "ResourceQuotaThreshold"
+	// usageUpdateInterval is the minimum interval between two updates of the usage snapshot of a project, unless the
+	// usage of a resource crosses an alert threshold.
+	usageUpdateInterval = time.Minute
+	// usageDeltaPercent is the change of the usage of a resource, in percent of its limit, from which the usage snapshot
+	// of a project is updated.
+	usageDeltaPercent = 5
+)
+
+var defaultResourceQuotaSelector = labels.Set{resourceQuotaLabel: "true"}.AsSelector()
+
+/*
+usageController takes snapshots of the quota of a project, of how much of it is allocated to its Namespaces and of how
+much of it their pods use, sets them in the project status and in the metrics, and raises an event when the usage of a
+resource crosses an alert threshold of the project
+*/
+type usageController struct {
+	ctx            context.Context
+	clusterName    string
+	projects       v3.ProjectInterface
+	enqueueAfter   func(namespace, name string, after time.Duration)
+	projectLister  v3.ProjectLister
+	nsIndexer      clientcache.Indexer
+	nsLister       v1.NamespaceLister
+	quotaLister    v1.ResourceQuotaLister
+	events         v1.EventInterface
+	notifierLister v3.NotifierLister
+	secretLister   v1.SecretLister
+	dialer         dialer.Factory
+	now            func() time.Time
+}
+
+type thresholdAlert struct {
+	resource  corev1.ResourceName
+	threshold int
+	used      string
+	limit     string
+}
+
+func (c *usageController) syncResourceQuota(key string, resourceQuota *corev1.ResourceQuota) (runtime.Object, error) {
+	if resourceQuota == nil || resourceQuota.Labels[resourceQuotaLabel] != "true" {
+		return nil, nil
+	}
+	ns, err := c.nsLister.Get("", resourceQuota.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	projectID := getProjectID(ns)
+	if projectID == "" {
+		return nil, nil
+	}
+	return nil, c.updateUsage(projectID)
+}
+
+func (c *usageController) syncProject(key string, p *v3.Project) (runtime.Object, error) {
+	if p == nil || p.DeletionTimestamp != nil {
+		_, name, err := clientcache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return nil, err
+		}
+		projectquota.DeleteUsage(c.clusterName, name)
+		return nil, nil
+	}
+	return nil, c.updateUsage(fmt.Sprintf("%s:%s", c.clusterName, p.Name))
+}
+
+func (c *usageController) updateUsage(projectID string) error {
+	projectNamespace, projectName := ref.Parse(projectID)
+	project, err := c.projectLister.Get(projectNamespace, projectName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// If Rancher is unaware of a project, we should ignore trying to report its usage
+			// A non-existent project is likely managed by another Rancher (e.g. Hosted Rancher)
+			return nil
+		}
+		return err
+	}
+
+	if project.Spec.ResourceQuota == nil {
+		projectquota.DeleteUsage(c.clusterName, project.Name)
+		if project.Status.ResourceQuotaUsage == nil {
+			return nil
+		}
+		toUpdate := project.DeepCopy()
+		toUpdate.Status.ResourceQuotaUsage = nil
+		_, err = c.projects.Update(toUpdate)
+		return err
+	}
+
+	usage, err := c.usage(project, projectID)
+	if err != nil {
+		return err
+	}
+	alerts, err := c.crossedThresholds(project, usage)
+	if err != nil {
+		return err
+	}
+	if err := c.setMetrics(project, usage); err != nil {
+		return err
+	}
+
+	if current := project.Status.ResourceQuotaUsage; current != nil {
+		changed, err := usageChanged(current, usage)
+		if err != nil || !changed {
+			return err
+		}
+		// the snapshots are rate limited, except when a threshold is crossed so that the alerts are raised right away
+		if apiequality.Semantic.DeepEqual(current.Thresholds, usage.Thresholds) {
+			if wait := c.untilNextUpdate(current); wait > 0 {
+				c.enqueueAfter(project.Namespace, project.Name, wait)
+				return nil
+			}
+		}
+	}
+	usage.UpdatedAt = c.now().UTC().Format(time.RFC3339)
+
+	toUpdate := project.DeepCopy()
+	toUpdate.Status.ResourceQuotaUsage = usage
+	updated, err := c.projects.Update(toUpdate)
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		c.alert(updated, alert)
+	}
+	return nil
+}
+
+// untilNextUpdate returns how long to wait before updating the usage snapshot of a project again.
+func (c *usageController) untilNextUpdate(current *v32.ProjectResourceQuotaUsage) time.Duration {
+	updatedAt, err := time.Parse(time.RFC3339, current.UpdatedAt)
+	if err != nil {
+		return 0
+	}
+	return updatedAt.Add(usageUpdateInterval).Sub(c.now())
+}
+
+// usageChanged returns whether a usage snapshot differs enough from the current one to be written: a limit, the
+// allocation, the namespaces or a crossed threshold changed, or the usage of a resource changed by usageDeltaPercent
+// of its limit.
+func usageChanged(current, usage *v32.ProjectResourceQuotaUsage) (bool, error) {
+	if !apiequality.Semantic.DeepEqual(current.Limit, usage.Limit) ||
+		!apiequality.Semantic.DeepEqual(current.Allocated, usage.Allocated) ||
+		!apiequality.Semantic.DeepEqual(current.Thresholds, usage.Thresholds) ||
+		len(current.Namespaces) != len(usage.Namespaces) {
+		return true, nil
+	}
+	if changed, err := usedChanged(current.Used, usage.Used, usage.Limit); err != nil || changed {
+		return changed, err
+	}
+	for i, nsUsage := range usage.Namespaces {
+		previous := current.Namespaces[i]
+		if previous.Namespace != nsUsage.Namespace || !apiequality.Semantic.DeepEqual(previous.Limit, nsUsage.Limit) {
+			return true, nil
+		}
+		if changed, err := usedChanged(previous.Used, nsUsage.Used, nsUsage.Limit); err != nil || changed {
+			return changed, err
+		}
+	}
+	return false, nil
+}
+
+// usedChanged returns whether the usage of a resource changed by usageDeltaPercent of its limit, or at all for the
+// resources without a limit.
+func usedChanged(previous, used, limit v32.ResourceQuotaLimit) (bool, error) {
+	previousList, err := convertProjectResourceLimitToResourceList(&previous)
+	if err != nil {
+		return false, err
+	}
+	usedList, err := convertProjectResourceLimitToResourceList(&used)
+	if err != nil {
+		return false, err
+	}
+	limitList, err := convertProjectResourceLimitToResourceList(&limit)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range quota.ResourceNames(quota.Add(previousList, usedList)) {
+		before, after := previousList[name], usedList[name]
+		if before.Cmp(after) == 0 {
+			continue
+		}
+		resourceLimit := limitList[name]
+		if resourceLimit.IsZero() {
+			return true, nil
+		}
+		delta := after.AsApproximateFloat64() - before.AsApproximateFloat64()
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta/resourceLimit.AsApproximateFloat64()*100 >= usageDeltaPercent {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// usage returns the quota of a project, how much of it is allocated to its namespaces and how much of it their pods
+// use according to their default ResourceQuota.
+func (c *usageController) usage(project *v3.Project, projectID string) (*v32.ProjectResourceQuotaUsage, error) {
+	usage := &v32.ProjectResourceQuotaUsage{
+		Limit:     *project.Spec.ResourceQuota.Limit.DeepCopy(),
+		Allocated: *project.Spec.ResourceQuota.UsedLimit.DeepCopy(),
+	}
+
+	namespaces, err := c.nsIndexer.ByIndex(nsByProjectIndex, projectID)
+	if err != nil {
+		return nil, err
+	}
+	usedResources := corev1.ResourceList{}
+	for _, n := range namespaces {
+		ns := n.(*corev1.Namespace)
+		if ns.DeletionTimestamp != nil {
+			continue
+		}
+		nsUsage := v32.NamespaceResourceQuotaUsage{Namespace: ns.Name}
+		nsLimit, err := getNamespaceResourceQuotaLimit(ns)
+		if err != nil {
+			return nil, err
+		}
+		if nsLimit != nil {
+			nsUsage.Limit = *nsLimit
+		}
+
+		resourceQuota, err := c.getDefaultResourceQuota(ns.Name)
+		if err != nil {
+			return nil, err
+		}
+		if resourceQuota != nil {
+			used, err := validate.ResourceListToLimit(resourceQuota.Status.Used)
+			if err != nil {
+				return nil, err
+			}
+			nsUsage.Used = *used
+			usedResources = quota.Add(usedResources, resourceQuota.Status.Used)
+		}
+		usage.Namespaces = append(usage.Namespaces, nsUsage)
+	}
+	sort.Slice(usage.Namespaces, func(i, j int) bool {
+		return usage.Namespaces[i].Namespace < usage.Namespaces[j].Namespace
+	})
+
+	used, err := validate.ResourceListToLimit(usedResources)
+	if err != nil {
+		return nil, err
+	}
+	usage.Used = *used
+	return usage, nil
+}
+
+func (c *usageController) getDefaultResourceQuota(namespace string) (*corev1.ResourceQuota, error) {
+	resourceQuotas, err := c.quotaLister.List(namespace, defaultResourceQuotaSelector)
+	if err != nil || len(resourceQuotas) == 0 {
+		return nil, err
+	}
+	return resourceQuotas[0], nil
+}
+
+// crossedThresholds sets the highest alert threshold crossed by the usage of each resource, and returns the alerts of
+// the resources whose usage crossed a higher threshold than in the last snapshot.
+func (c *usageController) crossedThresholds(project *v3.Project, usage *v32.ProjectResourceQuotaUsage) ([]thresholdAlert, error) {
+	thresholds := alertThresholds(project)
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+	limits, err := convertProjectResourceLimitToResourceList(&usage.Limit)
+	if err != nil {
+		return nil, err
+	}
+	used, err := convertProjectResourceLimitToResourceList(&usage.Used)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous map[string]int
+	if project.Status.ResourceQuotaUsage != nil {
+		previous = project.Status.ResourceQuotaUsage.Thresholds
+	}
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var alerts []thresholdAlert
+	for _, name := range names {
+		limit := limits[corev1.ResourceName(name)]
+		if limit.IsZero() {
+			continue
+		}
+		resourceUsed := used[corev1.ResourceName(name)]
+		percent := resourceUsed.AsApproximateFloat64() / limit.AsApproximateFloat64() * 100
+		crossed := 0
+		for _, threshold := range thresholds {
+			if percent >= float64(threshold) {
+				crossed = threshold
+			}
+		}
+		if crossed == 0 {
+			continue
+		}
+		if usage.Thresholds == nil {
+			usage.Thresholds = map[string]int{}
+		}
+		usage.Thresholds[name] = crossed
+		if crossed > previous[name] {
+			alerts = append(alerts, thresholdAlert{
+				resource:  corev1.ResourceName(name),
+				threshold: crossed,
+				used:      resourceUsed.String(),
+				limit:     limit.String(),
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// alertThresholds returns the sorted alert thresholds of a project, or the default ones when it sets none.
+func alertThresholds(project *v3.Project) []int {
+	var thresholds []int
+	if project.Spec.ResourceQuotaAlert != nil && len(project.Spec.ResourceQuotaAlert.Thresholds) > 0 {
+		thresholds = append(thresholds, project.Spec.ResourceQuotaAlert.Thresholds...)
+	} else {
+		for _, value := range strings.Split(settings.ResourceQuotaAlertThresholds.Get(), ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			threshold, err := strconv.Atoi(value)
+			if err != nil {
+				logrus.Errorf("Invalid resource quota alert threshold %q: %v", value, err)
+				continue
+			}
+			thresholds = append(thresholds, threshold)
+		}
+	}
+
+	valid := thresholds[:0]
+	for _, threshold := range thresholds {
+		if threshold > 0 && threshold <= 100 {
+			valid = append(valid, threshold)
+		}
+	}
+	sort.Ints(valid)
+	return valid
+}
+
+func (c *usageController) setMetrics(project *v3.Project, usage *v32.ProjectResourceQuotaUsage) error {
+	limit, err := convertProjectResourceLimitToResourceList(&usage.Limit)
+	if err != nil {
+		return err
+	}
+	allocated, err := convertProjectResourceLimitToResourceList(&usage.Allocated)
+	if err != nil {
+		return err
+	}
+	used, err := convertProjectResourceLimitToResourceList(&usage.Used)
+	if err != nil {
+		return err
+	}
+	usages := []projectquota.Usage{{Limit: limit, Allocated: allocated, Used: used}}
+
+	for _, nsUsage := range usage.Namespaces {
+		limit, err := convertProjectResourceLimitToResourceList(&nsUsage.Limit)
+		if err != nil {
+			return err
+		}
+		used, err := convertProjectResourceLimitToResourceList(&nsUsage.Used)
+		if err != nil {
+			return err
+		}
+		usages = append(usages, projectquota.Usage{Namespace: nsUsage.Namespace, Limit: limit, Used: used})
+	}
+
+	projectquota.SetUsage(c.clusterName, project.Name, usages)
+	return nil
+}
+
+// alert records an event on the project and sends it to the recipients of the project alerts. Failures are only
+// logged, since the crossed threshold is already recorded and the alert would not be raised again.
+func (c *usageController) alert(project *v3.Project, alert thresholdAlert) {
+	displayName := project.Spec.DisplayName
+	if displayName == "" {
+		displayName = project.Name
+	}
+	message := fmt.Sprintf("Usage of %s in project %s reached %d%% of its quota: %s used of %s",
+		alert.resource, displayName, alert.threshold, alert.used, alert.limit)
+
+	now := metav1.NewTime(c.now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: project.Name + "-",
+			Namespace:    project.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      "management.cattle.io/v3",
+			Kind:            "Project",
+			Namespace:       project.Namespace,
+			Name:            project.Name,
+			UID:             project.UID,
+			ResourceVersion: project.ResourceVersion,
+		},
+		Reason:         quotaThresholdReason,
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Source:         corev1.EventSource{Component: "resourcequota-usage"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := c.events.Create(event); err != nil {
+		logrus.Errorf("Failed to record resource quota alert for project %s: %v", project.Name, err)
+	}
+
+	if project.Spec.ResourceQuotaAlert == nil || len(project.Spec.ResourceQuotaAlert.Recipients) == 0 {
+		return
+	}
+	clusterDialer, err := c.dialer.ClusterDialer(c.clusterName)
+	if err != nil {
+		logrus.Errorf("Failed to get dialer to send resource quota alert for project %s: %v", project.Name, err)
+		return
+	}
+	msg := &notifiers.Message{
+		Title:   fmt.Sprintf("Notification From Rancher: project %s quota usage", displayName),
+		Content: message,
+	}
+	for _, recipient := range project.Spec.ResourceQuotaAlert.Recipients {
+		notifierNamespace, notifierName := ref.Parse(recipient.NotifierName)
+		if notifierNamespace == "" {
+			notifierNamespace = c.clusterName
+		}
+		notifier, err := c.notifierLister.Get(notifierNamespace, notifierName)
+		if err != nil {
+			logrus.Errorf("Failed to get notifier %s for resource quota alert of project %s: %v", recipient.NotifierName, project.Name, err)
+			continue
+		}
+		if err := notifiers.SendMessage(c.ctx, notifier, recipient.Recipient, msg, clusterDialer, &c.secretLister); err != nil {
+			logrus.Errorf("Failed to send resource quota alert of project %s to notifier %s: %v", project.Name, recipient.NotifierName, err)
+		}
+	}
+}
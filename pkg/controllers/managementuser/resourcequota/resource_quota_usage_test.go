@@ -0,0 +1,225 @@
+package resourcequota
+
+import (
+	"testing"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	corefakes "github.com/rancher/rancher/pkg/generated/norman/core/v1/fakes"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	fakes "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3/fakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newUsageController(t *testing.T, project *v3.Project, quotas ...*corev1.ResourceQuota) (*usageController, *[]*v3.Project, *[]*corev1.Event) {
+	nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{nsByProjectIndex: nsByProjectID})
+	for _, q := range quotas {
+		require.NoError(t, nsIndexer.Add(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: q.Namespace,
+				Annotations: map[string]string{
+					projectIDAnnotation:     "c-1:p-1",
+					ResourceQuotaAnnotation: `{"limit":{"pods":"10","extended":{"requests.nvidia.com/gpu":"2"}}}`,
+				},
+			},
+		}))
+	}
+
+	var updated []*v3.Project
+	var events []*corev1.Event
+	c := &usageController{
+		clusterName: "c-1",
+		projects: &fakes.ProjectInterfaceMock{
+			UpdateFunc: func(p *v3.Project) (*v3.Project, error) {
+				updated = append(updated, p)
+				project = p
+				return p, nil
+			},
+		},
+		projectLister: &fakes.ProjectListerMock{
+			GetFunc: func(namespace, name string) (*v3.Project, error) {
+				return project, nil
+			},
+		},
+		enqueueAfter: func(string, string, time.Duration) {},
+		nsIndexer:    nsIndexer,
+		quotaLister: &corefakes.ResourceQuotaListerMock{
+			ListFunc: func(namespace string, selector labels.Selector) ([]*corev1.ResourceQuota, error) {
+				var list []*corev1.ResourceQuota
+				for _, q := range quotas {
+					if q.Namespace == namespace && selector.Matches(labels.Set(q.Labels)) {
+						list = append(list, q)
+					}
+				}
+				return list, nil
+			},
+		},
+		events: &corefakes.EventInterfaceMock{
+			CreateFunc: func(e *corev1.Event) (*corev1.Event, error) {
+				events = append(events, e)
+				return e, nil
+			},
+		},
+		now: func() time.Time { return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	return c, &updated, &events
+}
+
+func newQuota(namespace string, pods, gpus string) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: namespace,
+			Labels:    map[string]string{resourceQuotaLabel: "true"},
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Used: corev1.ResourceList{
+				corev1.ResourcePods:       resource.MustParse(pods),
+				"requests.nvidia.com/gpu": resource.MustParse(gpus),
+			},
+		},
+	}
+}
+
+func newQuotaProject(alert *v32.ResourceQuotaAlert) *v3.Project {
+	return &v3.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "p-1", Namespace: "c-1"},
+		Spec: v32.ProjectSpec{
+			DisplayName: "test",
+			ResourceQuota: &v32.ProjectResourceQuota{
+				Limit: v32.ResourceQuotaLimit{
+					Pods:     "20",
+					Extended: map[string]string{"requests.nvidia.com/gpu": "4"},
+				},
+				UsedLimit: v32.ResourceQuotaLimit{
+					Pods:     "20",
+					Extended: map[string]string{"requests.nvidia.com/gpu": "4"},
+				},
+			},
+			ResourceQuotaAlert: alert,
+		},
+	}
+}
+
+func TestUpdateUsage(t *testing.T) {
+	project := newQuotaProject(nil)
+	c, updated, events := newUsageController(t, project, newQuota("ns-a", "3", "1"), newQuota("ns-b", "5", "2"))
+
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	require.Len(t, *updated, 1)
+	usage := (*updated)[0].Status.ResourceQuotaUsage
+	require.NotNil(t, usage)
+	assert.Equal(t, v32.ResourceQuotaLimit{
+		Pods:     "8",
+		Extended: map[string]string{"requests.nvidia.com/gpu": "3"},
+	}, usage.Used)
+	assert.Equal(t, project.Spec.ResourceQuota.UsedLimit, usage.Allocated)
+	require.Len(t, usage.Namespaces, 2)
+	assert.Equal(t, "ns-a", usage.Namespaces[0].Namespace)
+	assert.Equal(t, "10", usage.Namespaces[0].Limit.Pods)
+	assert.Equal(t, "3", usage.Namespaces[0].Used.Pods)
+	assert.Equal(t, "2022-01-01T00:00:00Z", usage.UpdatedAt)
+
+	// 3 of 4 GPUs is 75%, below the default thresholds.
+	assert.Empty(t, usage.Thresholds)
+	assert.Empty(t, *events)
+
+	// An unchanged usage is not updated again.
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Len(t, *updated, 1)
+}
+
+func TestUpdateUsageAlerts(t *testing.T) {
+	project := newQuotaProject(&v32.ResourceQuotaAlert{Thresholds: []int{50, 90}})
+	gpus := newQuota("ns-a", "3", "2")
+	c, updated, events := newUsageController(t, project, gpus)
+
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	require.Len(t, *updated, 1)
+	assert.Equal(t, map[string]int{"requests.nvidia.com/gpu": 50}, (*updated)[0].Status.ResourceQuotaUsage.Thresholds)
+	require.Len(t, *events, 1)
+	assert.Equal(t, quotaThresholdReason, (*events)[0].Reason)
+	assert.Equal(t, "Project", (*events)[0].InvolvedObject.Kind)
+	assert.Equal(t, "Usage of requests.nvidia.com/gpu in project test reached 50% of its quota: 2 used of 4", (*events)[0].Message)
+
+	// Crossing the same threshold again raises no new event, and the usage is updated once the interval elapsed.
+	gpus.Status.Used["requests.nvidia.com/gpu"] = resource.MustParse("3")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Len(t, *updated, 1)
+	now := c.now().Add(usageUpdateInterval)
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Len(t, *updated, 2)
+	assert.Len(t, *events, 1)
+
+	gpus.Status.Used["requests.nvidia.com/gpu"] = resource.MustParse("4")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Equal(t, map[string]int{"requests.nvidia.com/gpu": 90}, (*updated)[2].Status.ResourceQuotaUsage.Thresholds)
+	require.Len(t, *events, 2)
+
+	// Going back below the thresholds rearms the alerts.
+	gpus.Status.Used["requests.nvidia.com/gpu"] = resource.MustParse("1")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Empty(t, (*updated)[3].Status.ResourceQuotaUsage.Thresholds)
+	assert.Len(t, *events, 2)
+}
+
+func TestUpdateUsageRateLimit(t *testing.T) {
+	project := newQuotaProject(nil)
+	project.Spec.ResourceQuota.Limit.Pods = "100"
+	pods := newQuota("ns-a", "3", "0")
+	c, updated, events := newUsageController(t, project, pods)
+	obj, _, err := c.nsIndexer.GetByKey("ns-a")
+	require.NoError(t, err)
+	obj.(*corev1.Namespace).Annotations[ResourceQuotaAnnotation] = `{"limit":{"pods":"100","extended":{"requests.nvidia.com/gpu":"2"}}}`
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	var enqueued []time.Duration
+	c.enqueueAfter = func(_, _ string, after time.Duration) {
+		enqueued = append(enqueued, after)
+	}
+
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	require.Len(t, *updated, 1)
+
+	// A change below usageDeltaPercent of the limit is not written.
+	now = now.Add(2 * usageUpdateInterval)
+	pods.Status.Used[corev1.ResourcePods] = resource.MustParse("4")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Len(t, *updated, 1)
+	assert.Empty(t, enqueued)
+
+	// A meaningful change is written at most once per interval.
+	pods.Status.Used[corev1.ResourcePods] = resource.MustParse("13")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	require.Len(t, *updated, 2)
+	assert.Equal(t, "13", (*updated)[1].Status.ResourceQuotaUsage.Used.Pods)
+	now = now.Add(10 * time.Second)
+	pods.Status.Used[corev1.ResourcePods] = resource.MustParse("23")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	assert.Len(t, *updated, 2)
+	assert.Equal(t, []time.Duration{usageUpdateInterval - 10*time.Second}, enqueued)
+	now = now.Add(usageUpdateInterval)
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	require.Len(t, *updated, 3)
+	assert.Equal(t, "23", (*updated)[2].Status.ResourceQuotaUsage.Used.Pods)
+
+	// Crossing a threshold is written right away.
+	now = now.Add(time.Second)
+	pods.Status.Used["requests.nvidia.com/gpu"] = resource.MustParse("4")
+	require.NoError(t, c.updateUsage("c-1:p-1"))
+	require.Len(t, *updated, 4)
+	assert.Equal(t, map[string]int{"requests.nvidia.com/gpu": 95}, (*updated)[3].Status.ResourceQuotaUsage.Thresholds)
+	assert.Len(t, *events, 1)
+}
+
+func TestAlertThresholds(t *testing.T) {
+	assert.Equal(t, []int{80, 95}, alertThresholds(newQuotaProject(nil)))
+	assert.Equal(t, []int{10, 100}, alertThresholds(newQuotaProject(&v32.ResourceQuotaAlert{Thresholds: []int{100, 0, 10, 120}})))
+}
@@ -8,17 +8,19 @@
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/controllers/provisioningv2/rke2"
 	v1 "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/logging"
 	"github.com/rancher/rancher/pkg/provisioningv2/rke2/planner"
 	"github.com/rancher/rancher/pkg/wrangler"
 	"github.com/rancher/wrangler/pkg/generic"
 	"github.com/rancher/wrangler/pkg/relatedresource"
-	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+var logger = logging.Logger("pkg/controllers/provisioningv2/rke2/planner")
+
 type handler struct {
 	planner       *planner.Planner
 	controlPlanes v1.RKEControlPlaneController
@@ -53,7 +55,8 @@ func Register(ctx context.Context, clients *wrangler.Context, planner *planner.P
 }
 
 func (h *handler) OnChange(cp *rkev1.RKEControlPlane, status rkev1.RKEControlPlaneStatus) (rkev1.RKEControlPlaneStatus, error) {
-	logrus.Debugf("[planner] rkecluster %s/%s: handler OnChange called", cp.Namespace, cp.Name)
+	log := logger.WithFields(logging.ControllerFields(cp.Spec.ManagementClusterName, "planner"))
+	log.Debugf("[planner] rkecluster %s/%s: handler OnChange called", cp.Namespace, cp.Name)
 	if !cp.DeletionTimestamp.IsZero() {
 		return status, nil
 	}
@@ -66,11 +69,11 @@ func (h *handler) OnChange(cp *rkev1.RKEControlPlane, status rkev1.RKEControlPla
 		rke2.Reconciled.Reason(&status, "Waiting")
 	}
 
-	logrus.Debugf("[planner] rkecluster %s/%s: calling planner process", cp.Namespace, cp.Name)
+	log.Debugf("[planner] rkecluster %s/%s: calling planner process", cp.Namespace, cp.Name)
 	status, err := h.planner.Process(cp, status)
 	if err != nil {
 		if planner.IsErrWaiting(err) {
-			logrus.Infof("[planner] rkecluster %s/%s: waiting: %v", cp.Namespace, cp.Name, err)
+			log.Infof("[planner] rkecluster %s/%s: waiting: %v", cp.Namespace, cp.Name, err)
 			rke2.Reconciled.Message(&status, "reconciling cluster")
 			// if still waiting for same condition, convert err to generic.ErrSkip to avoid updating controlplane status and
 			// enqueue until no longer waiting.
@@ -89,7 +92,7 @@ func (h *handler) OnChange(cp *rkev1.RKEControlPlane, status rkev1.RKEControlPla
 				err = nil
 			}
 		} else if !errors.Is(err, generic.ErrSkip) {
-			logrus.Errorf("[planner] rkecluster %s/%s: error encountered during plan processing was %v", cp.Namespace, cp.Name, err)
+			log.Errorf("[planner] rkecluster %s/%s: error encountered during plan processing was %v", cp.Namespace, cp.Name, err)
 			rke2.Ready.SetError(&status, "", err)
 			rke2.Reconciled.SetError(&status, "", err)
 		}
@@ -97,7 +100,7 @@ func (h *handler) OnChange(cp *rkev1.RKEControlPlane, status rkev1.RKEControlPla
 			h.controlPlanes.EnqueueAfter(cp.Namespace, cp.Name, 5*time.Second)
 		}
 	} else {
-		logrus.Debugf("[planner] rkecluster %s/%s: reconciliation complete", cp.Namespace, cp.Name)
+		log.Debugf("[planner] rkecluster %s/%s: reconciliation complete", cp.Namespace, cp.Name)
 		rke2.Provisioned.True(&status)
 		rke2.Provisioned.Message(&status, "")
 		rke2.Provisioned.Reason(&status, "")
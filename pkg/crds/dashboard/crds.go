@@ -63,6 +63,19 @@ func List(cfg *rest.Config) (_ []crd.CRD, err error) {
 		newCRD(&v3.ClusterRegistrationToken{}, func(c crd.CRD) crd.CRD {
 			return c
 		}),
+		newCRD(&v3.RoleTemplateBindingRequest{}, func(c crd.CRD) crd.CRD {
+			c.Status = true
+			return c.
+				WithColumn("Subject", ".spec.userName").
+				WithColumn("Role", ".spec.roleTemplateName").
+				WithColumn("Phase", ".status.phase").
+				WithColumn("Expires", ".status.expiresAt")
+		}),
+		newCRD(&v3.RBACDriftReport{}, func(c crd.CRD) crd.CRD {
+			return c.
+				WithColumn("Checked", ".status.checkedAt").
+				WithColumn("Error", ".status.error")
+		}),
 		newCRD(&v3.Setting{}, func(c crd.CRD) crd.CRD {
 			c.NonNamespace = true
 			return c.
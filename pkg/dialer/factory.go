@@ -13,6 +13,7 @@
 	"github.com/rancher/norman/types/slice"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/tunnelserver"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -33,16 +34,18 @@
 
 func NewFactory(apiContext *config.ScaledContext, wrangler *wrangler.Context) (*Factory, error) {
 	return &Factory{
-		clusterLister: apiContext.Management.Clusters("").Controller().Lister(),
-		nodeLister:    apiContext.Management.Nodes("").Controller().Lister(),
-		TunnelServer:  wrangler.TunnelServer,
+		clusterLister:  apiContext.Management.Clusters("").Controller().Lister(),
+		nodeLister:     apiContext.Management.Nodes("").Controller().Lister(),
+		TunnelServer:   wrangler.TunnelServer,
+		TunnelSessions: wrangler.TunnelSessions,
 	}, nil
 }
 
 type Factory struct {
-	nodeLister    v3.NodeLister
-	clusterLister v3.ClusterLister
-	TunnelServer  *remotedialer.Server
+	nodeLister     v3.NodeLister
+	clusterLister  v3.ClusterLister
+	TunnelServer   *remotedialer.Server
+	TunnelSessions *tunnelserver.Sessions
 }
 
 func (f *Factory) ClusterDialer(clusterName string) (dialer.Dialer, error) {
@@ -193,7 +196,7 @@ func (f *Factory) clusterDialer(clusterName, address string) (dialer.Dialer, err
 
 	if f.TunnelServer.HasSession(cluster.Name) {
 		logrus.Tracef("dialerFactory: tunnel session found for cluster [%s]", cluster.Name)
-		cd := f.TunnelServer.Dialer(cluster.Name)
+		cd := f.TunnelSessions.Dialer(cluster.Name)
 		return func(ctx context.Context, network, address string) (net.Conn, error) {
 			if cluster.Status.Driver == v32.ClusterDriverRKE {
 				address = f.translateClusterAddress(cluster, hostPort, address)
@@ -239,7 +242,7 @@ func (f *Factory) clusterDialer(clusterName, address string) (dialer.Dialer, err
 	for i := 0; i < 4; i++ {
 		if f.TunnelServer.HasSession(cluster.Name) {
 			logrus.Debugf("Cluster [%s] has reconnected, resuming", cluster.Name)
-			cd := f.TunnelServer.Dialer(cluster.Name)
+			cd := f.TunnelSessions.Dialer(cluster.Name)
 			return func(ctx context.Context, network, address string) (net.Conn, error) {
 				if cluster.Status.Driver == v32.ClusterDriverRKE {
 					address = f.translateClusterAddress(cluster, hostPort, address)
@@ -286,7 +289,7 @@ func (f *Factory) DockerDialer(clusterName, machineName string) (dialer.Dialer,
 		if machine.Status.InternalNodeStatus.NodeInfo.OperatingSystem == "windows" {
 			network, address = "npipe", "//./pipe/docker_engine"
 		}
-		d := f.TunnelServer.Dialer(sessionKey)
+		d := f.TunnelSessions.Dialer(sessionKey)
 		return func(ctx context.Context, _ string, _ string) (net.Conn, error) {
 			return d(ctx, network, address)
 		}, nil
@@ -313,7 +316,7 @@ func (f *Factory) nodeDialer(clusterName, machineName string) (dialer.Dialer, er
 
 	sessionKey := machineSessionKey(machine)
 	if f.TunnelServer.HasSession(sessionKey) {
-		d := f.TunnelServer.Dialer(sessionKey)
+		d := f.TunnelSessions.Dialer(sessionKey)
 		return dialer.Dialer(d), nil
 	}
 
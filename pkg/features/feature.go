@@ -2,6 +2,8 @@
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,6 +14,14 @@
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// UpdatedByAnnotation is set on a Feature by whoever changes its value, so that the change can be recorded in the
+	// history of the feature.
+	UpdatedByAnnotation = "features.cattle.io/updated-by"
+	// SystemUser is the user recorded for the changes made by Rancher itself.
+	SystemUser = "system"
+)
+
 var (
 	features = make(map[string]*Feature)
 
@@ -52,7 +62,7 @@
 		"Gitops components in fleet",
 		true,
 		false,
-		true)
+		true).requires(Fleet)
 	Auth = newFeature(
 		"auth",
 		"Enable authentication",
@@ -70,7 +80,7 @@
 		"Enable provisioning of RKE2",
 		true,
 		false,
-		true)
+		true).requires(ProvisioningV2)
 	Legacy = newFeature(
 		"legacy",
 		"Enable legacy features",
@@ -121,6 +131,8 @@ type Feature struct {
 	dynamic bool
 	// Whether we should install this feature or assume something else will install and manage the Feature CR
 	install bool
+	// dependencies are the names of the features that must be enabled for this feature to be enabled
+	dependencies []string
 }
 
 // InitializeFeatures updates feature default if given valid --features flag and creates/updates necessary features in k8s
@@ -152,9 +164,10 @@ func InitializeFeatures(featuresClient managementv3.FeatureClient, featureArgs s
 						Value: nil,
 					},
 					Status: v3.FeatureStatus{
-						Default:     f.def,
-						Dynamic:     f.dynamic,
-						Description: f.description,
+						Default:      f.def,
+						Dynamic:      f.dynamic,
+						Description:  f.description,
+						Dependencies: f.dependencies,
 					},
 				}
 
@@ -179,6 +192,11 @@ func InitializeFeatures(featuresClient managementv3.FeatureClient, featureArgs s
 				newFeatureState.Status.Description = f.description
 			}
 
+			// checks if developer has changed dependencies from previous rancher version
+			if !reflect.DeepEqual(featureState.Status.Dependencies, f.dependencies) {
+				newFeatureState.Status.Dependencies = f.dependencies
+			}
+
 			newFeatureState, err = featuresClient.Update(newFeatureState)
 			if err != nil {
 				logrus.Errorf("unable to update feature %s in initialize features: %v", f.name, err)
@@ -203,6 +221,8 @@ func InitializeFeatures(featuresClient managementv3.FeatureClient, featureArgs s
 	}
 }
 
+// SetFeature sets the value of a feature, provided that its dependencies are enabled when enabling it and that no
+// enabled feature depends on it when disabling it.
 func SetFeature(featuresClient managementv3.FeatureClient, featureName string, value bool) error {
 	if featuresClient == nil {
 		return nil
@@ -213,7 +233,22 @@ func SetFeature(featuresClient managementv3.FeatureClient, featureName string, v
 		return err
 	}
 
+	err = ValidateDependencies(featureName, value, func(name string) (bool, error) {
+		dependency, err := featuresClient.Get(name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return enabledInMemory(name), nil
+		}
+		return IsEnabled(dependency), err
+	})
+	if err != nil {
+		return err
+	}
+
 	featureState.Spec.Value = &[]bool{value}[0]
+	if featureState.Annotations == nil {
+		featureState.Annotations = map[string]string{}
+	}
+	featureState.Annotations[UpdatedByAnnotation] = SystemUser
 	if _, err = featuresClient.Update(featureState); err != nil {
 		return err
 	}
@@ -221,6 +256,72 @@ func SetFeature(featuresClient managementv3.FeatureClient, featureName string, v
 	return nil
 }
 
+// ValidateDependencies returns an error if setting the feature to value would break a dependency: when enabling it, all
+// its dependencies must be enabled and when disabling it, none of the features depending on it may be enabled. The
+// enabled func returns the effective value of the other features.
+func ValidateDependencies(featureName string, value bool, enabled func(name string) (bool, error)) error {
+	if value {
+		var missing []string
+		for _, dependency := range Dependencies(featureName) {
+			ok, err := enabled(dependency)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				missing = append(missing, dependency)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("feature %s requires features [%s] to be enabled", featureName, strings.Join(missing, ", "))
+		}
+		return nil
+	}
+
+	var enabledDependents []string
+	for _, dependent := range Dependents(featureName) {
+		ok, err := enabled(dependent)
+		if err != nil {
+			return err
+		}
+		if ok {
+			enabledDependents = append(enabledDependents, dependent)
+		}
+	}
+	if len(enabledDependents) > 0 {
+		return fmt.Errorf("feature %s is required by enabled features [%s]", featureName, strings.Join(enabledDependents, ", "))
+	}
+	return nil
+}
+
+// Dependencies returns the names of the features that must be enabled for the named feature to be enabled.
+func Dependencies(featureName string) []string {
+	if f := features[featureName]; f != nil {
+		return f.dependencies
+	}
+	return nil
+}
+
+// Dependents returns the names of the features that depend on the named feature, sorted.
+func Dependents(featureName string) []string {
+	var dependents []string
+	for name, f := range features {
+		for _, dependency := range f.dependencies {
+			if dependency == featureName {
+				dependents = append(dependents, name)
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+func enabledInMemory(featureName string) bool {
+	if f := features[featureName]; f != nil {
+		return f.Enabled()
+	}
+	return false
+}
+
 // applyArgumentDefaults reads the features arguments and uses their values to overwrite
 // the corresponding feature default value
 func applyArgumentDefaults(featureArgs string) error {
@@ -304,6 +405,14 @@ func IsEnabled(feature *v3.Feature) bool {
 	return *feature.Spec.Value
 }
 
+// requires declares the features that must be enabled for the feature to be enabled.
+func (f *Feature) requires(dependencies ...*Feature) *Feature {
+	for _, dependency := range dependencies {
+		f.dependencies = append(f.dependencies, dependency.name)
+	}
+	return f
+}
+
 // newFeature adds feature to the global feature map
 func newFeature(name, description string, def, dynamic, install bool) *Feature {
 	feature := &Feature{
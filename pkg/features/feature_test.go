@@ -37,3 +37,20 @@ func TestInitializeNil(t *testing.T) {
 	InitializeFeatures(nil, "isfalse=true")
 	assert.True(IsDefFalse.Enabled())
 }
+
+func TestValidateDependencies(t *testing.T) {
+	enabled := map[string]bool{}
+	lookup := func(name string) (bool, error) {
+		return enabled[name], nil
+	}
+
+	assert.EqualError(t, ValidateDependencies(RKE2.Name(), true, lookup), "feature rke2 requires features [provisioningv2] to be enabled")
+	assert.NoError(t, ValidateDependencies(ProvisioningV2.Name(), false, lookup))
+
+	enabled[ProvisioningV2.Name()] = true
+	assert.NoError(t, ValidateDependencies(RKE2.Name(), true, lookup))
+
+	enabled[RKE2.Name()] = true
+	assert.EqualError(t, ValidateDependencies(ProvisioningV2.Name(), false, lookup), "feature provisioningv2 is required by enabled features [rke2]")
+	assert.NoError(t, ValidateDependencies(RKE2.Name(), false, lookup))
+}
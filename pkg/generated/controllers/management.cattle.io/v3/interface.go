@@ -94,11 +94,13 @@ type Interface interface {
 	ProjectMonitorGraph() ProjectMonitorGraphController
 	ProjectNetworkPolicy() ProjectNetworkPolicyController
 	ProjectRoleTemplateBinding() ProjectRoleTemplateBindingController
+	RBACDriftReport() RBACDriftReportController
 	RancherUserNotification() RancherUserNotificationController
 	RkeAddon() RkeAddonController
 	RkeK8sServiceOption() RkeK8sServiceOptionController
 	RkeK8sSystemImage() RkeK8sSystemImageController
 	RoleTemplate() RoleTemplateController
+	RoleTemplateBindingRequest() RoleTemplateBindingRequestController
 	SamlProvider() SamlProviderController
 	SamlToken() SamlTokenController
 	Setting() SettingController
@@ -312,6 +314,9 @@ func (c *version) ProjectNetworkPolicy() ProjectNetworkPolicyController {
 func (c *version) ProjectRoleTemplateBinding() ProjectRoleTemplateBindingController {
 	return NewProjectRoleTemplateBindingController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ProjectRoleTemplateBinding"}, "projectroletemplatebindings", true, c.controllerFactory)
 }
+func (c *version) RBACDriftReport() RBACDriftReportController {
+	return NewRBACDriftReportController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RBACDriftReport"}, "rbacdriftreports", true, c.controllerFactory)
+}
 func (c *version) RancherUserNotification() RancherUserNotificationController {
 	return NewRancherUserNotificationController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RancherUserNotification"}, "rancherusernotifications", false, c.controllerFactory)
 }
@@ -327,6 +332,9 @@ func (c *version) RkeK8sSystemImage() RkeK8sSystemImageController {
 func (c *version) RoleTemplate() RoleTemplateController {
 	return NewRoleTemplateController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RoleTemplate"}, "roletemplates", false, c.controllerFactory)
 }
+func (c *version) RoleTemplateBindingRequest() RoleTemplateBindingRequestController {
+	return NewRoleTemplateBindingRequestController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "RoleTemplateBindingRequest"}, "roletemplatebindingrequests", true, c.controllerFactory)
+}
 func (c *version) SamlProvider() SamlProviderController {
 	return NewSamlProviderController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "SamlProvider"}, "samlproviders", false, c.controllerFactory)
 }
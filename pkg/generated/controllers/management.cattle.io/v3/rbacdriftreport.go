@@ -0,0 +1,376 @@
+/*
+Copyright 2023 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by main. DO NOT EDIT.
+
+package v3
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/lasso/pkg/client"
+	"github.com/rancher/lasso/pkg/controller"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/pkg/apply"
+	"github.com/rancher/wrangler/pkg/condition"
+	"github.com/rancher/wrangler/pkg/generic"
+	"github.com/rancher/wrangler/pkg/kv"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+type RBACDriftReportHandler func(string, *v3.RBACDriftReport) (*v3.RBACDriftReport, error)
+
+type RBACDriftReportController interface {
+	generic.ControllerMeta
+	RBACDriftReportClient
+
+	OnChange(ctx context.Context, name string, sync RBACDriftReportHandler)
+	OnRemove(ctx context.Context, name string, sync RBACDriftReportHandler)
+	Enqueue(namespace, name string)
+	EnqueueAfter(namespace, name string, duration time.Duration)
+
+	Cache() RBACDriftReportCache
+}
+
+type RBACDriftReportClient interface {
+	Create(*v3.RBACDriftReport) (*v3.RBACDriftReport, error)
+	Update(*v3.RBACDriftReport) (*v3.RBACDriftReport, error)
+	UpdateStatus(*v3.RBACDriftReport) (*v3.RBACDriftReport, error)
+	Delete(namespace, name string, options *metav1.DeleteOptions) error
+	Get(namespace, name string, options metav1.GetOptions) (*v3.RBACDriftReport, error)
+	List(namespace string, opts metav1.ListOptions) (*v3.RBACDriftReportList, error)
+	Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (result *v3.RBACDriftReport, err error)
+}
+
+type RBACDriftReportCache interface {
+	Get(namespace, name string) (*v3.RBACDriftReport, error)
+	List(namespace string, selector labels.Selector) ([]*v3.RBACDriftReport, error)
+
+	AddIndexer(indexName string, indexer RBACDriftReportIndexer)
+	GetByIndex(indexName, key string) ([]*v3.RBACDriftReport, error)
+}
+
+type RBACDriftReportIndexer func(obj *v3.RBACDriftReport) ([]string, error)
+
+type rBACDriftReportController struct {
+	controller    controller.SharedController
+	client        *client.Client
+	gvk           schema.GroupVersionKind
+	groupResource schema.GroupResource
+}
+
+func NewRBACDriftReportController(gvk schema.GroupVersionKind, resource string, namespaced bool, controller controller.SharedControllerFactory) RBACDriftReportController {
+	c := controller.ForResourceKind(gvk.GroupVersion().WithResource(resource), gvk.Kind, namespaced)
+	return &rBACDriftReportController{
+		controller: c,
+		client:     c.Client(),
+		gvk:        gvk,
+		groupResource: schema.GroupResource{
+			Group:    gvk.Group,
+			Resource: resource,
+		},
+	}
+}
+
+func FromRBACDriftReportHandlerToHandler(sync RBACDriftReportHandler) generic.Handler {
+	return func(key string, obj runtime.Object) (ret runtime.Object, err error) {
+		var v *v3.RBACDriftReport
+		if obj == nil {
+			v, err = sync(key, nil)
+		} else {
+			v, err = sync(key, obj.(*v3.RBACDriftReport))
+		}
+		if v == nil {
+			return nil, err
+		}
+		return v, err
+	}
+}
+
+func (c *rBACDriftReportController) Updater() generic.Updater {
+	return func(obj runtime.Object) (runtime.Object, error) {
+		newObj, err := c.Update(obj.(*v3.RBACDriftReport))
+		if newObj == nil {
+			return nil, err
+		}
+		return newObj, err
+	}
+}
+
+func UpdateRBACDriftReportDeepCopyOnChange(client RBACDriftReportClient, obj *v3.RBACDriftReport, handler func(obj *v3.RBACDriftReport) (*v3.RBACDriftReport, error)) (*v3.RBACDriftReport, error) {
+	if obj == nil {
+		return obj, nil
+	}
+
+	copyObj := obj.DeepCopy()
+	newObj, err := handler(copyObj)
+	if newObj != nil {
+		copyObj = newObj
+	}
+	if obj.ResourceVersion == copyObj.ResourceVersion && !equality.Semantic.DeepEqual(obj, copyObj) {
+		return client.Update(copyObj)
+	}
+
+	return copyObj, err
+}
+
+func (c *rBACDriftReportController) AddGenericHandler(ctx context.Context, name string, handler generic.Handler) {
+	c.controller.RegisterHandler(ctx, name, controller.SharedControllerHandlerFunc(handler))
+}
+
+func (c *rBACDriftReportController) AddGenericRemoveHandler(ctx context.Context, name string, handler generic.Handler) {
+	c.AddGenericHandler(ctx, name, generic.NewRemoveHandler(name, c.Updater(), handler))
+}
+
+func (c *rBACDriftReportController) OnChange(ctx context.Context, name string, sync RBACDriftReportHandler) {
+	c.AddGenericHandler(ctx, name, FromRBACDriftReportHandlerToHandler(sync))
+}
+
+func (c *rBACDriftReportController) OnRemove(ctx context.Context, name string, sync RBACDriftReportHandler) {
+	c.AddGenericHandler(ctx, name, generic.NewRemoveHandler(name, c.Updater(), FromRBACDriftReportHandlerToHandler(sync)))
+}
+
+func (c *rBACDriftReportController) Enqueue(namespace, name string) {
+	c.controller.Enqueue(namespace, name)
+}
+
+func (c *rBACDriftReportController) EnqueueAfter(namespace, name string, duration time.Duration) {
+	c.controller.EnqueueAfter(namespace, name, duration)
+}
+
+func (c *rBACDriftReportController) Informer() cache.SharedIndexInformer {
+	return c.controller.Informer()
+}
+
+func (c *rBACDriftReportController) GroupVersionKind() schema.GroupVersionKind {
+	return c.gvk
+}
+
+func (c *rBACDriftReportController) Cache() RBACDriftReportCache {
+	return &rBACDriftReportCache{
+		indexer:  c.Informer().GetIndexer(),
+		resource: c.groupResource,
+	}
+}
+
+func (c *rBACDriftReportController) Create(obj *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+	result := &v3.RBACDriftReport{}
+	return result, c.client.Create(context.TODO(), obj.Namespace, obj, result, metav1.CreateOptions{})
+}
+
+func (c *rBACDriftReportController) Update(obj *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+	result := &v3.RBACDriftReport{}
+	return result, c.client.Update(context.TODO(), obj.Namespace, obj, result, metav1.UpdateOptions{})
+}
+
+func (c *rBACDriftReportController) UpdateStatus(obj *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+	result := &v3.RBACDriftReport{}
+	return result, c.client.UpdateStatus(context.TODO(), obj.Namespace, obj, result, metav1.UpdateOptions{})
+}
+
+func (c *rBACDriftReportController) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	if options == nil {
+		options = &metav1.DeleteOptions{}
+	}
+	return c.client.Delete(context.TODO(), namespace, name, *options)
+}
+
+func (c *rBACDriftReportController) Get(namespace, name string, options metav1.GetOptions) (*v3.RBACDriftReport, error) {
+	result := &v3.RBACDriftReport{}
+	return result, c.client.Get(context.TODO(), namespace, name, result, options)
+}
+
+func (c *rBACDriftReportController) List(namespace string, opts metav1.ListOptions) (*v3.RBACDriftReportList, error) {
+	result := &v3.RBACDriftReportList{}
+	return result, c.client.List(context.TODO(), namespace, result, opts)
+}
+
+func (c *rBACDriftReportController) Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.client.Watch(context.TODO(), namespace, opts)
+}
+
+func (c *rBACDriftReportController) Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (*v3.RBACDriftReport, error) {
+	result := &v3.RBACDriftReport{}
+	return result, c.client.Patch(context.TODO(), namespace, name, pt, data, result, metav1.PatchOptions{}, subresources...)
+}
+
+type rBACDriftReportCache struct {
+	indexer  cache.Indexer
+	resource schema.GroupResource
+}
+
+func (c *rBACDriftReportCache) Get(namespace, name string) (*v3.RBACDriftReport, error) {
+	obj, exists, err := c.indexer.GetByKey(namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(c.resource, name)
+	}
+	return obj.(*v3.RBACDriftReport), nil
+}
+
+func (c *rBACDriftReportCache) List(namespace string, selector labels.Selector) (ret []*v3.RBACDriftReport, err error) {
+
+	err = cache.ListAllByNamespace(c.indexer, namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v3.RBACDriftReport))
+	})
+
+	return ret, err
+}
+
+func (c *rBACDriftReportCache) AddIndexer(indexName string, indexer RBACDriftReportIndexer) {
+	utilruntime.Must(c.indexer.AddIndexers(map[string]cache.IndexFunc{
+		indexName: func(obj interface{}) (strings []string, e error) {
+			return indexer(obj.(*v3.RBACDriftReport))
+		},
+	}))
+}
+
+func (c *rBACDriftReportCache) GetByIndex(indexName, key string) (result []*v3.RBACDriftReport, err error) {
+	objs, err := c.indexer.ByIndex(indexName, key)
+	if err != nil {
+		return nil, err
+	}
+	result = make([]*v3.RBACDriftReport, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.(*v3.RBACDriftReport))
+	}
+	return result, nil
+}
+
+type RBACDriftReportStatusHandler func(obj *v3.RBACDriftReport, status v3.RBACDriftReportStatus) (v3.RBACDriftReportStatus, error)
+
+type RBACDriftReportGeneratingHandler func(obj *v3.RBACDriftReport, status v3.RBACDriftReportStatus) ([]runtime.Object, v3.RBACDriftReportStatus, error)
+
+func RegisterRBACDriftReportStatusHandler(ctx context.Context, controller RBACDriftReportController, condition condition.Cond, name string, handler RBACDriftReportStatusHandler) {
+	statusHandler := &rBACDriftReportStatusHandler{
+		client:    controller,
+		condition: condition,
+		handler:   handler,
+	}
+	controller.AddGenericHandler(ctx, name, FromRBACDriftReportHandlerToHandler(statusHandler.sync))
+}
+
+func RegisterRBACDriftReportGeneratingHandler(ctx context.Context, controller RBACDriftReportController, apply apply.Apply,
+	condition condition.Cond, name string, handler RBACDriftReportGeneratingHandler, opts *generic.GeneratingHandlerOptions) {
+	statusHandler := &rBACDriftReportGeneratingHandler{
+		RBACDriftReportGeneratingHandler: handler,
+		apply:                            apply,
+		name:                             name,
+		gvk:                              controller.GroupVersionKind(),
+	}
+	if opts != nil {
+		statusHandler.opts = *opts
+	}
+	controller.OnChange(ctx, name, statusHandler.Remove)
+	RegisterRBACDriftReportStatusHandler(ctx, controller, condition, name, statusHandler.Handle)
+}
+
+type rBACDriftReportStatusHandler struct {
+	client    RBACDriftReportClient
+	condition condition.Cond
+	handler   RBACDriftReportStatusHandler
+}
+
+func (a *rBACDriftReportStatusHandler) sync(key string, obj *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+	if obj == nil {
+		return obj, nil
+	}
+
+	origStatus := obj.Status.DeepCopy()
+	obj = obj.DeepCopy()
+	newStatus, err := a.handler(obj, obj.Status)
+	if err != nil {
+		// Revert to old status on error
+		newStatus = *origStatus.DeepCopy()
+	}
+
+	if a.condition != "" {
+		if errors.IsConflict(err) {
+			a.condition.SetError(&newStatus, "", nil)
+		} else {
+			a.condition.SetError(&newStatus, "", err)
+		}
+	}
+	if !equality.Semantic.DeepEqual(origStatus, &newStatus) {
+		if a.condition != "" {
+			// Since status has changed, update the lastUpdatedTime
+			a.condition.LastUpdated(&newStatus, time.Now().UTC().Format(time.RFC3339))
+		}
+
+		var newErr error
+		obj.Status = newStatus
+		newObj, newErr := a.client.UpdateStatus(obj)
+		if err == nil {
+			err = newErr
+		}
+		if newErr == nil {
+			obj = newObj
+		}
+	}
+	return obj, err
+}
+
+type rBACDriftReportGeneratingHandler struct {
+	RBACDriftReportGeneratingHandler
+	apply apply.Apply
+	opts  generic.GeneratingHandlerOptions
+	gvk   schema.GroupVersionKind
+	name  string
+}
+
+func (a *rBACDriftReportGeneratingHandler) Remove(key string, obj *v3.RBACDriftReport) (*v3.RBACDriftReport, error) {
+	if obj != nil {
+		return obj, nil
+	}
+
+	obj = &v3.RBACDriftReport{}
+	obj.Namespace, obj.Name = kv.RSplit(key, "/")
+	obj.SetGroupVersionKind(a.gvk)
+
+	return nil, generic.ConfigureApplyForObject(a.apply, obj, &a.opts).
+		WithOwner(obj).
+		WithSetID(a.name).
+		ApplyObjects()
+}
+
+func (a *rBACDriftReportGeneratingHandler) Handle(obj *v3.RBACDriftReport, status v3.RBACDriftReportStatus) (v3.RBACDriftReportStatus, error) {
+	if !obj.DeletionTimestamp.IsZero() {
+		return status, nil
+	}
+
+	objs, newStatus, err := a.RBACDriftReportGeneratingHandler(obj, status)
+	if err != nil {
+		return newStatus, err
+	}
+
+	return newStatus, generic.ConfigureApplyForObject(a.apply, obj, &a.opts).
+		WithOwner(obj).
+		WithSetID(a.name).
+		ApplyObjects(objs...)
+}
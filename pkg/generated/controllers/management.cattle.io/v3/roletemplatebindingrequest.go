@@ -0,0 +1,376 @@
+/*
+Copyright 2023 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by main. DO NOT EDIT.
+
+package v3
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/lasso/pkg/client"
+	"github.com/rancher/lasso/pkg/controller"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/pkg/apply"
+	"github.com/rancher/wrangler/pkg/condition"
+	"github.com/rancher/wrangler/pkg/generic"
+	"github.com/rancher/wrangler/pkg/kv"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+type RoleTemplateBindingRequestHandler func(string, *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error)
+
+type RoleTemplateBindingRequestController interface {
+	generic.ControllerMeta
+	RoleTemplateBindingRequestClient
+
+	OnChange(ctx context.Context, name string, sync RoleTemplateBindingRequestHandler)
+	OnRemove(ctx context.Context, name string, sync RoleTemplateBindingRequestHandler)
+	Enqueue(namespace, name string)
+	EnqueueAfter(namespace, name string, duration time.Duration)
+
+	Cache() RoleTemplateBindingRequestCache
+}
+
+type RoleTemplateBindingRequestClient interface {
+	Create(*v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error)
+	Update(*v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error)
+	UpdateStatus(*v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error)
+	Delete(namespace, name string, options *metav1.DeleteOptions) error
+	Get(namespace, name string, options metav1.GetOptions) (*v3.RoleTemplateBindingRequest, error)
+	List(namespace string, opts metav1.ListOptions) (*v3.RoleTemplateBindingRequestList, error)
+	Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (result *v3.RoleTemplateBindingRequest, err error)
+}
+
+type RoleTemplateBindingRequestCache interface {
+	Get(namespace, name string) (*v3.RoleTemplateBindingRequest, error)
+	List(namespace string, selector labels.Selector) ([]*v3.RoleTemplateBindingRequest, error)
+
+	AddIndexer(indexName string, indexer RoleTemplateBindingRequestIndexer)
+	GetByIndex(indexName, key string) ([]*v3.RoleTemplateBindingRequest, error)
+}
+
+type RoleTemplateBindingRequestIndexer func(obj *v3.RoleTemplateBindingRequest) ([]string, error)
+
+type roleTemplateBindingRequestController struct {
+	controller    controller.SharedController
+	client        *client.Client
+	gvk           schema.GroupVersionKind
+	groupResource schema.GroupResource
+}
+
+func NewRoleTemplateBindingRequestController(gvk schema.GroupVersionKind, resource string, namespaced bool, controller controller.SharedControllerFactory) RoleTemplateBindingRequestController {
+	c := controller.ForResourceKind(gvk.GroupVersion().WithResource(resource), gvk.Kind, namespaced)
+	return &roleTemplateBindingRequestController{
+		controller: c,
+		client:     c.Client(),
+		gvk:        gvk,
+		groupResource: schema.GroupResource{
+			Group:    gvk.Group,
+			Resource: resource,
+		},
+	}
+}
+
+func FromRoleTemplateBindingRequestHandlerToHandler(sync RoleTemplateBindingRequestHandler) generic.Handler {
+	return func(key string, obj runtime.Object) (ret runtime.Object, err error) {
+		var v *v3.RoleTemplateBindingRequest
+		if obj == nil {
+			v, err = sync(key, nil)
+		} else {
+			v, err = sync(key, obj.(*v3.RoleTemplateBindingRequest))
+		}
+		if v == nil {
+			return nil, err
+		}
+		return v, err
+	}
+}
+
+func (c *roleTemplateBindingRequestController) Updater() generic.Updater {
+	return func(obj runtime.Object) (runtime.Object, error) {
+		newObj, err := c.Update(obj.(*v3.RoleTemplateBindingRequest))
+		if newObj == nil {
+			return nil, err
+		}
+		return newObj, err
+	}
+}
+
+func UpdateRoleTemplateBindingRequestDeepCopyOnChange(client RoleTemplateBindingRequestClient, obj *v3.RoleTemplateBindingRequest, handler func(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error)) (*v3.RoleTemplateBindingRequest, error) {
+	if obj == nil {
+		return obj, nil
+	}
+
+	copyObj := obj.DeepCopy()
+	newObj, err := handler(copyObj)
+	if newObj != nil {
+		copyObj = newObj
+	}
+	if obj.ResourceVersion == copyObj.ResourceVersion && !equality.Semantic.DeepEqual(obj, copyObj) {
+		return client.Update(copyObj)
+	}
+
+	return copyObj, err
+}
+
+func (c *roleTemplateBindingRequestController) AddGenericHandler(ctx context.Context, name string, handler generic.Handler) {
+	c.controller.RegisterHandler(ctx, name, controller.SharedControllerHandlerFunc(handler))
+}
+
+func (c *roleTemplateBindingRequestController) AddGenericRemoveHandler(ctx context.Context, name string, handler generic.Handler) {
+	c.AddGenericHandler(ctx, name, generic.NewRemoveHandler(name, c.Updater(), handler))
+}
+
+func (c *roleTemplateBindingRequestController) OnChange(ctx context.Context, name string, sync RoleTemplateBindingRequestHandler) {
+	c.AddGenericHandler(ctx, name, FromRoleTemplateBindingRequestHandlerToHandler(sync))
+}
+
+func (c *roleTemplateBindingRequestController) OnRemove(ctx context.Context, name string, sync RoleTemplateBindingRequestHandler) {
+	c.AddGenericHandler(ctx, name, generic.NewRemoveHandler(name, c.Updater(), FromRoleTemplateBindingRequestHandlerToHandler(sync)))
+}
+
+func (c *roleTemplateBindingRequestController) Enqueue(namespace, name string) {
+	c.controller.Enqueue(namespace, name)
+}
+
+func (c *roleTemplateBindingRequestController) EnqueueAfter(namespace, name string, duration time.Duration) {
+	c.controller.EnqueueAfter(namespace, name, duration)
+}
+
+func (c *roleTemplateBindingRequestController) Informer() cache.SharedIndexInformer {
+	return c.controller.Informer()
+}
+
+func (c *roleTemplateBindingRequestController) GroupVersionKind() schema.GroupVersionKind {
+	return c.gvk
+}
+
+func (c *roleTemplateBindingRequestController) Cache() RoleTemplateBindingRequestCache {
+	return &roleTemplateBindingRequestCache{
+		indexer:  c.Informer().GetIndexer(),
+		resource: c.groupResource,
+	}
+}
+
+func (c *roleTemplateBindingRequestController) Create(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	result := &v3.RoleTemplateBindingRequest{}
+	return result, c.client.Create(context.TODO(), obj.Namespace, obj, result, metav1.CreateOptions{})
+}
+
+func (c *roleTemplateBindingRequestController) Update(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	result := &v3.RoleTemplateBindingRequest{}
+	return result, c.client.Update(context.TODO(), obj.Namespace, obj, result, metav1.UpdateOptions{})
+}
+
+func (c *roleTemplateBindingRequestController) UpdateStatus(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	result := &v3.RoleTemplateBindingRequest{}
+	return result, c.client.UpdateStatus(context.TODO(), obj.Namespace, obj, result, metav1.UpdateOptions{})
+}
+
+func (c *roleTemplateBindingRequestController) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	if options == nil {
+		options = &metav1.DeleteOptions{}
+	}
+	return c.client.Delete(context.TODO(), namespace, name, *options)
+}
+
+func (c *roleTemplateBindingRequestController) Get(namespace, name string, options metav1.GetOptions) (*v3.RoleTemplateBindingRequest, error) {
+	result := &v3.RoleTemplateBindingRequest{}
+	return result, c.client.Get(context.TODO(), namespace, name, result, options)
+}
+
+func (c *roleTemplateBindingRequestController) List(namespace string, opts metav1.ListOptions) (*v3.RoleTemplateBindingRequestList, error) {
+	result := &v3.RoleTemplateBindingRequestList{}
+	return result, c.client.List(context.TODO(), namespace, result, opts)
+}
+
+func (c *roleTemplateBindingRequestController) Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.client.Watch(context.TODO(), namespace, opts)
+}
+
+func (c *roleTemplateBindingRequestController) Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (*v3.RoleTemplateBindingRequest, error) {
+	result := &v3.RoleTemplateBindingRequest{}
+	return result, c.client.Patch(context.TODO(), namespace, name, pt, data, result, metav1.PatchOptions{}, subresources...)
+}
+
+type roleTemplateBindingRequestCache struct {
+	indexer  cache.Indexer
+	resource schema.GroupResource
+}
+
+func (c *roleTemplateBindingRequestCache) Get(namespace, name string) (*v3.RoleTemplateBindingRequest, error) {
+	obj, exists, err := c.indexer.GetByKey(namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(c.resource, name)
+	}
+	return obj.(*v3.RoleTemplateBindingRequest), nil
+}
+
+func (c *roleTemplateBindingRequestCache) List(namespace string, selector labels.Selector) (ret []*v3.RoleTemplateBindingRequest, err error) {
+
+	err = cache.ListAllByNamespace(c.indexer, namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v3.RoleTemplateBindingRequest))
+	})
+
+	return ret, err
+}
+
+func (c *roleTemplateBindingRequestCache) AddIndexer(indexName string, indexer RoleTemplateBindingRequestIndexer) {
+	utilruntime.Must(c.indexer.AddIndexers(map[string]cache.IndexFunc{
+		indexName: func(obj interface{}) (strings []string, e error) {
+			return indexer(obj.(*v3.RoleTemplateBindingRequest))
+		},
+	}))
+}
+
+func (c *roleTemplateBindingRequestCache) GetByIndex(indexName, key string) (result []*v3.RoleTemplateBindingRequest, err error) {
+	objs, err := c.indexer.ByIndex(indexName, key)
+	if err != nil {
+		return nil, err
+	}
+	result = make([]*v3.RoleTemplateBindingRequest, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.(*v3.RoleTemplateBindingRequest))
+	}
+	return result, nil
+}
+
+type RoleTemplateBindingRequestStatusHandler func(obj *v3.RoleTemplateBindingRequest, status v3.RoleTemplateBindingRequestStatus) (v3.RoleTemplateBindingRequestStatus, error)
+
+type RoleTemplateBindingRequestGeneratingHandler func(obj *v3.RoleTemplateBindingRequest, status v3.RoleTemplateBindingRequestStatus) ([]runtime.Object, v3.RoleTemplateBindingRequestStatus, error)
+
+func RegisterRoleTemplateBindingRequestStatusHandler(ctx context.Context, controller RoleTemplateBindingRequestController, condition condition.Cond, name string, handler RoleTemplateBindingRequestStatusHandler) {
+	statusHandler := &roleTemplateBindingRequestStatusHandler{
+		client:    controller,
+		condition: condition,
+		handler:   handler,
+	}
+	controller.AddGenericHandler(ctx, name, FromRoleTemplateBindingRequestHandlerToHandler(statusHandler.sync))
+}
+
+func RegisterRoleTemplateBindingRequestGeneratingHandler(ctx context.Context, controller RoleTemplateBindingRequestController, apply apply.Apply,
+	condition condition.Cond, name string, handler RoleTemplateBindingRequestGeneratingHandler, opts *generic.GeneratingHandlerOptions) {
+	statusHandler := &roleTemplateBindingRequestGeneratingHandler{
+		RoleTemplateBindingRequestGeneratingHandler: handler,
+		apply: apply,
+		name:  name,
+		gvk:   controller.GroupVersionKind(),
+	}
+	if opts != nil {
+		statusHandler.opts = *opts
+	}
+	controller.OnChange(ctx, name, statusHandler.Remove)
+	RegisterRoleTemplateBindingRequestStatusHandler(ctx, controller, condition, name, statusHandler.Handle)
+}
+
+type roleTemplateBindingRequestStatusHandler struct {
+	client    RoleTemplateBindingRequestClient
+	condition condition.Cond
+	handler   RoleTemplateBindingRequestStatusHandler
+}
+
+func (a *roleTemplateBindingRequestStatusHandler) sync(key string, obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	if obj == nil {
+		return obj, nil
+	}
+
+	origStatus := obj.Status.DeepCopy()
+	obj = obj.DeepCopy()
+	newStatus, err := a.handler(obj, obj.Status)
+	if err != nil {
+		// Revert to old status on error
+		newStatus = *origStatus.DeepCopy()
+	}
+
+	if a.condition != "" {
+		if errors.IsConflict(err) {
+			a.condition.SetError(&newStatus, "", nil)
+		} else {
+			a.condition.SetError(&newStatus, "", err)
+		}
+	}
+	if !equality.Semantic.DeepEqual(origStatus, &newStatus) {
+		if a.condition != "" {
+			// Since status has changed, update the lastUpdatedTime
+			a.condition.LastUpdated(&newStatus, time.Now().UTC().Format(time.RFC3339))
+		}
+
+		var newErr error
+		obj.Status = newStatus
+		newObj, newErr := a.client.UpdateStatus(obj)
+		if err == nil {
+			err = newErr
+		}
+		if newErr == nil {
+			obj = newObj
+		}
+	}
+	return obj, err
+}
+
+type roleTemplateBindingRequestGeneratingHandler struct {
+	RoleTemplateBindingRequestGeneratingHandler
+	apply apply.Apply
+	opts  generic.GeneratingHandlerOptions
+	gvk   schema.GroupVersionKind
+	name  string
+}
+
+func (a *roleTemplateBindingRequestGeneratingHandler) Remove(key string, obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	if obj != nil {
+		return obj, nil
+	}
+
+	obj = &v3.RoleTemplateBindingRequest{}
+	obj.Namespace, obj.Name = kv.RSplit(key, "/")
+	obj.SetGroupVersionKind(a.gvk)
+
+	return nil, generic.ConfigureApplyForObject(a.apply, obj, &a.opts).
+		WithOwner(obj).
+		WithSetID(a.name).
+		ApplyObjects()
+}
+
+func (a *roleTemplateBindingRequestGeneratingHandler) Handle(obj *v3.RoleTemplateBindingRequest, status v3.RoleTemplateBindingRequestStatus) (v3.RoleTemplateBindingRequestStatus, error) {
+	if !obj.DeletionTimestamp.IsZero() {
+		return status, nil
+	}
+
+	objs, newStatus, err := a.RoleTemplateBindingRequestGeneratingHandler(obj, status)
+	if err != nil {
+		return newStatus, err
+	}
+
+	return newStatus, generic.ConfigureApplyForObject(a.apply, obj, &a.opts).
+		WithOwner(obj).
+		WithSetID(a.name).
+		ApplyObjects(objs...)
+}
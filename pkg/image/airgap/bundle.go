@@ -0,0 +1,317 @@
+package airgap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Platform selects the image to take out of a multi-architecture manifest list.
+type Platform struct {
+	OS           string
+	Architecture string
+	Variant      string
+}
+
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// ParsePlatform parses a platform in the os/arch[/variant] form, e.g. linux/amd64 or linux/arm/v7.
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform [%s], expected os/arch[/variant]", s)
+	}
+	p := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+func (p Platform) matches(other *ocispec.Platform) bool {
+	if other == nil {
+		return false
+	}
+	return other.OS == p.OS && other.Architecture == p.Architecture && (p.Variant == "" || other.Variant == p.Variant)
+}
+
+// manifest holds the fields shared by docker v2 schema 2 and OCI image manifests and indexes.
+type manifest struct {
+	MediaType string               `json:"mediaType,omitempty"`
+	Config    ocispec.Descriptor   `json:"config"`
+	Layers    []ocispec.Descriptor `json:"layers"`
+	Manifests []ocispec.Descriptor `json:"manifests"`
+}
+
+func isIndex(mediaType string) bool {
+	return mediaType == MediaTypeOCIIndex || mediaType == MediaTypeDockerManifestList
+}
+
+// Bundler copies images between registries and an OCI layout.
+type Bundler struct {
+	Client   *Client
+	Layout   *Layout
+	Platform Platform
+}
+
+// Save pulls every image into the layout. Images and blobs that are already complete are skipped and partial
+// downloads are resumed, so Save can be rerun after an interruption. All images are attempted; the returned error
+// lists the ones that failed.
+func (b *Bundler) Save(ctx context.Context, images []string) error {
+	var failed []string
+	for _, image := range images {
+		if err := b.saveImage(ctx, image); err != nil {
+			logrus.Errorf("failed to save image %s: %v", image, err)
+			failed = append(failed, image)
+			continue
+		}
+		logrus.Infof("saved image %s", image)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to save %d of %d images: %s", len(failed), len(images), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (b *Bundler) saveImage(ctx context.Context, image string) error {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	data, mediaType, err := b.Client.GetManifest(ctx, ref, ref.Version())
+	if err != nil {
+		return err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+
+	if isIndex(mediaType) {
+		var index manifest
+		if err := json.Unmarshal(data, &index); err != nil {
+			return errors.Wrap(err, "decoding manifest list")
+		}
+		if ref.Digest != "" {
+			// narrowing the list down to a platform would change its digest, so images pinned to the digest of a
+			// manifest list are saved whole to keep the digest resolving
+			return b.saveIndex(ctx, ref, desc, data, index)
+		}
+		var selected *ocispec.Descriptor
+		for i := range index.Manifests {
+			if b.Platform.matches(index.Manifests[i].Platform) {
+				selected = &index.Manifests[i]
+				break
+			}
+		}
+		if selected == nil {
+			return fmt.Errorf("no manifest for platform %s", b.Platform)
+		}
+		if data, mediaType, err = b.Client.GetManifest(ctx, ref, selected.Digest.String()); err != nil {
+			return err
+		}
+		desc = *selected
+		desc.MediaType = mediaType
+	}
+
+	if err := b.saveManifest(ctx, ref, desc, data); err != nil {
+		return err
+	}
+	return b.addImage(ref, desc)
+}
+
+// saveIndex saves a manifest list along with the images of every platform it lists.
+func (b *Bundler) saveIndex(ctx context.Context, ref Reference, desc ocispec.Descriptor, data []byte, index manifest) error {
+	for _, child := range index.Manifests {
+		childData, mediaType, err := b.Client.GetManifest(ctx, ref, child.Digest.String())
+		if err != nil {
+			return err
+		}
+		child.MediaType = mediaType
+		if err := b.saveManifest(ctx, ref, child, childData); err != nil {
+			return err
+		}
+	}
+	if digest.FromBytes(data) != desc.Digest {
+		return fmt.Errorf("manifest content does not match digest %s", desc.Digest)
+	}
+	if _, err := b.Layout.WriteBlob(data); err != nil {
+		return err
+	}
+	return b.addImage(ref, desc)
+}
+
+// saveManifest saves the config and layers of an image manifest, then the manifest itself.
+func (b *Bundler) saveManifest(ctx context.Context, ref Reference, desc ocispec.Descriptor, data []byte) error {
+	if digest.FromBytes(data) != desc.Digest {
+		return fmt.Errorf("manifest content does not match digest %s", desc.Digest)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return errors.Wrap(err, "decoding manifest")
+	}
+	for _, blob := range append([]ocispec.Descriptor{m.Config}, m.Layers...) {
+		if err := b.saveBlob(ctx, ref, blob); err != nil {
+			return err
+		}
+	}
+	_, err := b.Layout.WriteBlob(data)
+	return err
+}
+
+func (b *Bundler) addImage(ref Reference, desc ocispec.Descriptor) error {
+	desc.Annotations = map[string]string{AnnotationImageName: ref.Name}
+	if ref.Tag != "" {
+		desc.Annotations[ocispec.AnnotationRefName] = ref.Tag
+	}
+	return b.Layout.AddImage(ref.Name, desc)
+}
+
+func (b *Bundler) saveBlob(ctx context.Context, ref Reference, blob ocispec.Descriptor) error {
+	if b.Layout.HasBlob(blob.Digest) {
+		return nil
+	}
+
+	f, offset, err := b.Layout.PartialBlob(blob.Digest, true)
+	if err != nil {
+		return err
+	}
+	if offset > blob.Size {
+		f.Close()
+		if f, offset, err = b.Layout.PartialBlob(blob.Digest, false); err != nil {
+			return err
+		}
+	}
+
+	if offset < blob.Size {
+		body, ranged, err := b.Client.GetBlob(ctx, ref, blob.Digest.String(), offset)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if offset > 0 && !ranged {
+			logrus.Debugf("registry %s does not support resuming downloads, restarting blob %s", ref.Host, blob.Digest)
+			f.Close()
+			if f, _, err = b.Layout.PartialBlob(blob.Digest, false); err != nil {
+				body.Close()
+				return err
+			}
+		}
+		_, err = io.Copy(f, body)
+		body.Close()
+		if err != nil {
+			f.Close()
+			return errors.Wrapf(err, "downloading blob %s", blob.Digest)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return b.Layout.CommitBlob(blob.Digest)
+}
+
+// Push uploads every image in the layout to registry, keeping their repository paths and tags. Blobs that already
+// exist in the target registry are not uploaded again.
+func (b *Bundler) Push(ctx context.Context, registry string) error {
+	index, err := b.Layout.ReadIndex()
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, desc := range index.Manifests {
+		name := desc.Annotations[AnnotationImageName]
+		if name == "" {
+			logrus.Warnf("skipping manifest %s without image name", desc.Digest)
+			continue
+		}
+		if err := b.pushImage(ctx, registry, name, desc); err != nil {
+			logrus.Errorf("failed to push image %s: %v", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		logrus.Infof("pushed image %s to %s", name, registry)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to push %d of %d images: %s", len(failed), len(index.Manifests), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (b *Bundler) pushImage(ctx context.Context, registry, name string, desc ocispec.Descriptor) error {
+	source, err := ParseReference(name)
+	if err != nil {
+		return err
+	}
+	target, err := source.WithRegistry(registry)
+	if err != nil {
+		return err
+	}
+
+	// images pulled by tag may have been narrowed down to a single platform, so images without a tag are pushed under
+	// the digest of what is actually stored
+	version := target.Tag
+	if version == "" {
+		version = desc.Digest.String()
+	}
+	return b.pushManifest(ctx, target, version, desc)
+}
+
+// pushManifest uploads a manifest under version along with its blobs, or with the manifests it lists in the case of a
+// manifest list.
+func (b *Bundler) pushManifest(ctx context.Context, target Reference, version string, desc ocispec.Descriptor) error {
+	data, err := b.Layout.ReadBlob(desc.Digest)
+	if err != nil {
+		return err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return errors.Wrap(err, "decoding manifest")
+	}
+
+	if isIndex(desc.MediaType) {
+		for _, child := range m.Manifests {
+			if err := b.pushManifest(ctx, target, child.Digest.String(), child); err != nil {
+				return err
+			}
+		}
+		return b.Client.PushManifest(ctx, target, version, desc.MediaType, data)
+	}
+
+	for _, blob := range append([]ocispec.Descriptor{m.Config}, m.Layers...) {
+		exists, err := b.Client.BlobExists(ctx, target, blob.Digest.String())
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		f, err := os.Open(b.Layout.BlobPath(blob.Digest))
+		if err != nil {
+			return err
+		}
+		err = b.Client.PushBlob(ctx, target, blob.Digest.String(), blob.Size, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return b.Client.PushManifest(ctx, target, version, desc.MediaType, data)
+}
@@ -0,0 +1,272 @@
+package airgap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is an in-memory registry implementing the subset of the distribution API used by the Bundler.
+type fakeRegistry struct {
+	sync.Mutex
+	manifests  map[string][]byte // repository:version -> content
+	mediaTypes map[string]string // repository:version -> media type
+	blobs      map[string][]byte // digest -> content
+	blobGets   map[string]int
+	token      string
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		manifests:  map[string][]byte{},
+		mediaTypes: map[string]string{},
+		blobs:      map[string][]byte{},
+		blobGets:   map[string]int{},
+	}
+}
+
+func (f *fakeRegistry) addBlob(content []byte) ocispec.Descriptor {
+	d := digest.FromBytes(content)
+	f.blobs[d.String()] = content
+	return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: d, Size: int64(len(content))}
+}
+
+func (f *fakeRegistry) addManifest(repository, tag, mediaType string, obj interface{}) ocispec.Descriptor {
+	data, _ := json.Marshal(obj)
+	d := digest.FromBytes(data)
+	for _, version := range []string{tag, d.String()} {
+		f.manifests[repository+":"+version] = data
+		f.mediaTypes[repository+":"+version] = mediaType
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(data))}
+}
+
+func (f *fakeRegistry) addImage(repository, tag string, layers ...[]byte) ocispec.Descriptor {
+	m := manifest{
+		MediaType: MediaTypeOCIManifest,
+		Config:    f.addBlob([]byte(repository + ":" + tag)),
+	}
+	for _, layer := range layers {
+		m.Layers = append(m.Layers, f.addBlob(layer))
+	}
+	return f.addManifest(repository, tag, MediaTypeOCIManifest, m)
+}
+
+func (f *fakeRegistry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	if req.URL.Path == "/token" {
+		json.NewEncoder(rw).Encode(map[string]string{"token": f.token})
+		return
+	}
+	if f.token != "" && req.Header.Get("Authorization") != "Bearer "+f.token {
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="fake"`, req.Host))
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(path, "/manifests/"):
+		parts := strings.SplitN(path, "/manifests/", 2)
+		key := parts[0] + ":" + parts[1]
+		if req.Method == http.MethodPut {
+			data, _ := ioutil.ReadAll(req.Body)
+			f.manifests[key] = data
+			f.mediaTypes[key] = req.Header.Get("Content-Type")
+			rw.WriteHeader(http.StatusCreated)
+			return
+		}
+		data, ok := f.manifests[key]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Header().Set("Content-Type", f.mediaTypes[key])
+		rw.Write(data)
+	case strings.Contains(path, "/blobs/uploads/"):
+		if req.Method == http.MethodPost {
+			rw.Header().Set("Location", "/upload/"+strings.SplitN(path, "/blobs/", 2)[0])
+			rw.WriteHeader(http.StatusAccepted)
+			return
+		}
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+	case strings.Contains(path, "/blobs/"):
+		d := strings.SplitN(path, "/blobs/", 2)[1]
+		data, ok := f.blobs[d]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Method == http.MethodHead {
+			return
+		}
+		f.blobGets[d]++
+		var offset int
+		if r := req.Header.Get("Range"); r != "" {
+			fmt.Sscanf(r, "bytes=%d-", &offset)
+			rw.WriteHeader(http.StatusPartialContent)
+		}
+		rw.Write(data[offset:])
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeRegistry) serveUpload(rw http.ResponseWriter, req *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	data, _ := ioutil.ReadAll(req.Body)
+	d := req.URL.Query().Get("digest")
+	if digest.FromBytes(data).String() != d {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.blobs[d] = data
+	rw.WriteHeader(http.StatusCreated)
+}
+
+func startFakeRegistry(t *testing.T, f *fakeRegistry) string {
+	mux := http.NewServeMux()
+	mux.Handle("/", f)
+	mux.HandleFunc("/upload/", f.serveUpload)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	u, _ := url.Parse(server.URL)
+	return u.Host
+}
+
+func TestSaveAndPush(t *testing.T) {
+	source := newFakeRegistry()
+	source.token = "pull-token"
+	sourceHost := startFakeRegistry(t, source)
+
+	sharedLayer := []byte("shared base layer")
+	agent := source.addImage("rancher/agent", "v1", sharedLayer, []byte("agent layer"))
+	arm := source.addImage("rancher/agent", "v1-arm64", sharedLayer, []byte("arm layer"))
+	agent.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	source.addManifest("rancher/agent", "multi", MediaTypeOCIIndex, manifest{
+		MediaType: MediaTypeOCIIndex,
+		Manifests: []ocispec.Descriptor{arm, agent},
+	})
+	source.addImage("rancher/shell", "v2", sharedLayer)
+
+	layout, err := OpenLayout(t.TempDir())
+	require.NoError(t, err)
+
+	// simulate an interrupted download of the agent layer
+	agentLayer := digest.FromBytes([]byte("agent layer"))
+	require.NoError(t, ioutil.WriteFile(layout.BlobPath(agentLayer)+partialSuffix, []byte("agent"), 0644))
+
+	client := NewClient(nil)
+	client.PlainHTTP[sourceHost] = true
+	bundler := &Bundler{Client: client, Layout: layout, Platform: Platform{OS: "linux", Architecture: "amd64"}}
+	images := []string{sourceHost + "/rancher/agent:multi", sourceHost + "/rancher/shell:v2"}
+	require.NoError(t, bundler.Save(context.Background(), images))
+
+	// rerunning does not download anything again
+	require.NoError(t, bundler.Save(context.Background(), images))
+	for d, count := range source.blobGets {
+		assert.Equal(t, 1, count, "blob %s downloaded %d times", d, count)
+	}
+	assert.False(t, layout.HasBlob(digest.FromBytes([]byte("arm layer"))), "only the requested platform is saved")
+
+	index, err := layout.ReadIndex()
+	require.NoError(t, err)
+	require.Len(t, index.Manifests, 2)
+	assert.Equal(t, agent.Digest, index.Manifests[0].Digest)
+	assert.Equal(t, sourceHost+"/rancher/agent:multi", index.Manifests[0].Annotations[AnnotationImageName])
+	assert.Equal(t, "multi", index.Manifests[0].Annotations[ocispec.AnnotationRefName])
+
+	var archive bytes.Buffer
+	require.NoError(t, layout.Archive(&archive))
+	extracted, err := ExtractLayout(&archive, t.TempDir())
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(extracted.Root, ocispec.ImageLayoutFile))
+	require.NoError(t, err)
+
+	target := newFakeRegistry()
+	targetHost := startFakeRegistry(t, target)
+	client.PlainHTTP[targetHost] = true
+	pusher := &Bundler{Client: client, Layout: extracted}
+	require.NoError(t, pusher.Push(context.Background(), targetHost))
+
+	assert.Contains(t, target.manifests, "rancher/agent:multi")
+	assert.Contains(t, target.manifests, "rancher/shell:v2")
+	assert.Equal(t, MediaTypeOCIManifest, target.mediaTypes["rancher/agent:multi"])
+	assert.Contains(t, target.blobs, digest.FromBytes(sharedLayer).String())
+	assert.Contains(t, target.blobs, agentLayer.String())
+}
+
+func TestSaveMissingPlatform(t *testing.T) {
+	source := newFakeRegistry()
+	sourceHost := startFakeRegistry(t, source)
+	arm := source.addImage("rancher/agent", "v1-arm64", []byte("arm layer"))
+	arm.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	source.addManifest("rancher/agent", "v1", MediaTypeDockerManifestList, manifest{
+		MediaType: MediaTypeDockerManifestList,
+		Manifests: []ocispec.Descriptor{arm},
+	})
+
+	layout, err := OpenLayout(t.TempDir())
+	require.NoError(t, err)
+	client := NewClient(nil)
+	client.PlainHTTP[sourceHost] = true
+	bundler := &Bundler{Client: client, Layout: layout, Platform: Platform{OS: "windows", Architecture: "amd64"}}
+
+	err = bundler.Save(context.Background(), []string{sourceHost + "/rancher/agent:v1"})
+	assert.ErrorContains(t, err, "failed to save 1 of 1 images")
+}
+
+func TestSaveAndPushByIndexDigest(t *testing.T) {
+	source := newFakeRegistry()
+	sourceHost := startFakeRegistry(t, source)
+	amd := source.addImage("rancher/agent", "v1-amd64", []byte("amd layer"))
+	arm := source.addImage("rancher/agent", "v1-arm64", []byte("arm layer"))
+	amd.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	list := source.addManifest("rancher/agent", "v1", MediaTypeOCIIndex, manifest{
+		MediaType: MediaTypeOCIIndex,
+		Manifests: []ocispec.Descriptor{amd, arm},
+	})
+
+	layout, err := OpenLayout(t.TempDir())
+	require.NoError(t, err)
+	client := NewClient(nil)
+	client.PlainHTTP[sourceHost] = true
+	bundler := &Bundler{Client: client, Layout: layout, Platform: Platform{OS: "linux", Architecture: "amd64"}}
+	require.NoError(t, bundler.Save(context.Background(), []string{sourceHost + "/rancher/agent@" + list.Digest.String()}))
+
+	// the manifest list is kept whole so that its digest still resolves
+	index, err := layout.ReadIndex()
+	require.NoError(t, err)
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, list.Digest, index.Manifests[0].Digest)
+	assert.Equal(t, MediaTypeOCIIndex, index.Manifests[0].MediaType)
+	assert.True(t, layout.HasBlob(digest.FromBytes([]byte("arm layer"))))
+
+	target := newFakeRegistry()
+	targetHost := startFakeRegistry(t, target)
+	client.PlainHTTP[targetHost] = true
+	require.NoError(t, (&Bundler{Client: client, Layout: layout}).Push(context.Background(), targetHost))
+	assert.Contains(t, target.manifests, "rancher/agent:"+list.Digest.String())
+	assert.Contains(t, target.manifests, "rancher/agent:"+amd.Digest.String())
+	assert.Contains(t, target.manifests, "rancher/agent:"+arm.Digest.String())
+}
@@ -0,0 +1,254 @@
+package airgap
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	indexFile     = "index.json"
+	partialSuffix = ".partial"
+
+	// AnnotationImageName records the fully qualified name of an image in index.json. It is the same annotation
+	// containerd uses, so the archive can be imported with `ctr images import` as well.
+	AnnotationImageName = "io.containerd.image.name"
+)
+
+// Layout is an OCI image layout directory. Blobs are content addressed, so layers shared between images are only
+// stored once.
+type Layout struct {
+	Root string
+}
+
+// OpenLayout creates the layout at root if it does not exist yet, or opens the existing one so that an interrupted
+// download can be resumed.
+func OpenLayout(root string) (*Layout, error) {
+	if err := os.MkdirAll(filepath.Join(root, "blobs", "sha256"), 0755); err != nil {
+		return nil, err
+	}
+	l := &Layout{Root: root}
+
+	layoutPath := filepath.Join(root, ocispec.ImageLayoutFile)
+	if _, err := os.Stat(layoutPath); os.IsNotExist(err) {
+		if err := writeJSON(layoutPath, ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion}); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(root, indexFile)); os.IsNotExist(err) {
+		index := ocispec.Index{}
+		index.SchemaVersion = 2
+		index.Manifests = []ocispec.Descriptor{}
+		if err := l.WriteIndex(index); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// BlobPath returns the path a blob with the given digest is stored at.
+func (l *Layout) BlobPath(d digest.Digest) string {
+	return filepath.Join(l.Root, "blobs", d.Algorithm().String(), d.Encoded())
+}
+
+// HasBlob reports whether the blob has been completely written.
+func (l *Layout) HasBlob(d digest.Digest) bool {
+	_, err := os.Stat(l.BlobPath(d))
+	return err == nil
+}
+
+// ReadBlob returns the content of a blob.
+func (l *Layout) ReadBlob(d digest.Digest) ([]byte, error) {
+	return ioutil.ReadFile(l.BlobPath(d))
+}
+
+// WriteBlob stores data as a blob and returns its digest.
+func (l *Layout) WriteBlob(data []byte) (digest.Digest, error) {
+	d := digest.FromBytes(data)
+	if l.HasBlob(d) {
+		return d, nil
+	}
+	path := l.BlobPath(d)
+	if err := ioutil.WriteFile(path+partialSuffix, data, 0644); err != nil {
+		return "", err
+	}
+	return d, os.Rename(path+partialSuffix, path)
+}
+
+// PartialBlob opens the incomplete download of a blob for appending and returns the number of bytes already
+// present. When resume is false the partial content is discarded.
+func (l *Layout) PartialBlob(d digest.Digest, resume bool) (*os.File, int64, error) {
+	flags := os.O_CREATE | os.O_WRONLY
+	if resume {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(l.BlobPath(d)+partialSuffix, flags, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// CommitBlob verifies the partial download of a blob against its digest and moves it into place.
+func (l *Layout) CommitBlob(d digest.Digest) error {
+	path := l.BlobPath(d)
+	f, err := os.Open(path + partialSuffix)
+	if err != nil {
+		return err
+	}
+	verifier := d.Verifier()
+	_, err = io.Copy(verifier, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		os.Remove(path + partialSuffix)
+		return fmt.Errorf("content of blob %s does not match its digest", d)
+	}
+	return os.Rename(path+partialSuffix, path)
+}
+
+// ReadIndex returns the index.json of the layout.
+func (l *Layout) ReadIndex() (ocispec.Index, error) {
+	var index ocispec.Index
+	data, err := ioutil.ReadFile(filepath.Join(l.Root, indexFile))
+	if err != nil {
+		return index, err
+	}
+	return index, json.Unmarshal(data, &index)
+}
+
+// WriteIndex replaces the index.json of the layout.
+func (l *Layout) WriteIndex(index ocispec.Index) error {
+	return writeJSON(filepath.Join(l.Root, indexFile), index)
+}
+
+// AddImage records a manifest under the given image name, replacing a previous entry with the same name.
+func (l *Layout) AddImage(name string, desc ocispec.Descriptor) error {
+	index, err := l.ReadIndex()
+	if err != nil {
+		return err
+	}
+	manifests := index.Manifests[:0]
+	for _, m := range index.Manifests {
+		if m.Annotations[AnnotationImageName] != name {
+			manifests = append(manifests, m)
+		}
+	}
+	index.Manifests = append(manifests, desc)
+	return l.WriteIndex(index)
+}
+
+// Archive writes the layout as an uncompressed tarball. Incomplete downloads are left out.
+func (l *Layout) Archive(w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(l.Root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(path, partialSuffix) {
+			return nil
+		}
+		name, err := filepath.Rel(l.Root, path)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// ExtractLayout unpacks an archive written by Archive into root and opens it.
+func ExtractLayout(r io.Reader, root string) (*Layout, error) {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(root, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(root)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("invalid path [%s] in archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return nil, err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, ocispec.ImageLayoutFile)); err != nil {
+		return nil, errors.Wrap(err, "archive is not an OCI image layout")
+	}
+	return OpenLayout(root)
+}
+
+func writeJSON(path string, obj interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	tmp := path + partialSuffix
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
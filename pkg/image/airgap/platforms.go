@@ -0,0 +1,110 @@
+package airgap
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// PlatformResult is the result of inspecting the platforms of one image.
+type PlatformResult struct {
+	Platforms []Platform
+	Err       error
+}
+
+// Platforms returns the platforms an image is published for. For manifest lists these are the platforms of the
+// listed manifests, for single manifests the platform is read from the image config.
+func (c *Client) Platforms(ctx context.Context, image string) ([]Platform, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	data, mediaType, err := c.GetManifest(ctx, ref, ref.Version())
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "decoding manifest")
+	}
+
+	if isIndex(mediaType) {
+		var platforms []Platform
+		for _, desc := range m.Manifests {
+			// attestation manifests and the like have no platform or an unknown one
+			if desc.Platform == nil || desc.Platform.OS == "unknown" {
+				continue
+			}
+			platforms = append(platforms, Platform{
+				OS:           desc.Platform.OS,
+				Architecture: desc.Platform.Architecture,
+				Variant:      desc.Platform.Variant,
+			})
+		}
+		return platforms, nil
+	}
+
+	config, err := c.readBlob(ctx, ref, m.Config)
+	if err != nil {
+		return nil, err
+	}
+	var platform ocispec.Platform
+	if err := json.Unmarshal(config, &platform); err != nil {
+		return nil, errors.Wrap(err, "decoding image config")
+	}
+	return []Platform{{
+		OS:           platform.OS,
+		Architecture: platform.Architecture,
+		Variant:      platform.Variant,
+	}}, nil
+}
+
+// InspectPlatforms looks up the platforms of every image concurrently.
+func (c *Client) InspectPlatforms(ctx context.Context, images []string) map[string]PlatformResult {
+	var (
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		result  = make(map[string]PlatformResult, len(images))
+		pending = make(chan string)
+	)
+	for i := 0; i < checkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for image := range pending {
+				platforms, err := c.Platforms(ctx, image)
+				lock.Lock()
+				result[image] = PlatformResult{Platforms: platforms, Err: err}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, image := range images {
+		pending <- image
+	}
+	close(pending)
+	wg.Wait()
+	return result
+}
+
+func (c *Client) readBlob(ctx context.Context, ref Reference, desc ocispec.Descriptor) ([]byte, error) {
+	body, _, err := c.GetBlob(ctx, ref, desc.Digest.String(), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if digest.FromBytes(data) != desc.Digest {
+		return nil, errors.Errorf("content of blob %s does not match its digest", desc.Digest)
+	}
+	return data, nil
+}
@@ -0,0 +1,50 @@
+package airgap
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectPlatforms(t *testing.T) {
+	registry := newFakeRegistry()
+	host := startFakeRegistry(t, registry)
+
+	config, _ := json.Marshal(ocispec.Image{OS: "linux", Architecture: "amd64"})
+	registry.addManifest("rancher/single", "v1", MediaTypeDockerManifest, manifest{
+		MediaType: MediaTypeDockerManifest,
+		Config:    registry.addBlob(config),
+	})
+
+	amd64 := registry.addImage("rancher/multi", "v1-amd64", []byte("amd64"))
+	amd64.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm := registry.addImage("rancher/multi", "v1-arm", []byte("arm"))
+	arm.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	attestation := registry.addImage("rancher/multi", "v1-attestation", []byte("attestation"))
+	attestation.Platform = &ocispec.Platform{OS: "unknown", Architecture: "unknown"}
+	registry.addManifest("rancher/multi", "v1", MediaTypeOCIIndex, manifest{
+		MediaType: MediaTypeOCIIndex,
+		Manifests: []ocispec.Descriptor{amd64, arm, attestation},
+	})
+
+	client := NewClient(nil)
+	client.PlainHTTP[host] = true
+	results := client.InspectPlatforms(context.Background(), []string{
+		host + "/rancher/single:v1",
+		host + "/rancher/multi:v1",
+		host + "/rancher/missing:v1",
+	})
+
+	require.NoError(t, results[host+"/rancher/single:v1"].Err)
+	assert.Equal(t, []Platform{{OS: "linux", Architecture: "amd64"}}, results[host+"/rancher/single:v1"].Platforms)
+	require.NoError(t, results[host+"/rancher/multi:v1"].Err)
+	assert.Equal(t, []Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm", Variant: "v7"},
+	}, results[host+"/rancher/multi:v1"].Platforms)
+	assert.Error(t, results[host+"/rancher/missing:v1"].Err)
+}
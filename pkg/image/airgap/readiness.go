@@ -0,0 +1,108 @@
+package airgap
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const checkWorkers = 8
+
+// ImageStatus is the result of looking up one image in a registry.
+type ImageStatus struct {
+	Image   string
+	Present bool
+	Err     error
+}
+
+// CheckImages looks up the manifest of every image in the registry it references, without downloading any layers.
+// The result is in the same order as images.
+func (c *Client) CheckImages(ctx context.Context, images []string) []ImageStatus {
+	result := make([]ImageStatus, len(images))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < checkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result[i] = c.checkImage(ctx, images[i])
+			}
+		}()
+	}
+	for i := range images {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return result
+}
+
+func (c *Client) checkImage(ctx context.Context, image string) ImageStatus {
+	status := ImageStatus{Image: image}
+	ref, err := ParseReference(image)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Present, status.Err = c.ManifestExists(ctx, ref, ref.Version())
+	return status
+}
+
+// DockerConfigCredentials returns the credentials stored in a base64 encoded docker config JSON, as generated for
+// cluster private registries.
+func DockerConfigCredentials(encoded string) (Credentials, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	data, err := base64.URLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode registry credentials")
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to read registry credentials")
+	}
+
+	credentials := map[string][2]string{}
+	for registry, auth := range config.Auths {
+		username, password := auth.Username, auth.Password
+		if username == "" && auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode credentials for registry %s", registry)
+			}
+			username, password, _ = strings.Cut(string(decoded), ":")
+		}
+		credentials[registryHost(registry)] = [2]string{username, password}
+	}
+
+	return func(host string) (string, string) {
+		c := credentials[registryHost(host)]
+		return c[0], c[1]
+	}, nil
+}
+
+// registryHost returns the host of a docker config registry key, which may be a URL like https://index.docker.io/v1/.
+// The Docker Hub aliases are mapped to the host the registry client talks to.
+func registryHost(registry string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case dockerHubDomain, "index." + dockerHubDomain:
+		return dockerHubRegistry
+	}
+	return host
+}
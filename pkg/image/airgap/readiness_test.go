@@ -0,0 +1,62 @@
+package airgap
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckImages(t *testing.T) {
+	registry := newFakeRegistry()
+	host := startFakeRegistry(t, registry)
+	registry.addImage("rancher/rancher-agent", "v2.7.0", []byte("layer"))
+
+	client := NewClient(nil)
+	client.PlainHTTP[host] = true
+	statuses := client.CheckImages(context.Background(), []string{
+		host + "/rancher/rancher-agent:v2.7.0",
+		host + "/rancher/shell:v0.1.18",
+		"not a valid image",
+	})
+
+	require.Len(t, statuses, 3)
+	assert.True(t, statuses[0].Present)
+	assert.NoError(t, statuses[0].Err)
+	assert.False(t, statuses[1].Present)
+	assert.NoError(t, statuses[1].Err)
+	assert.False(t, statuses[2].Present)
+	assert.Error(t, statuses[2].Err)
+}
+
+func TestDockerConfigCredentials(t *testing.T) {
+	config := `{"auths":{"registry.example.com":{"username":"user","password":"pass"},"https://other.example.com/":{"auth":"` +
+		base64.StdEncoding.EncodeToString([]byte("admin:secret")) + `"}}}`
+
+	credentials, err := DockerConfigCredentials(base64.URLEncoding.EncodeToString([]byte(config)))
+	require.NoError(t, err)
+
+	username, password := credentials("registry.example.com")
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pass", password)
+	username, password = credentials("other.example.com")
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "secret", password)
+	username, _ = credentials("docker.io")
+	assert.Empty(t, username)
+
+	config = `{"auths":{"https://index.docker.io/v1/":{"username":"hub","password":"token"}}}`
+	credentials, err = DockerConfigCredentials(base64.URLEncoding.EncodeToString([]byte(config)))
+	require.NoError(t, err)
+	for _, host := range []string{dockerHubRegistry, dockerHubDomain} {
+		username, password = credentials(host)
+		assert.Equal(t, "hub", username)
+		assert.Equal(t, "token", password)
+	}
+
+	credentials, err = DockerConfigCredentials("")
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+}
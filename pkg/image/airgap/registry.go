@@ -0,0 +1,439 @@
+package airgap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+const (
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+var manifestAcceptHeader = strings.Join([]string{
+	MediaTypeOCIIndex,
+	MediaTypeOCIManifest,
+	MediaTypeDockerManifestList,
+	MediaTypeDockerManifest,
+}, ", ")
+
+// Credentials returns the username and password to use for the given registry host. An empty username means
+// anonymous access.
+type Credentials func(host string) (string, string)
+
+// Client is a minimal Docker Registry HTTP API V2 client. It only implements the calls needed to copy images
+// between registries and an OCI layout, so that no docker daemon is required.
+type Client struct {
+	HTTPClient  *http.Client
+	Credentials Credentials
+	// PlainHTTP lists registry hosts that must be reached over http instead of https.
+	PlainHTTP map[string]bool
+
+	lock   sync.Mutex
+	tokens map[string]string
+}
+
+func NewClient(credentials Credentials) *Client {
+	return &Client{
+		HTTPClient:  http.DefaultClient,
+		Credentials: credentials,
+		PlainHTTP:   map[string]bool{},
+		tokens:      map[string]string{},
+	}
+}
+
+// Reference is a parsed image reference split into the parts used by the registry API.
+type Reference struct {
+	// Name is the fully qualified name of the image, i.e. docker.io/rancher/rancher:v2.7.0.
+	Name string
+	// Host is the registry host the image is served from.
+	Host string
+	// Repository is the repository path within the registry.
+	Repository string
+	// Tag is the tag of the image, if any.
+	Tag string
+	// Digest is the digest of the image, if any. It takes precedence over the tag when fetching.
+	Digest string
+}
+
+// ParseReference parses a docker style image reference. Images without a domain are resolved against Docker Hub.
+func ParseReference(image string) (Reference, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return Reference{}, errors.Wrapf(err, "invalid image reference [%s]", image)
+	}
+	named = reference.TagNameOnly(named)
+
+	ref := Reference{
+		Name:       named.String(),
+		Host:       reference.Domain(named),
+		Repository: reference.Path(named),
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		ref.Digest = digested.Digest().String()
+	}
+	if ref.Host == dockerHubDomain {
+		ref.Host = dockerHubRegistry
+	}
+	return ref, nil
+}
+
+// Version returns the tag or digest used to address the manifest of the image.
+func (r Reference) Version() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// WithRegistry returns the reference rewritten to live in the given registry, keeping the repository path. This is
+// how images are laid out in a private registry by the Rancher load scripts.
+func (r Reference) WithRegistry(registry string) (Reference, error) {
+	name := registry + "/" + r.Repository
+	if r.Tag != "" {
+		name += ":" + r.Tag
+	} else if r.Digest != "" {
+		name += "@" + r.Digest
+	}
+	return ParseReference(name)
+}
+
+// GetManifest fetches the manifest addressed by version (a tag or digest) and returns its content and media type.
+func (c *Client) GetManifest(ctx context.Context, ref Reference, version string) ([]byte, string, error) {
+	resp, err := c.do(ctx, ref, http.MethodGet, "/manifests/"+version, nil, map[string]string{"Accept": manifestAcceptHeader})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", responseError(resp, "fetching manifest %s@%s", ref.Repository, version)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType := resp.Header.Get("Content-Type")
+	if mediaType == "" || mediaType == "application/json" || mediaType == "text/plain" {
+		mediaType = detectMediaType(data)
+	}
+	return data, mediaType, nil
+}
+
+// ManifestExists reports whether the registry has a manifest for version (a tag or digest).
+func (c *Client) ManifestExists(ctx context.Context, ref Reference, version string) (bool, error) {
+	resp, err := c.do(ctx, ref, http.MethodHead, "/manifests/"+version, nil, map[string]string{"Accept": manifestAcceptHeader})
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, responseError(resp, "checking manifest %s@%s", ref.Repository, version)
+}
+
+// GetBlob opens the blob with the given digest starting at offset. The returned boolean is true when the registry
+// honored the range request, otherwise the body starts at the beginning of the blob.
+func (c *Client) GetBlob(ctx context.Context, ref Reference, digest string, offset int64) (io.ReadCloser, bool, error) {
+	headers := map[string]string{}
+	if offset > 0 {
+		headers["Range"] = fmt.Sprintf("bytes=%d-", offset)
+	}
+	resp, err := c.do(ctx, ref, http.MethodGet, "/blobs/"+digest, nil, headers)
+	if err != nil {
+		return nil, false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, false, nil
+	case http.StatusPartialContent:
+		return resp.Body, true, nil
+	}
+	defer resp.Body.Close()
+	return nil, false, responseError(resp, "fetching blob %s@%s", ref.Repository, digest)
+}
+
+// BlobExists reports whether the registry already has the blob with the given digest.
+func (c *Client) BlobExists(ctx context.Context, ref Reference, digest string) (bool, error) {
+	resp, err := c.do(ctx, ref, http.MethodHead, "/blobs/"+digest, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, responseError(resp, "checking blob %s@%s", ref.Repository, digest)
+}
+
+// PushBlob uploads a blob in a single request.
+func (c *Client) PushBlob(ctx context.Context, ref Reference, digest string, size int64, content io.Reader) error {
+	resp, err := c.do(ctx, ref, http.MethodPost, "/blobs/uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return responseError(resp, "starting upload of blob %s@%s", ref.Repository, digest)
+	}
+
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return errors.Wrapf(err, "invalid upload location for blob %s@%s", ref.Repository, digest)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location.String(), content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err = c.send(req, ref)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp, "uploading blob %s@%s", ref.Repository, digest)
+	}
+	return nil
+}
+
+// PushManifest uploads a manifest and tags it with version.
+func (c *Client) PushManifest(ctx context.Context, ref Reference, version, mediaType string, data []byte) error {
+	resp, err := c.do(ctx, ref, http.MethodPut, "/manifests/"+version, data, map[string]string{"Content-Type": mediaType})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return responseError(resp, "pushing manifest %s:%s", ref.Repository, version)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, ref Reference, method, path string, body []byte, headers map[string]string) (*http.Response, error) {
+	scheme := "https"
+	if c.PlainHTTP[ref.Host] {
+		scheme = "http"
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s%s", scheme, ref.Host, ref.Repository, path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		req.Body, _ = req.GetBody()
+		req.ContentLength = int64(len(body))
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return c.send(req, ref)
+}
+
+// send performs the request, answering an authentication challenge once if the registry asks for one. Requests with
+// a body that cannot be replayed are only retried when the body was never consumed, which is why uploads are
+// preceded by a POST that primes the token cache.
+func (c *Client) send(req *http.Request, ref Reference) (*http.Response, error) {
+	scope := "repository:" + ref.Repository + ":pull"
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		scope += ",push"
+	}
+	c.authorize(req, ref.Host, scope)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	if err := c.login(req.Context(), ref.Host, scope, challenge); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("registry %s requested authentication after the request body was sent", ref.Host)
+		}
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	c.authorize(retry, ref.Host, scope)
+	return c.HTTPClient.Do(retry)
+}
+
+func (c *Client) authorize(req *http.Request, host, scope string) {
+	if req.URL.Host != host {
+		// never hand registry credentials to a different host, such as a storage backend an upload redirects to
+		return
+	}
+	c.lock.Lock()
+	token := c.tokens[host+"/"+scope]
+	if token == "" {
+		token = c.tokens[host]
+	}
+	c.lock.Unlock()
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+}
+
+func (c *Client) credentials(host string) (string, string) {
+	if c.Credentials == nil {
+		return "", ""
+	}
+	return c.Credentials(host)
+}
+
+func (c *Client) login(ctx context.Context, host, scope, challenge string) error {
+	authType, params := parseChallenge(challenge)
+	username, password := c.credentials(host)
+
+	switch strings.ToLower(authType) {
+	case "basic":
+		if username == "" {
+			return fmt.Errorf("registry %s requires credentials", host)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(username, password)
+		c.setToken(host, req.Header.Get("Authorization"))
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("registry %s returned unsupported authentication challenge [%s]", host, challenge)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("registry %s returned invalid token realm [%s]", host, params["realm"])
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	requested := scope
+	if params["scope"] != "" {
+		requested = params["scope"]
+	}
+	query.Set("scope", requested)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "requesting token for %s", host)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return errors.Wrapf(err, "decoding token from %s", realm.Host)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	c.setToken(host+"/"+scope, "Bearer "+token.Token)
+	return nil
+}
+
+func (c *Client) setToken(key, value string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.tokens == nil {
+		c.tokens = map[string]string{}
+	}
+	c.tokens[key] = value
+}
+
+// parseChallenge parses a WWW-Authenticate header value such as
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	authType, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return authType, params
+}
+
+func detectMediaType(data []byte) string {
+	var manifest struct {
+		MediaType string            `json:"mediaType"`
+		Manifests []json.RawMessage `json:"manifests"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ""
+	}
+	if manifest.MediaType != "" {
+		return manifest.MediaType
+	}
+	if manifest.Manifests != nil {
+		return MediaTypeOCIIndex
+	}
+	return MediaTypeOCIManifest
+}
+
+func responseError(resp *http.Response, format string, args ...interface{}) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s: unexpected status %s: %s", fmt.Sprintf(format, args...), resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,70 @@
+package airgap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{
+			image: "rancher/rancher:v2.7.0",
+			want: Reference{
+				Name:       "docker.io/rancher/rancher:v2.7.0",
+				Host:       "registry-1.docker.io",
+				Repository: "rancher/rancher",
+				Tag:        "v2.7.0",
+			},
+		},
+		{
+			image: "busybox",
+			want: Reference{
+				Name:       "docker.io/library/busybox:latest",
+				Host:       "registry-1.docker.io",
+				Repository: "library/busybox",
+				Tag:        "latest",
+			},
+		},
+		{
+			image: "my.registry.com:5000/rancher/shell@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			want: Reference{
+				Name:       "my.registry.com:5000/rancher/shell@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				Host:       "my.registry.com:5000",
+				Repository: "rancher/shell",
+				Digest:     "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := ParseReference(tt.image)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	ref, err := ParseReference("rancher/rancher:v2.7.0")
+	require.NoError(t, err)
+	target, err := ref.WithRegistry("my.registry.com:5000")
+	require.NoError(t, err)
+	assert.Equal(t, "my.registry.com:5000/rancher/rancher:v2.7.0", target.Name)
+}
+
+func TestParseChallenge(t *testing.T) {
+	authType, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:rancher/rancher:pull"`)
+	assert.Equal(t, "Bearer", authType)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:rancher/rancher:pull",
+	}, params)
+
+	authType, params = parseChallenge(`Basic realm="Registry Realm"`)
+	assert.Equal(t, "Basic", authType)
+	assert.Equal(t, "Registry Realm", params["realm"])
+}
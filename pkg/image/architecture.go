@@ -0,0 +1,38 @@
+package image
+
+import (
+	"sort"
+	"strings"
+)
+
+// GetArchitectureLists splits the images generated by GetImages by the architectures they are published for.
+// imagesAndSources is in the "image source1,source2" format and architectures maps each image to the architectures
+// its manifest (list) provides. For every requested architecture it returns the images available for it, and the
+// entries of imagesAndSources whose image lacks it, so that the charts or KDM releases referencing them can be
+// tracked down. Images without an entry in architectures could not be inspected and are reported as lacking every
+// architecture.
+func GetArchitectureLists(imagesAndSources []string, architectures map[string][]string, requested []string) (map[string][]string, map[string][]string) {
+	available := make(map[string][]string, len(requested))
+	missing := make(map[string][]string, len(requested))
+
+	for _, imageAndSources := range imagesAndSources {
+		image := strings.Split(imageAndSources, " ")[0]
+		imageArchitectures := make(map[string]struct{}, len(architectures[image]))
+		for _, arch := range architectures[image] {
+			imageArchitectures[arch] = struct{}{}
+		}
+		for _, arch := range requested {
+			if _, ok := imageArchitectures[arch]; ok {
+				available[arch] = append(available[arch], image)
+			} else {
+				missing[arch] = append(missing[arch], imageAndSources)
+			}
+		}
+	}
+
+	for _, arch := range requested {
+		sort.Strings(available[arch])
+		sort.Strings(missing[arch])
+	}
+	return available, missing
+}
@@ -0,0 +1,34 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetArchitectureLists(t *testing.T) {
+	imagesAndSources := []string{
+		"rancher/fleet:v0.5.0 fleet:100.1.0",
+		"rancher/mirrored-coreos-etcd:v3.5.4 system",
+		"rancher/shell:v0.1.18 core",
+		"rancher/unreachable:v1 rancher-monitoring:100.1.0",
+	}
+	architectures := map[string][]string{
+		"rancher/fleet:v0.5.0":                {"amd64", "arm64"},
+		"rancher/mirrored-coreos-etcd:v3.5.4": {"amd64"},
+		"rancher/shell:v0.1.18":               {"arm64", "amd64", "arm", "arm/v7"},
+	}
+
+	available, missing := GetArchitectureLists(imagesAndSources, architectures, []string{"amd64", "arm64", "arm/v7"})
+
+	assert.Equal(t, []string{"rancher/fleet:v0.5.0", "rancher/mirrored-coreos-etcd:v3.5.4", "rancher/shell:v0.1.18"}, available["amd64"])
+	assert.Equal(t, []string{"rancher/fleet:v0.5.0", "rancher/shell:v0.1.18"}, available["arm64"])
+	assert.Equal(t, []string{"rancher/shell:v0.1.18"}, available["arm/v7"])
+
+	assert.Equal(t, []string{"rancher/unreachable:v1 rancher-monitoring:100.1.0"}, missing["amd64"])
+	assert.Equal(t, []string{
+		"rancher/mirrored-coreos-etcd:v3.5.4 system",
+		"rancher/unreachable:v1 rancher-monitoring:100.1.0",
+	}, missing["arm64"])
+	assert.Len(t, missing["arm/v7"], 3)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/rancher/rancher/pkg/image/airgap"
+	"github.com/rancher/rancher/pkg/image/release"
+)
+
+const usage = `Usage:
+  go run main.go save [--platform linux/amd64] [--data data.json] [--layout dir] [--output rancher-images.oci.tar] SYSTEM_CHART_PATH CHART_PATH IMAGE...
+  go run main.go push --registry my.registry.com:5000 [--archive rancher-images.oci.tar | --layout dir] [--plain-http]
+
+save resolves the images of the Rancher release given by the TAG environment variable the same way pkg/image/export
+does, from the charts, system charts, KDM data and the Rancher images given as arguments, and pulls the ones of the OS
+of the platform into an OCI image layout. The arguments include the rancher/wins upgrade image, which is only saved for
+Windows along with the agent image of the REPO environment variable. Layers shared between images are stored once and
+reruns resume where a previous run stopped. The layout is then written as a single tarball.
+
+push uploads the images of an OCI archive or layout to a private registry. Registry credentials are read from the
+REGISTRY_USERNAME and REGISTRY_PASSWORD environment variables.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "save":
+		err = save(os.Args[2:])
+	case "push":
+		err = push(os.Args[2:])
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func save(args []string) error {
+	flags := flag.NewFlagSet("save", flag.ExitOnError)
+	dataFile := flags.String("data", "data.json", "KDM data file")
+	platform := flags.String("platform", "linux/amd64", "platform to select from multi-architecture images")
+	layoutDir := flags.String("layout", "rancher-images.oci", "OCI layout directory used to stage and resume downloads")
+	output := flags.String("output", "rancher-images.oci.tar", "OCI archive to create, empty to only populate the layout")
+	flags.Parse(args)
+	if flags.NArg() < 2 {
+		return fmt.Errorf("save requires the system chart and chart paths\n%s", usage)
+	}
+
+	p, err := airgap.ParsePlatform(*platform)
+	if err != nil {
+		return err
+	}
+	images, err := resolveImages(*dataFile, flags.Arg(0), flags.Arg(1), flags.Args()[2:], p)
+	if err != nil {
+		return err
+	}
+	layout, err := airgap.OpenLayout(*layoutDir)
+	if err != nil {
+		return err
+	}
+
+	bundler := &airgap.Bundler{
+		Client:   airgap.NewClient(credentials("")),
+		Layout:   layout,
+		Platform: p,
+	}
+	log.Printf("Saving %d images to %s\n", len(images), *layoutDir)
+	if err := bundler.Save(context.Background(), images); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return nil
+	}
+	log.Printf("Creating %s\n", *output)
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := layout.Archive(f); err != nil {
+		f.Close()
+		return err
+	}
+	// the tarball is only complete once it is flushed to disk
+	return f.Close()
+}
+
+// resolveImages returns the images of the Rancher release for the OS of the platform.
+func resolveImages(dataFile, systemChartsPath, chartsPath string, imagesFromArgs []string, platform airgap.Platform) ([]string, error) {
+	images, err := release.Resolve(release.Config{
+		Tag:              os.Getenv("TAG"),
+		Repo:             os.Getenv("REPO"),
+		DataFile:         dataFile,
+		SystemChartsPath: systemChartsPath,
+		ChartsPath:       chartsPath,
+		ImagesFromArgs:   imagesFromArgs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if platform.OS == "windows" {
+		return images.Windows, nil
+	}
+	return images.Linux, nil
+}
+
+func push(args []string) error {
+	flags := flag.NewFlagSet("push", flag.ExitOnError)
+	registry := flags.String("registry", "", "target private registry:port")
+	archive := flags.String("archive", "rancher-images.oci.tar", "OCI archive created by save")
+	layoutDir := flags.String("layout", "", "OCI layout directory to push instead of an archive")
+	plainHTTP := flags.Bool("plain-http", false, "connect to the target registry over http")
+	flags.Parse(args)
+
+	if *registry == "" {
+		return fmt.Errorf("--registry is required\n%s", usage)
+	}
+
+	var (
+		layout *airgap.Layout
+		err    error
+	)
+	if *layoutDir != "" {
+		layout, err = airgap.OpenLayout(*layoutDir)
+	} else {
+		layout, err = extractArchive(*archive)
+		if layout != nil {
+			defer os.RemoveAll(layout.Root)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	client := airgap.NewClient(credentials(*registry))
+	if *plainHTTP {
+		client.PlainHTTP[*registry] = true
+	}
+	bundler := &airgap.Bundler{
+		Client: client,
+		Layout: layout,
+	}
+	return bundler.Push(context.Background(), *registry)
+}
+
+func extractArchive(archive string) (*airgap.Layout, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir, err := ioutil.TempDir("", "rancher-images-oci")
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Extracting %s\n", archive)
+	layout, err := airgap.ExtractLayout(f, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return layout, nil
+}
+
+// credentials returns the credentials from the environment for the given registry. Source registries are accessed
+// anonymously.
+func credentials(registry string) airgap.Credentials {
+	return func(host string) (string, string) {
+		if registry == "" || host != registry {
+			return "", ""
+		}
+		return os.Getenv("REGISTRY_USERNAME"), os.Getenv("REGISTRY_PASSWORD")
+	}
+}
@@ -0,0 +1,106 @@
+package image
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	rketypes "github.com/rancher/rke/types"
+	"gopkg.in/yaml.v2"
+)
+
+// ClusterImageConfig holds everything that decides which images a single cluster pulls.
+type ClusterImageConfig struct {
+	Cluster *v3.Cluster
+	OsType  OSType
+	// KubernetesVersion is the version the images are computed for. It can differ from the version the cluster runs
+	// to check an upgrade before it happens.
+	KubernetesVersion string
+	// RKESystemImages are the system images of KubernetesVersion, nil for clusters that are not provisioned by RKE.
+	RKESystemImages *rketypes.RKESystemImages
+	// SystemChartImages are the images of the Rancher system charts.
+	SystemChartImages []string
+	// AppValues maps the name of every catalog app installed in the cluster to the values it was installed with.
+	AppValues map[string]map[string]interface{}
+}
+
+// ClusterImage is an image a cluster needs, as it is pulled from the cluster's private registry.
+type ClusterImage struct {
+	Image   string
+	Sources []string
+}
+
+// GetClusterImages computes the images needed by a specific cluster: the agent images, the system images of its
+// Kubernetes version, the system charts and the charts of its installed apps. The returned images are rewritten
+// with ResolveWithCluster so that they point at the registry the cluster pulls from.
+func GetClusterImages(config ClusterImageConfig) ([]ClusterImage, error) {
+	imagesSet := make(map[string]map[string]struct{})
+
+	// unlike System.FetchImages the tools images are left out, they are only pulled by features a cluster may not use
+	if config.RKESystemImages != nil {
+		systemImages, err := flatImagesFromCollections(*config.RKESystemImages)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch images from system")
+		}
+		setImages("system", systemImages, imagesSet)
+	}
+
+	setRequirementImages(config.OsType, imagesSet)
+	setImages("core", []string{settings.AgentImage.Get()}, imagesSet)
+	if config.OsType == Linux && config.Cluster != nil && config.Cluster.Status.Driver == v3.ClusterDriverImported {
+		setImages("core", []string{settings.AuthImage.Get()}, imagesSet)
+	}
+	setImages("system-charts", config.SystemChartImages, imagesSet)
+
+	for app, values := range config.AppValues {
+		converted, err := toYAMLMap(values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read values of app %s", app)
+		}
+		if err := pickImagesFromValuesMap(imagesSet, converted, fmt.Sprintf("app:%s", app), config.OsType); err != nil {
+			return nil, err
+		}
+	}
+
+	convertMirroredImages(imagesSet)
+
+	resolved := make(map[string]map[string]struct{}, len(imagesSet))
+	for image, sources := range imagesSet {
+		for source := range sources {
+			addSourceToImage(resolved, ResolveWithCluster(image, config.Cluster), source)
+		}
+	}
+
+	images, _ := generateImageAndSourceLists(resolved)
+	result := make([]ClusterImage, 0, len(images))
+	for _, image := range images {
+		result = append(result, ClusterImage{
+			Image:   image,
+			Sources: SortedSources(resolved[image]),
+		})
+	}
+	return result, nil
+}
+
+// toYAMLMap converts values decoded from JSON into the form values files are decoded into, so that the same image
+// lookup can be used for both.
+func toYAMLMap(values map[string]interface{}) (map[interface{}]interface{}, error) {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var converted map[interface{}]interface{}
+	return converted, yaml.Unmarshal(data, &converted)
+}
+
+// SortedSources returns the sources of an image, sorted.
+func SortedSources(imageSources map[string]struct{}) []string {
+	sources := make([]string, 0, len(imageSources))
+	for source := range imageSources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
@@ -0,0 +1,64 @@
+package image
+
+import (
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	rketypes "github.com/rancher/rke/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClusterImages(t *testing.T) {
+	cluster := &v3.Cluster{
+		Spec: v3.ClusterSpec{
+			ClusterSpecBase: v3.ClusterSpecBase{
+				RancherKubernetesEngineConfig: &rketypes.RancherKubernetesEngineConfig{
+					PrivateRegistries: []rketypes.PrivateRegistry{{URL: "registry.example.com"}},
+				},
+			},
+		},
+	}
+
+	images, err := GetClusterImages(ClusterImageConfig{
+		Cluster:           cluster,
+		OsType:            Linux,
+		KubernetesVersion: "v1.24.9-rancher1-1",
+		RKESystemImages: &rketypes.RKESystemImages{
+			Etcd:    "rancher/mirrored-coreos-etcd:v3.5.4",
+			CoreDNS: "rancher/mirrored-coredns-coredns:1.9.3",
+		},
+		SystemChartImages: []string{"rancher/fleet-agent:v0.5.0"},
+		AppValues: map[string]map[string]interface{}{
+			"cattle-monitoring-system/rancher-monitoring": {
+				"prometheus": map[string]interface{}{
+					"image": map[string]interface{}{
+						"repository": "rancher/mirrored-prometheus-prometheus",
+						"tag":        "v2.38.0",
+					},
+				},
+				"windowsExporter": map[string]interface{}{
+					"image": map[string]interface{}{
+						"repository": "rancher/windows_exporter-package",
+						"tag":        "v0.0.3",
+						"os":         "windows",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	got := map[string][]string{}
+	for _, image := range images {
+		got[image.Image] = image.Sources
+	}
+	assert.Equal(t, []string{"system"}, got["registry.example.com/rancher/mirrored-coreos-etcd:v3.5.4"])
+	assert.Equal(t, []string{"system"}, got["registry.example.com/rancher/mirrored-coredns-coredns:1.9.3"])
+	assert.Equal(t, []string{"system-charts"}, got["registry.example.com/rancher/fleet-agent:v0.5.0"])
+	assert.Equal(t, []string{"app:cattle-monitoring-system/rancher-monitoring"}, got["registry.example.com/rancher/mirrored-prometheus-prometheus:v2.38.0"])
+	assert.Contains(t, got, "registry.example.com/"+settings.AgentImage.Get())
+	assert.NotContains(t, got, "registry.example.com/rancher/windows_exporter-package:v0.0.3")
+	assert.NotContains(t, got, "registry.example.com/"+v3.ToolsSystemImages.PipelineSystemImages.Jenkins)
+}
@@ -1,22 +1,17 @@
 package main
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
-	"github.com/coreos/go-semver/semver"
-	kd "github.com/rancher/rancher/pkg/controllers/management/kontainerdrivermetadata"
 	img "github.com/rancher/rancher/pkg/image"
-	ext "github.com/rancher/rancher/pkg/image/external"
-	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/image/airgap"
+	"github.com/rancher/rancher/pkg/image/release"
 	"github.com/rancher/rke/types/image"
-	"github.com/rancher/rke/types/kdm"
 )
 
 var (
@@ -57,94 +52,24 @@ func main() {
 }
 
 func run(systemChartsPath, chartsPath string, imagesFromArgs []string) error {
-	rancherVersion, ok := os.LookupEnv("TAG")
+	tag, ok := os.LookupEnv("TAG")
 	if !ok {
-		return fmt.Errorf("no tag %s", rancherVersion)
-	}
-	if !img.IsValidSemver(rancherVersion) || strings.HasPrefix(rancherVersion, "dev") || strings.HasPrefix(rancherVersion, "master") || strings.HasSuffix(rancherVersion, "-head") {
-		rancherVersion = settings.RancherVersionDev
-	}
-	rancherVersion = strings.TrimPrefix(rancherVersion, "v")
-
-	// already downloaded in dapper
-	b, err := ioutil.ReadFile(filepath.Join("data.json"))
-	if os.IsNotExist(err) {
-		b, err = ioutil.ReadFile(filepath.Join(os.Getenv("HOME"), "bin", "data.json"))
-	}
-	if err != nil {
-		return err
-	}
-	data, err := kdm.FromData(b)
-	if err != nil {
-		return err
-	}
-
-	linuxInfo, windowsInfo := kd.GetK8sVersionInfo(
-		rancherVersion,
-		data.K8sVersionRKESystemImages,
-		data.K8sVersionServiceOptions,
-		data.K8sVersionWindowsServiceOptions,
-		data.K8sVersionInfo,
-	)
-
-	var k8sVersions []string
-	for k := range linuxInfo.RKESystemImages {
-		k8sVersions = append(k8sVersions, k)
-	}
-	sort.Strings(k8sVersions)
-	if err := writeSliceToFile(filepath.Join(os.Getenv("HOME"), "bin", "rancher-rke-k8s-versions.txt"), k8sVersions); err != nil {
-		return err
-	}
-
-	k8sVersion1_21_0 := &semver.Version{
-		Major: 1,
-		Minor: 21,
-		Patch: 0,
+		return fmt.Errorf("no tag %s", tag)
 	}
 
-	externalLinuxImages := make(map[string][]string)
-
-	k3sUpgradeImages, err := ext.GetExternalImages(rancherVersion, data.K3S, ext.K3S, k8sVersion1_21_0, img.Linux)
-	if err != nil {
-		return err
-	}
-	if k3sUpgradeImages != nil {
-		externalLinuxImages["k3sUpgrade"] = k3sUpgradeImages
-	}
-
-	// RKE2 Provisioning will only be supported on Kubernetes v1.21+. In addition, only RKE2
-	// releases corresponding to Kubernetes v1.21+ include the "rke2-images-all.linux-amd64.txt" file that we need.
-	rke2AllImages, err := ext.GetExternalImages(rancherVersion, data.RKE2, ext.RKE2, k8sVersion1_21_0, img.Linux)
-	if err != nil {
-		return err
-	}
-	if rke2AllImages != nil {
-		externalLinuxImages["rke2All"] = rke2AllImages
-	}
-
-	sort.Strings(imagesFromArgs)
-	winsIndex := sort.SearchStrings(imagesFromArgs, "rancher/wins")
-	if winsIndex > len(imagesFromArgs)-1 {
-		return errors.New("rancher/wins upgrade image not found")
-	}
-
-	winsAgentUpdateImage := imagesFromArgs[winsIndex]
-	linuxImagesFromArgs := append(imagesFromArgs[:winsIndex], imagesFromArgs[winsIndex+1:]...)
-
-	exportConfig := img.ExportConfig{
+	// data.json is already downloaded in dapper
+	images, err := release.Resolve(release.Config{
+		Tag:              tag,
+		Repo:             os.Getenv("REPO"),
+		DataFile:         "data.json",
 		SystemChartsPath: systemChartsPath,
 		ChartsPath:       chartsPath,
-		OsType:           img.Linux,
-		RancherVersion:   rancherVersion,
-	}
-	targetImages, targetImagesAndSources, err := img.GetImages(exportConfig, externalLinuxImages, linuxImagesFromArgs, linuxInfo.RKESystemImages)
+		ImagesFromArgs:   imagesFromArgs,
+	})
 	if err != nil {
 		return err
 	}
-
-	exportConfig.OsType = img.Windows
-	targetWindowsImages, targetWindowsImagesAndSources, err := img.GetImages(exportConfig, nil, []string{getWindowsAgentImage(), winsAgentUpdateImage}, windowsInfo.RKESystemImages)
-	if err != nil {
+	if err := writeSliceToFile(filepath.Join(os.Getenv("HOME"), "bin", "rancher-rke-k8s-versions.txt"), images.K8sVersions); err != nil {
 		return err
 	}
 
@@ -153,8 +78,8 @@ type imageTextLists struct {
 		imagesAndSources []string
 	}
 	for arch, imageLists := range map[string]imageTextLists{
-		"linux":   {images: targetImages, imagesAndSources: targetImagesAndSources},
-		"windows": {images: targetWindowsImages, imagesAndSources: targetWindowsImagesAndSources},
+		"linux":   {images: images.Linux, imagesAndSources: images.LinuxAndSources},
+		"windows": {images: images.Windows, imagesAndSources: images.WindowsAndSources},
 	} {
 		err = imagesText(arch, imageLists.images)
 		if err != nil {
@@ -180,6 +105,65 @@ type imageTextLists struct {
 		}
 	}
 
+	// inspecting every manifest takes a while, so per-architecture lists are only generated on request
+	if architectures := os.Getenv("IMAGE_ARCHITECTURES"); architectures != "" {
+		if err := architectureLists(strings.Split(architectures, ","), saveImagesAndSources(images.LinuxAndSources)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// architectureLists inspects the manifests of the linux images and writes which architectures each image is
+// published for, a list of images per requested architecture, and the images (with the charts or KDM releases they
+// come from) lacking a requested architecture.
+func architectureLists(requested []string, imagesAndSources []string) error {
+	var images []string
+	for _, imageAndSources := range imagesAndSources {
+		images = append(images, strings.Split(imageAndSources, " ")[0])
+	}
+
+	log.Printf("Inspecting the architectures of %d images\n", len(images))
+	results := airgap.NewClient(nil).InspectPlatforms(context.Background(), images)
+
+	architectures := make(map[string][]string, len(results))
+	var lines []string
+	for _, image := range images {
+		result := results[image]
+		if result.Err != nil {
+			log.Printf("Failed to inspect the architectures of %s: %v\n", image, result.Err)
+			lines = append(lines, fmt.Sprintf("%s error:%v", image, result.Err))
+			continue
+		}
+		for _, platform := range result.Platforms {
+			if platform.OS != "linux" {
+				continue
+			}
+			architectures[image] = append(architectures[image], platform.Architecture)
+			if platform.Variant != "" {
+				architectures[image] = append(architectures[image], platform.Architecture+"/"+platform.Variant)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", image, strings.Join(architectures[image], ",")))
+	}
+	if err := writeSliceToFile("rancher-images-architectures.txt", lines); err != nil {
+		return err
+	}
+
+	available, missing := img.GetArchitectureLists(imagesAndSources, architectures, requested)
+	for _, arch := range requested {
+		suffix := strings.ReplaceAll(arch, "/", "-")
+		if err := writeSliceToFile(fmt.Sprintf("rancher-images-%s.txt", suffix), available[arch]); err != nil {
+			return err
+		}
+		if err := writeSliceToFile(fmt.Sprintf("rancher-images-missing-%s.txt", suffix), missing[arch]); err != nil {
+			return err
+		}
+		if len(missing[arch]) > 0 {
+			log.Printf("WARNING: %d images are not available for %s, see rancher-images-missing-%s.txt\n", len(missing[arch]), arch, suffix)
+		}
+	}
 	return nil
 }
 
@@ -351,18 +335,6 @@ func mirrorScript(arch string, targetImages []string) error {
 	return nil
 }
 
-func getWindowsAgentImage() string {
-	tag, ok := os.LookupEnv("TAG")
-	if !ok {
-		return ""
-	}
-	repo, ok := os.LookupEnv("REPO")
-	if !ok {
-		return ""
-	}
-	return fmt.Sprintf("%s/rancher-agent:%s", repo, tag)
-}
-
 func getScript(arch, fileType string) string {
 	return scriptMap[fmt.Sprintf("%s-%s", arch, fileType)]
 }
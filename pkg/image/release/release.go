@@ -0,0 +1,150 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	kd "github.com/rancher/rancher/pkg/controllers/management/kontainerdrivermetadata"
+	img "github.com/rancher/rancher/pkg/image"
+	ext "github.com/rancher/rancher/pkg/image/external"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rke/types/kdm"
+)
+
+const winsImage = "rancher/wins"
+
+// Config selects the Rancher release whose images are resolved, and where its charts and KDM data are read from.
+type Config struct {
+	// Tag is the tag of the release, the images of the dev version are resolved for dev and head builds.
+	Tag string
+	// Repo is the image repository of the Rancher agent, which is also run on Windows nodes.
+	Repo string
+	// DataFile is the KDM data file, data.json in $HOME/bin is read if it does not exist.
+	DataFile         string
+	SystemChartsPath string
+	ChartsPath       string
+	// ImagesFromArgs are the Rancher images of the release. The rancher/wins upgrade image is only used on Windows
+	// nodes, the others only on Linux nodes.
+	ImagesFromArgs []string
+}
+
+// Images are the images of a Rancher release per OS, each also listed with the sources it comes from in the
+// "image source1,source2" format.
+type Images struct {
+	Linux             []string
+	LinuxAndSources   []string
+	Windows           []string
+	WindowsAndSources []string
+	// K8sVersions are the Kubernetes versions of the RKE system images of the release.
+	K8sVersions []string
+}
+
+// Version returns the Rancher version whose images are resolved for a release tag.
+func Version(tag string) string {
+	if !img.IsValidSemver(tag) || strings.HasPrefix(tag, "dev") || strings.HasPrefix(tag, "master") || strings.HasSuffix(tag, "-head") {
+		tag = settings.RancherVersionDev
+	}
+	return strings.TrimPrefix(tag, "v")
+}
+
+// Resolve returns the images of the Rancher release from its charts, system charts, KDM data and the Rancher images
+// given as arguments.
+func Resolve(config Config) (*Images, error) {
+	rancherVersion := Version(config.Tag)
+	data, err := loadData(config.DataFile)
+	if err != nil {
+		return nil, err
+	}
+	linuxInfo, windowsInfo := kd.GetK8sVersionInfo(
+		rancherVersion,
+		data.K8sVersionRKESystemImages,
+		data.K8sVersionServiceOptions,
+		data.K8sVersionWindowsServiceOptions,
+		data.K8sVersionInfo,
+	)
+
+	images := &Images{}
+	for k := range linuxInfo.RKESystemImages {
+		images.K8sVersions = append(images.K8sVersions, k)
+	}
+	sort.Strings(images.K8sVersions)
+
+	// RKE2 Provisioning will only be supported on Kubernetes v1.21+. In addition, only RKE2
+	// releases corresponding to Kubernetes v1.21+ include the "rke2-images-all.linux-amd64.txt" file that we need.
+	k8sVersion1_21_0 := &semver.Version{Major: 1, Minor: 21, Patch: 0}
+	externalLinuxImages := make(map[string][]string)
+	k3sUpgradeImages, err := ext.GetExternalImages(rancherVersion, data.K3S, ext.K3S, k8sVersion1_21_0, img.Linux)
+	if err != nil {
+		return nil, err
+	}
+	if k3sUpgradeImages != nil {
+		externalLinuxImages["k3sUpgrade"] = k3sUpgradeImages
+	}
+	rke2AllImages, err := ext.GetExternalImages(rancherVersion, data.RKE2, ext.RKE2, k8sVersion1_21_0, img.Linux)
+	if err != nil {
+		return nil, err
+	}
+	if rke2AllImages != nil {
+		externalLinuxImages["rke2All"] = rke2AllImages
+	}
+
+	linuxImagesFromArgs, windowsImagesFromArgs, err := splitImagesFromArgs(config.ImagesFromArgs)
+	if err != nil {
+		return nil, err
+	}
+	if config.Tag != "" && config.Repo != "" {
+		windowsImagesFromArgs = append(windowsImagesFromArgs, fmt.Sprintf("%s/rancher-agent:%s", config.Repo, config.Tag))
+	}
+
+	exportConfig := img.ExportConfig{
+		SystemChartsPath: config.SystemChartsPath,
+		ChartsPath:       config.ChartsPath,
+		OsType:           img.Linux,
+		RancherVersion:   rancherVersion,
+	}
+	images.Linux, images.LinuxAndSources, err = img.GetImages(exportConfig, externalLinuxImages, linuxImagesFromArgs, linuxInfo.RKESystemImages)
+	if err != nil {
+		return nil, err
+	}
+
+	exportConfig.OsType = img.Windows
+	images.Windows, images.WindowsAndSources, err = img.GetImages(exportConfig, nil, windowsImagesFromArgs, windowsInfo.RKESystemImages)
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// splitImagesFromArgs splits the Rancher images given as arguments into the ones used on Linux nodes and the
+// rancher/wins upgrade image used on Windows nodes.
+func splitImagesFromArgs(imagesFromArgs []string) ([]string, []string, error) {
+	var linux, windows []string
+	for _, image := range imagesFromArgs {
+		if strings.SplitN(image, ":", 2)[0] == winsImage {
+			windows = append(windows, image)
+		} else {
+			linux = append(linux, image)
+		}
+	}
+	if len(windows) == 0 {
+		return nil, nil, errors.New("rancher/wins upgrade image not found")
+	}
+	return linux, windows, nil
+}
+
+func loadData(dataFile string) (kdm.Data, error) {
+	b, err := ioutil.ReadFile(dataFile)
+	if os.IsNotExist(err) {
+		b, err = ioutil.ReadFile(filepath.Join(os.Getenv("HOME"), "bin", "data.json"))
+	}
+	if err != nil {
+		return kdm.Data{}, err
+	}
+	return kdm.FromData(b)
+}
@@ -0,0 +1,42 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSplitsImagesByOS(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(dataFile, []byte("{}"), 0600))
+	config := Config{
+		Tag:            "v2.7.2",
+		Repo:           "rancher",
+		DataFile:       dataFile,
+		ImagesFromArgs: []string{"rancher/wins:v0.4.11", "rancher/rancher:v2.7.2", "rancher/rancher-agent:v2.7.2"},
+	}
+
+	images, err := Resolve(config)
+	require.NoError(t, err)
+	assert.Contains(t, images.Linux, "rancher/rancher:v2.7.2")
+	assert.Contains(t, images.Linux, "rancher/rancher-agent:v2.7.2")
+	assert.NotContains(t, images.Linux, "rancher/wins:v0.4.11")
+	assert.Contains(t, images.Windows, "rancher/wins:v0.4.11")
+	assert.Contains(t, images.Windows, "rancher/rancher-agent:v2.7.2")
+	assert.NotContains(t, images.Windows, "rancher/rancher:v2.7.2")
+
+	config.ImagesFromArgs = []string{"rancher/rancher:v2.7.2", "rancher/windows-agent:v1"}
+	_, err = Resolve(config)
+	assert.Error(t, err)
+}
+
+func TestVersion(t *testing.T) {
+	assert.Equal(t, "2.7.2", Version("v2.7.2"))
+	assert.Equal(t, "2.7.2-rc1", Version("v2.7.2-rc1"))
+	for _, tag := range []string{"", "dev", "master-head", "v2.7-head", "not-a-version"} {
+		assert.Equal(t, Version("dev"), Version(tag), tag)
+	}
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pborman/uuid"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// RequestIDHeader carries the ID of an API request, set by the client or assigned by Middleware.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds the request IDs accepted from the clients, as they end up in every log entry.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// Middleware assigns an ID to every API request, keeping the one set by the client if any, and returns it in the
+// response so that the logs of a request can be found from it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewRandom().String()
+		}
+		rw.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id)))
+	})
+}
+
+// RequestID returns the ID of the API request of the context, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns an entry of the standard logger with the fields of the API request of the context: its ID and,
+// once the request is authenticated, its user.
+func FromContext(ctx context.Context) *logrus.Entry {
+	fields := logrus.Fields{}
+	if id := RequestID(ctx); id != "" {
+		fields[RequestIDField] = id
+	}
+	if user, ok := request.UserFrom(ctx); ok {
+		fields[UserField] = user.GetName()
+	}
+	return logrus.WithFields(fields)
+}
+
+// ForController returns an entry of the standard logger with the fields of a controller. The cluster is empty for the
+// controllers of the management cluster.
+func ForController(cluster, controller string) *logrus.Entry {
+	return logrus.WithFields(ControllerFields(cluster, controller))
+}
+
+// ControllerFields returns the fields of a controller, for the entries of the loggers of the components.
+func ControllerFields(cluster, controller string) logrus.Fields {
+	fields := logrus.Fields{
+		ControllerField: controller,
+	}
+	if cluster != "" {
+		fields[ClusterField] = cluster
+	}
+	return fields
+}
@@ -0,0 +1,142 @@
+// Package logging configures the logging of Rancher: the structured fields its entries carry, and the log levels of
+// the standard logger and of the loggers of its components, which can be changed at runtime.
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ComponentLevel is the log level of the loggers of the components under a component, see Logger. A component is a
+// package path, or a suffix of one made of whole path elements, such as pkg/controllers/provisioningv2/rke2/planner or
+// rke2/planner. It applies to the packages under it as well.
+type ComponentLevel struct {
+	Component string
+	Level     logrus.Level
+}
+
+type config struct {
+	base       logrus.Level
+	components []ComponentLevel
+}
+
+var (
+	lock    sync.Mutex
+	current atomic.Value
+)
+
+func init() {
+	current.Store(&config{base: logrus.GetLevel()})
+}
+
+// ParseLevels parses a comma separated list of component=level pairs, such as
+// "provisioningv2/rke2/planner=debug,pkg/auth=trace".
+func ParseLevels(spec string) ([]ComponentLevel, error) {
+	var result []ComponentLevel
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid component log level %q, expected component=level", pair)
+		}
+		component := strings.Trim(strings.TrimSpace(parts[0]), "/")
+		if component == "" {
+			return nil, fmt.Errorf("invalid component log level %q, component is empty", pair)
+		}
+		level, err := logrus.ParseLevel(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid component log level %q: %w", pair, err)
+		}
+		result = append(result, ComponentLevel{Component: component, Level: level})
+	}
+	return result, nil
+}
+
+// SetLevel sets the level of the components without a level of their own.
+func SetLevel(level logrus.Level) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	cfg := *current.Load().(*config)
+	cfg.base = level
+	apply(&cfg)
+}
+
+// GetLevel returns the level of the components without a level of their own.
+func GetLevel() logrus.Level {
+	return current.Load().(*config).base
+}
+
+// SetComponentLevels replaces the levels of the components with the ones in spec, in the format of ParseLevels.
+func SetComponentLevels(spec string) error {
+	components, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	cfg := *current.Load().(*config)
+	cfg.components = components
+	apply(&cfg)
+	for _, component := range components {
+		if !registered(component.Component) {
+			logrus.Warnf("No component logger is registered under %s, its log level %s has no effect", component.Component, component.Level)
+		}
+	}
+	return nil
+}
+
+// registered returns whether the logger of a component is under a component. It must be called with lock held.
+func registered(component string) bool {
+	for pkg := range loggers {
+		if strings.Contains("/"+pkg+"/", "/"+component+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetComponentLevels returns the levels of the components.
+func GetComponentLevels() []ComponentLevel {
+	return append([]ComponentLevel(nil), current.Load().(*config).components...)
+}
+
+// apply stores the configuration and sets the level of the standard logger to the base level, and the one of the
+// loggers of the components to the level of their component. It must be called with lock held.
+func apply(cfg *config) {
+	current.Store(cfg)
+	logrus.SetLevel(cfg.base)
+	for component, logger := range loggers {
+		logger.SetLevel(cfg.level(component))
+	}
+}
+
+// level returns the level of the logger of a package: the one of the component closest to it, such as rke2/planner rather than
+// pkg/controllers for pkg/controllers/provisioningv2/rke2/planner, or the base level if no component matches.
+func (c *config) level(pkg string) logrus.Level {
+	if len(c.components) == 0 || pkg == "" {
+		return c.base
+	}
+
+	path := "/" + pkg + "/"
+	level, closest := c.base, -1
+	for _, component := range c.components {
+		index := strings.LastIndex(path, "/"+component.Component+"/")
+		if index < 0 {
+			continue
+		}
+		if end := index + len(component.Component); end > closest {
+			level, closest = component.Level, end
+		}
+	}
+	return level
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ComponentField is the component of the logger that logged the entry.
+	ComponentField = "component"
+	// ClusterField is the cluster the entry relates to.
+	ClusterField = "cluster"
+	// ControllerField is the controller that logged the entry.
+	ControllerField = "controller"
+	// RequestIDField is the ID of the API request the entry relates to.
+	RequestIDField = "requestID"
+	// UserField is the user who sent the API request the entry relates to.
+	UserField = "user"
+)
+
+// loggers are the loggers of the components returned by Logger, by component. They are guarded by lock.
+var loggers = map[string]*logrus.Logger{}
+
+// Logger returns an entry of the logger of the packages under component, such as pkg/provisioningv2/rke2/planner, with
+// the ComponentField. The logger writes like the standard logger but at the level of the component, so that the calls
+// below that level return before building the entry, like the ones of the standard logger below the base level.
+func Logger(component string) *logrus.Entry {
+	component = strings.Trim(component, "/")
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	logger, ok := loggers[component]
+	if !ok {
+		logger = &logrus.Logger{
+			Out:       standardOut{},
+			Formatter: standardFormatter{},
+			Hooks:     logrus.StandardLogger().Hooks,
+			Level:     current.Load().(*config).level(component),
+		}
+		loggers[component] = logger
+	}
+	return logger.WithField(ComponentField, component)
+}
+
+// standardOut writes to the output of the standard logger, which is set once the loggers of the components exist.
+type standardOut struct{}
+
+func (standardOut) Write(p []byte) (int, error) {
+	return logrus.StandardLogger().Out.Write(p)
+}
+
+// standardFormatter formats with the formatter of the standard logger, which is set once the loggers of the
+// components exist.
+type standardFormatter struct{}
+
+func (standardFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return logrus.StandardLogger().Formatter.Format(entry)
+}
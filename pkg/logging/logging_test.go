@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels(" auth=trace, /provisioningv2/rke2/planner/=debug,,")
+	require.NoError(t, err)
+	assert.Equal(t, []ComponentLevel{
+		{Component: "auth", Level: logrus.TraceLevel},
+		{Component: "provisioningv2/rke2/planner", Level: logrus.DebugLevel},
+	}, levels)
+
+	levels, err = ParseLevels("")
+	require.NoError(t, err)
+	assert.Empty(t, levels)
+
+	for _, spec := range []string{"planner", "=debug", "planner=verbose"} {
+		_, err := ParseLevels(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestConfigLevel(t *testing.T) {
+	components, err := ParseLevels("pkg/controllers=warn,rke2/planner=debug")
+	require.NoError(t, err)
+	cfg := &config{base: logrus.InfoLevel, components: components}
+
+	assert.Equal(t, logrus.DebugLevel, cfg.level("github.com/rancher/rancher/pkg/controllers/provisioningv2/rke2/planner"))
+	assert.Equal(t, logrus.DebugLevel, cfg.level("github.com/rancher/rancher/pkg/controllers/provisioningv2/rke2/planner/sub"))
+	assert.Equal(t, logrus.WarnLevel, cfg.level("github.com/rancher/rancher/pkg/controllers/management/auth"))
+	assert.Equal(t, logrus.InfoLevel, cfg.level("github.com/rancher/rancher/pkg/controllers2"))
+	assert.Equal(t, logrus.InfoLevel, cfg.level("github.com/rancher/rancher/pkg/provisioningv2/rke2planner"))
+	assert.Equal(t, logrus.InfoLevel, cfg.level(""))
+}
+
+// setupLogger sets the formatter of the standard logger, writing to the returned buffer until the test ends.
+func setupLogger(t *testing.T, formatter logrus.Formatter) *bytes.Buffer {
+	logger := logrus.StandardLogger()
+	out, previousFormatter, previousLevel := logger.Out, logger.Formatter, GetLevel()
+	t.Cleanup(func() {
+		logger.SetOutput(out)
+		logger.SetFormatter(previousFormatter)
+		SetComponentLevels("")
+		SetLevel(previousLevel)
+	})
+
+	buf := &bytes.Buffer{}
+	logger.SetOutput(buf)
+	logger.SetFormatter(formatter)
+	SetLevel(logrus.InfoLevel)
+	return buf
+}
+
+func TestLoggerComponentLevels(t *testing.T) {
+	buf := setupLogger(t, &logrus.TextFormatter{DisableTimestamp: true})
+	logger := Logger("/pkg/logging/test/")
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, SetComponentLevels("logging/test=debug"))
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel(), "the standard logger must keep the base level")
+	logger.Debug("shown")
+	logger.Trace("hidden")
+	logrus.Debug("hidden")
+	assert.Contains(t, buf.String(), "shown")
+	assert.Contains(t, buf.String(), ComponentField+"=pkg/logging/test")
+	assert.NotContains(t, buf.String(), "hidden")
+
+	buf.Reset()
+	require.NoError(t, SetComponentLevels("pkg/other=debug,pkg/logging=error"))
+	logger.Debug("hidden")
+	logger.Warn("hidden")
+	logger.Error("shown")
+	assert.Contains(t, buf.String(), "shown")
+	assert.NotContains(t, buf.String(), "hidden")
+
+	buf.Reset()
+	require.NoError(t, SetComponentLevels("logging/test=debug,pkg/unknown=debug"))
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), "No component logger is registered under pkg/unknown")
+
+	buf.Reset()
+	require.NoError(t, SetComponentLevels(""))
+	SetLevel(logrus.DebugLevel)
+	logger.Debug("shown")
+	logger.Trace("hidden")
+	assert.Contains(t, buf.String(), "shown")
+	assert.NotContains(t, buf.String(), "hidden")
+}
+
+func TestLoggerJSON(t *testing.T) {
+	buf := setupLogger(t, &logrus.JSONFormatter{})
+
+	Logger("pkg/logging/test").WithFields(ControllerFields("c-abc", "nodes")).WithField(RequestIDField, "r-1").Info("synced")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "synced", entry["msg"])
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "pkg/logging/test", entry[ComponentField])
+	assert.Equal(t, "c-abc", entry[ClusterField])
+	assert.Equal(t, "nodes", entry[ControllerField])
+	assert.Equal(t, "r-1", entry[RequestIDField])
+}
+
+func TestMiddleware(t *testing.T) {
+	var entry *logrus.Entry
+	handler := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ctx := request.WithUser(req.Context(), &user.DefaultInfo{Name: "u-abc"})
+		entry = FromContext(ctx)
+	}))
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v3/clusters", nil)
+	req.Header.Set(RequestIDHeader, "client-id")
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, "client-id", rw.Header().Get(RequestIDHeader))
+	assert.Equal(t, "client-id", entry.Data[RequestIDField])
+	assert.Equal(t, "u-abc", entry.Data[UserField])
+
+	rw = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/v3/clusters", nil)
+	req.Header.Set(RequestIDHeader, strings.Repeat("x", maxRequestIDLength+1))
+	handler.ServeHTTP(rw, req)
+	id := rw.Header().Get(RequestIDHeader)
+	assert.Len(t, id, 36, "overlong request IDs must be replaced")
+	assert.Equal(t, id, entry.Data[RequestIDField])
+}
@@ -5,7 +5,9 @@
 	"net"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/rancher/rancher/pkg/logging"
 	"github.com/sirupsen/logrus"
 )
 
@@ -48,10 +50,14 @@ func (s *Server) ListenAndServe() error {
 
 func (s *Server) loglevel(rw http.ResponseWriter, req *http.Request) {
 	// curl -X POST -d "level=debug" localhost:12345/v1/loglevel
+	// curl -X POST -d "components=provisioningv2/rke2/planner=debug" localhost:12345/v1/loglevel
 	logrus.Debugf("Received loglevel request")
 	if req.Method == http.MethodGet {
-		level := logrus.GetLevel().String()
+		level := logging.GetLevel().String()
 		rw.Write([]byte(fmt.Sprintf("%s\n", level)))
+		for _, component := range logging.GetComponentLevels() {
+			rw.Write([]byte(fmt.Sprintf("%s=%s\n", component.Component, component.Level)))
+		}
 	}
 
 	if req.Method == http.MethodPost {
@@ -59,12 +65,21 @@ func (s *Server) loglevel(rw http.ResponseWriter, req *http.Request) {
 			rw.WriteHeader(http.StatusInternalServerError)
 			rw.Write([]byte(fmt.Sprintf("Failed to parse form: %v\n", err)))
 		}
+		if components, ok := req.Form["components"]; ok {
+			if err := logging.SetComponentLevels(strings.Join(components, ",")); err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				rw.Write([]byte(fmt.Sprintf("Failed to parse component loglevels: %v\n", err)))
+			} else {
+				rw.Write([]byte("OK\n"))
+			}
+			return
+		}
 		level, err := logrus.ParseLevel(req.Form.Get("level"))
 		if err != nil {
 			rw.WriteHeader(http.StatusInternalServerError)
 			rw.Write([]byte(fmt.Sprintf("Failed to parse loglevel: %v\n", err)))
 		} else {
-			logrus.SetLevel(level)
+			logging.SetLevel(level)
 			rw.Write([]byte("OK\n"))
 		}
 	}
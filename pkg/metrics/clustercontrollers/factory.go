@@ -0,0 +1,185 @@
+package clustercontrollers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/lasso/pkg/controller"
+	"github.com/rancher/rancher/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Instrument wraps the controller factory of a user cluster so that the handlers registered through it and the
+// queues of its controllers are measured, labeled by cluster.
+func Instrument(cluster string, factory controller.SharedControllerFactory) controller.SharedControllerFactory {
+	return &sharedControllerFactory{
+		SharedControllerFactory: factory,
+		cluster:                 cluster,
+		controllers:             map[controller.SharedController]*sharedController{},
+	}
+}
+
+type sharedControllerFactory struct {
+	controller.SharedControllerFactory
+
+	cluster     string
+	lock        sync.Mutex
+	controllers map[controller.SharedController]*sharedController
+}
+
+func (f *sharedControllerFactory) ForObject(obj runtime.Object) (controller.SharedController, error) {
+	c, err := f.SharedControllerFactory.ForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	name := obj.GetObjectKind().GroupVersionKind()
+	if name.Kind == "" {
+		t := reflect.TypeOf(obj)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		name.Kind = t.Name()
+	}
+	return f.wrap(c, kindName(name)), nil
+}
+
+func (f *sharedControllerFactory) ForKind(gvk schema.GroupVersionKind) (controller.SharedController, error) {
+	c, err := f.SharedControllerFactory.ForKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrap(c, kindName(gvk)), nil
+}
+
+func (f *sharedControllerFactory) ForResource(gvr schema.GroupVersionResource, namespaced bool) controller.SharedController {
+	return f.wrap(f.SharedControllerFactory.ForResource(gvr, namespaced), resourceName(gvr))
+}
+
+func (f *sharedControllerFactory) ForResourceKind(gvr schema.GroupVersionResource, kind string, namespaced bool) controller.SharedController {
+	return f.wrap(f.SharedControllerFactory.ForResourceKind(gvr, kind, namespaced), kindName(gvr.GroupVersion().WithKind(kind)))
+}
+
+// wrap returns the instrumented controller of c, the same one for every call so that its queue is measured once.
+func (f *sharedControllerFactory) wrap(c controller.SharedController, kind string) controller.SharedController {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if wrapped, ok := f.controllers[c]; ok {
+		return wrapped
+	}
+	wrapped := &sharedController{
+		SharedController: c,
+		cluster:          f.cluster,
+		kind:             kind,
+		pending:          map[string]struct{}{},
+	}
+	f.controllers[c] = wrapped
+	return wrapped
+}
+
+// kindName names the kind a controller reconciles, e.g. Namespace or ClusterRoleTemplateBinding.management.cattle.io.
+func kindName(gvk schema.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return gvk.Kind
+	}
+	return fmt.Sprintf("%s.%s", gvk.Kind, gvk.Group)
+}
+
+// resourceName stands in for the kind of a controller created for a resource, e.g. configmaps.
+func resourceName(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource
+	}
+	return fmt.Sprintf("%s.%s", gvr.Resource, gvr.Group)
+}
+
+// sharedController measures the handlers of a controller and tracks the keys waiting in its queue. As the queue
+// only holds a key once, the depth is the number of distinct keys enqueued since they were last handled.
+type sharedController struct {
+	controller.SharedController
+
+	cluster string
+	kind    string
+	once    sync.Once
+	lock    sync.Mutex
+	pending map[string]struct{}
+}
+
+func (s *sharedController) RegisterHandler(ctx context.Context, name string, handler controller.SharedControllerHandler) {
+	s.once.Do(func() {
+		s.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    s.enqueued,
+			UpdateFunc: func(_, obj interface{}) { s.enqueued(obj) },
+			DeleteFunc: s.enqueued,
+		})
+		go func() {
+			<-ctx.Done()
+			deleteQueueDepth(s.cluster, s.kind)
+		}()
+	})
+
+	s.SharedController.RegisterHandler(ctx, name, controller.SharedControllerHandlerFunc(func(key string, obj runtime.Object) (runtime.Object, error) {
+		s.handled(key)
+		start := time.Now()
+		result, err := handler.OnChange(key, obj)
+		observeReconcile(s.cluster, s.kind, name, time.Since(start), err)
+		if logrus.IsLevelEnabled(logrus.TraceLevel) {
+			logging.ForController(s.cluster, name).Tracef("Reconciled %s in %v, error: %v", key, time.Since(start), err)
+		}
+		if err != nil {
+			// the key is requeued
+			s.add(key)
+		}
+		return result, err
+	}))
+}
+
+func (s *sharedController) Enqueue(namespace, name string) {
+	s.add(key(namespace, name))
+	s.SharedController.Enqueue(namespace, name)
+}
+
+func (s *sharedController) EnqueueAfter(namespace, name string, delay time.Duration) {
+	s.add(key(namespace, name))
+	s.SharedController.EnqueueAfter(namespace, name, delay)
+}
+
+func (s *sharedController) EnqueueKey(k string) {
+	s.add(k)
+	s.SharedController.EnqueueKey(k)
+}
+
+func (s *sharedController) enqueued(obj interface{}) {
+	k, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err == nil {
+		s.add(k)
+	}
+}
+
+func (s *sharedController) add(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pending[key] = struct{}{}
+	setQueueDepth(s.cluster, s.kind, len(s.pending))
+}
+
+func (s *sharedController) handled(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.pending, key)
+	setQueueDepth(s.cluster, s.kind, len(s.pending))
+}
+
+func key(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return strings.Join([]string{namespace, name}, "/")
+}
@@ -0,0 +1,87 @@
+package clustercontrollers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rancher/lasso/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeController struct {
+	controller.SharedController
+	informer cache.SharedIndexInformer
+	handlers []controller.SharedControllerHandler
+}
+
+func (f *fakeController) Informer() cache.SharedIndexInformer { return f.informer }
+func (f *fakeController) EnqueueKey(string)                   {}
+
+func (f *fakeController) RegisterHandler(_ context.Context, _ string, handler controller.SharedControllerHandler) {
+	f.handlers = append(f.handlers, handler)
+}
+
+type fakeFactory struct {
+	controller.SharedControllerFactory
+	controllers map[schema.GroupVersionResource]*fakeController
+}
+
+func (f *fakeFactory) ForResourceKind(gvr schema.GroupVersionResource, _ string, _ bool) controller.SharedController {
+	c, ok := f.controllers[gvr]
+	if !ok {
+		c = &fakeController{informer: cache.NewSharedIndexInformer(&cache.ListWatch{}, &corev1.ConfigMap{}, 0, cache.Indexers{})}
+		f.controllers[gvr] = c
+	}
+	return c
+}
+
+func TestInstrument(t *testing.T) {
+	prometheusMetrics = true
+	defer func() { prometheusMetrics = false }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := &fakeFactory{controllers: map[schema.GroupVersionResource]*fakeController{}}
+	factory := Instrument("c-1", fake)
+	gvr := corev1.SchemeGroupVersion.WithResource("configmaps")
+
+	c := factory.ForResourceKind(gvr, "ConfigMap", true)
+	assert.Same(t, c, factory.ForResourceKind(gvr, "ConfigMap", true), "controllers must be wrapped once")
+
+	c.RegisterHandler(ctx, "configmap-handler", controller.SharedControllerHandlerFunc(func(key string, obj runtime.Object) (runtime.Object, error) {
+		if key == "ns/fail" {
+			return nil, errors.New("failed")
+		}
+		return obj, nil
+	}))
+
+	c.EnqueueKey("ns/ok")
+	c.EnqueueKey("ns/fail")
+	c.EnqueueKey("ns/ok")
+	depth := queueDepth.With(prometheus.Labels{"cluster": "c-1", "kind": "ConfigMap"})
+	assert.Equal(t, 2.0, testutil.ToFloat64(depth))
+
+	handler := fake.controllers[gvr].handlers[0]
+	_, err := handler.OnChange("ns/ok", nil)
+	assert.NoError(t, err)
+	_, err = handler.OnChange("ns/fail", nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(depth), "failed keys are requeued")
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconciles.With(prometheus.Labels{"cluster": "c-1", "kind": "ConfigMap", "handler": "configmap-handler", "error": "false"})))
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconciles.With(prometheus.Labels{"cluster": "c-1", "kind": "ConfigMap", "handler": "configmap-handler", "error": "true"})))
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		return testutil.CollectAndCount(queueDepth) == 0
+	}, time.Second, 10*time.Millisecond)
+}
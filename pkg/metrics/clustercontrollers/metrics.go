@@ -0,0 +1,90 @@
+// Package clustercontrollers instruments the controllers Rancher runs against user clusters, so that their reconcile
+// rate, errors, latency and queue depth can be told apart by cluster.
+package clustercontrollers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	prometheusMetrics = false
+
+	reconciles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_controller",
+			Name:      "reconcile_total",
+			Help:      "Total count of reconciles of a user cluster controller, by the kind it reconciles and the handler",
+		},
+		[]string{"cluster", "kind", "handler", "error"},
+	)
+
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "cluster_controller",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Time taken by the reconciles of a user cluster controller, by the kind it reconciles and the handler",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"cluster", "kind", "handler"},
+	)
+
+	queueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "cluster_controller",
+			Name:      "queue_depth",
+			Help:      "Number of objects of a user cluster waiting to be reconciled, by the kind of the queue holding them",
+		},
+		[]string{"cluster", "kind"},
+	)
+
+	// Collectors are the metrics labeled by cluster, so that the metrics of deleted clusters can be garbage collected.
+	Collectors = []interface{}{
+		reconciles, reconcileDuration, queueDepth,
+	}
+)
+
+// RegisterMetrics registers the user cluster controller metrics for Prometheus.
+func RegisterMetrics() {
+	prometheusMetrics = true
+
+	prometheus.MustRegister(reconciles)
+	prometheus.MustRegister(reconcileDuration)
+	prometheus.MustRegister(queueDepth)
+}
+
+func observeReconcile(cluster, kind, handler string, duration time.Duration, err error) {
+	if prometheusMetrics {
+		reconciles.With(prometheus.Labels{
+			"cluster": cluster,
+			"kind":    kind,
+			"handler": handler,
+			"error":   strconv.FormatBool(err != nil),
+		}).Inc()
+		reconcileDuration.With(prometheus.Labels{
+			"cluster": cluster,
+			"kind":    kind,
+			"handler": handler,
+		}).Observe(duration.Seconds())
+	}
+}
+
+func setQueueDepth(cluster, kind string, depth int) {
+	if prometheusMetrics {
+		queueDepth.With(prometheus.Labels{
+			"cluster": cluster,
+			"kind":    kind,
+		}).Set(float64(depth))
+	}
+}
+
+func deleteQueueDepth(cluster, kind string) {
+	if prometheusMetrics {
+		queueDepth.Delete(prometheus.Labels{
+			"cluster": cluster,
+			"kind":    kind,
+		})
+	}
+}
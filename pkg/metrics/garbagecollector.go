@@ -6,9 +6,13 @@
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics/clustercontrollers"
+	"github.com/rancher/rancher/pkg/metrics/projectquota"
 	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/tunnelserver"
 	rm "github.com/rancher/remotedialer/metrics"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/labels"
@@ -83,8 +87,12 @@ func (gc *metricGarbageCollector) metricGarbageCollection() {
 	}
 
 	buildObservedLabelMaps(targetMetricsByNameForClientKey, "clientkey", observedLabelsMap)
+	buildObservedLabelMaps(tunnelserver.SessionCollectors, "clientkey", observedLabelsMap)
 	buildObservedLabelMaps(targetMetricsByIPForPeer, "peer", observedLabelsMap)
 	buildObservedLabelMaps([]interface{}{clusterOwner}, "cluster", observedLabelsMap)
+	buildObservedLabelMaps(clustercontrollers.Collectors, "cluster", observedLabelsMap)
+	buildObservedLabelMaps(projectquota.Collectors, "cluster", observedLabelsMap)
+	buildObservedLabelMaps(circuit.Collectors, "cluster", observedLabelsMap)
 
 	removedCount := removeMetricsForDeletedResource(observedLabelsMap, observedResourceNames)
 
@@ -152,6 +160,12 @@ func removeMetricsForDeletedResource(observedMetrics map[string]map[interface{}]
 					} else {
 						logrus.Errorf("[metrics-garbage-collector] failed to delete %T metrics related to %s: %v", v, m, label)
 					}
+				case *prometheus.HistogramVec:
+					if v.Delete(label) {
+						removedCount++
+					} else {
+						logrus.Errorf("[metrics-garbage-collector] failed to delete %T metrics related to %s: %v", v, m, label)
+					}
 				default:
 					logrus.Errorf("[metrics-garbage-collector] saw unknown Metric definition %T", v)
 				}
@@ -12,7 +12,11 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/rancher/pkg/auth/util"
 	"github.com/rancher/rancher/pkg/clustermanager"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
+	"github.com/rancher/rancher/pkg/metrics/clustercontrollers"
+	"github.com/rancher/rancher/pkg/metrics/projectquota"
 	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/tunnelserver"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/wrangler/pkg/ticker"
 	authV1 "k8s.io/api/authorization/v1"
@@ -226,6 +230,14 @@ func Register(ctx context.Context, scaledContext *config.ScaledContext) {
 	prometheus.MustRegister(numNodes)
 	prometheus.MustRegister(numCores)
 
+	// tunnel session metrics
+	tunnelserver.RegisterMetrics()
+
+	// user cluster controller metrics
+	clustercontrollers.RegisterMetrics()
+	projectquota.RegisterMetrics()
+	circuit.RegisterMetrics()
+
 	gc := metricGarbageCollector{
 		clusterLister:  scaledContext.Management.Clusters("").Controller().Lister(),
 		nodeLister:     scaledContext.Management.Nodes("").Controller().Lister(),
@@ -0,0 +1,128 @@
+// Package projectquota exposes the quotas of projects and of their namespaces, with how much of them is allocated and
+// used, so that their usage can be graphed and alerted on over time.
+package projectquota
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	prometheusMetrics = false
+
+	quotaLabels = []string{"cluster", "project", "namespace", "resource"}
+
+	limit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "project_resource_quota",
+			Name:      "limit",
+			Help:      "Quota of a resource in a project, or in one of its namespaces when the namespace label is set",
+		},
+		quotaLabels,
+	)
+
+	allocated = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "project_resource_quota",
+			Name:      "allocated",
+			Help:      "Amount of the quota of a resource in a project that is allocated to its namespaces",
+		},
+		quotaLabels,
+	)
+
+	used = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "project_resource_quota",
+			Name:      "used",
+			Help:      "Amount of a resource used in a project, or in one of its namespaces when the namespace label is set",
+		},
+		quotaLabels,
+	)
+
+	// Collectors are the metrics labeled by cluster, so that the metrics of deleted clusters can be garbage collected.
+	Collectors = []interface{}{
+		limit, allocated, used,
+	}
+
+	lock sync.Mutex
+	// series are the labels of the metrics last set for each project, so that the ones no longer set can be deleted.
+	series = map[string]map[*prometheus.GaugeVec][]prometheus.Labels{}
+)
+
+// Usage is the quota of a project, or of one of its namespaces when Namespace is set.
+type Usage struct {
+	Namespace string
+	Limit     corev1.ResourceList
+	Allocated corev1.ResourceList
+	Used      corev1.ResourceList
+}
+
+// RegisterMetrics registers the project quota metrics for Prometheus.
+func RegisterMetrics() {
+	prometheusMetrics = true
+
+	prometheus.MustRegister(limit)
+	prometheus.MustRegister(allocated)
+	prometheus.MustRegister(used)
+}
+
+// SetUsage replaces the metrics of a project with usages.
+func SetUsage(cluster, project string, usages []Usage) {
+	if !prometheusMetrics {
+		return
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	set := map[*prometheus.GaugeVec][]prometheus.Labels{}
+	for _, usage := range usages {
+		setGauges(set, limit, cluster, project, usage.Namespace, usage.Limit)
+		setGauges(set, allocated, cluster, project, usage.Namespace, usage.Allocated)
+		setGauges(set, used, cluster, project, usage.Namespace, usage.Used)
+	}
+
+	key := cluster + ":" + project
+	for gauge, labels := range series[key] {
+		for _, l := range labels {
+			if !containsLabels(set[gauge], l) {
+				gauge.Delete(l)
+			}
+		}
+	}
+	if len(set) == 0 {
+		delete(series, key)
+		return
+	}
+	series[key] = set
+}
+
+// DeleteUsage deletes the metrics of a project.
+func DeleteUsage(cluster, project string) {
+	SetUsage(cluster, project, nil)
+}
+
+func setGauges(set map[*prometheus.GaugeVec][]prometheus.Labels, gauge *prometheus.GaugeVec, cluster, project, namespace string,
+	resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		labels := prometheus.Labels{
+			"cluster":   cluster,
+			"project":   project,
+			"namespace": namespace,
+			"resource":  string(name),
+		}
+		gauge.With(labels).Set(quantity.AsApproximateFloat64())
+		set[gauge] = append(set[gauge], labels)
+	}
+}
+
+func containsLabels(set []prometheus.Labels, labels prometheus.Labels) bool {
+	for _, l := range set {
+		if l["namespace"] == labels["namespace"] && l["resource"] == labels["resource"] {
+			return true
+		}
+	}
+	return false
+}
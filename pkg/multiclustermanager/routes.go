@@ -30,8 +30,12 @@
 	"github.com/rancher/rancher/pkg/multiclustermanager/whitelist"
 	"github.com/rancher/rancher/pkg/pipeline/hooks"
 	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/rbac/bindingrequest"
+	"github.com/rancher/rancher/pkg/rbac/effective"
 	"github.com/rancher/rancher/pkg/rkenodeconfigserver"
 	"github.com/rancher/rancher/pkg/telemetry"
+	"github.com/rancher/rancher/pkg/tracing"
+	"github.com/rancher/rancher/pkg/tunnelserver"
 	"github.com/rancher/rancher/pkg/tunnelserver/mcmauthorizer"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/steve/pkg/auth"
@@ -40,7 +44,7 @@
 func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcmauthorizer.Authorizer, scaledContext *config.ScaledContext, clusterManager *clustermanager.Manager) (func(http.Handler) http.Handler, error) {
 	var (
 		k8sProxy             = k8sProxyPkg.New(scaledContext, scaledContext.Dialer, clusterManager)
-		connectHandler       = scaledContext.Dialer.(*rancherdialer.Factory).TunnelServer
+		connectHandler       = scaledContext.Wrangler.TunnelSessions.Handler(scaledContext.Dialer.(*rancherdialer.Factory).TunnelServer)
 		connectConfigHandler = rkenodeconfigserver.Handler(tunnelAuthorizer, scaledContext)
 		clusterImport        = clusterregistrationtokens.ClusterImport{Clusters: scaledContext.Management.Clusters("")}
 	)
@@ -78,8 +82,10 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	unauthed.Handle("/v3/connect/config", connectConfigHandler)
 	unauthed.Handle("/v3/connect", connectHandler)
 	unauthed.Handle("/v3/connect/register", connectHandler)
+	unauthed.Handle("/v3/connect/probe", http.HandlerFunc(tunnelAuthorizer.ProbeHandler))
 	unauthed.Handle("/v3/import/{token}_{clusterId}.yaml", http.HandlerFunc(clusterImport.ClusterImportHandler))
 	unauthed.Handle("/v3/settings/cacerts", managementAPI).MatcherFunc(onlyGet)
+	unauthed.Handle("/v3/settings/next-cacerts", managementAPI).MatcherFunc(onlyGet)
 	unauthed.Handle("/v3/settings/first-login", managementAPI).MatcherFunc(onlyGet)
 	unauthed.Handle("/v3/settings/ui-banners", managementAPI).MatcherFunc(onlyGet)
 	unauthed.Handle("/v3/settings/ui-issues", managementAPI).MatcherFunc(onlyGet)
@@ -95,7 +101,7 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	// Authenticated routes
 	authed := mux.NewRouter()
 	authed.UseEncodedPath()
-	impersonatingAuth := auth.ToMiddleware(requests.NewImpersonatingAuth(sar.NewSubjectAccessReview(clusterManager)))
+	impersonatingAuth := auth.Middleware(tracing.Phase("auth.impersonate", auth.ToMiddleware(requests.NewImpersonatingAuth(sar.NewSubjectAccessReview(clusterManager)))))
 	accessControlHandler := rbac.NewAccessControlHandler()
 
 	authed.Use(mux.MiddlewareFunc(impersonatingAuth))
@@ -109,6 +115,12 @@ func router(ctx context.Context, localClusterEnabled bool, tunnelAuthorizer *mcm
 	authed.Path("/v3/tokenreview").Methods(http.MethodPost).Handler(&webhook.TokenReviewer{})
 	authed.Path("/metrics/{clusterID}").Handler(metricsHandler)
 	authed.Path(supportconfigs.Endpoint).Handler(&supportConfigGenerator)
+	authed.Path(tunnelserver.SessionsEndpoint).Methods(http.MethodGet).Handler(tunnelserver.NewSessionsHandler(scaledContext.Wrangler.TunnelSessions, scaledContext.PeerManager, scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews()))
+	authed.Path(tunnelserver.SessionsEndpoint).Methods(http.MethodPost).Queries("action", "rebalance").Handler(tunnelserver.NewRebalanceHandler(scaledContext.Wrangler.TunnelSteering, scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews()))
+	permissionsResolver := effective.NewResolver(scaledContext.Wrangler.Mgmt, scaledContext.Wrangler.RBAC)
+	authed.Path(effective.Endpoint).Methods(http.MethodGet).Handler(effective.NewHandler(permissionsResolver, scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews()))
+	authed.Path(effective.PreviewEndpoint).Methods(http.MethodPost).Handler(effective.NewPreviewHandler(permissionsResolver, scaledContext.K8sClient.AuthorizationV1().SubjectAccessReviews()))
+	authed.Path(bindingrequest.Endpoint).Methods(http.MethodPost).Handler(bindingrequest.NewHandler(scaledContext.Wrangler.Mgmt.RoleTemplateBindingRequest(), permissionsResolver))
 	authed.PathPrefix("/k8s/clusters/").Handler(k8sProxy)
 	authed.PathPrefix("/meta/proxy").Handler(metaProxy)
 	authed.PathPrefix("/v1-telemetry").Handler(telemetry.NewProxy())
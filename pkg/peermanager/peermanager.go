@@ -1,5 +1,10 @@
 package peermanager
 
+import (
+	"hash/crc32"
+	"math"
+)
+
 type Peers struct {
 	SelfID string
 	IDs    []string
@@ -12,4 +17,15 @@ type PeerManager interface {
 	Leader()
 	AddListener(l chan<- Peers)
 	RemoveListener(l chan<- Peers)
+	Peers() Peers
+}
+
+// Owner returns which of the Rancher servers owns the controllers of the cluster with the given UID. The ids must be
+// sorted and include the ID of the calling server, so that every server computes the same owner.
+func Owner(ids []string, clusterUID string) string {
+	ck := crc32.ChecksumIEEE([]byte(clusterUID))
+	if ck == math.MaxUint32 {
+		ck--
+	}
+	return ids[int(ck)*len(ids)/math.MaxUint32]
 }
@@ -26,7 +26,6 @@
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/rancher/wrangler/pkg/kv"
 	"github.com/rancher/wrangler/pkg/yaml"
-	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -121,14 +120,14 @@ func addRoleConfig(config map[string]interface{}, controlPlane *rkev1.RKEControl
 	// If this is a control-plane node, then we need to set arguments/(and for RKE2, volume mounts) to allow probes
 	// to run.
 	if isControlPlane(entry) {
-		logrus.Debug("addRoleConfig rendering arguments and mounts for kube-controller-manager")
+		logger.Debug("addRoleConfig rendering arguments and mounts for kube-controller-manager")
 		certDirArg, certDirMount := renderArgAndMount(config[KubeControllerManagerArg], config[KubeControllerManagerExtraMount], runtime, DefaultKubeControllerManagerDefaultSecurePort, DefaultKubeControllerManagerCertDir)
 		config[KubeControllerManagerArg] = certDirArg
 		if runtime == rke2.RuntimeRKE2 {
 			config[KubeControllerManagerExtraMount] = certDirMount
 		}
 
-		logrus.Debug("addRoleConfig rendering arguments and mounts for kube-scheduler")
+		logger.Debug("addRoleConfig rendering arguments and mounts for kube-scheduler")
 		certDirArg, certDirMount = renderArgAndMount(config[KubeSchedulerArg], config[KubeSchedulerExtraMount], runtime, DefaultKubeSchedulerDefaultSecurePort, DefaultKubeSchedulerCertDir)
 		config[KubeSchedulerArg] = certDirArg
 		if runtime == rke2.RuntimeRKE2 {
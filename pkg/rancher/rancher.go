@@ -30,10 +30,12 @@
 	dashboarddata "github.com/rancher/rancher/pkg/data/dashboard"
 	"github.com/rancher/rancher/pkg/features"
 	mgmntv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/logging"
 	"github.com/rancher/rancher/pkg/multiclustermanager"
 	"github.com/rancher/rancher/pkg/namespace"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/tls"
+	"github.com/rancher/rancher/pkg/tracing"
 	"github.com/rancher/rancher/pkg/ui"
 	"github.com/rancher/rancher/pkg/websocket"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -77,6 +79,9 @@ type Options struct {
 	AuditLevel        int
 	Features          string
 	ClusterRegistry   string
+	// TracingEndpoint is the OTLP/HTTP endpoint of the collector the traces are exported to, tracing is off if empty
+	TracingEndpoint    string
+	TracingSampleRatio float64
 }
 
 type Rancher struct {
@@ -99,6 +104,10 @@ func New(ctx context.Context, clientConfg clientcmd.ClientConfig, opts *Options)
 		opts = &Options{}
 	}
 
+	if err := tracing.Setup(ctx, opts.TracingEndpoint, opts.TracingSampleRatio); err != nil {
+		return nil, err
+	}
+
 	restConfig, err := clientConfg.ClientConfig()
 	if err != nil {
 		return nil, err
@@ -198,6 +207,7 @@ func New(ctx context.Context, clientConfg clientcmd.ClientConfig, opts *Options)
 		wranglerContext.Mgmt.Cluster().Cache(),
 		localClusterEnabled(opts),
 		steve,
+		wranglerContext.ClusterProxyGuard,
 	)
 	if err != nil {
 		return nil, err
@@ -217,8 +227,8 @@ func New(ctx context.Context, clientConfg clientcmd.ClientConfig, opts *Options)
 	aggregationMiddleware := aggregation.NewMiddleware(ctx, wranglerContext.Mgmt.APIService(), wranglerContext.TunnelServer)
 
 	return &Rancher{
-		Auth: authServer.Authenticator.Chain(
-			auditFilter),
+		Auth: steveauth.Middleware(tracing.Phase("auth", authServer.Authenticator.Chain(
+			auditFilter))),
 		Handler: responsewriter.Chain{
 			auth.SetXAPICattleAuthHeader,
 			responsewriter.ContentTypeOptions,
@@ -288,7 +298,7 @@ func (r *Rancher) ListenAndServe(ctx context.Context) error {
 	r.startAggregation(ctx)
 	go r.Steve.StartAggregation(ctx)
 	if err := tls.ListenAndServe(ctx, r.Wrangler.RESTConfig,
-		r.Auth(r.Handler),
+		tracing.Handler("rancher", logging.Middleware(r.Auth(r.Handler))),
 		r.opts.BindHost,
 		r.opts.HTTPSListenPort,
 		r.opts.HTTPListenPort,
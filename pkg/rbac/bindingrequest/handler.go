@@ -0,0 +1,180 @@
+// Package bindingrequest serves the approval of RoleTemplateBindingRequests.
+package bindingrequest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	managementv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/logging"
+	"github.com/rancher/rancher/pkg/rbac"
+	"github.com/rancher/rancher/pkg/rbac/effective"
+	"github.com/rancher/rancher/pkg/settings"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// Endpoint is the path a RoleTemplateBindingRequest is approved or denied at, with the approve or deny action.
+	Endpoint = "/v1/roletemplatebindingrequests/{namespace}/{name}"
+
+	ApproveAction = "approve"
+	DenyAction    = "deny"
+)
+
+// Resolver returns the subjects and grants of a user.
+type Resolver interface {
+	ForUser(userName string, filter effective.Filter) ([]rbacv1.Subject, []effective.Grant, error)
+}
+
+// Decision is the optional body of the approve and deny actions.
+type Decision struct {
+	Message string `json:"message,omitempty"`
+}
+
+type handler struct {
+	requests managementv3.RoleTemplateBindingRequestClient
+	resolver Resolver
+	now      func() time.Time
+}
+
+// NewHandler returns the handler of the actions of Endpoint. Only a global admin, or a member of the cluster or
+// project of the request through one of the BindingRequestApproverRoles, may approve or deny a pending request, and
+// never one for themselves.
+func NewHandler(requests managementv3.RoleTemplateBindingRequestClient, resolver Resolver) http.Handler {
+	return &handler{
+		requests: requests,
+		resolver: resolver,
+		now:      time.Now,
+	}
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	phase := map[string]string{
+		ApproveAction: v3.RoleTemplateBindingRequestApproved,
+		DenyAction:    v3.RoleTemplateBindingRequestDenied,
+	}[req.URL.Query().Get("action")]
+	if phase == "" {
+		util.ReturnHTTPError(rw, req, http.StatusBadRequest, "action must be approve or deny")
+		return
+	}
+	userInfo, ok := request.UserFrom(req.Context())
+	if !ok {
+		util.ReturnHTTPError(rw, req, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+	var decision Decision
+	if req.ContentLength != 0 {
+		if err := json.NewDecoder(req.Body).Decode(&decision); err != nil {
+			util.ReturnHTTPError(rw, req, http.StatusBadRequest, "invalid decision: "+err.Error())
+			return
+		}
+	}
+
+	vars := mux.Vars(req)
+	obj, err := h.requests.Get(vars["namespace"], vars["name"], metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(rw, req, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		h.fail(rw, req, err)
+		return
+	}
+	if obj.Status.Phase != v3.RoleTemplateBindingRequestPending {
+		util.ReturnHTTPError(rw, req, http.StatusConflict, "request is not pending")
+		return
+	}
+
+	subjects, grants, err := h.resolver.ForUser(userInfo.GetName(), effective.Filter{Cluster: obj.Spec.ClusterName})
+	if err != nil && !apierrors.IsNotFound(err) {
+		h.fail(rw, req, err)
+		return
+	}
+	if IsSubject(subjects, obj) {
+		util.ReturnHTTPError(rw, req, http.StatusForbidden, "users cannot decide on their own requests")
+		return
+	}
+	if !CanApprove(grants, obj) {
+		util.ReturnHTTPError(rw, req, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	obj = obj.DeepCopy()
+	obj.Status.Phase = phase
+	obj.Status.DecidedBy = userInfo.GetName()
+	obj.Status.DecidedAt = h.now().UTC().Format(time.RFC3339)
+	obj.Status.Message = decision.Message
+	if phase == v3.RoleTemplateBindingRequestApproved && obj.Spec.Duration.Duration > 0 {
+		obj.Status.ExpiresAt = h.now().Add(obj.Spec.Duration.Duration).UTC().Format(time.RFC3339)
+	}
+	obj, err = h.requests.UpdateStatus(obj)
+	if apierrors.IsConflict(err) {
+		util.ReturnHTTPError(rw, req, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		h.fail(rw, req, err)
+		return
+	}
+
+	logging.FromContext(req.Context()).Infof("Request %s/%s for role template %s was %s", obj.Namespace, obj.Name, obj.Spec.RoleTemplateName, strings.ToLower(phase))
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(obj); err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to write binding request response: %v", err)
+	}
+}
+
+func (h *handler) fail(rw http.ResponseWriter, req *http.Request, err error) {
+	logging.FromContext(req.Context()).Errorf("Failed to decide on binding request: %v", err)
+	util.ReturnHTTPError(rw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+}
+
+// IsSubject returns whether the request is for one of the subjects.
+func IsSubject(subjects []rbacv1.Subject, obj *v3.RoleTemplateBindingRequest) bool {
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.UserKind && subject.Name == obj.Spec.UserName ||
+			subject.Kind == rbacv1.GroupKind && subject.Name == obj.Spec.GroupPrincipalName {
+			return true
+		}
+	}
+	return false
+}
+
+// CanApprove returns whether the grants make their subject an approver of the request: they come from the global
+// admin role, or from one of the BindingRequestApproverRoles bound in the cluster, or project, of the request.
+func CanApprove(grants []effective.Grant, obj *v3.RoleTemplateBindingRequest) bool {
+	roles := map[string]bool{}
+	for _, role := range strings.Split(settings.BindingRequestApproverRoles.Get(), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles[role] = true
+		}
+	}
+
+	for _, grant := range grants {
+		if len(grant.Chain) < 2 {
+			continue
+		}
+		role := grant.Chain[1]
+		switch grant.Scope {
+		case effective.GlobalScope:
+			if role.Name == rbac.GlobalAdmin {
+				return true
+			}
+		case effective.ClusterScope:
+			if role.Kind == "RoleTemplate" && roles[role.Name] && grant.Cluster == obj.Spec.ClusterName {
+				return true
+			}
+		case effective.ProjectScope:
+			if role.Kind == "RoleTemplate" && roles[role.Name] && obj.Spec.ProjectName != "" && grant.Project == obj.Spec.ProjectName {
+				return true
+			}
+		}
+	}
+	return false
+}
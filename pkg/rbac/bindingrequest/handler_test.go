@@ -0,0 +1,129 @@
+package bindingrequest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	managementv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac/effective"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type fakeRequestClient struct {
+	managementv3.RoleTemplateBindingRequestClient
+	requests map[string]*v3.RoleTemplateBindingRequest
+}
+
+func (f *fakeRequestClient) Get(namespace, name string, _ metav1.GetOptions) (*v3.RoleTemplateBindingRequest, error) {
+	if obj, ok := f.requests[namespace+"/"+name]; ok {
+		return obj, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+}
+
+func (f *fakeRequestClient) UpdateStatus(obj *v3.RoleTemplateBindingRequest) (*v3.RoleTemplateBindingRequest, error) {
+	f.requests[obj.Namespace+"/"+obj.Name] = obj
+	return obj, nil
+}
+
+type fakeResolver struct {
+	subjects map[string][]rbacv1.Subject
+	grants   map[string][]effective.Grant
+}
+
+func (f *fakeResolver) ForUser(userName string, _ effective.Filter) ([]rbacv1.Subject, []effective.Grant, error) {
+	return f.subjects[userName], f.grants[userName], nil
+}
+
+func roleGrant(scope effective.Scope, cluster, project, role string) effective.Grant {
+	return effective.Grant{
+		Scope:   scope,
+		Cluster: cluster,
+		Project: project,
+		Chain:   []effective.Link{{Kind: "ClusterRoleTemplateBinding", Name: "b"}, {Kind: "RoleTemplate", Name: role}},
+	}
+}
+
+func TestCanApprove(t *testing.T) {
+	clusterRequest := &v3.RoleTemplateBindingRequest{Spec: v3.RoleTemplateBindingRequestSpec{ClusterName: "c-1"}}
+	projectRequest := &v3.RoleTemplateBindingRequest{Spec: v3.RoleTemplateBindingRequestSpec{ClusterName: "c-1", ProjectName: "c-1:p-1"}}
+	admin := effective.Grant{
+		Scope: effective.GlobalScope,
+		Chain: []effective.Link{{Kind: "GlobalRoleBinding", Name: "grb"}, {Kind: "GlobalRole", Name: "admin"}},
+	}
+
+	assert.True(t, CanApprove([]effective.Grant{admin}, clusterRequest))
+	assert.True(t, CanApprove([]effective.Grant{roleGrant(effective.ClusterScope, "c-1", "", "cluster-owner")}, clusterRequest))
+	assert.True(t, CanApprove([]effective.Grant{roleGrant(effective.ClusterScope, "c-1", "", "cluster-owner")}, projectRequest))
+	assert.True(t, CanApprove([]effective.Grant{roleGrant(effective.ProjectScope, "c-1", "c-1:p-1", "project-owner")}, projectRequest))
+	assert.False(t, CanApprove([]effective.Grant{roleGrant(effective.ProjectScope, "c-1", "c-1:p-1", "project-owner")}, clusterRequest))
+	assert.False(t, CanApprove([]effective.Grant{roleGrant(effective.ProjectScope, "c-1", "c-1:p-2", "project-owner")}, projectRequest))
+	assert.False(t, CanApprove([]effective.Grant{roleGrant(effective.ClusterScope, "c-2", "", "cluster-owner")}, clusterRequest))
+	assert.False(t, CanApprove([]effective.Grant{roleGrant(effective.ClusterScope, "c-1", "", "cluster-member")}, clusterRequest))
+	assert.False(t, CanApprove(nil, clusterRequest))
+}
+
+func TestHandler(t *testing.T) {
+	pending := func() *v3.RoleTemplateBindingRequest {
+		return &v3.RoleTemplateBindingRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "oncall", Namespace: "c-1"},
+			Spec:       v3.RoleTemplateBindingRequestSpec{UserName: "u-dev", ClusterName: "c-1", RoleTemplateName: "cluster-member", Duration: metav1.Duration{Duration: time.Hour}},
+			Status:     v3.RoleTemplateBindingRequestStatus{Phase: v3.RoleTemplateBindingRequestPending},
+		}
+	}
+	requests := &fakeRequestClient{requests: map[string]*v3.RoleTemplateBindingRequest{"c-1/oncall": pending()}}
+	resolver := &fakeResolver{
+		subjects: map[string][]rbacv1.Subject{
+			"u-dev":   {{Kind: rbacv1.UserKind, Name: "u-dev"}},
+			"u-owner": {{Kind: rbacv1.UserKind, Name: "u-owner"}},
+		},
+		grants: map[string][]effective.Grant{
+			"u-dev":   {roleGrant(effective.ClusterScope, "c-1", "", "cluster-owner")},
+			"u-owner": {roleGrant(effective.ClusterScope, "c-1", "", "cluster-owner")},
+		},
+	}
+	h := NewHandler(requests, resolver).(*handler)
+	h.now = func() time.Time { return time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC) }
+	router := mux.NewRouter()
+	router.Path(Endpoint).Methods(http.MethodPost).Handler(h)
+
+	serve := func(userName, path, body string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, serve("u-owner", "/v1/roletemplatebindingrequests/c-1/oncall?action=grant", ""))
+	assert.Equal(t, http.StatusNotFound, serve("u-owner", "/v1/roletemplatebindingrequests/c-1/missing?action=approve", ""))
+	assert.Equal(t, http.StatusForbidden, serve("u-dev", "/v1/roletemplatebindingrequests/c-1/oncall?action=approve", ""))
+	assert.Equal(t, http.StatusForbidden, serve("u-other", "/v1/roletemplatebindingrequests/c-1/oncall?action=approve", ""))
+
+	require.Equal(t, http.StatusOK, serve("u-owner", "/v1/roletemplatebindingrequests/c-1/oncall?action=approve", `{"message":"incident 42"}`))
+	obj := requests.requests["c-1/oncall"]
+	assert.Equal(t, v3.RoleTemplateBindingRequestApproved, obj.Status.Phase)
+	assert.Equal(t, "u-owner", obj.Status.DecidedBy)
+	assert.Equal(t, "2023-03-01T12:00:00Z", obj.Status.DecidedAt)
+	assert.Equal(t, "incident 42", obj.Status.Message)
+	assert.Equal(t, "2023-03-01T13:00:00Z", obj.Status.ExpiresAt)
+
+	assert.Equal(t, http.StatusConflict, serve("u-owner", "/v1/roletemplatebindingrequests/c-1/oncall?action=deny", ""))
+
+	requests.requests["c-1/oncall"] = pending()
+	require.Equal(t, http.StatusOK, serve("u-owner", "/v1/roletemplatebindingrequests/c-1/oncall?action=deny", ""))
+	assert.Equal(t, v3.RoleTemplateBindingRequestDenied, requests.requests["c-1/oncall"].Status.Phase)
+	assert.Empty(t, requests.requests["c-1/oncall"].Status.ExpiresAt)
+}
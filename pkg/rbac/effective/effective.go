@@ -0,0 +1,407 @@
+// Package effective computes the permissions the GlobalRoleBindings, ClusterRoleTemplateBindings and
+// ProjectRoleTemplateBindings grant, with the chain of bindings and roles each of them comes from.
+package effective
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	wrbacv1 "github.com/rancher/wrangler/pkg/generated/controllers/rbac/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Scope is where the rules of a grant apply.
+type Scope string
+
+const (
+	// GlobalScope is the management cluster, for the rules of GlobalRoles.
+	GlobalScope Scope = "global"
+	// ClusterScope is a downstream cluster, for the rules of the RoleTemplates of ClusterRoleTemplateBindings.
+	ClusterScope Scope = "cluster"
+	// ProjectScope is the namespaces of a project, for the rules of the RoleTemplates of ProjectRoleTemplateBindings.
+	ProjectScope Scope = "project"
+
+	// AllClusters is the cluster of the grants that apply to every downstream cluster.
+	AllClusters = "*"
+
+	clusterAdminRole = "cluster-admin"
+)
+
+// Link is one object of the chain a grant comes from.
+type Link struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Grant is the rules a role gives to the subject of a binding. Its chain starts with the binding, followed by the
+// roles leading to the one the rules are from, such as a RoleTemplate inherited by the one of the binding.
+type Grant struct {
+	Subject rbacv1.Subject      `json:"subject"`
+	Scope   Scope               `json:"scope"`
+	Cluster string              `json:"cluster,omitempty"`
+	Project string              `json:"project,omitempty"`
+	Chain   []Link              `json:"chain"`
+	Rules   []rbacv1.PolicyRule `json:"rules"`
+}
+
+// Filter limits the grants to the bindings of one cluster or project. Grants in GlobalScope are never filtered out.
+type Filter struct {
+	Cluster string
+	// Project is the ID of a project, in the clusterName:projectName format.
+	Project string
+}
+
+// Resolver computes the grants from the caches of the management cluster.
+type Resolver struct {
+	globalRoles        mgmtcontrollers.GlobalRoleCache
+	globalRoleBindings mgmtcontrollers.GlobalRoleBindingCache
+	crtbs              mgmtcontrollers.ClusterRoleTemplateBindingCache
+	prtbs              mgmtcontrollers.ProjectRoleTemplateBindingCache
+	roleTemplates      mgmtcontrollers.RoleTemplateCache
+	users              mgmtcontrollers.UserCache
+	userAttributes     mgmtcontrollers.UserAttributeCache
+	clusterRoles       wrbacv1.ClusterRoleCache
+}
+
+// NewResolver returns a Resolver reading from the caches of the controllers.
+func NewResolver(mgmt mgmtcontrollers.Interface, rbacControllers wrbacv1.Interface) *Resolver {
+	return &Resolver{
+		globalRoles:        mgmt.GlobalRole().Cache(),
+		globalRoleBindings: mgmt.GlobalRoleBinding().Cache(),
+		crtbs:              mgmt.ClusterRoleTemplateBinding().Cache(),
+		prtbs:              mgmt.ProjectRoleTemplateBinding().Cache(),
+		roleTemplates:      mgmt.RoleTemplate().Cache(),
+		users:              mgmt.User().Cache(),
+		userAttributes:     mgmt.UserAttribute().Cache(),
+		clusterRoles:       rbacControllers.ClusterRole().Cache(),
+	}
+}
+
+// principals are the names a subject may be bound by.
+type principals struct {
+	users  map[string]bool
+	groups map[string]bool
+}
+
+func (p principals) subjects() []rbacv1.Subject {
+	var result []rbacv1.Subject
+	for name := range p.users {
+		result = append(result, rbacv1.Subject{Kind: rbacv1.UserKind, Name: name})
+	}
+	for name := range p.groups {
+		result = append(result, rbacv1.Subject{Kind: rbacv1.GroupKind, Name: name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind > result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// ForUser returns the grants of a user, from the bindings of the user, of its principals and of the groups it was
+// last seen a member of, along with these subjects.
+func (r *Resolver) ForUser(userName string, filter Filter) ([]rbacv1.Subject, []Grant, error) {
+	user, err := r.users.Get(userName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := principals{
+		users:  map[string]bool{user.Name: true},
+		groups: map[string]bool{},
+	}
+	for _, id := range user.PrincipalIDs {
+		p.users[id] = true
+	}
+	attribs, err := r.userAttributes.Get(userName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	if attribs != nil {
+		for _, groups := range attribs.GroupPrincipals {
+			for _, group := range groups.Items {
+				p.groups[group.Name] = true
+			}
+		}
+	}
+
+	grants, err := r.grants(p.match, filter)
+	return p.subjects(), grants, err
+}
+
+// ForGroup returns the grants of the bindings of a group principal.
+func (r *Resolver) ForGroup(group string, filter Filter) ([]rbacv1.Subject, []Grant, error) {
+	p := principals{groups: map[string]bool{group: true}}
+	grants, err := r.grants(p.match, filter)
+	return p.subjects(), grants, err
+}
+
+// Who returns the grants allowing verb on the resource of the API group, with only the rules that allow it. The
+// resource may name a subresource, such as pods/log.
+func (r *Resolver) Who(verb, apiGroup, resource string, filter Filter) ([]Grant, error) {
+	grants, err := r.grants(func(rbacv1.Subject) bool { return true }, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Grant
+	for _, grant := range grants {
+		var rules []rbacv1.PolicyRule
+		for _, rule := range grant.Rules {
+			if Allows(rule, verb, apiGroup, resource) {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) > 0 {
+			grant.Rules = rules
+			result = append(result, grant)
+		}
+	}
+	return result, nil
+}
+
+// Allows returns whether the rule allows verb on the resource of the API group.
+func Allows(rule rbacv1.PolicyRule, verb, apiGroup, resource string) bool {
+	return contains(rule.Verbs, verb) && contains(rule.APIGroups, apiGroup) && contains(rule.Resources, resource)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == rbacv1.ResourceAll {
+			return true
+		}
+	}
+	return false
+}
+
+func (p principals) match(subject rbacv1.Subject) bool {
+	switch subject.Kind {
+	case rbacv1.UserKind:
+		return p.users[subject.Name]
+	case rbacv1.GroupKind:
+		return p.groups[subject.Name]
+	}
+	return false
+}
+
+// bindingSubjects returns the subjects a binding can match: a user is bound by its name or by its principal.
+func bindingSubjects(userName, userPrincipalName, groupName, groupPrincipalName string) []rbacv1.Subject {
+	var result []rbacv1.Subject
+	for _, name := range []string{userName, userPrincipalName} {
+		if name != "" {
+			result = append(result, rbacv1.Subject{Kind: rbacv1.UserKind, Name: name})
+		}
+	}
+	for _, name := range []string{groupName, groupPrincipalName} {
+		if name != "" {
+			result = append(result, rbacv1.Subject{Kind: rbacv1.GroupKind, Name: name})
+		}
+	}
+	return result
+}
+
+// matchSubject returns the first subject of the binding that matches, if any.
+func matchSubject(subjects []rbacv1.Subject, match func(rbacv1.Subject) bool) (rbacv1.Subject, bool) {
+	for _, subject := range subjects {
+		if match(subject) {
+			return subject, true
+		}
+	}
+	return rbacv1.Subject{}, false
+}
+
+// grants returns the grants of the bindings matching a subject, except for the expired ones.
+func (r *Resolver) grants(match func(rbacv1.Subject) bool, filter Filter) ([]Grant, error) {
+	var result []Grant
+	now := time.Now()
+
+	grbs, err := r.globalRoleBindings.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, grb := range grbs {
+		subject, ok := matchSubject(bindingSubjects(grb.UserName, "", "", grb.GroupPrincipalName), match)
+		if !ok {
+			continue
+		}
+		grants, err := r.globalRoleGrants(grb, subject)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, grants...)
+	}
+
+	if filter.Project == "" {
+		crtbs, err := r.crtbs.List(filter.Cluster, labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, crtb := range crtbs {
+			if expired, _ := rbac.BindingExpired(crtb, now); expired {
+				continue
+			}
+			subject, ok := matchSubject(bindingSubjects(crtb.UserName, crtb.UserPrincipalName, crtb.GroupName, crtb.GroupPrincipalName), match)
+			if !ok {
+				continue
+			}
+			grant := Grant{
+				Subject: subject,
+				Scope:   ClusterScope,
+				Cluster: crtb.ClusterName,
+				Chain:   []Link{{Kind: "ClusterRoleTemplateBinding", Name: crtb.Name, Namespace: crtb.Namespace}},
+			}
+			if result, err = r.roleTemplateGrants(crtb.RoleTemplateName, grant, result, map[string]bool{}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	prtbs, err := r.prtbs.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, prtb := range prtbs {
+		if filter.Cluster != "" && prtb.ObjClusterName() != filter.Cluster ||
+			filter.Project != "" && prtb.ProjectName != filter.Project {
+			continue
+		}
+		if expired, _ := rbac.BindingExpired(prtb, now); expired {
+			continue
+		}
+		subjects := bindingSubjects(prtb.UserName, prtb.UserPrincipalName, prtb.GroupName, prtb.GroupPrincipalName)
+		if prtb.ServiceAccount != "" {
+			namespace, name := splitServiceAccount(prtb.ServiceAccount)
+			subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace})
+		}
+		subject, ok := matchSubject(subjects, match)
+		if !ok {
+			continue
+		}
+		grant := Grant{
+			Subject: subject,
+			Scope:   ProjectScope,
+			Cluster: prtb.ObjClusterName(),
+			Project: prtb.ProjectName,
+			Chain:   []Link{{Kind: "ProjectRoleTemplateBinding", Name: prtb.Name, Namespace: prtb.Namespace}},
+		}
+		if result, err = r.roleTemplateGrants(prtb.RoleTemplateName, grant, result, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	sortGrants(result)
+	return result, nil
+}
+
+// globalRoleGrants returns the grant of the GlobalRole of the binding. Global admins are also bound to the
+// cluster-admin ClusterRole in every downstream cluster.
+func (r *Resolver) globalRoleGrants(grb *v3.GlobalRoleBinding, subject rbacv1.Subject) ([]Grant, error) {
+	globalRole, err := r.globalRoles.Get(grb.GlobalRoleName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	chain := []Link{
+		{Kind: "GlobalRoleBinding", Name: grb.Name},
+		{Kind: "GlobalRole", Name: globalRole.Name},
+	}
+	result := []Grant{{
+		Subject: subject,
+		Scope:   GlobalScope,
+		Chain:   chain,
+		Rules:   globalRole.Rules,
+	}}
+	if grb.GlobalRoleName != rbac.GlobalAdmin {
+		return result, nil
+	}
+
+	clusterAdmin, err := r.clusterRoles.Get(clusterAdminRole)
+	if apierrors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return append(result, Grant{
+		Subject: subject,
+		Scope:   ClusterScope,
+		Cluster: AllClusters,
+		Chain:   append(chain[:len(chain):len(chain)], Link{Kind: "ClusterRole", Name: clusterAdmin.Name}),
+		Rules:   clusterAdmin.Rules,
+	}), nil
+}
+
+// roleTemplateGrants appends a grant for the RoleTemplate and for each RoleTemplate it inherits, the same way
+// rbac.RulesFromTemplate gathers their rules. The grant holds the subject, scope and chain of the binding.
+func (r *Resolver) roleTemplateGrants(name string, grant Grant, result []Grant, seen map[string]bool) ([]Grant, error) {
+	if seen[name] {
+		return result, nil
+	}
+	seen[name] = true
+
+	rt, err := r.roleTemplates.Get(name)
+	if apierrors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	grant.Chain = append(grant.Chain[:len(grant.Chain):len(grant.Chain)], Link{Kind: "RoleTemplate", Name: rt.Name})
+	if rt.External && rt.Context == "cluster" {
+		cr, err := r.clusterRoles.Get(rt.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if cr != nil && len(cr.Rules) > 0 {
+			external := grant
+			external.Chain = append(grant.Chain[:len(grant.Chain):len(grant.Chain)], Link{Kind: "ClusterRole", Name: cr.Name})
+			external.Rules = cr.Rules
+			result = append(result, external)
+		}
+	}
+	if len(rt.Rules) > 0 {
+		own := grant
+		own.Rules = rt.Rules
+		result = append(result, own)
+	}
+
+	for _, inherited := range rt.RoleTemplateNames {
+		if result, err = r.roleTemplateGrants(inherited, grant, result, seen); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// splitServiceAccount splits the namespace:name service account of a ProjectRoleTemplateBinding.
+func splitServiceAccount(serviceAccount string) (string, string) {
+	if parts := strings.SplitN(serviceAccount, ":", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return "", serviceAccount
+}
+
+// sortGrants orders the grants by scope, then by cluster and project, keeping the order of the chains of a binding.
+func sortGrants(grants []Grant) {
+	order := map[Scope]int{GlobalScope: 0, ClusterScope: 1, ProjectScope: 2}
+	sort.SliceStable(grants, func(i, j int) bool {
+		a, b := grants[i], grants[j]
+		if order[a.Scope] != order[b.Scope] {
+			return order[a.Scope] < order[b.Scope]
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Project < b.Project
+	})
+}
@@ -0,0 +1,343 @@
+package effective
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	wrbacv1 "github.com/rancher/wrangler/pkg/generated/controllers/rbac/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authzv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var notFound = apierrors.NewNotFound(schema.GroupResource{}, "")
+
+type fakeGlobalRoleCache struct {
+	mgmtcontrollers.GlobalRoleCache
+	roles map[string]*v3.GlobalRole
+}
+
+func (f *fakeGlobalRoleCache) Get(name string) (*v3.GlobalRole, error) {
+	if role, ok := f.roles[name]; ok {
+		return role, nil
+	}
+	return nil, notFound
+}
+
+type fakeGlobalRoleBindingCache struct {
+	mgmtcontrollers.GlobalRoleBindingCache
+	bindings []*v3.GlobalRoleBinding
+}
+
+func (f *fakeGlobalRoleBindingCache) List(labels.Selector) ([]*v3.GlobalRoleBinding, error) {
+	return f.bindings, nil
+}
+
+type fakeCRTBCache struct {
+	mgmtcontrollers.ClusterRoleTemplateBindingCache
+	bindings []*v3.ClusterRoleTemplateBinding
+}
+
+func (f *fakeCRTBCache) List(namespace string, _ labels.Selector) ([]*v3.ClusterRoleTemplateBinding, error) {
+	var result []*v3.ClusterRoleTemplateBinding
+	for _, binding := range f.bindings {
+		if namespace == "" || binding.Namespace == namespace {
+			result = append(result, binding)
+		}
+	}
+	return result, nil
+}
+
+type fakePRTBCache struct {
+	mgmtcontrollers.ProjectRoleTemplateBindingCache
+	bindings []*v3.ProjectRoleTemplateBinding
+}
+
+func (f *fakePRTBCache) List(string, labels.Selector) ([]*v3.ProjectRoleTemplateBinding, error) {
+	return f.bindings, nil
+}
+
+type fakeRoleTemplateCache struct {
+	mgmtcontrollers.RoleTemplateCache
+	templates map[string]*v3.RoleTemplate
+}
+
+func (f *fakeRoleTemplateCache) Get(name string) (*v3.RoleTemplate, error) {
+	if rt, ok := f.templates[name]; ok {
+		return rt, nil
+	}
+	return nil, notFound
+}
+
+func (f *fakeRoleTemplateCache) List(labels.Selector) ([]*v3.RoleTemplate, error) {
+	var templates []*v3.RoleTemplate
+	for _, rt := range f.templates {
+		templates = append(templates, rt)
+	}
+	return templates, nil
+}
+
+type fakeUserCache struct {
+	mgmtcontrollers.UserCache
+	users map[string]*v3.User
+}
+
+func (f *fakeUserCache) Get(name string) (*v3.User, error) {
+	if user, ok := f.users[name]; ok {
+		return user, nil
+	}
+	return nil, notFound
+}
+
+type fakeUserAttributeCache struct {
+	mgmtcontrollers.UserAttributeCache
+	attributes map[string]*v3.UserAttribute
+}
+
+func (f *fakeUserAttributeCache) Get(name string) (*v3.UserAttribute, error) {
+	if attribs, ok := f.attributes[name]; ok {
+		return attribs, nil
+	}
+	return nil, notFound
+}
+
+type fakeClusterRoleCache struct {
+	wrbacv1.ClusterRoleCache
+	roles map[string]*rbacv1.ClusterRole
+}
+
+func (f *fakeClusterRoleCache) Get(name string) (*rbacv1.ClusterRole, error) {
+	if role, ok := f.roles[name]; ok {
+		return role, nil
+	}
+	return nil, notFound
+}
+
+var (
+	readPods   = rbacv1.PolicyRule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	deletePods = rbacv1.PolicyRule{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	all        = rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}
+	readNodes  = rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"nodes"}}
+)
+
+func newTestResolver() *Resolver {
+	return &Resolver{
+		globalRoles: &fakeGlobalRoleCache{roles: map[string]*v3.GlobalRole{
+			"admin": {ObjectMeta: metav1.ObjectMeta{Name: "admin"}, Rules: []rbacv1.PolicyRule{all}},
+			"user":  {ObjectMeta: metav1.ObjectMeta{Name: "user"}, Rules: []rbacv1.PolicyRule{readNodes}},
+		}},
+		globalRoleBindings: &fakeGlobalRoleBindingCache{bindings: []*v3.GlobalRoleBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "grb-admin"}, UserName: "u-admin", GlobalRoleName: "admin"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "grb-user"}, UserName: "u-dev", GlobalRoleName: "user"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "grb-missing"}, UserName: "u-dev", GlobalRoleName: "missing"},
+		}},
+		crtbs: &fakeCRTBCache{bindings: []*v3.ClusterRoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-team", Namespace: "c-1"}, ClusterName: "c-1", GroupPrincipalName: "github_team://1", RoleTemplateName: "cluster-member"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-other", Namespace: "c-2"}, ClusterName: "c-2", UserName: "u-other", RoleTemplateName: "cluster-member"},
+		}},
+		prtbs: &fakePRTBCache{bindings: []*v3.ProjectRoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "prtb-dev", Namespace: "p-1"}, ProjectName: "c-1:p-1", UserPrincipalName: "github_user://7", RoleTemplateName: "project-owner"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "prtb-sa", Namespace: "p-1"}, ProjectName: "c-1:p-1", ServiceAccount: "ns:deployer", RoleTemplateName: "project-owner"},
+		}},
+		roleTemplates: &fakeRoleTemplateCache{templates: map[string]*v3.RoleTemplate{
+			"cluster-member": {ObjectMeta: metav1.ObjectMeta{Name: "cluster-member"}, Context: "cluster", External: true, RoleTemplateNames: []string{"view"}},
+			"view":           {ObjectMeta: metav1.ObjectMeta{Name: "view"}, Rules: []rbacv1.PolicyRule{readPods}, RoleTemplateNames: []string{"view"}},
+			"project-owner":  {ObjectMeta: metav1.ObjectMeta{Name: "project-owner"}, Rules: []rbacv1.PolicyRule{deletePods}, RoleTemplateNames: []string{"view", "missing"}},
+		}},
+		users: &fakeUserCache{users: map[string]*v3.User{
+			"u-dev": {ObjectMeta: metav1.ObjectMeta{Name: "u-dev"}, PrincipalIDs: []string{"local://u-dev", "github_user://7"}},
+		}},
+		userAttributes: &fakeUserAttributeCache{attributes: map[string]*v3.UserAttribute{
+			"u-dev": {ObjectMeta: metav1.ObjectMeta{Name: "u-dev"}, GroupPrincipals: map[string]v3.Principals{
+				"github": {Items: []v3.Principal{{ObjectMeta: metav1.ObjectMeta{Name: "github_team://1"}}}},
+			}},
+		}},
+		clusterRoles: &fakeClusterRoleCache{roles: map[string]*rbacv1.ClusterRole{
+			"cluster-member": {ObjectMeta: metav1.ObjectMeta{Name: "cluster-member"}, Rules: []rbacv1.PolicyRule{readNodes}},
+			"cluster-admin":  {ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}, Rules: []rbacv1.PolicyRule{all}},
+		}},
+	}
+}
+
+func TestForUser(t *testing.T) {
+	resolver := newTestResolver()
+
+	subjects, grants, err := resolver.ForUser("u-dev", Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{
+		{Kind: rbacv1.UserKind, Name: "github_user://7"},
+		{Kind: rbacv1.UserKind, Name: "local://u-dev"},
+		{Kind: rbacv1.UserKind, Name: "u-dev"},
+		{Kind: rbacv1.GroupKind, Name: "github_team://1"},
+	}, subjects)
+
+	team := rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "github_team://1"}
+	principal := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "github_user://7"}
+	crtb := Link{Kind: "ClusterRoleTemplateBinding", Name: "crtb-team", Namespace: "c-1"}
+	prtb := Link{Kind: "ProjectRoleTemplateBinding", Name: "prtb-dev", Namespace: "p-1"}
+	assert.Equal(t, []Grant{
+		{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-dev"},
+			Scope:   GlobalScope,
+			Chain:   []Link{{Kind: "GlobalRoleBinding", Name: "grb-user"}, {Kind: "GlobalRole", Name: "user"}},
+			Rules:   []rbacv1.PolicyRule{readNodes},
+		},
+		{
+			Subject: team, Scope: ClusterScope, Cluster: "c-1",
+			Chain: []Link{crtb, {Kind: "RoleTemplate", Name: "cluster-member"}, {Kind: "ClusterRole", Name: "cluster-member"}},
+			Rules: []rbacv1.PolicyRule{readNodes},
+		},
+		{
+			Subject: team, Scope: ClusterScope, Cluster: "c-1",
+			Chain: []Link{crtb, {Kind: "RoleTemplate", Name: "cluster-member"}, {Kind: "RoleTemplate", Name: "view"}},
+			Rules: []rbacv1.PolicyRule{readPods},
+		},
+		{
+			Subject: principal, Scope: ProjectScope, Cluster: "c-1", Project: "c-1:p-1",
+			Chain: []Link{prtb, {Kind: "RoleTemplate", Name: "project-owner"}},
+			Rules: []rbacv1.PolicyRule{deletePods},
+		},
+		{
+			Subject: principal, Scope: ProjectScope, Cluster: "c-1", Project: "c-1:p-1",
+			Chain: []Link{prtb, {Kind: "RoleTemplate", Name: "project-owner"}, {Kind: "RoleTemplate", Name: "view"}},
+			Rules: []rbacv1.PolicyRule{readPods},
+		},
+	}, grants)
+
+	_, grants, err = resolver.ForUser("u-dev", Filter{Cluster: "c-2"})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, GlobalScope, grants[0].Scope)
+
+	_, _, err = resolver.ForUser("u-missing", Filter{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestForUserGlobalAdmin(t *testing.T) {
+	resolver := newTestResolver()
+	resolver.users.(*fakeUserCache).users["u-admin"] = &v3.User{ObjectMeta: metav1.ObjectMeta{Name: "u-admin"}}
+	_, grants, err := resolver.ForUser("u-admin", Filter{})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, GlobalScope, grants[0].Scope)
+	assert.Equal(t, ClusterScope, grants[1].Scope)
+	assert.Equal(t, AllClusters, grants[1].Cluster)
+	assert.Equal(t, Link{Kind: "ClusterRole", Name: "cluster-admin"}, grants[1].Chain[2])
+}
+
+func TestForGroup(t *testing.T) {
+	subjects, grants, err := newTestResolver().ForGroup("github_team://1", Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "github_team://1"}}, subjects)
+	require.Len(t, grants, 2)
+	for _, grant := range grants {
+		assert.Equal(t, "crtb-team", grant.Chain[0].Name)
+	}
+}
+
+func TestExpiredBindings(t *testing.T) {
+	resolver := newTestResolver()
+	crtbs := resolver.crtbs.(*fakeCRTBCache).bindings
+	crtbs[0].Annotations = map[string]string{rbac.ExpiresAtAnnotation: time.Now().Add(-time.Minute).Format(time.RFC3339)}
+
+	_, grants, err := resolver.ForGroup("github_team://1", Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+
+	crtbs[0].Annotations[rbac.ExpiresAtAnnotation] = time.Now().Add(time.Hour).Format(time.RFC3339)
+	_, grants, err = resolver.ForGroup("github_team://1", Filter{})
+	require.NoError(t, err)
+	assert.Len(t, grants, 2)
+}
+
+func TestWho(t *testing.T) {
+	resolver := newTestResolver()
+
+	grants, err := resolver.Who("delete", "", "pods", Filter{Project: "c-1:p-1"})
+	require.NoError(t, err)
+	var subjects []rbacv1.Subject
+	for _, grant := range grants {
+		subjects = append(subjects, grant.Subject)
+		assert.NotContains(t, grant.Rules, readNodes)
+	}
+	assert.Equal(t, []rbacv1.Subject{
+		{Kind: rbacv1.UserKind, Name: "u-admin"},
+		{Kind: rbacv1.UserKind, Name: "u-admin"},
+		{Kind: rbacv1.UserKind, Name: "github_user://7"},
+		{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "ns"},
+	}, subjects)
+
+	grants, err = resolver.Who("list", "", "pods", Filter{Cluster: "c-2"})
+	require.NoError(t, err)
+	require.Len(t, grants, 3)
+	assert.Equal(t, rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-other"}, grants[2].Subject)
+	assert.Equal(t, []rbacv1.PolicyRule{readPods}, grants[2].Rules)
+
+	grants, err = resolver.Who("get", "apps", "deployments", Filter{Cluster: "c-1"})
+	require.NoError(t, err)
+	assert.Len(t, grants, 2, "only the global admin grants match")
+}
+
+func TestAllows(t *testing.T) {
+	assert.True(t, Allows(readPods, "get", "", "pods"))
+	assert.False(t, Allows(readPods, "delete", "", "pods"))
+	assert.False(t, Allows(readPods, "get", "apps", "pods"))
+	assert.False(t, Allows(readPods, "get", "", "pods/log"))
+	assert.True(t, Allows(all, "delete", "apps", "deployments/scale"))
+}
+
+func TestHandler(t *testing.T) {
+	allowed := true
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		assert.Equal(t, "effectivepermissions", review.Spec.ResourceAttributes.Resource)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+	handler := NewHandler(newTestResolver(), client.AuthorizationV1().SubjectAccessReviews())
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, Endpoint+"?"+query, nil)
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: "u-admin"}))
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	rw := serve("user=u-dev&project=c-1:p-1")
+	require.Equal(t, http.StatusOK, rw.Code)
+	var response Response
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+	assert.Len(t, response.Subjects, 4)
+	assert.Len(t, response.Grants, 3)
+
+	assert.Equal(t, http.StatusNotFound, serve("user=u-missing").Code)
+	assert.Equal(t, http.StatusBadRequest, serve("verb=get").Code)
+
+	rw = serve("verb=create&resource=secrets&cluster=c-3")
+	require.Equal(t, http.StatusOK, rw.Code)
+	response = Response{}
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+	assert.Empty(t, response.Subjects)
+	assert.Len(t, response.Grants, 2)
+
+	allowed = false
+	assert.Equal(t, http.StatusForbidden, serve("group=github_team://1").Code)
+}
@@ -0,0 +1,153 @@
+package effective
+
+import (
+	"encoding/json"
+	"net/http"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/requests/sar"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/logging"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+const (
+	// Endpoint is the path the effective permissions are queried at.
+	Endpoint = "/v1/effectivepermissions"
+	// PreviewEndpoint is the path the impact of an edit of a RoleTemplate is previewed at.
+	PreviewEndpoint = "/v1/roletemplatepreviews"
+
+	maxPreviewBodySize = 1 << 20
+)
+
+// Response holds the grants matching a query. Subjects are the user, principals and groups the grants of a user or
+// group were looked up for, and are empty for the queries by verb and resource.
+type Response struct {
+	Subjects []rbacv1.Subject `json:"subjects,omitempty"`
+	Grants   []Grant          `json:"grants"`
+}
+
+type handler struct {
+	resolver             *Resolver
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewHandler returns the handler of Endpoint. It answers one of the queries:
+//   - ?user=<user ID>: the grants of a user, including the ones of its principals and groups.
+//   - ?group=<group principal ID>: the grants of a group.
+//   - ?verb=<verb>&resource=<resource>[&apiGroup=<group>]: the grants allowing verb on resource, and who they go to.
+//
+// The cluster and project query parameters limit the grants to the bindings of a cluster or project. Access
+// requires the permission to get effectivepermissions in management.cattle.io.
+func NewHandler(resolver *Resolver, subjectAccessReviews authv1.SubjectAccessReviewInterface) http.Handler {
+	return &handler{
+		resolver:             resolver,
+		subjectAccessReviews: subjectAccessReviews,
+	}
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	authorized, err := sar.UserCanAccess(req, h.subjectAccessReviews, "get", "effectivepermissions")
+	if err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to authorize request for effective permissions: %v", err)
+		util.ReturnHTTPError(rw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	if !authorized {
+		util.ReturnHTTPError(rw, req, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	query := req.URL.Query()
+	filter := Filter{
+		Cluster: query.Get("cluster"),
+		Project: query.Get("project"),
+	}
+
+	var response Response
+	switch {
+	case query.Get("user") != "":
+		response.Subjects, response.Grants, err = h.resolver.ForUser(query.Get("user"), filter)
+	case query.Get("group") != "":
+		response.Subjects, response.Grants, err = h.resolver.ForGroup(query.Get("group"), filter)
+	case query.Get("verb") != "" && query.Get("resource") != "":
+		response.Grants, err = h.resolver.Who(query.Get("verb"), query.Get("apiGroup"), query.Get("resource"), filter)
+	default:
+		util.ReturnHTTPError(rw, req, http.StatusBadRequest, "one of user, group, or verb and resource is required")
+		return
+	}
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(rw, req, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to compute effective permissions: %v", err)
+		util.ReturnHTTPError(rw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if response.Grants == nil {
+		response.Grants = []Grant{}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(response); err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to write effective permissions response: %v", err)
+	}
+}
+
+type previewHandler struct {
+	resolver             *Resolver
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewPreviewHandler returns the handler of PreviewEndpoint. It takes the proposed RoleTemplate as the body, and
+// returns the Preview of replacing the current RoleTemplate of the same name with it, without changing anything.
+// Access requires the permission to update roletemplates in management.cattle.io.
+func NewPreviewHandler(resolver *Resolver, subjectAccessReviews authv1.SubjectAccessReviewInterface) http.Handler {
+	return &previewHandler{
+		resolver:             resolver,
+		subjectAccessReviews: subjectAccessReviews,
+	}
+}
+
+func (h *previewHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	authorized, err := sar.UserCanAccess(req, h.subjectAccessReviews, "update", "roletemplates")
+	if err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to authorize request for role template preview: %v", err)
+		util.ReturnHTTPError(rw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	if !authorized {
+		util.ReturnHTTPError(rw, req, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	var proposed v3.RoleTemplate
+	if err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, maxPreviewBodySize)).Decode(&proposed); err != nil {
+		util.ReturnHTTPError(rw, req, http.StatusBadRequest, "invalid role template: "+err.Error())
+		return
+	}
+	if proposed.Name == "" {
+		util.ReturnHTTPError(rw, req, http.StatusBadRequest, "metadata.name is required")
+		return
+	}
+
+	preview, err := h.resolver.Preview(&proposed)
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPError(rw, req, http.StatusNotFound, err.Error())
+		return
+	} else if apierrors.IsBadRequest(err) {
+		util.ReturnHTTPError(rw, req, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to preview role template %s: %v", proposed.Name, err)
+		util.ReturnHTTPError(rw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(preview); err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to write role template preview response: %v", err)
+	}
+}
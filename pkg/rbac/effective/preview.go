@@ -0,0 +1,218 @@
+package effective
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rbac"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-helpers/auth/rbac/validation"
+)
+
+// Preview is the impact of an edit of a RoleTemplate, before the ClusterRoles and Roles of the downstream clusters
+// are reconciled with it.
+type Preview struct {
+	RoleTemplate string `json:"roleTemplate"`
+	// RoleTemplates are the edited RoleTemplate and the ones inheriting it whose rules change.
+	RoleTemplates []RoleTemplateImpact `json:"roleTemplates"`
+	// Clusters are the clusters with bindings to the RoleTemplates.
+	Clusters []string `json:"clusters"`
+	// Bindings are the bindings to the RoleTemplates, with the subjects whose permissions change.
+	Bindings []BindingImpact `json:"bindings"`
+}
+
+// RoleTemplateImpact is the change of the rules of a RoleTemplate, including the ones it inherits, broken down into
+// one verb, resource and resource name per rule.
+type RoleTemplateImpact struct {
+	Name    string              `json:"name"`
+	Added   []rbacv1.PolicyRule `json:"added,omitempty"`
+	Removed []rbacv1.PolicyRule `json:"removed,omitempty"`
+}
+
+// BindingImpact is a binding to a RoleTemplate whose rules change.
+type BindingImpact struct {
+	Binding      Link           `json:"binding"`
+	Subject      rbacv1.Subject `json:"subject"`
+	Cluster      string         `json:"cluster"`
+	Project      string         `json:"project,omitempty"`
+	RoleTemplate string         `json:"roleTemplate"`
+}
+
+// Preview returns the impact of replacing the RoleTemplate of the same name with proposed. Nothing is changed.
+func (r *Resolver) Preview(proposed *v3.RoleTemplate) (*Preview, error) {
+	current, err := r.roleTemplates.Get(proposed.Name)
+	if err != nil {
+		return nil, err
+	}
+	if proposed.Context == "" {
+		proposed = proposed.DeepCopy()
+		proposed.Context = current.Context
+	}
+	if proposed.Context != current.Context {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("the context of role template %s cannot change", proposed.Name))
+	}
+
+	before := r.roleTemplates.Get
+	after := func(name string) (*v3.RoleTemplate, error) {
+		if name == proposed.Name {
+			return proposed, nil
+		}
+		return r.roleTemplates.Get(name)
+	}
+
+	roleTemplates, err := r.roleTemplates.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	preview := &Preview{
+		RoleTemplate:  proposed.Name,
+		RoleTemplates: []RoleTemplateImpact{},
+		Clusters:      []string{},
+		Bindings:      []BindingImpact{},
+	}
+	affected := map[string]bool{}
+	for _, rt := range roleTemplates {
+		oldRules, inheritedBefore, err := r.flatten(rt.Name, before)
+		if err != nil {
+			return nil, err
+		}
+		newRules, inheritedAfter, err := r.flatten(rt.Name, after)
+		if err != nil {
+			return nil, err
+		}
+		if !inheritedBefore[proposed.Name] && !inheritedAfter[proposed.Name] {
+			continue
+		}
+
+		_, added := validation.Covers(oldRules, newRules)
+		_, removed := validation.Covers(newRules, oldRules)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+		affected[rt.Name] = true
+		preview.RoleTemplates = append(preview.RoleTemplates, RoleTemplateImpact{
+			Name:    rt.Name,
+			Added:   added,
+			Removed: removed,
+		})
+	}
+	sort.Slice(preview.RoleTemplates, func(i, j int) bool {
+		return preview.RoleTemplates[i].Name < preview.RoleTemplates[j].Name
+	})
+
+	if err := r.affectedBindings(affected, preview); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// flatten returns the rules of a RoleTemplate and of the ones it inherits, along with the names of all of them, the
+// same way rbac.RulesFromTemplate gathers the rules.
+func (r *Resolver) flatten(name string, get func(string) (*v3.RoleTemplate, error)) ([]rbacv1.PolicyRule, map[string]bool, error) {
+	var rules []rbacv1.PolicyRule
+	seen := map[string]bool{}
+	var gather func(string) error
+	gather = func(name string) error {
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+
+		rt, err := get(name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if rt.External && rt.Context == "cluster" {
+			cr, err := r.clusterRoles.Get(rt.Name)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			if cr != nil {
+				rules = append(rules, cr.Rules...)
+			}
+		}
+		rules = append(rules, rt.Rules...)
+		for _, inherited := range rt.RoleTemplateNames {
+			if err := gather(inherited); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return rules, seen, gather(name)
+}
+
+// affectedBindings adds the unexpired bindings to the affected RoleTemplates, and their clusters, to the preview.
+func (r *Resolver) affectedBindings(affected map[string]bool, preview *Preview) error {
+	if len(affected) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	clusters := map[string]bool{}
+	crtbs, err := r.crtbs.List("", labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, crtb := range crtbs {
+		if expired, _ := rbac.BindingExpired(crtb, now); expired || !affected[crtb.RoleTemplateName] {
+			continue
+		}
+		subjects := bindingSubjects(crtb.UserName, crtb.UserPrincipalName, crtb.GroupName, crtb.GroupPrincipalName)
+		if len(subjects) == 0 {
+			continue
+		}
+		clusters[crtb.ClusterName] = true
+		preview.Bindings = append(preview.Bindings, BindingImpact{
+			Binding:      Link{Kind: "ClusterRoleTemplateBinding", Name: crtb.Name, Namespace: crtb.Namespace},
+			Subject:      subjects[0],
+			Cluster:      crtb.ClusterName,
+			RoleTemplate: crtb.RoleTemplateName,
+		})
+	}
+
+	prtbs, err := r.prtbs.List("", labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, prtb := range prtbs {
+		if expired, _ := rbac.BindingExpired(prtb, now); expired || !affected[prtb.RoleTemplateName] {
+			continue
+		}
+		subjects := bindingSubjects(prtb.UserName, prtb.UserPrincipalName, prtb.GroupName, prtb.GroupPrincipalName)
+		if prtb.ServiceAccount != "" {
+			namespace, name := splitServiceAccount(prtb.ServiceAccount)
+			subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace})
+		}
+		if len(subjects) == 0 {
+			continue
+		}
+		clusters[prtb.ObjClusterName()] = true
+		preview.Bindings = append(preview.Bindings, BindingImpact{
+			Binding:      Link{Kind: "ProjectRoleTemplateBinding", Name: prtb.Name, Namespace: prtb.Namespace},
+			Subject:      subjects[0],
+			Cluster:      prtb.ObjClusterName(),
+			Project:      prtb.ProjectName,
+			RoleTemplate: prtb.RoleTemplateName,
+		})
+	}
+
+	for cluster := range clusters {
+		preview.Clusters = append(preview.Clusters, cluster)
+	}
+	sort.Strings(preview.Clusters)
+	sort.SliceStable(preview.Bindings, func(i, j int) bool {
+		a, b := preview.Bindings[i], preview.Bindings[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Project < b.Project
+	})
+	return nil
+}
@@ -0,0 +1,108 @@
+package effective
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authzv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPreview(t *testing.T) {
+	resolver := newTestResolver()
+
+	getPods := rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	listPods := rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	preview, err := resolver.Preview(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "view"},
+		Rules:      []rbacv1.PolicyRule{getPods, readNodes},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "view", preview.RoleTemplate)
+	assert.Equal(t, []RoleTemplateImpact{
+		{Name: "cluster-member", Added: []rbacv1.PolicyRule{}, Removed: []rbacv1.PolicyRule{listPods}},
+		{Name: "project-owner", Added: []rbacv1.PolicyRule{readNodes}, Removed: []rbacv1.PolicyRule{listPods}},
+		{Name: "view", Added: []rbacv1.PolicyRule{readNodes}, Removed: []rbacv1.PolicyRule{listPods}},
+	}, preview.RoleTemplates)
+	assert.Equal(t, []string{"c-1", "c-2"}, preview.Clusters)
+	require.Len(t, preview.Bindings, 4)
+	assert.Equal(t, Link{Kind: "ClusterRoleTemplateBinding", Name: "crtb-team", Namespace: "c-1"}, preview.Bindings[0].Binding)
+	assert.Equal(t, "c-1:p-1", preview.Bindings[2].Project)
+	assert.Equal(t, "c-2", preview.Bindings[3].Cluster)
+}
+
+func TestPreviewUnchanged(t *testing.T) {
+	resolver := newTestResolver()
+
+	preview, err := resolver.Preview(&v3.RoleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-owner"},
+		Rules:      []rbacv1.PolicyRule{deletePods, readPods},
+		// view was already inherited, so adding its rules changes nothing.
+		RoleTemplateNames: []string{"view"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, preview.RoleTemplates)
+	assert.Empty(t, preview.Clusters)
+	assert.Empty(t, preview.Bindings)
+}
+
+func TestPreviewErrors(t *testing.T) {
+	resolver := newTestResolver()
+
+	_, err := resolver.Preview(&v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: "missing"}})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	_, err = resolver.Preview(&v3.RoleTemplate{ObjectMeta: metav1.ObjectMeta{Name: "cluster-member"}, Context: "project"})
+	assert.True(t, apierrors.IsBadRequest(err))
+}
+
+func TestPreviewHandler(t *testing.T) {
+	allowed := true
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzv1.SubjectAccessReview)
+		assert.Equal(t, "update", review.Spec.ResourceAttributes.Verb)
+		assert.Equal(t, "roletemplates", review.Spec.ResourceAttributes.Resource)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+	handler := NewPreviewHandler(newTestResolver(), client.AuthorizationV1().SubjectAccessReviews())
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, PreviewEndpoint, bytes.NewBufferString(body))
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: "u-admin"}))
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	rw := serve(`{"metadata":{"name":"project-owner"},"roleTemplateNames":["view"]}`)
+	require.Equal(t, http.StatusOK, rw.Code)
+	var preview Preview
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &preview))
+	require.Len(t, preview.RoleTemplates, 1)
+	assert.Equal(t, []rbacv1.PolicyRule{deletePods}, preview.RoleTemplates[0].Removed)
+	assert.Equal(t, []string{"c-1"}, preview.Clusters)
+
+	assert.Equal(t, http.StatusBadRequest, serve(`{"rules":[]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(`{`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(`{"metadata":{"name":"view"},"context":"cluster"}`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(`{"metadata":{"name":"missing"}}`).Code)
+
+	allowed = false
+	assert.Equal(t, http.StatusForbidden, serve(`{"metadata":{"name":"view"}}`).Code)
+}
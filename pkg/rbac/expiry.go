@@ -0,0 +1,39 @@
+package rbac
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExpiresAtAnnotation holds the RFC3339 time a ClusterRoleTemplateBinding or ProjectRoleTemplateBinding expires at.
+// The RoleBindings of an expired binding are removed from the downstream cluster.
+const ExpiresAtAnnotation = "authz.management.cattle.io/expires-at"
+
+// BindingExpiry returns the time the binding expires at, and false if it does not expire. A binding whose expiry
+// cannot be parsed expired at the zero time, so that a mistyped expiry does not grant access forever.
+func BindingExpiry(obj metav1.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[ExpiresAtAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logrus.Warnf("Binding %s/%s has an invalid %s annotation %q, treating it as expired", obj.GetNamespace(), obj.GetName(), ExpiresAtAnnotation, value)
+		return time.Time{}, true
+	}
+	return expiresAt, true
+}
+
+// BindingExpired returns whether the binding expired at now, and how long until it does if it did not.
+func BindingExpired(obj metav1.Object, now time.Time) (bool, time.Duration) {
+	expiresAt, ok := BindingExpiry(obj)
+	if !ok {
+		return false, 0
+	}
+	if remaining := expiresAt.Sub(now); remaining > 0 {
+		return false, remaining
+	}
+	return true, 0
+}
@@ -0,0 +1,34 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBindingExpired(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	binding := func(expiresAt string) *metav1.ObjectMeta {
+		meta := &metav1.ObjectMeta{Name: "crtb-1", Namespace: "c-1"}
+		if expiresAt != "" {
+			meta.Annotations = map[string]string{ExpiresAtAnnotation: expiresAt}
+		}
+		return meta
+	}
+
+	expired, remaining := BindingExpired(binding(""), now)
+	assert.False(t, expired)
+	assert.Zero(t, remaining)
+
+	expired, remaining = BindingExpired(binding("2023-03-01T13:30:00Z"), now)
+	assert.False(t, expired)
+	assert.Equal(t, 90*time.Minute, remaining)
+
+	expired, _ = BindingExpired(binding("2023-03-01T12:00:00Z"), now)
+	assert.True(t, expired)
+
+	expired, _ = BindingExpired(binding("tomorrow"), now)
+	assert.True(t, expired, "an invalid expiry must not grant access forever")
+}
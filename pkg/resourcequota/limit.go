@@ -0,0 +1,135 @@
+package resourcequota
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rancher/norman/types/convert"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ResourceQuotaConversion maps the fields of a ResourceQuotaLimit to the names of their resources in a ResourceQuota,
+// when they differ.
+var ResourceQuotaConversion = map[string]string{
+	"replicationControllers": "replicationcontrollers",
+	"configMaps":             "configmaps",
+	"persistentVolumeClaims": "persistentvolumeclaims",
+	"servicesNodePorts":      "services.nodeports",
+	"servicesLoadBalancers":  "services.loadbalancers",
+	"requestsCpu":            "requests.cpu",
+	"requestsMemory":         "requests.memory",
+	"requestsStorage":        "requests.storage",
+	"limitsCpu":              "limits.cpu",
+	"limitsMemory":           "limits.memory",
+}
+
+// limitFields are the fields of a ResourceQuotaLimit, except Extended.
+var limitFields = func() map[string]bool {
+	fields := map[string]bool{}
+	t := reflect.TypeOf(v32.ResourceQuotaLimit{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if t.Field(i).Type.Kind() == reflect.String {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// LimitToMap returns the limits of a ResourceQuotaLimit keyed by the name of their field, or by the name of their
+// resource for the extended ones.
+func LimitToMap(limit *v32.ResourceQuotaLimit) (map[string]string, error) {
+	limits := map[string]string{}
+	if limit == nil {
+		return limits, nil
+	}
+	standard := *limit
+	standard.Extended = nil
+	converted, err := convert.EncodeToMap(&standard)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range converted {
+		limits[key] = convert.ToString(value)
+	}
+	for key, value := range limit.Extended {
+		limits[key] = value
+	}
+	return limits, nil
+}
+
+// MapToLimit returns the ResourceQuotaLimit of limits keyed the way LimitToMap keys them.
+func MapToLimit(limits map[string]string) (*v32.ResourceQuotaLimit, error) {
+	standard := map[string]interface{}{}
+	extended := map[string]string{}
+	for key, value := range limits {
+		if limitFields[key] {
+			standard[key] = value
+		} else {
+			extended[key] = value
+		}
+	}
+	limit := &v32.ResourceQuotaLimit{}
+	if err := convert.ToObj(standard, limit); err != nil {
+		return nil, err
+	}
+	if len(extended) > 0 {
+		limit.Extended = extended
+	}
+	return limit, nil
+}
+
+// ValidateLimit checks that the extended limits are quantities of valid resource names, like requests.nvidia.com/gpu
+// or count/deployments.apps, that have no field of their own.
+func ValidateLimit(limit *v32.ResourceQuotaLimit) error {
+	if limit == nil {
+		return nil
+	}
+	names := make([]string, 0, len(limit.Extended))
+	for name := range limit.Extended {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resourceFields := map[string]string{}
+	for field := range limitFields {
+		resourceFields[field] = field
+		if name, ok := ResourceQuotaConversion[field]; ok {
+			resourceFields[name] = field
+		}
+	}
+	for _, name := range names {
+		if field, ok := resourceFields[name]; ok {
+			return fmt.Errorf("extended resource %s must be set with the %s field", name, field)
+		}
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			return fmt.Errorf("invalid extended resource name %s: %s", name, strings.Join(errs, ", "))
+		}
+		if _, err := resource.ParseQuantity(limit.Extended[name]); err != nil {
+			return fmt.Errorf("invalid quantity %q for extended resource %s: %v", limit.Extended[name], name, err)
+		}
+	}
+	return nil
+}
+
+// ResourceListToLimit returns the ResourceQuotaLimit of the resources of a ResourceQuota, such as its used resources.
+func ResourceListToLimit(resources api.ResourceList) (*v32.ResourceQuotaLimit, error) {
+	fields := map[string]string{}
+	for field, name := range ResourceQuotaConversion {
+		fields[name] = field
+	}
+	limits := map[string]string{}
+	for name, quantity := range resources {
+		key := string(name)
+		if field, ok := fields[key]; ok {
+			key = field
+		}
+		limits[key] = quantity.String()
+	}
+	return MapToLimit(limits)
+}
@@ -0,0 +1,219 @@
+package resourcequota
+
+import (
+	"fmt"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
+)
+
+// limitQuantity is the quantity of a resource set by a field of a ContainerResourceLimit.
+type limitQuantity struct {
+	field string
+	name  api.ResourceName
+	value string
+}
+
+// ConvertLimitToLimitRangeSpec returns the LimitRangeSpec of a ContainerResourceLimit, with an item for each of its
+// containers, pods and persistent volume claims that it sets anything on. It returns nil when it sets nothing.
+func ConvertLimitToLimitRangeSpec(limit *v32.ContainerResourceLimit) (*api.LimitRangeSpec, error) {
+	if limit == nil {
+		return nil, nil
+	}
+
+	var err error
+	container := api.LimitRangeItem{Type: api.LimitTypeContainer}
+	container.DefaultRequest, err = toResourceList(
+		limitQuantity{"requestsCpu", api.ResourceCPU, limit.RequestsCPU},
+		limitQuantity{"requestsMemory", api.ResourceMemory, limit.RequestsMemory},
+	)
+	if err != nil {
+		return nil, err
+	}
+	container.Default, err = toResourceList(
+		limitQuantity{"limitsCpu", api.ResourceCPU, limit.LimitsCPU},
+		limitQuantity{"limitsMemory", api.ResourceMemory, limit.LimitsMemory},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := setBounds(&container, "container", limit.Container); err != nil {
+		return nil, err
+	}
+
+	pod := api.LimitRangeItem{Type: api.LimitTypePod}
+	if err := setBounds(&pod, "pod", limit.Pod); err != nil {
+		return nil, err
+	}
+
+	pvc := api.LimitRangeItem{Type: api.LimitTypePersistentVolumeClaim}
+	if limit.PersistentVolumeClaim != nil {
+		pvc.Min, err = toResourceList(limitQuantity{"persistentVolumeClaim.minStorage", api.ResourceStorage, limit.PersistentVolumeClaim.MinStorage})
+		if err != nil {
+			return nil, err
+		}
+		pvc.Max, err = toResourceList(limitQuantity{"persistentVolumeClaim.maxStorage", api.ResourceStorage, limit.PersistentVolumeClaim.MaxStorage})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var items []api.LimitRangeItem
+	for _, item := range []api.LimitRangeItem{container, pod, pvc} {
+		if len(item.Default) > 0 || len(item.DefaultRequest) > 0 || len(item.Min) > 0 || len(item.Max) > 0 ||
+			len(item.MaxLimitRequestRatio) > 0 {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return &api.LimitRangeSpec{Limits: items}, nil
+}
+
+func setBounds(item *api.LimitRangeItem, field string, bounds *v32.ResourceLimitBounds) error {
+	if bounds == nil {
+		return nil
+	}
+	var err error
+	item.Min, err = toResourceList(
+		limitQuantity{field + ".minCpu", api.ResourceCPU, bounds.MinCPU},
+		limitQuantity{field + ".minMemory", api.ResourceMemory, bounds.MinMemory},
+	)
+	if err != nil {
+		return err
+	}
+	item.Max, err = toResourceList(
+		limitQuantity{field + ".maxCpu", api.ResourceCPU, bounds.MaxCPU},
+		limitQuantity{field + ".maxMemory", api.ResourceMemory, bounds.MaxMemory},
+	)
+	if err != nil {
+		return err
+	}
+	item.MaxLimitRequestRatio, err = toResourceList(
+		limitQuantity{field + ".maxLimitRequestRatioCpu", api.ResourceCPU, bounds.MaxLimitRequestRatioCPU},
+		limitQuantity{field + ".maxLimitRequestRatioMemory", api.ResourceMemory, bounds.MaxLimitRequestRatioMemory},
+	)
+	return err
+}
+
+func toResourceList(quantities ...limitQuantity) (api.ResourceList, error) {
+	var resources api.ResourceList
+	for _, q := range quantities {
+		if q.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q for %s: %v", q.value, q.field, err)
+		}
+		if resources == nil {
+			resources = api.ResourceList{}
+		}
+		resources[q.name] = quantity
+	}
+	return resources, nil
+}
+
+// ValidateLimitRange checks that the min of each resource of a ContainerResourceLimit is at most its max, that its
+// defaults are within them and that its max limit/request ratios are at least 1 and hold for its defaults.
+func ValidateLimitRange(limit *v32.ContainerResourceLimit) error {
+	spec, err := ConvertLimitToLimitRangeSpec(limit)
+	if err != nil || spec == nil {
+		return err
+	}
+	for _, item := range spec.Limits {
+		// default the item the way the api server does, to validate the defaults the pods actually get
+		corev1defaults.SetDefaults_LimitRangeItem(&item)
+		for name, min := range item.Min {
+			if max, ok := item.Max[name]; ok && min.Cmp(max) > 0 {
+				return fmt.Errorf("%s min %s of %s is greater than its max %s", item.Type, min.String(), name, max.String())
+			}
+			if request, ok := item.DefaultRequest[name]; ok && request.Cmp(min) < 0 {
+				return fmt.Errorf("%s default request %s of %s is lower than its min %s", item.Type, request.String(), name, min.String())
+			}
+		}
+		for name, max := range item.Max {
+			if def, ok := item.Default[name]; ok && def.Cmp(max) > 0 {
+				return fmt.Errorf("%s default limit %s of %s is greater than its max %s", item.Type, def.String(), name, max.String())
+			}
+		}
+		for name, request := range item.DefaultRequest {
+			if def, ok := item.Default[name]; ok && request.Cmp(def) > 0 {
+				return fmt.Errorf("%s default request %s of %s is greater than its default limit %s", item.Type, request.String(), name, def.String())
+			}
+		}
+		for name, ratio := range item.MaxLimitRequestRatio {
+			if ratio.Cmp(resource.MustParse("1")) < 0 {
+				return fmt.Errorf("%s max limit/request ratio %s of %s is lower than 1", item.Type, ratio.String(), name)
+			}
+			request, requestOk := item.DefaultRequest[name]
+			def, defOk := item.Default[name]
+			if requestOk && defOk && !request.IsZero() &&
+				float64(def.MilliValue())/float64(request.MilliValue()) > ratio.AsApproximateFloat64() {
+				return fmt.Errorf("%s default limit %s of %s is more than %s times its default request %s", item.Type,
+					def.String(), name, ratio.String(), request.String())
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateLimitRangeQuota checks that the defaults and mins of a ContainerResourceLimit fit in the quota of a
+// namespace, so that the pods and persistent volume claims they apply to can be admitted.
+func ValidateLimitRangeQuota(limit *v32.ContainerResourceLimit, quota *v32.ResourceQuotaLimit) error {
+	if quota == nil {
+		return nil
+	}
+	spec, err := ConvertLimitToLimitRangeSpec(limit)
+	if err != nil || spec == nil {
+		return err
+	}
+	quotaList, err := ConvertLimitToResourceList(quota)
+	if err != nil {
+		return err
+	}
+
+	check := func(item api.LimitRangeItem, kind string, resources api.ResourceList, quotaNames map[api.ResourceName]api.ResourceName) error {
+		for name, quantity := range resources {
+			quotaName, ok := quotaNames[name]
+			if !ok {
+				continue
+			}
+			if hard, ok := quotaList[quotaName]; ok && quantity.Cmp(hard) > 0 {
+				return fmt.Errorf("%s %s %s of %s exceeds the %s quota %s", item.Type, kind, quantity.String(), name,
+					quotaName, hard.String())
+			}
+		}
+		return nil
+	}
+	// the quota is keyed by the fields of the ResourceQuotaLimit
+	requests := map[api.ResourceName]api.ResourceName{
+		api.ResourceCPU:     "requestsCpu",
+		api.ResourceMemory:  "requestsMemory",
+		api.ResourceStorage: "requestsStorage",
+	}
+	limits := map[api.ResourceName]api.ResourceName{
+		api.ResourceCPU:    "limitsCpu",
+		api.ResourceMemory: "limitsMemory",
+	}
+
+	for _, item := range spec.Limits {
+		corev1defaults.SetDefaults_LimitRangeItem(&item)
+		if err := check(item, "default request", item.DefaultRequest, requests); err != nil {
+			return err
+		}
+		if err := check(item, "default limit", item.Default, limits); err != nil {
+			return err
+		}
+		if err := check(item, "min", item.Min, requests); err != nil {
+			return err
+		}
+		if err := check(item, "min", item.Min, limits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package resourcequota
+
+import (
+	"testing"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestConvertLimitToLimitRangeSpec(t *testing.T) {
+	spec, err := ConvertLimitToLimitRangeSpec(&v32.ContainerResourceLimit{})
+	require.NoError(t, err)
+	assert.Nil(t, spec)
+
+	spec, err = ConvertLimitToLimitRangeSpec(&v32.ContainerResourceLimit{
+		RequestsCPU: "100m",
+		LimitsCPU:   "200m",
+		Container: &v32.ResourceLimitBounds{
+			MinMemory:               "64Mi",
+			MaxCPU:                  "1",
+			MaxLimitRequestRatioCPU: "4",
+		},
+		PersistentVolumeClaim: &v32.StorageLimitBounds{MaxStorage: "10Gi"},
+	})
+	require.NoError(t, err)
+	require.Len(t, spec.Limits, 2)
+
+	container := spec.Limits[0]
+	assert.Equal(t, api.LimitTypeContainer, container.Type)
+	assert.Equal(t, "100m", container.DefaultRequest.Cpu().String())
+	assert.Equal(t, "200m", container.Default.Cpu().String())
+	assert.Equal(t, "64Mi", container.Min.Memory().String())
+	assert.Equal(t, "1", container.Max.Cpu().String())
+	assert.Equal(t, "4", container.MaxLimitRequestRatio.Cpu().String())
+
+	pvc := spec.Limits[1]
+	assert.Equal(t, api.LimitTypePersistentVolumeClaim, pvc.Type)
+	max := pvc.Max[api.ResourceStorage]
+	assert.Equal(t, "10Gi", max.String())
+	assert.Empty(t, pvc.Min)
+
+	_, err = ConvertLimitToLimitRangeSpec(&v32.ContainerResourceLimit{Pod: &v32.ResourceLimitBounds{MaxCPU: "lots"}})
+	assert.EqualError(t, err, `invalid quantity "lots" for pod.maxCpu: `+resource.ErrFormatWrong.Error())
+}
+
+func TestValidateLimitRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *v32.ContainerResourceLimit
+		err   string
+	}{
+		{
+			name: "valid",
+			limit: &v32.ContainerResourceLimit{
+				RequestsCPU: "100m",
+				LimitsCPU:   "200m",
+				Container:   &v32.ResourceLimitBounds{MinCPU: "50m", MaxCPU: "1", MaxLimitRequestRatioCPU: "2"},
+				Pod:         &v32.ResourceLimitBounds{MaxMemory: "1Gi"},
+			},
+		},
+		{
+			name:  "min greater than max",
+			limit: &v32.ContainerResourceLimit{Pod: &v32.ResourceLimitBounds{MinMemory: "2Gi", MaxMemory: "1Gi"}},
+			err:   "Pod min 2Gi of memory is greater than its max 1Gi",
+		},
+		{
+			name:  "default request lower than min",
+			limit: &v32.ContainerResourceLimit{RequestsCPU: "10m", Container: &v32.ResourceLimitBounds{MinCPU: "50m"}},
+			err:   "Container default request 10m of cpu is lower than its min 50m",
+		},
+		{
+			name:  "default limit greater than max",
+			limit: &v32.ContainerResourceLimit{LimitsCPU: "2", Container: &v32.ResourceLimitBounds{MaxCPU: "1"}},
+			err:   "Container default limit 2 of cpu is greater than its max 1",
+		},
+		{
+			name:  "default request greater than default limit",
+			limit: &v32.ContainerResourceLimit{RequestsMemory: "2Gi", LimitsMemory: "1Gi"},
+			err:   "Container default request 2Gi of memory is greater than its default limit 1Gi",
+		},
+		{
+			name:  "ratio lower than 1",
+			limit: &v32.ContainerResourceLimit{Container: &v32.ResourceLimitBounds{MaxLimitRequestRatioCPU: "500m"}},
+			err:   "Container max limit/request ratio 500m of cpu is lower than 1",
+		},
+		{
+			name: "defaults exceed ratio",
+			limit: &v32.ContainerResourceLimit{
+				RequestsCPU: "100m",
+				LimitsCPU:   "500m",
+				Container:   &v32.ResourceLimitBounds{MaxLimitRequestRatioCPU: "2"},
+			},
+			err: "Container default limit 500m of cpu is more than 2 times its default request 100m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLimitRange(tt.limit)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateLimitRangeQuota(t *testing.T) {
+	quota := &v32.ResourceQuotaLimit{RequestsCPU: "1", LimitsMemory: "1Gi", RequestsStorage: "5Gi"}
+
+	assert.NoError(t, ValidateLimitRangeQuota(&v32.ContainerResourceLimit{RequestsCPU: "500m", LimitsMemory: "512Mi"}, quota))
+	assert.NoError(t, ValidateLimitRangeQuota(&v32.ContainerResourceLimit{RequestsCPU: "2"}, nil))
+
+	assert.EqualError(t, ValidateLimitRangeQuota(&v32.ContainerResourceLimit{RequestsCPU: "2"}, quota),
+		"Container default request 2 of cpu exceeds the requestsCpu quota 1")
+	// a max without default limit is the default limit of the containers
+	assert.EqualError(t, ValidateLimitRangeQuota(&v32.ContainerResourceLimit{
+		Container: &v32.ResourceLimitBounds{MaxMemory: "2Gi"},
+	}, quota), "Container default limit 2Gi of memory exceeds the limitsMemory quota 1Gi")
+	assert.EqualError(t, ValidateLimitRangeQuota(&v32.ContainerResourceLimit{
+		PersistentVolumeClaim: &v32.StorageLimitBounds{MinStorage: "10Gi"},
+	}, quota), "PersistentVolumeClaim min 10Gi of storage exceeds the requestsStorage quota 5Gi")
+}
@@ -0,0 +1,107 @@
+package resourcequota
+
+import (
+	"testing"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitToMap(t *testing.T) {
+	limit := &v32.ResourceQuotaLimit{
+		Pods:        "10",
+		RequestsCPU: "2",
+		Extended: map[string]string{
+			"requests.nvidia.com/gpu": "4",
+			"count/deployments.apps":  "20",
+		},
+	}
+
+	limits, err := LimitToMap(limit)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"pods":                    "10",
+		"requestsCpu":             "2",
+		"requests.nvidia.com/gpu": "4",
+		"count/deployments.apps":  "20",
+	}, limits)
+
+	converted, err := MapToLimit(limits)
+	require.NoError(t, err)
+	assert.Equal(t, limit, converted)
+
+	converted, err = MapToLimit(map[string]string{"pods": "10"})
+	require.NoError(t, err)
+	assert.Equal(t, &v32.ResourceQuotaLimit{Pods: "10"}, converted)
+}
+
+func TestIsQuotaFitExtended(t *testing.T) {
+	projectLimit := &v32.ResourceQuotaLimit{
+		Pods:     "10",
+		Extended: map[string]string{"requests.nvidia.com/gpu": "4"},
+	}
+	nsLimit := &v32.ResourceQuotaLimit{
+		Pods:     "2",
+		Extended: map[string]string{"requests.nvidia.com/gpu": "2"},
+	}
+
+	fit, exceeded, err := IsQuotaFit(nsLimit, []*v32.ResourceQuotaLimit{nsLimit}, projectLimit)
+	require.NoError(t, err)
+	assert.True(t, fit)
+	assert.Empty(t, exceeded)
+
+	fit, exceeded, err = IsQuotaFit(nsLimit, []*v32.ResourceQuotaLimit{nsLimit, nsLimit}, projectLimit)
+	require.NoError(t, err)
+	assert.False(t, fit)
+	require.Len(t, exceeded, 1)
+	gpus := exceeded["requests.nvidia.com/gpu"]
+	assert.Equal(t, "6", gpus.String())
+}
+
+func TestValidateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		extended map[string]string
+		wantErr  bool
+	}{
+		{
+			name: "valid",
+			extended: map[string]string{
+				"requests.nvidia.com/gpu":                           "1",
+				"count/deployments.apps":                            "5",
+				"gold.storageclass.storage.k8s.io/requests.storage": "10Gi",
+			},
+		},
+		{
+			name:     "standard field name",
+			extended: map[string]string{"pods": "1"},
+			wantErr:  true,
+		},
+		{
+			name:     "standard resource name",
+			extended: map[string]string{"requests.cpu": "1"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid name",
+			extended: map[string]string{"requests nvidia": "1"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid quantity",
+			extended: map[string]string{"requests.nvidia.com/gpu": "many"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLimit(&v32.ResourceQuotaLimit{Extended: tt.extended})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
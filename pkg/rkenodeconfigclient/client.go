@@ -2,6 +2,8 @@
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -9,6 +11,7 @@
 	"io/ioutil"
 	"net/http"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/rancher/rancher/pkg/agent/node"
@@ -22,7 +25,8 @@
 )
 
 var (
-	client = &http.Client{
+	clientLock sync.Mutex
+	client     = &http.Client{
 		Timeout: 300 * time.Second,
 	}
 
@@ -55,7 +59,7 @@ func ConfigClient(ctx context.Context, url string, header http.Header, writeCert
 	nodeOrClusterNotFoundRetryLimit := 3
 	interval := 120
 	for {
-		nc, err := getConfig(client, url, header)
+		nc, err := getConfig(configClient(), url, header)
 		if err != nil {
 			if _, ok := err.(*ErrNodeOrClusterNotFound); ok {
 				if nodeOrClusterNotFoundRetryLimit < 1 {
@@ -113,6 +117,26 @@ func ConfigClient(ctx context.Context, url string, header http.Header, writeCert
 	}
 }
 
+// SetRootCAs rebuilds the client requesting the node config to verify Rancher against rootCAs, for the client to
+// trust the CA Rancher rotated to.
+func SetRootCAs(rootCAs *x509.CertPool) {
+	clientLock.Lock()
+	defer clientLock.Unlock()
+	client = &http.Client{
+		Timeout: 300 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+		},
+	}
+}
+
+func configClient() *http.Client {
+	clientLock.Lock()
+	defer clientLock.Unlock()
+	return client
+}
+
 func getConfig(client *http.Client, url string, header http.Header) (*rkeworker.NodeConfig, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -51,9 +51,11 @@ func namespaceTypes(schemas *types.Schemas) *types.Schemas {
 			ContainerDefaultResourceLimit string `json:"containerDefaultResourceLimit,omitempty" norman:"type=containerResourceLimit"`
 		}{}).
 		MustImport(&Version, NamespaceMove{}).
+		MustImport(&Version, NamespaceMoveOutput{}).
 		MustImportAndCustomize(&Version, v1.Namespace{}, func(schema *types.Schema) {
 			schema.ResourceActions["move"] = types.Action{
-				Input: "namespaceMove",
+				Input:  "namespaceMove",
+				Output: "namespaceMoveOutput",
 			}
 		})
 }
@@ -44,10 +44,34 @@ type ResourceQuotaLimit struct {
 	RequestsStorage        string `json:"requestsStorage,omitempty"`
 	LimitsCPU              string `json:"limitsCpu,omitempty"`
 	LimitsMemory           string `json:"limitsMemory,omitempty"`
+
+	Extended map[string]string `json:"extended,omitempty"`
 }
 
 type NamespaceMove struct {
 	ProjectID string `json:"projectId,omitempty"`
+	// DryRun reports the changes the move would make, without moving the namespace.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// NamespaceMoveOutput reports the changes a move makes to a namespace.
+type NamespaceMoveOutput struct {
+	ProjectID         string `json:"projectId,omitempty"`
+	PreviousProjectID string `json:"previousProjectId,omitempty"`
+	Moved             bool   `json:"moved"`
+	// ResourceQuota is the quota of the namespace in the project it moves to.
+	ResourceQuota          *NamespaceResourceQuota     `json:"resourceQuota,omitempty"`
+	AccessChanges          []NamespaceMoveAccessChange `json:"accessChanges,omitempty"`
+	NetworkPoliciesAdded   []string                    `json:"networkPoliciesAdded,omitempty"`
+	NetworkPoliciesRemoved []string                    `json:"networkPoliciesRemoved,omitempty"`
+}
+
+// NamespaceMoveAccessChange is the role templates a user or group gains or loses in a namespace that moves.
+type NamespaceMoveAccessChange struct {
+	UserName           string   `json:"userName,omitempty"`
+	GroupPrincipalName string   `json:"groupPrincipalName,omitempty"`
+	Granted            []string `json:"granted,omitempty"`
+	Revoked            []string `json:"revoked,omitempty"`
 }
 
 type ContainerResourceLimit struct {
@@ -55,4 +79,22 @@ type ContainerResourceLimit struct {
 	RequestsMemory string `json:"requestsMemory,omitempty"`
 	LimitsCPU      string `json:"limitsCpu,omitempty"`
 	LimitsMemory   string `json:"limitsMemory,omitempty"`
+
+	Container             *ResourceLimitBounds `json:"container,omitempty"`
+	Pod                   *ResourceLimitBounds `json:"pod,omitempty"`
+	PersistentVolumeClaim *StorageLimitBounds  `json:"persistentVolumeClaim,omitempty"`
+}
+
+type ResourceLimitBounds struct {
+	MinCPU                     string `json:"minCpu,omitempty"`
+	MinMemory                  string `json:"minMemory,omitempty"`
+	MaxCPU                     string `json:"maxCpu,omitempty"`
+	MaxMemory                  string `json:"maxMemory,omitempty"`
+	MaxLimitRequestRatioCPU    string `json:"maxLimitRequestRatioCpu,omitempty"`
+	MaxLimitRequestRatioMemory string `json:"maxLimitRequestRatioMemory,omitempty"`
+}
+
+type StorageLimitBounds struct {
+	MinStorage string `json:"minStorage,omitempty"`
+	MaxStorage string `json:"maxStorage,omitempty"`
 }
@@ -254,6 +254,8 @@ func clusterTypes(schemas *types.Schemas) *types.Schemas {
 		MustImport(&Version, v3.RestoreFromEtcdBackupInput{}).
 		MustImport(&Version, v3.SaveAsTemplateInput{}).
 		MustImport(&Version, v3.SaveAsTemplateOutput{}).
+		MustImport(&Version, v3.CheckImagesInput{}).
+		MustImport(&Version, v3.CheckImagesOutput{}).
 		AddMapperForType(&Version, v1.EnvVar{},
 			&m.Move{
 				From: "envVar",
@@ -312,6 +314,10 @@ func clusterTypes(schemas *types.Schemas) *types.Schemas {
 				Input:  "saveAsTemplateInput",
 				Output: "saveAsTemplateOutput",
 			}
+			schema.ResourceActions[v3.ClusterActionCheckImages] = types.Action{
+				Input:  "checkImagesInput",
+				Output: "checkImagesOutput",
+			}
 		})
 }
 
@@ -967,11 +973,16 @@ func clusterTemplateTypes(schemas *types.Schemas) *types.Schemas {
 			&m.Embed{Field: "status"},
 			m.DisplayName{}).
 		MustImport(&Version, v3.ClusterTemplateQuestionsOutput{}).
+		MustImport(&Version, v3.ClusterTemplateMigrationInput{}).
+		MustImport(&Version, v3.ClusterTemplateComplianceOutput{}).
 		MustImport(&Version, v3.ClusterTemplate{}).
 		MustImportAndCustomize(&Version, v3.ClusterTemplateRevision{}, func(schema *types.Schema) {
 			schema.ResourceActions = map[string]types.Action{
 				"disable": {},
 				"enable":  {},
+				"migrateClusters": {
+					Input: "clusterTemplateMigrationInput",
+				},
 			}
 			schema.CollectionActions = map[string]types.Action{
 				"listquestions": {
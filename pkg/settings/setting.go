@@ -59,11 +59,13 @@
 	KDMBranch                           = NewSetting("kdm-branch", "release-v2.6")
 	MachineVersion                      = NewSetting("machine-version", "dev")
 	Namespace                           = NewSetting("namespace", os.Getenv("CATTLE_NAMESPACE"))
+	NextCACerts                         = NewSetting("next-cacerts", "")
 	PasswordMinLength                   = NewSetting("password-min-length", "12")
 	PeerServices                        = NewSetting("peer-service", os.Getenv("CATTLE_PEER_SERVICE"))
 	RDNSServerBaseURL                   = NewSetting("rdns-base-url", "https://api.lb.rancher.cloud/v1")
 	RkeVersion                          = NewSetting("rke-version", "")
 	RkeMetadataConfig                   = NewSetting("rke-metadata-config", getMetadataConfig())
+	ServerFailoverURLs                  = NewSetting("server-failover-urls", "")
 	ServerImage                         = NewSetting("server-image", "rancher/rancher")
 	ServerURL                           = NewSetting("server-url", "")
 	ServerVersion                       = NewSetting("server-version", "dev")
@@ -118,6 +120,22 @@
 	// AuthUserSessionTTLMinutes represents the time to live for tokens used for login sessions in minutes.
 	AuthUserSessionTTLMinutes = NewSetting("auth-user-session-ttl-minutes", "960") // 16 hours
 
+	// BindingRequestApproverRoles is a comma separated list of the RoleTemplates whose members may approve the
+	// RoleTemplateBindingRequests of their cluster or project. Global admins may approve any request.
+	BindingRequestApproverRoles = NewSetting("binding-request-approver-roles", "cluster-owner,project-owner")
+
+	// RBACDriftCheckIntervalMinutes is how often the RoleBindings and ClusterRoleBindings of each cluster are compared
+	// with the ones its ClusterRoleTemplateBindings and ProjectRoleTemplateBindings grant, 0 to disable the check.
+	RBACDriftCheckIntervalMinutes = NewSetting("rbac-drift-check-interval-minutes", "60")
+
+	// RBACDriftAutoRepair makes the RBAC drift check recreate the missing and modified bindings it finds, and delete
+	// the stale ones Rancher created. Bindings that Rancher did not create are only reported.
+	RBACDriftAutoRepair = NewSetting("rbac-drift-auto-repair", "false")
+
+	// ResourceQuotaAlertThresholds is a comma separated list of the percentages of a project quota whose crossing by
+	// the usage of a resource raises an event, for the projects that do not set their own thresholds.
+	ResourceQuotaAlertThresholds = NewSetting("resource-quota-alert-thresholds", "80,95")
+
 	// CSPAdapterMinVersion is used to determine if an existing installation of the CSP adapter should be upgraded to a new version
 	// has no effect if the csp adapter is not installed
 	CSPAdapterMinVersion = NewSetting("csp-adapter-min-version", "")
@@ -140,6 +158,34 @@
 	// Deprecated: On removal use kubeconfig-default-ttl-minutes for all kubeconfigs.
 	KubeconfigTokenTTLMinutes = NewSetting("kubeconfig-token-ttl-minutes", "960") // 16 hours
 
+	// ClusterProxyBreakerThreshold is the number of consecutive failures to dial a downstream cluster after which the
+	// requests proxied to it fail fast, until ClusterProxyBreakerCooldownSeconds have passed. 0 disables the breaker.
+	ClusterProxyBreakerThreshold = NewSetting("cluster-proxy-breaker-threshold", "5")
+
+	// ClusterProxyBreakerCooldownSeconds is the time for which the requests to a cluster fail fast once its breaker opened.
+	ClusterProxyBreakerCooldownSeconds = NewSetting("cluster-proxy-breaker-cooldown-seconds", "30")
+
+	// ClusterProxyUserQPS is the rate of requests each user can proxy to each downstream cluster. 0 disables the limit.
+	ClusterProxyUserQPS = NewSetting("cluster-proxy-user-qps", "50")
+
+	// ClusterProxyUserBurst is the number of requests each user can proxy to each downstream cluster in a burst.
+	ClusterProxyUserBurst = NewSetting("cluster-proxy-user-burst", "100")
+
+	// LogLevels is a comma separated list of component=level pairs overriding the log level of the packages under each
+	// component that log through a component logger, such as provisioningv2/rke2/planner=debug. The global log level is
+	// unchanged. A component without a component logger under it is logged as a warning and has no effect. It applies
+	// to every Rancher server without a restart.
+	LogLevels = NewSetting("log-levels", "")
+
+	// TunnelSteeringMaxAttempts is the number of times in a row an agent connecting to a Rancher server that does not
+	// own its cluster is asked to reconnect, hoping to land on the owner, before it is accepted. 0 disables steering.
+	// The agents do not bound the reconnects themselves, so this is the only limit.
+	TunnelSteeringMaxAttempts = NewSetting("tunnel-steering-max-attempts", "3")
+
+	// TunnelRebalanceDelaySeconds is the time the Rancher servers wait for their peers to settle after a change before
+	// they disconnect the agents of the clusters they do not own, so that the agents get steered. 0 disables it.
+	TunnelRebalanceDelaySeconds = NewSetting("tunnel-rebalance-delay-seconds", "60")
+
 	// RancherWebhookMinVersion is the minimum version of the webhook that rancher will install
 	RancherWebhookMinVersion = NewSetting("rancher-webhook-min-version", "")
 
@@ -358,6 +404,7 @@ func DefaultAgentSettings() []Setting {
 		ServerVersion,
 		InstallUUID,
 		IngressIPDomain,
+		ServerFailoverURLs,
 	}
 }
 
@@ -39,6 +39,8 @@
 	rancherCertFile    = "/etc/rancher/ssl/cert.pem"
 	rancherKeyFile     = "/etc/rancher/ssl/key.pem"
 	rancherCACertsFile = "/etc/rancher/ssl/cacerts.pem"
+	// rancherNextCACertsFile holds the CA Rancher is about to rotate to, announced to the agents before the rotation.
+	rancherNextCACertsFile = "/etc/rancher/ssl/next/cacerts.pem"
 
 	commonName = "rancher"
 )
@@ -214,9 +216,36 @@ func SetupListener(secrets corev1controllers.SecretController, acmeDomains []str
 		}
 	}
 
+	nextCAForAgent, err := readNextCA(caForAgent, noCACerts)
+	if err != nil {
+		return nil, err
+	}
+	if settings.NextCACerts.Get() != nextCAForAgent {
+		if err := settings.NextCACerts.Set(nextCAForAgent); err != nil {
+			return nil, err
+		}
+	}
+
 	return opts, nil
 }
 
+// readNextCA returns the CA Rancher is about to rotate to, for agents pinning the CA checksum to learn it ahead of
+// the rotation. There is none once the rotation is done and the next CA became the current one.
+func readNextCA(caForAgent string, noCACerts bool) (string, error) {
+	if noCACerts || !fileExists(rancherNextCACertsFile) {
+		return "", nil
+	}
+	ca, err := readPEM(rancherNextCACertsFile)
+	if err != nil {
+		return "", err
+	}
+	ca = strings.TrimSpace(ca)
+	if ca == caForAgent {
+		return "", nil
+	}
+	return ca, nil
+}
+
 func readConfig(secrets corev1controllers.SecretController, acmeDomains []string, noCACerts bool) (string, bool, *server.ListenOpts, error) {
 	var (
 		ca  string
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+)
+
+// Handler traces the requests served by next, continuing the traces of the callers propagated in their trace headers.
+func Handler(operation string, next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, operation)
+}
+
+type phaseKey struct{}
+
+type phase struct {
+	span   trace.Span
+	parent trace.Span
+}
+
+// Phase traces the time spent in a middleware, e.g. an authentication filter, until it hands the request over to the
+// next handler. The spans started by the next handlers are siblings of the span of the phase rather than its children.
+func Phase(name string, middleware func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		handler := middleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			if p, ok := ctx.Value(phaseKey{}).(*phase); ok {
+				p.span.End()
+				ctx = trace.ContextWithSpan(ctx, p.parent)
+			}
+			next.ServeHTTP(rw, req.WithContext(ctx))
+		}))
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			parent := trace.SpanFromContext(req.Context())
+			ctx, span := StartSpan(req.Context(), name)
+			defer span.End()
+			ctx = context.WithValue(ctx, phaseKey{}, &phase{span: span, parent: parent})
+			handler.ServeHTTP(rw, req.WithContext(ctx))
+		})
+	}
+}
+
+// Transport traces the round trips of rt and propagates the trace headers to the servers. Upgrade requests are not
+// traced, as their response bodies must not be wrapped.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return &transport{
+		base:   rt,
+		traced: otelhttp.NewTransport(rt),
+	}
+}
+
+type transport struct {
+	base   http.RoundTripper
+	traced http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if httpstream.IsUpgradeRequest(req) {
+		return t.base.RoundTrip(req)
+	}
+	return t.traced.RoundTrip(req)
+}
+
+// WrappedRoundTripper exposes the wrapped transport, so that its dialer can still be found for upgrade requests.
+func (t *transport) WrappedRoundTripper() http.RoundTripper {
+	return t.base
+}
+
+// DialContext traces the connections opened by dial, e.g. through the tunnel of a cluster.
+func DialContext(cluster string, dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		ctx, span := StartSpan(ctx, "tunnel.dial",
+			attribute.String("cluster", cluster),
+			attribute.String("net.transport", network),
+			attribute.String("net.peer.name", address),
+		)
+		conn, err := dial(ctx, network, address)
+		EndSpan(span, err)
+		return conn, err
+	}
+}
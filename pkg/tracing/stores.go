@@ -0,0 +1,154 @@
+package tracing
+
+import (
+	"github.com/rancher/apiserver/pkg/types"
+	normantypes "github.com/rancher/norman/types"
+	"github.com/rancher/steve/pkg/schema"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// NormanSchemas traces the operations of the stores of the norman schemas.
+func NormanSchemas(schemas *normantypes.Schemas) {
+	for _, s := range schemas.Schemas() {
+		if s.Store == nil {
+			continue
+		}
+		if _, ok := s.Store.(*normanStore); ok {
+			continue
+		}
+		s.Store = &normanStore{Store: s.Store}
+	}
+}
+
+type normanStore struct {
+	normantypes.Store
+}
+
+// start starts the span of an operation, carried by the request of apiContext until the returned func restores it.
+func (s *normanStore) start(apiContext *normantypes.APIContext, schema *normantypes.Schema, operation string) func(error) {
+	req := apiContext.Request
+	if req == nil {
+		return func(error) {}
+	}
+	ctx, span := StartSpan(req.Context(), "norman.store."+operation,
+		attribute.String("schema", schema.ID),
+		attribute.String("version", schema.Version.Version),
+	)
+	apiContext.Request = req.WithContext(ctx)
+	return func(err error) {
+		apiContext.Request = req
+		EndSpan(span, err)
+	}
+}
+
+func (s *normanStore) ByID(apiContext *normantypes.APIContext, schema *normantypes.Schema, id string) (map[string]interface{}, error) {
+	end := s.start(apiContext, schema, "byID")
+	result, err := s.Store.ByID(apiContext, schema, id)
+	end(err)
+	return result, err
+}
+
+func (s *normanStore) List(apiContext *normantypes.APIContext, schema *normantypes.Schema, opt *normantypes.QueryOptions) ([]map[string]interface{}, error) {
+	end := s.start(apiContext, schema, "list")
+	result, err := s.Store.List(apiContext, schema, opt)
+	end(err)
+	return result, err
+}
+
+func (s *normanStore) Create(apiContext *normantypes.APIContext, schema *normantypes.Schema, data map[string]interface{}) (map[string]interface{}, error) {
+	end := s.start(apiContext, schema, "create")
+	result, err := s.Store.Create(apiContext, schema, data)
+	end(err)
+	return result, err
+}
+
+func (s *normanStore) Update(apiContext *normantypes.APIContext, schema *normantypes.Schema, data map[string]interface{}, id string) (map[string]interface{}, error) {
+	end := s.start(apiContext, schema, "update")
+	result, err := s.Store.Update(apiContext, schema, data, id)
+	end(err)
+	return result, err
+}
+
+func (s *normanStore) Delete(apiContext *normantypes.APIContext, schema *normantypes.Schema, id string) (map[string]interface{}, error) {
+	end := s.start(apiContext, schema, "delete")
+	result, err := s.Store.Delete(apiContext, schema, id)
+	end(err)
+	return result, err
+}
+
+func (s *normanStore) Watch(apiContext *normantypes.APIContext, schema *normantypes.Schema, opt *normantypes.QueryOptions) (chan map[string]interface{}, error) {
+	end := s.start(apiContext, schema, "watch")
+	result, err := s.Store.Watch(apiContext, schema, opt)
+	end(err)
+	return result, err
+}
+
+// SteveTemplate is a schema template tracing the operations of the stores of all the steve schemas.
+func SteveTemplate() schema.Template {
+	return schema.Template{
+		Customize: func(s *types.APISchema) {
+			if s.Store == nil {
+				return
+			}
+			if _, ok := s.Store.(*steveStore); ok {
+				return
+			}
+			s.Store = &steveStore{Store: s.Store}
+		},
+	}
+}
+
+type steveStore struct {
+	types.Store
+}
+
+func (s *steveStore) start(apiOp *types.APIRequest, schema *types.APISchema, operation string) (*types.APIRequest, func(error)) {
+	ctx, span := StartSpan(apiOp.Context(), "steve.store."+operation, attribute.String("schema", schema.ID))
+	return apiOp.WithContext(ctx), func(err error) {
+		EndSpan(span, err)
+	}
+}
+
+func (s *steveStore) ByID(apiOp *types.APIRequest, schema *types.APISchema, id string) (types.APIObject, error) {
+	apiOp, end := s.start(apiOp, schema, "byID")
+	result, err := s.Store.ByID(apiOp, schema, id)
+	end(err)
+	return result, err
+}
+
+func (s *steveStore) List(apiOp *types.APIRequest, schema *types.APISchema) (types.APIObjectList, error) {
+	apiOp, end := s.start(apiOp, schema, "list")
+	result, err := s.Store.List(apiOp, schema)
+	end(err)
+	return result, err
+}
+
+func (s *steveStore) Create(apiOp *types.APIRequest, schema *types.APISchema, data types.APIObject) (types.APIObject, error) {
+	apiOp, end := s.start(apiOp, schema, "create")
+	result, err := s.Store.Create(apiOp, schema, data)
+	end(err)
+	return result, err
+}
+
+func (s *steveStore) Update(apiOp *types.APIRequest, schema *types.APISchema, data types.APIObject, id string) (types.APIObject, error) {
+	apiOp, end := s.start(apiOp, schema, "update")
+	result, err := s.Store.Update(apiOp, schema, data, id)
+	end(err)
+	return result, err
+}
+
+func (s *steveStore) Delete(apiOp *types.APIRequest, schema *types.APISchema, id string) (types.APIObject, error) {
+	apiOp, end := s.start(apiOp, schema, "delete")
+	result, err := s.Store.Delete(apiOp, schema, id)
+	end(err)
+	return result, err
+}
+
+// Watch only traces the start of the watch, the context of the watch is left untouched as it lasts as long as the
+// request.
+func (s *steveStore) Watch(apiOp *types.APIRequest, schema *types.APISchema, w types.WatchRequest) (chan types.APIEvent, error) {
+	_, end := s.start(apiOp, schema, "watch")
+	result, err := s.Store.Watch(apiOp, schema, w)
+	end(err)
+	return result, err
+}
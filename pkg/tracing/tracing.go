@@ -0,0 +1,104 @@
+// Package tracing sets up OpenTelemetry tracing of the requests served by Rancher, from the authentication filters
+// down to the tunnels and kube-apiservers of the downstream clusters.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName      = "github.com/rancher/rancher"
+	serviceName     = "rancher"
+	shutdownTimeout = 5 * time.Second
+)
+
+// Setup configures the W3C trace context propagation of the requests and, when a collector endpoint is given, the
+// export of their spans to it over OTLP/HTTP, e.g. http://otel-collector.cattle-monitoring-system:4318. Only the
+// given ratio of the traces started by Rancher is sampled, the traces started by the callers are sampled as they
+// decided. The exporter is flushed and shut down when ctx is done.
+func Setup(ctx context.Context, endpoint string, sampleRatio float64) error {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if endpoint == "" {
+		return nil
+	}
+
+	options, err := driverOptions(endpoint)
+	if err != nil {
+		return err
+	}
+	exporter, err := otlp.NewExporter(ctx, otlphttp.NewDriver(options...))
+	if err != nil {
+		return fmt.Errorf("failed to create the OTLP exporter for %s: %w", endpoint, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(settings.ServerVersion.Get()),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	logrus.Infof("Exporting traces to %s with a sample ratio of %v", endpoint, sampleRatio)
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			logrus.Warnf("Failed to flush traces: %v", err)
+		}
+	}()
+	return nil
+}
+
+func driverOptions(endpoint string) ([]otlphttp.Option, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid tracing endpoint %q, expected a URL such as http://collector:4318", endpoint)
+	}
+
+	options := []otlphttp.Option{otlphttp.WithEndpoint(u.Host)}
+	switch u.Scheme {
+	case "http":
+		options = append(options, otlphttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("invalid tracing endpoint %q, the scheme must be http or https", endpoint)
+	}
+	if u.Path != "" && u.Path != "/" {
+		options = append(options, otlphttp.WithTracesURLPath(u.Path))
+	}
+	return options, nil
+}
+
+// StartSpan starts a span as a child of the span of ctx, if any.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan ends a span, recording err if the operation it covers failed.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+	return exporter
+}
+
+func spansByName(exporter *tracetest.InMemoryExporter) map[string]*sdktrace.SpanSnapshot {
+	result := map[string]*sdktrace.SpanSnapshot{}
+	for _, span := range exporter.GetSpans() {
+		result[span.Name] = span
+	}
+	return result
+}
+
+func TestPhase(t *testing.T) {
+	exporter := setupExporter(t)
+
+	auth := Phase("auth", func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") == "" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(rw, req)
+		})
+	})
+	handler := Handler("rancher", auth(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, span := StartSpan(req.Context(), "store")
+		span.End()
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/clusters", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := spansByName(exporter)
+	require.Len(t, spans, 3)
+	server, authSpan, store := spans["rancher"], spans["auth"], spans["store"]
+	require.NotNil(t, server)
+	require.NotNil(t, authSpan)
+	require.NotNil(t, store)
+	assert.Equal(t, server.SpanContext.SpanID(), authSpan.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.SpanID(), store.Parent.SpanID(), "the spans after the phase must not be its children")
+	assert.False(t, authSpan.EndTime.After(store.StartTime), "the phase must end when the request is handed over")
+
+	exporter.Reset()
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/clusters", nil))
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	spans = spansByName(exporter)
+	assert.Len(t, spans, 2)
+	assert.Contains(t, spans, "auth", "rejected requests must end the phase")
+}
+
+func TestTransport(t *testing.T) {
+	exporter := setupExporter(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		traceparent = req.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	base := &http.Transport{}
+	rt := Transport(base)
+	assert.Same(t, base, rt.(interface{ WrappedRoundTripper() http.RoundTripper }).WrappedRoundTripper())
+
+	ctx, span := StartSpan(context.Background(), "proxy")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	span.End()
+
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Len(t, exporter.GetSpans(), 2)
+}
+
+func TestDialContext(t *testing.T) {
+	exporter := setupExporter(t)
+
+	dial := DialContext("c-1", func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("failed to find Session for client c-1")
+	})
+	_, err := dial(context.Background(), "tcp", "127.0.0.1:6443")
+	assert.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "tunnel.dial", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].StatusCode)
+}
+
+func TestDriverOptions(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		options  int
+		wantErr  bool
+	}{
+		{endpoint: "http://collector:4318", options: 2},
+		{endpoint: "https://collector:4318/custom/v1/traces", options: 2},
+		{endpoint: "collector:4318", wantErr: true},
+		{endpoint: "grpc://collector:4317", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			options, err := driverOptions(tt.endpoint)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, options, tt.options)
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package tunnelserver
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/rancher/rancher/pkg/agent/connect"
 	"github.com/rancher/remotedialer"
 	"github.com/sirupsen/logrus"
 )
@@ -13,6 +15,14 @@ type Authorizers struct {
 }
 
 func ErrorWriter(rw http.ResponseWriter, req *http.Request, code int, err error) {
+	var steerErr *SteerError
+	if errors.As(err, &steerErr) {
+		logrus.Debugf("Steering tunnel request: %v", err)
+		rw.Header().Set(connect.OwnerHeader, steerErr.Owner)
+		remotedialer.DefaultErrorWriter(rw, req, http.StatusMisdirectedRequest, err)
+		return
+	}
+
 	fullAddress := req.RemoteAddr
 	forwardedFor := req.Header.Get("X-Forwarded-For")
 	if forwardedFor != "" {
@@ -36,6 +46,7 @@ func (a *Authorizers) Authorize(req *http.Request) (clientKey string, authed boo
 			}
 			continue
 		}
+		setClientKey(req, key)
 		return key, authed, err
 	}
 
@@ -0,0 +1,31 @@
+package mcmauthorizer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/rancher/rancher/pkg/agent/connect"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ProbeHandler serves the tunnel probe the agent diagnosis checks its token with. Unlike a connection to the tunnel,
+// it only looks up the cluster of the token: it registers no node, updates no cluster and opens no session.
+func (t *Authorizer) ProbeHandler(rw http.ResponseWriter, req *http.Request) {
+	token := req.Header.Get(Token)
+	if token == "" {
+		http.Error(rw, "missing token", http.StatusUnauthorized)
+		return
+	}
+	if _, err := t.getClusterByToken(token); errors.Is(err, ErrClusterNotFound) || apierrors.IsNotFound(err) {
+		http.Error(rw, "token is not valid", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(connect.ProbeResult{WebsocketUpgrade: websocket.IsWebSocketUpgrade(req)})
+}
@@ -0,0 +1,153 @@
+package tunnelserver
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	prometheusMetrics = false
+
+	sessionConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "tunnel_session",
+			Name:      "connected_timestamp_seconds",
+			Help:      "Unix time the current tunnel session of an agent was established on this Rancher server",
+		},
+		[]string{"clientkey", "peer"},
+	)
+
+	sessionReceiveBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "tunnel_session",
+			Name:      "receive_bytes_total",
+			Help:      "Total bytes received over the tunnel sessions of an agent",
+		},
+		[]string{"clientkey"},
+	)
+
+	sessionTransmitBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "tunnel_session",
+			Name:      "transmit_bytes_total",
+			Help:      "Total bytes sent over the tunnel sessions of an agent",
+		},
+		[]string{"clientkey"},
+	)
+
+	sessionActiveStreams = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "tunnel_session",
+			Name:      "active_streams",
+			Help:      "Number of connections currently dialed through the tunnel of an agent",
+		},
+		[]string{"clientkey"},
+	)
+
+	sessionDialDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "tunnel_session",
+			Name:      "dial_duration_seconds",
+			Help:      "Time taken to dial a connection through the tunnel of an agent",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"clientkey", "success"},
+	)
+
+	sessionReconnects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "tunnel_session",
+			Name:      "reconnects_total",
+			Help:      "Total count of tunnel sessions an agent established on this Rancher server after its first one",
+		},
+		[]string{"clientkey"},
+	)
+
+	sessionSteered = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "tunnel_session",
+			Name:      "steered_total",
+			Help:      "Total count of connection attempts of an agent steered to the Rancher server owning its cluster",
+		},
+		[]string{"clientkey"},
+	)
+
+	// SessionCollectors are the tunnel session metrics labeled by client key, so that the metrics of deleted
+	// clusters and nodes can be garbage collected.
+	SessionCollectors = []interface{}{
+		sessionConnected, sessionReceiveBytes, sessionTransmitBytes, sessionActiveStreams, sessionDialDuration,
+		sessionReconnects, sessionSteered,
+	}
+)
+
+// RegisterMetrics registers the tunnel session metrics for Prometheus.
+func RegisterMetrics() {
+	prometheusMetrics = true
+
+	prometheus.MustRegister(sessionConnected)
+	prometheus.MustRegister(sessionReceiveBytes)
+	prometheus.MustRegister(sessionTransmitBytes)
+	prometheus.MustRegister(sessionActiveStreams)
+	prometheus.MustRegister(sessionDialDuration)
+	prometheus.MustRegister(sessionReconnects)
+	prometheus.MustRegister(sessionSteered)
+}
+
+func setSessionConnected(clientKey string, peer bool, connectedAt time.Time) {
+	if prometheusMetrics {
+		sessionConnected.With(prometheus.Labels{
+			"clientkey": clientKey,
+			"peer":      strconv.FormatBool(peer),
+		}).Set(float64(connectedAt.Unix()))
+	}
+}
+
+func unsetSessionConnected(clientKey string, peer bool) {
+	if prometheusMetrics {
+		sessionConnected.Delete(prometheus.Labels{
+			"clientkey": clientKey,
+			"peer":      strconv.FormatBool(peer),
+		})
+	}
+}
+
+func addReceiveBytes(clientKey string, n int) {
+	if prometheusMetrics {
+		sessionReceiveBytes.With(prometheus.Labels{"clientkey": clientKey}).Add(float64(n))
+	}
+}
+
+func addTransmitBytes(clientKey string, n int) {
+	if prometheusMetrics {
+		sessionTransmitBytes.With(prometheus.Labels{"clientkey": clientKey}).Add(float64(n))
+	}
+}
+
+func setActiveStreams(clientKey string, streams int64) {
+	if prometheusMetrics {
+		sessionActiveStreams.With(prometheus.Labels{"clientkey": clientKey}).Set(float64(streams))
+	}
+}
+
+func observeDial(clientKey string, duration time.Duration, err error) {
+	if prometheusMetrics {
+		sessionDialDuration.With(prometheus.Labels{
+			"clientkey": clientKey,
+			"success":   strconv.FormatBool(err == nil),
+		}).Observe(duration.Seconds())
+	}
+}
+
+func incReconnects(clientKey string) {
+	if prometheusMetrics {
+		sessionReconnects.With(prometheus.Labels{"clientkey": clientKey}).Inc()
+	}
+}
+
+func incSteered(clientKey string) {
+	if prometheusMetrics {
+		sessionSteered.With(prometheus.Labels{"clientkey": clientKey}).Inc()
+	}
+}
@@ -179,6 +179,13 @@ func (p *peerManager) addRemovePeers(endpoints *v1.Endpoints) {
 }
 
 func (p *peerManager) notify() {
+	peers := p.currentPeers()
+	for c := range p.listeners {
+		c <- peers
+	}
+}
+
+func (p *peerManager) currentPeers() peermanager.Peers {
 	peers := peermanager.Peers{
 		Leader: p.leader,
 		Ready:  p.ready,
@@ -188,10 +195,14 @@ func (p *peerManager) notify() {
 	for id := range p.peers {
 		peers.IDs = append(peers.IDs, id)
 	}
+	return peers
+}
 
-	for c := range p.listeners {
-		c <- peers
-	}
+// Peers returns the current view of the Rancher servers this one peers with.
+func (p *peerManager) Peers() peermanager.Peers {
+	p.Lock()
+	defer p.Unlock()
+	return p.currentPeers()
 }
 
 func (p *peerManager) AddListener(c chan<- peermanager.Peers) {
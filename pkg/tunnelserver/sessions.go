@@ -0,0 +1,334 @@
+package tunnelserver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rancher/rancher/pkg/agent/connect"
+	"github.com/rancher/remotedialer"
+)
+
+// reconnectWindow is how long the connect count of a client key is kept after its last session is gone. An agent
+// connecting again within the window is counted as a reconnect.
+const reconnectWindow = time.Hour
+
+type clientKeyContextKey struct{}
+
+// clientKeyHolder carries the client key the authorizers resolve for a connect request back to the Sessions handler.
+type clientKeyHolder struct {
+	clientKey string
+}
+
+// setClientKey records the client key a connect request was authorized as, if the request is tracked.
+func setClientKey(req *http.Request, clientKey string) {
+	if holder, ok := req.Context().Value(clientKeyContextKey{}).(*clientKeyHolder); ok {
+		holder.clientKey = clientKey
+	}
+}
+
+// SessionInfo describes a live tunnel session on this Rancher server.
+type SessionInfo struct {
+	ClientKey     string    `json:"clientKey"`
+	Cluster       string    `json:"cluster,omitempty"`
+	Node          string    `json:"node,omitempty"`
+	Peer          bool      `json:"peer"`
+	Steerable     bool      `json:"steerable"`
+	RemoteAddress string    `json:"remoteAddress"`
+	ConnectedAt   time.Time `json:"connectedAt"`
+	BytesReceived int64     `json:"bytesReceived"`
+	BytesSent     int64     `json:"bytesSent"`
+	ActiveStreams int64     `json:"activeStreams"`
+	Reconnects    int       `json:"reconnects"`
+}
+
+type session struct {
+	id            int64
+	clientKey     string
+	peer          bool
+	steerable     bool
+	remoteAddress string
+	conn          net.Conn
+	connectedAt   time.Time
+	received      int64
+	sent          int64
+}
+
+// Sessions keeps track of the tunnel sessions of the remotedialer server: the agents and peers connected to this
+// Rancher server, the traffic of their sessions and the connections dialed through them.
+type Sessions struct {
+	lock     sync.Mutex
+	server   *remotedialer.Server
+	nextID   int64
+	sessions map[int64]*session
+	streams  map[string]int64
+	connects map[string]int
+	// disconnected holds when the last session of a client key in connects was removed
+	disconnected map[string]time.Time
+}
+
+func NewSessions(server *remotedialer.Server) *Sessions {
+	return &Sessions{
+		server:       server,
+		sessions:     map[int64]*session{},
+		streams:      map[string]int64{},
+		connects:     map[string]int{},
+		disconnected: map[string]time.Time{},
+	}
+}
+
+// Handler wraps the connect handler of the remotedialer server. A session is tracked from the moment its connection
+// is upgraded to a websocket until the handler returns.
+func (s *Sessions) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		holder := &clientKeyHolder{}
+		req = req.WithContext(context.WithValue(req.Context(), clientKeyContextKey{}, holder))
+		writer := &sessionWriter{
+			ResponseWriter: rw,
+			sessions:       s,
+			req:            req,
+			holder:         holder,
+		}
+		next.ServeHTTP(writer, req)
+		if writer.session != nil {
+			s.remove(writer.session)
+		}
+	})
+}
+
+// Dialer returns the dialer of the remotedialer server for clientKey, instrumented to record the dial latency and
+// the connections open through the tunnel.
+func (s *Sessions) Dialer(clientKey string) remotedialer.Dialer {
+	dialer := s.server.Dialer(clientKey)
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := dialer(ctx, network, address)
+		observeDial(clientKey, time.Since(start), err)
+		if err != nil {
+			return nil, err
+		}
+		s.addStreams(clientKey, 1)
+		return &streamConn{Conn: conn, closed: func() { s.addStreams(clientKey, -1) }}, nil
+	}
+}
+
+// List returns the live sessions, sorted by client key and connect time.
+func (s *Sessions) List() []SessionInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result := make([]SessionInfo, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		result = append(result, s.info(session))
+	}
+
+	sortSessions(result)
+	return result
+}
+
+// disconnect closes the connections of the sessions matching the filter and returns them. The remotedialer server
+// then drops the sessions and their agents reconnect.
+func (s *Sessions) disconnect(filter func(info SessionInfo) bool) []SessionInfo {
+	var (
+		result []SessionInfo
+		conns  []net.Conn
+	)
+
+	s.lock.Lock()
+	for _, session := range s.sessions {
+		if info := s.info(session); filter(info) {
+			result = append(result, info)
+			conns = append(conns, session.conn)
+		}
+	}
+	s.lock.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+	sortSessions(result)
+	return result
+}
+
+func (s *Sessions) info(session *session) SessionInfo {
+	info := SessionInfo{
+		ClientKey:     session.clientKey,
+		Peer:          session.peer,
+		Steerable:     session.steerable,
+		RemoteAddress: session.remoteAddress,
+		ConnectedAt:   session.connectedAt,
+		BytesReceived: atomic.LoadInt64(&session.received),
+		BytesSent:     atomic.LoadInt64(&session.sent),
+		ActiveStreams: s.streams[session.clientKey],
+		Reconnects:    s.connects[session.clientKey] - 1,
+	}
+	if !session.peer {
+		info.Cluster, info.Node = splitClientKey(session.clientKey)
+	}
+	return info
+}
+
+// sortSessions sorts the sessions by client key and connect time.
+func sortSessions(sessions []SessionInfo) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].ClientKey != sessions[j].ClientKey {
+			return sessions[i].ClientKey < sessions[j].ClientKey
+		}
+		return sessions[i].ConnectedAt.Before(sessions[j].ConnectedAt)
+	})
+}
+
+func (s *Sessions) add(clientKey string, peer, steerable bool, remoteAddress string, conn net.Conn) *session {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.nextID++
+	session := &session{
+		id:            s.nextID,
+		clientKey:     clientKey,
+		peer:          peer,
+		steerable:     steerable,
+		remoteAddress: remoteAddress,
+		conn:          conn,
+		connectedAt:   time.Now(),
+	}
+	s.sessions[session.id] = session
+	s.pruneConnects(session.connectedAt)
+	delete(s.disconnected, clientKey)
+	s.connects[clientKey]++
+	if s.connects[clientKey] > 1 {
+		incReconnects(clientKey)
+	}
+	setSessionConnected(clientKey, peer, session.connectedAt)
+	return session
+}
+
+func (s *Sessions) remove(session *session) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.sessions, session.id)
+	connected, connectedAsPeer := false, false
+	for _, other := range s.sessions {
+		if other.clientKey == session.clientKey {
+			connected = true
+			connectedAsPeer = connectedAsPeer || other.peer == session.peer
+		}
+	}
+	if !connected {
+		s.disconnected[session.clientKey] = time.Now()
+	}
+	if !connectedAsPeer {
+		unsetSessionConnected(session.clientKey, session.peer)
+	}
+}
+
+// pruneConnects forgets the connect count of the client keys without a session for longer than reconnectWindow, so
+// that agents that went away for good do not pile up.
+func (s *Sessions) pruneConnects(now time.Time) {
+	for clientKey, disconnectedAt := range s.disconnected {
+		if now.Sub(disconnectedAt) > reconnectWindow {
+			delete(s.connects, clientKey)
+			delete(s.disconnected, clientKey)
+		}
+	}
+}
+
+func (s *Sessions) addStreams(clientKey string, delta int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.streams[clientKey] += delta
+	setActiveStreams(clientKey, s.streams[clientKey])
+	if s.streams[clientKey] <= 0 {
+		delete(s.streams, clientKey)
+	}
+}
+
+// splitClientKey returns the cluster and node of a client key. Cluster agents connect as <cluster> and node agents
+// as <cluster>:<node>.
+func splitClientKey(clientKey string) (string, string) {
+	parts := strings.SplitN(clientKey, ":", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return clientKey, ""
+}
+
+func remoteAddress(req *http.Request) string {
+	if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		return strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+	return req.RemoteAddr
+}
+
+// sessionWriter starts tracking the session when the websocket upgrade hijacks the connection, and counts the bytes
+// flowing over it.
+type sessionWriter struct {
+	http.ResponseWriter
+	sessions *Sessions
+	req      *http.Request
+	holder   *clientKeyHolder
+	session  *session
+}
+
+func (w *sessionWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// peers are authenticated by the remotedialer server itself and never reach the authorizers
+	clientKey, peer := w.holder.clientKey, false
+	if clientKey == "" {
+		clientKey, peer = w.req.Header.Get(remotedialer.ID), true
+	}
+	steerable := !peer && w.req.Header.Get(connect.SteeringHeader) != ""
+	w.session = w.sessions.add(clientKey, peer, steerable, remoteAddress(w.req), conn)
+	return &countingConn{Conn: conn, session: w.session}, rw, nil
+}
+
+type countingConn struct {
+	net.Conn
+	session *session
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		atomic.AddInt64(&c.session.received, int64(n))
+		addReceiveBytes(c.session.clientKey, n)
+	}
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		atomic.AddInt64(&c.session.sent, int64(n))
+		addTransmitBytes(c.session.clientKey, n)
+	}
+	return n, err
+}
+
+type streamConn struct {
+	net.Conn
+	once   sync.Once
+	closed func()
+}
+
+func (c *streamConn) Close() error {
+	c.once.Do(c.closed)
+	return c.Conn.Close()
+}
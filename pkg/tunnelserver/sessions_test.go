@@ -0,0 +1,150 @@
+package tunnelserver
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/peermanager"
+	"github.com/rancher/remotedialer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePeerManager struct {
+	peermanager.PeerManager
+	peers peermanager.Peers
+}
+
+func (f *fakePeerManager) Peers() peermanager.Peers {
+	return f.peers
+}
+
+// connectAgent simulates a connect request: the authorizers run, then the connection is hijacked and served until
+// done is closed.
+func connectAgent(t *testing.T, sessions *Sessions, header http.Header, clientKey string, done <-chan struct{}) {
+	t.Helper()
+	auth := &Authorizers{}
+	auth.Add(func(req *http.Request) (string, bool, error) {
+		return clientKey, clientKey != "", nil
+	})
+
+	hijacked := make(chan struct{})
+	handler := sessions.Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		auth.Authorize(req)
+		conn, _, err := rw.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Write([]byte("hello"))
+		close(hijacked)
+		<-done
+	}))
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		req.Write(conn)
+		bufio.NewReader(conn).ReadString('o')
+	}()
+	<-hijacked
+}
+
+func TestSessions(t *testing.T) {
+	sessions := NewSessions(remotedialer.New(nil, nil))
+	done := make(chan struct{})
+	defer close(done)
+
+	connectAgent(t, sessions, nil, "c-abc:m-1", done)
+	connectAgent(t, sessions, nil, "c-abc", done)
+	connectAgent(t, sessions, http.Header{remotedialer.ID: []string{"10.0.0.2"}}, "", done)
+
+	list := sessions.List()
+	require.Len(t, list, 3)
+	assert.Equal(t, "10.0.0.2", list[0].ClientKey)
+	assert.True(t, list[0].Peer)
+	assert.Equal(t, "c-abc", list[1].Cluster)
+	assert.Equal(t, "", list[1].Node)
+	assert.Equal(t, "c-abc", list[2].Cluster)
+	assert.Equal(t, "m-1", list[2].Node)
+	assert.Equal(t, int64(5), list[2].BytesSent)
+
+	handler := &sessionsHandler{
+		sessions: sessions,
+		peerManager: &fakePeerManager{peers: peermanager.Peers{
+			SelfID: "10.0.0.1",
+			IDs:    []string{"10.0.0.3", "10.0.0.2"},
+			Leader: true,
+		}},
+	}
+	response := handler.list("")
+	assert.Equal(t, "10.0.0.1", response.Replica)
+	assert.True(t, response.Leader)
+	assert.Len(t, response.Clusters["c-abc"], 2)
+	require.Len(t, response.Peers, 2)
+	assert.Equal(t, "10.0.0.2", response.Peers[0].ID)
+	assert.True(t, response.Peers[0].Connected)
+	assert.False(t, response.Peers[1].Connected)
+
+	assert.Empty(t, handler.list("c-other").Clusters)
+}
+
+func TestSessionsReconnect(t *testing.T) {
+	sessions := NewSessions(remotedialer.New(nil, nil))
+
+	first := make(chan struct{})
+	connectAgent(t, sessions, nil, "c-abc", first)
+	close(first)
+	assert.Eventually(t, func() bool { return len(sessions.List()) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	second := make(chan struct{})
+	defer close(second)
+	connectAgent(t, sessions, nil, "c-abc", second)
+	list := sessions.List()
+	require.Len(t, list, 1)
+	assert.Equal(t, 1, list[0].Reconnects)
+}
+
+func TestSessionsPruneConnects(t *testing.T) {
+	sessions := NewSessions(remotedialer.New(nil, nil))
+
+	first := make(chan struct{})
+	connectAgent(t, sessions, nil, "c-abc", first)
+	close(first)
+	assert.Eventually(t, func() bool { return len(sessions.List()) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	sessions.lock.Lock()
+	assert.Equal(t, 1, sessions.connects["c-abc"])
+	sessions.disconnected["c-abc"] = time.Now().Add(-2 * reconnectWindow)
+	sessions.lock.Unlock()
+
+	// the count of an agent gone for longer than the window is dropped when another session connects
+	second := make(chan struct{})
+	defer close(second)
+	connectAgent(t, sessions, nil, "c-other", second)
+	sessions.lock.Lock()
+	defer sessions.lock.Unlock()
+	assert.NotContains(t, sessions.connects, "c-abc")
+	assert.Empty(t, sessions.disconnected)
+	assert.Equal(t, 1, sessions.connects["c-other"])
+}
+
+func TestSplitClientKey(t *testing.T) {
+	cluster, node := splitClientKey("c-abc:m-xyz")
+	assert.Equal(t, "c-abc", cluster)
+	assert.Equal(t, "m-xyz", node)
+
+	cluster, node = splitClientKey("local")
+	assert.Equal(t, "local", cluster)
+	assert.Equal(t, "", node)
+}
@@ -0,0 +1,151 @@
+package tunnelserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/rancher/rancher/pkg/auth/requests/sar"
+	"github.com/rancher/rancher/pkg/auth/util"
+	"github.com/rancher/rancher/pkg/logging"
+	"github.com/rancher/rancher/pkg/peermanager"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// SessionsEndpoint is the path the tunnel sessions of this Rancher server are listed at.
+const SessionsEndpoint = "/v1/tunnelsessions"
+
+// SessionsResponse lists the live tunnel sessions on one Rancher server.
+type SessionsResponse struct {
+	// Replica is the peer ID of this Rancher server, empty when not running in clustered mode.
+	Replica string `json:"replica,omitempty"`
+	Leader  bool   `json:"leader"`
+	// Peers are the other Rancher servers known to the peer manager, with the sessions they opened to this one.
+	Peers []PeerSessions `json:"peers"`
+	// Clusters holds the agent sessions, keyed by cluster.
+	Clusters map[string][]SessionInfo `json:"clusters"`
+}
+
+// PeerSessions are the sessions a peer Rancher server has open to this one.
+type PeerSessions struct {
+	ID        string        `json:"id"`
+	Connected bool          `json:"connected"`
+	Sessions  []SessionInfo `json:"sessions,omitempty"`
+}
+
+type sessionsHandler struct {
+	sessions             *Sessions
+	peerManager          peermanager.PeerManager
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewSessionsHandler returns the handler of SessionsEndpoint. The optional cluster query parameter limits the
+// agent sessions to one cluster. Access requires the permission to get tunnelsessions in management.cattle.io.
+func NewSessionsHandler(sessions *Sessions, peerManager peermanager.PeerManager, subjectAccessReviews authv1.SubjectAccessReviewInterface) http.Handler {
+	return &sessionsHandler{
+		sessions:             sessions,
+		peerManager:          peerManager,
+		subjectAccessReviews: subjectAccessReviews,
+	}
+}
+
+func (h *sessionsHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !authorize(rw, req, h.subjectAccessReviews, "get") {
+		return
+	}
+
+	response := h.list(req.URL.Query().Get("cluster"))
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(response); err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to write tunnel sessions response: %v", err)
+	}
+}
+
+func (h *sessionsHandler) list(cluster string) SessionsResponse {
+	response := SessionsResponse{
+		Clusters: map[string][]SessionInfo{},
+	}
+
+	peerSessions := map[string][]SessionInfo{}
+	for _, session := range h.sessions.List() {
+		if session.Peer {
+			peerSessions[session.ClientKey] = append(peerSessions[session.ClientKey], session)
+			continue
+		}
+		if cluster != "" && session.Cluster != cluster {
+			continue
+		}
+		response.Clusters[session.Cluster] = append(response.Clusters[session.Cluster], session)
+	}
+
+	if h.peerManager != nil {
+		peers := h.peerManager.Peers()
+		response.Replica = peers.SelfID
+		response.Leader = peers.Leader
+		for _, id := range peers.IDs {
+			response.Peers = append(response.Peers, PeerSessions{
+				ID:        id,
+				Connected: len(peerSessions[id]) > 0,
+				Sessions:  peerSessions[id],
+			})
+		}
+	}
+	sort.Slice(response.Peers, func(i, j int) bool {
+		return response.Peers[i].ID < response.Peers[j].ID
+	})
+	return response
+}
+
+// RebalanceResponse lists the agent sessions a rebalance disconnected from one Rancher server.
+type RebalanceResponse struct {
+	Replica      string        `json:"replica,omitempty"`
+	Disconnected []SessionInfo `json:"disconnected"`
+}
+
+type rebalanceHandler struct {
+	steering             *Steering
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
+}
+
+// NewRebalanceHandler returns the handler of the rebalance action of SessionsEndpoint. It disconnects the agents
+// connected to the Rancher server handling the request whose cluster is owned by another server, so that they get
+// steered to the owner. Access requires the permission to update tunnelsessions in management.cattle.io.
+func NewRebalanceHandler(steering *Steering, subjectAccessReviews authv1.SubjectAccessReviewInterface) http.Handler {
+	return &rebalanceHandler{
+		steering:             steering,
+		subjectAccessReviews: subjectAccessReviews,
+	}
+}
+
+func (h *rebalanceHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !authorize(rw, req, h.subjectAccessReviews, "update") {
+		return
+	}
+
+	response := RebalanceResponse{
+		Disconnected: h.steering.Rebalance(),
+	}
+	if h.steering.PeerManager != nil {
+		response.Replica = h.steering.PeerManager.Peers().SelfID
+	}
+	logging.FromContext(req.Context()).Infof("Rebalance disconnected %d tunnel sessions", len(response.Disconnected))
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(response); err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to write tunnel rebalance response: %v", err)
+	}
+}
+
+// authorize checks that the user may perform verb on tunnelsessions, writing the error response if not.
+func authorize(rw http.ResponseWriter, req *http.Request, subjectAccessReviews authv1.SubjectAccessReviewInterface, verb string) bool {
+	authorized, err := sar.UserCanAccess(req, subjectAccessReviews, verb, "tunnelsessions")
+	if err != nil {
+		logging.FromContext(req.Context()).Errorf("Failed to authorize request for tunnel sessions: %v", err)
+		util.ReturnHTTPError(rw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return false
+	}
+	if !authorized {
+		util.ReturnHTTPError(rw, req, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return false
+	}
+	return true
+}
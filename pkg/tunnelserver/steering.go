@@ -0,0 +1,155 @@
+package tunnelserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/agent/connect"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/peermanager"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/remotedialer"
+	"github.com/sirupsen/logrus"
+)
+
+// SteerError rejects the connect request of an agent whose cluster is owned by another Rancher server. It is written
+// as a 421 Misdirected Request, upon which the agent reconnects through the load balancer.
+type SteerError struct {
+	ClientKey string
+	Owner     string
+}
+
+func (e *SteerError) Error() string {
+	return fmt.Sprintf("cluster of %s is owned by Rancher server %s", e.ClientKey, e.Owner)
+}
+
+// Steering places the tunnels of the agents on the Rancher server owning the controllers of their cluster, so that the
+// requests of those controllers do not hop between peers. The Rancher servers cannot be addressed by the agents, which
+// connect through a load balancer, so an agent landing on another server is asked to reconnect until it reaches the
+// owner or tunnel-steering-max-attempts is exhausted.
+type Steering struct {
+	// PeerManager and Sessions are set once the tunnel server the steering authorizes for is created. PeerManager
+	// stays nil when Rancher is not running in clustered mode, in which case agents are never steered.
+	PeerManager peermanager.PeerManager
+	Sessions    *Sessions
+	clusters    mgmtcontrollers.ClusterCache
+}
+
+func NewSteering(clusters mgmtcontrollers.ClusterCache) *Steering {
+	return &Steering{
+		clusters: clusters,
+	}
+}
+
+// Authorizer wraps the authorizer of the tunnel server to steer the agents it authorizes.
+func (s *Steering) Authorizer(next remotedialer.Authorizer) remotedialer.Authorizer {
+	return func(req *http.Request) (string, bool, error) {
+		clientKey, authed, err := next(req)
+		if err != nil || !authed {
+			return clientKey, authed, err
+		}
+		if owner := s.steer(req, clientKey); owner != "" {
+			incSteered(clientKey)
+			return "", false, &SteerError{ClientKey: clientKey, Owner: owner}
+		}
+		return clientKey, authed, nil
+	}
+}
+
+// steer returns the owner the agent should be steered to, if any. Registrations are never steered, as they update
+// the cluster and its nodes.
+func (s *Steering) steer(req *http.Request, clientKey string) string {
+	value := req.Header.Get(connect.SteeringHeader)
+	if value == "" || strings.HasSuffix(req.URL.Path, "/register") {
+		return ""
+	}
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts >= settings.TunnelSteeringMaxAttempts.GetInt() {
+		return ""
+	}
+	cluster, _ := splitClientKey(clientKey)
+	return s.owner(cluster)
+}
+
+// owner returns the peer owning the controllers of the cluster, or an empty string when this server owns them or the
+// owner is unknown.
+func (s *Steering) owner(clusterName string) string {
+	if s.PeerManager == nil {
+		return ""
+	}
+	peers := s.PeerManager.Peers()
+	if !peers.Ready || len(peers.IDs) == 0 {
+		return ""
+	}
+	cluster, err := s.clusters.Get(clusterName)
+	if err != nil {
+		return ""
+	}
+
+	ids := append([]string{peers.SelfID}, peers.IDs...)
+	sort.Strings(ids)
+	if owner := peermanager.Owner(ids, string(cluster.UID)); owner != peers.SelfID {
+		return owner
+	}
+	return ""
+}
+
+// Rebalance disconnects the agents connected to this server whose cluster is owned by another one, so that they
+// reconnect and get steered to the owner. Only the agents supporting steering are disconnected; the others would
+// land on a random server again. It returns the disconnected sessions.
+func (s *Steering) Rebalance() []SessionInfo {
+	return s.Sessions.disconnect(func(info SessionInfo) bool {
+		return info.Steerable && s.owner(info.Cluster) != ""
+	})
+}
+
+// Start rebalances the agents every time the peers changed and then settled for tunnel-rebalance-delay-seconds, such
+// as after Rancher was scaled up.
+func (s *Steering) Start(ctx context.Context) {
+	if s.PeerManager == nil {
+		return
+	}
+
+	c := make(chan peermanager.Peers, 100)
+	s.PeerManager.AddListener(c)
+	go func() {
+		<-ctx.Done()
+		s.PeerManager.RemoveListener(c)
+		close(c)
+	}()
+
+	go func() {
+		var (
+			current string
+			settled <-chan time.Time
+		)
+		for {
+			select {
+			case peers, ok := <-c:
+				if !ok {
+					return
+				}
+				ids := append([]string{}, peers.IDs...)
+				sort.Strings(ids)
+				key := strings.Join(ids, ",")
+				if !peers.Ready || key == current {
+					continue
+				}
+				current = key
+				if delay := settings.TunnelRebalanceDelaySeconds.GetInt(); delay > 0 {
+					settled = time.After(time.Duration(delay) * time.Second)
+				}
+			case <-settled:
+				settled = nil
+				if disconnected := s.Rebalance(); len(disconnected) > 0 {
+					logrus.Infof("Disconnected %d tunnel sessions of clusters owned by other Rancher servers", len(disconnected))
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,142 @@
+package tunnelserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/agent/connect"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/peermanager"
+	"github.com/rancher/remotedialer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeClusterCache struct {
+	mgmtcontrollers.ClusterCache
+	clusters map[string]*v3.Cluster
+}
+
+func (f *fakeClusterCache) Get(name string) (*v3.Cluster, error) {
+	if cluster, ok := f.clusters[name]; ok {
+		return cluster, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: "management.cattle.io", Resource: "clusters"}, name)
+}
+
+var steeringPeers = peermanager.Peers{
+	SelfID: "10.0.0.1",
+	IDs:    []string{"10.0.0.2"},
+	Ready:  true,
+}
+
+// newSteering returns a steering for the clusters c-self, owned by this server, and c-other, owned by its peer.
+func newSteering(t *testing.T) *Steering {
+	t.Helper()
+	clusters := &fakeClusterCache{clusters: map[string]*v3.Cluster{}}
+	ids := []string{"10.0.0.1", "10.0.0.2"}
+	for i := 0; len(clusters.clusters) < 2; i++ {
+		uid := fmt.Sprintf("uid-%d", i)
+		name := "c-other"
+		if peermanager.Owner(ids, uid) == steeringPeers.SelfID {
+			name = "c-self"
+		}
+		if _, ok := clusters.clusters[name]; !ok {
+			clusters.clusters[name] = &v3.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(uid)}}
+		}
+	}
+
+	steering := NewSteering(clusters)
+	steering.PeerManager = &fakePeerManager{peers: steeringPeers}
+	return steering
+}
+
+func TestSteeringAuthorizer(t *testing.T) {
+	steering := newSteering(t)
+
+	tests := []struct {
+		name      string
+		clientKey string
+		path      string
+		attempts  string
+		owner     string
+	}{
+		{name: "owned by peer", clientKey: "c-other", attempts: "0", owner: "10.0.0.2"},
+		{name: "node agent owned by peer", clientKey: "c-other:m-1", attempts: "1", owner: "10.0.0.2"},
+		{name: "owned by self", clientKey: "c-self", attempts: "0"},
+		{name: "agent without steering", clientKey: "c-other"},
+		{name: "attempts exhausted", clientKey: "c-other", attempts: "3"},
+		{name: "registration", clientKey: "c-other", path: "/v3/connect/register", attempts: "0"},
+		{name: "unknown cluster", clientKey: "stv-cluster-c-other", attempts: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorize := steering.Authorizer(func(req *http.Request) (string, bool, error) {
+				return tt.clientKey, true, nil
+			})
+			path := tt.path
+			if path == "" {
+				path = "/v3/connect"
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tt.attempts != "" {
+				req.Header.Set(connect.SteeringHeader, tt.attempts)
+			}
+
+			clientKey, authed, err := authorize(req)
+			if tt.owner == "" {
+				require.NoError(t, err)
+				assert.True(t, authed)
+				assert.Equal(t, tt.clientKey, clientKey)
+				return
+			}
+			var steerErr *SteerError
+			require.True(t, errors.As(err, &steerErr))
+			assert.False(t, authed)
+			assert.Equal(t, tt.owner, steerErr.Owner)
+		})
+	}
+}
+
+func TestSteeringNotClustered(t *testing.T) {
+	steering := newSteering(t)
+	steering.PeerManager = &fakePeerManager{peers: peermanager.Peers{SelfID: "10.0.0.1", IDs: []string{"10.0.0.2"}}}
+	assert.Empty(t, steering.owner("c-other"), "agents must not be steered before the peers are ready")
+
+	steering.PeerManager = nil
+	assert.Empty(t, steering.owner("c-other"))
+}
+
+func TestErrorWriterSteering(t *testing.T) {
+	rw := httptest.NewRecorder()
+	ErrorWriter(rw, httptest.NewRequest(http.MethodGet, "/v3/connect", nil), http.StatusBadRequest,
+		&SteerError{ClientKey: "c-other", Owner: "10.0.0.2"})
+	assert.Equal(t, http.StatusMisdirectedRequest, rw.Code)
+	assert.Equal(t, "10.0.0.2", rw.Header().Get(connect.OwnerHeader))
+}
+
+func TestSteeringRebalance(t *testing.T) {
+	steering := newSteering(t)
+	steering.Sessions = NewSessions(remotedialer.New(nil, nil))
+	done := make(chan struct{})
+	defer close(done)
+
+	steerable := http.Header{connect.SteeringHeader: []string{"0"}}
+	connectAgent(t, steering.Sessions, steerable, "c-other", done)
+	connectAgent(t, steering.Sessions, steerable, "c-self", done)
+	connectAgent(t, steering.Sessions, nil, "c-other:m-1", done)
+	connectAgent(t, steering.Sessions, http.Header{remotedialer.ID: []string{"10.0.0.2"}}, "", done)
+
+	disconnected := steering.Rebalance()
+	require.Len(t, disconnected, 1)
+	assert.Equal(t, "c-other", disconnected[0].ClientKey)
+	assert.True(t, disconnected[0].Steerable)
+}
@@ -35,6 +35,7 @@
 	projectv3 "github.com/rancher/rancher/pkg/generated/norman/project.cattle.io/v3"
 	rbacv1 "github.com/rancher/rancher/pkg/generated/norman/rbac.authorization.k8s.io/v1"
 	storagev1 "github.com/rancher/rancher/pkg/generated/norman/storage.k8s.io/v1"
+	"github.com/rancher/rancher/pkg/metrics/clustercontrollers"
 	"github.com/rancher/rancher/pkg/peermanager"
 	clusterSchema "github.com/rancher/rancher/pkg/schemas/cluster.cattle.io/v3"
 	managementSchema "github.com/rancher/rancher/pkg/schemas/management.cattle.io/v3"
@@ -420,7 +421,7 @@ func NewUserContext(scaledContext *ScaledContext, config rest.Config, clusterNam
 		KindNamespace: context.KindNamespaces,
 	})
 
-	controllerFactory := controller.NewSharedControllerFactory(cacheFactory, controllers.GetOptsFromEnv(controllers.User))
+	controllerFactory := clustercontrollers.Instrument(clusterName, controller.NewSharedControllerFactory(cacheFactory, controllers.GetOptsFromEnv(controllers.User)))
 	context.ControllerFactory = controllerFactory
 
 	context.K8sClient, err = kubernetes.NewForConfig(&config)
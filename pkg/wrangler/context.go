@@ -25,6 +25,7 @@
 	"github.com/rancher/rancher/pkg/catalogv2/content"
 	"github.com/rancher/rancher/pkg/catalogv2/helmop"
 	"github.com/rancher/rancher/pkg/catalogv2/system"
+	"github.com/rancher/rancher/pkg/clusterrouter/circuit"
 	"github.com/rancher/rancher/pkg/controllers"
 	"github.com/rancher/rancher/pkg/generated/controllers/catalog.cattle.io"
 	catalogcontrollers "github.com/rancher/rancher/pkg/generated/controllers/catalog.cattle.io/v1"
@@ -127,6 +128,9 @@ type Context struct {
 	MultiClusterManager MultiClusterManager
 	TunnelServer        *remotedialer.Server
 	TunnelAuthorizer    *tunnelserver.Authorizers
+	TunnelSessions      *tunnelserver.Sessions
+	TunnelSteering      *tunnelserver.Steering
+	ClusterProxyGuard   *circuit.Guard
 	PeerManager         peermanager.PeerManager
 	Provisioning        provisioningv1.Interface
 	RBAC                rbacv1.Interface
@@ -396,11 +400,16 @@ func NewContext(ctx context.Context, clientConfig clientcmd.ClientConfig, restCo
 	}
 
 	tunnelAuth := &tunnelserver.Authorizers{}
-	tunnelServer := remotedialer.New(tunnelAuth.Authorize, tunnelserver.ErrorWriter)
+	tunnelSteering := tunnelserver.NewSteering(mgmt.Management().V3().Cluster().Cache())
+	tunnelServer := remotedialer.New(tunnelSteering.Authorizer(tunnelAuth.Authorize), tunnelserver.ErrorWriter)
+	tunnelSessions := tunnelserver.NewSessions(tunnelServer)
+	tunnelSteering.Sessions = tunnelSessions
 	peerManager, err := tunnelserver.NewPeerManager(ctx, core.Core().V1().Endpoints(), tunnelServer)
 	if err != nil {
 		return nil, err
 	}
+	tunnelSteering.PeerManager = peerManager
+	tunnelSteering.Start(ctx)
 
 	leadership := leader.NewManager("", "cattle-controllers", k8s)
 	leadership.OnLeader(func(ctx context.Context) error {
@@ -444,6 +453,9 @@ func NewContext(ctx context.Context, clientConfig clientcmd.ClientConfig, restCo
 		SystemChartsManager:     systemCharts,
 		TunnelAuthorizer:        tunnelAuth,
 		TunnelServer:            tunnelServer,
+		TunnelSessions:          tunnelSessions,
+		TunnelSteering:          tunnelSteering,
+		ClusterProxyGuard:       circuit.NewGuard(),
 
 		mgmt:         mgmt,
 		apps:         apps,